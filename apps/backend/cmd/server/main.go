@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"backend-go/internal/config"
@@ -101,9 +102,71 @@ func main() {
 		})
 	})
 
+	// Email is optional, but a configured SMTP server must be usable
+	if err := cfg.Mail.Validate(); err != nil {
+		logger.Fatal("Invalid mail configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
 	// Setup all routes with middleware
 	routes.Setup(app, db, cfg)
 
+	// Start background reminder dispatcher (leader-locked, safe to run on every replica)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if cfg.Scheduler.Enabled {
+		if err := cfg.Scheduler.Validate(); err != nil {
+			logger.Fatal("Invalid scheduler configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+
+		jwtService := services.NewJWTService(&services.JWTConfig{SecretKey: cfg.JWTSecret}, db)
+		dispatcher := services.NewReminderDispatcher(db, &services.ReminderDispatcherConfig{
+			Interval:               cfg.Scheduler.Interval,
+			EvaluationReminderDays: cfg.Scheduler.EvaluationReminderDays,
+			CleanupInterval:        cfg.Scheduler.CleanupInterval,
+			LeaderLockKey:          cfg.Scheduler.LeaderLockKey,
+			BatchSize:              cfg.Scheduler.BatchSize,
+		}, jwtService)
+		dispatcher.Start(ctx)
+	}
+
+	// Start export workers (jobs are claimed from the database, safe to run on every replica)
+	if cfg.Export.WorkersEnabled {
+		if err := cfg.Export.Validate(); err != nil {
+			logger.Fatal("Invalid export configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+
+		exportService := services.NewExportService(db, services.ExportSettings{
+			Directory:    cfg.Export.Directory,
+			Workers:      cfg.Export.Workers,
+			PollInterval: cfg.Export.PollInterval,
+			Retention:    cfg.Export.Retention,
+			StaleAfter:   cfg.Export.StaleAfter,
+		})
+		exportService.StartWorkers(ctx)
+	}
+
+	// Start the recycle bin purge job (deletes are idempotent, safe to run on every replica)
+	if cfg.RecycleBin.PurgeEnabled {
+		if err := cfg.RecycleBin.Validate(); err != nil {
+			logger.Fatal("Invalid recycle bin configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+
+		recycleBinService := services.NewRecycleBinService(db, services.RecycleBinSettings{
+			Retention:     cfg.RecycleBin.Retention,
+			PurgeInterval: cfg.RecycleBin.PurgeInterval,
+		})
+		recycleBinService.StartPurgeJob(ctx)
+	}
+
 	// Start server
 	port := cfg.Port
 	if port == "" {
@@ -2,4 +2,7 @@ module internship-backend
 
 go 1.21
 
-require github.com/lib/pq v1.10.9
\ No newline at end of file
+require (
+	github.com/boombuler/barcode v1.1.0
+	github.com/lib/pq v1.10.9
+)
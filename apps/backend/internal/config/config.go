@@ -14,6 +14,10 @@ type Config struct {
 	LogLevel       string
 	LogFormat      string
 	TwoFactor      *TwoFactorConfig
+	Scheduler      *SchedulerConfig
+	Export         *ExportConfig
+	RecycleBin     *RecycleBinConfig
+	Mail           *MailConfig
 }
 
 func Load() *Config {
@@ -26,6 +30,10 @@ func Load() *Config {
 		LogLevel:       getEnv("LOG_LEVEL", "info"),
 		LogFormat:      getEnv("LOG_FORMAT", "json"),
 		TwoFactor:      LoadTwoFactorConfig(),
+		Scheduler:      LoadSchedulerConfig(),
+		Export:         LoadExportConfig(),
+		RecycleBin:     LoadRecycleBinConfig(),
+		Mail:           LoadMailConfig(),
 	}
 }
 
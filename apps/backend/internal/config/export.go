@@ -0,0 +1,80 @@
+package config
+
+import (
+	"time"
+)
+
+// ExportConfig holds configuration for asynchronous export jobs and saved report files
+type ExportConfig struct {
+	// WorkersEnabled starts the export worker pool on this instance
+	WorkersEnabled bool `json:"workers_enabled"`
+
+	// Workers is the number of export jobs processed concurrently per instance
+	Workers int `json:"workers"`
+
+	// PollInterval between checks for queued export jobs
+	PollInterval time.Duration `json:"poll_interval"`
+
+	// Directory where finished export files are written
+	Directory string `json:"directory"`
+
+	// Retention is how long a finished export file can be downloaded before it is deleted
+	Retention time.Duration `json:"retention"`
+
+	// StaleAfter marks a running job as failed when it has not reported progress for this long
+	StaleAfter time.Duration `json:"stale_after"`
+
+	// ReportDirectory is where generated saved reports are written
+	ReportDirectory string `json:"report_directory"`
+
+	// ReportRetention is how long a generated saved report can be downloaded before it must be regenerated
+	ReportRetention time.Duration `json:"report_retention"`
+}
+
+// LoadExportConfig loads export configuration from environment variables
+func LoadExportConfig() *ExportConfig {
+	return &ExportConfig{
+		WorkersEnabled: getEnvAsBool("EXPORT_WORKERS_ENABLED", true),
+		Workers:        getEnvAsInt("EXPORT_WORKERS", 2),
+		PollInterval:   getEnvAsDuration("EXPORT_POLL_INTERVAL", 5*time.Second),
+		Directory:      getEnv("EXPORT_DIRECTORY", "uploads/exports"),
+		Retention:      getEnvAsDuration("EXPORT_RETENTION", 72*time.Hour),
+		StaleAfter:     getEnvAsDuration("EXPORT_STALE_AFTER", 30*time.Minute),
+
+		ReportDirectory: getEnv("REPORT_DIRECTORY", "uploads/reports"),
+		ReportRetention: getEnvAsDuration("REPORT_RETENTION", 30*24*time.Hour),
+	}
+}
+
+// Validate checks if the export configuration is valid
+func (c *ExportConfig) Validate() error {
+	if c.Workers < 1 {
+		return &ConfigError{Field: "workers", Message: "at least one export worker is required"}
+	}
+
+	if c.PollInterval < time.Second {
+		return &ConfigError{Field: "poll_interval", Message: "export poll interval must be at least one second"}
+	}
+
+	if c.Directory == "" {
+		return &ConfigError{Field: "directory", Message: "export directory is required"}
+	}
+
+	if c.Retention < time.Hour {
+		return &ConfigError{Field: "retention", Message: "export retention must be at least one hour"}
+	}
+
+	if c.StaleAfter < time.Minute {
+		return &ConfigError{Field: "stale_after", Message: "stale job timeout must be at least one minute"}
+	}
+
+	if c.ReportDirectory == "" {
+		return &ConfigError{Field: "report_directory", Message: "report directory is required"}
+	}
+
+	if c.ReportRetention < time.Hour {
+		return &ConfigError{Field: "report_retention", Message: "report retention must be at least one hour"}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+// MailConfig holds configuration for outgoing email
+type MailConfig struct {
+	// Host of the SMTP server; email is disabled when empty
+	Host string `json:"host"`
+
+	// Port of the SMTP server
+	Port int `json:"port"`
+
+	// Username and Password authenticate with the SMTP server when a username is set
+	Username string `json:"username"`
+	Password string `json:"-"`
+
+	// FromAddress is the sender of outgoing email
+	FromAddress string `json:"from_address"`
+
+	// AppURL is the public base URL links in email point to
+	AppURL string `json:"app_url"`
+}
+
+// LoadMailConfig loads mail configuration from environment variables
+func LoadMailConfig() *MailConfig {
+	return &MailConfig{
+		Host:        getEnv("MAIL_HOST", ""),
+		Port:        getEnvAsInt("MAIL_PORT", 587),
+		Username:    getEnv("MAIL_USERNAME", ""),
+		Password:    getEnv("MAIL_PASSWORD", ""),
+		FromAddress: getEnv("MAIL_FROM_ADDRESS", ""),
+		AppURL:      getEnv("APP_URL", "http://localhost:8080"),
+	}
+}
+
+// Enabled reports whether an SMTP server is configured
+func (c *MailConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// Validate checks if the mail configuration is valid
+func (c *MailConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		return &ConfigError{Field: "port", Message: "mail port must be between 1 and 65535"}
+	}
+
+	if c.FromAddress == "" {
+		return &ConfigError{Field: "from_address", Message: "mail from address is required"}
+	}
+
+	if c.AppURL == "" {
+		return &ConfigError{Field: "app_url", Message: "app URL is required for links in email"}
+	}
+
+	return nil
+}
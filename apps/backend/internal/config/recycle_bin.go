@@ -0,0 +1,39 @@
+package config
+
+import (
+	"time"
+)
+
+// RecycleBinConfig holds configuration for soft-deleted records and the purge job
+type RecycleBinConfig struct {
+	// PurgeEnabled starts the purge job on this instance
+	PurgeEnabled bool `json:"purge_enabled"`
+
+	// Retention is how long a deleted record can be restored before it is permanently deleted
+	Retention time.Duration `json:"retention"`
+
+	// PurgeInterval between purge runs
+	PurgeInterval time.Duration `json:"purge_interval"`
+}
+
+// LoadRecycleBinConfig loads recycle bin configuration from environment variables
+func LoadRecycleBinConfig() *RecycleBinConfig {
+	return &RecycleBinConfig{
+		PurgeEnabled:  getEnvAsBool("RECYCLE_BIN_PURGE_ENABLED", true),
+		Retention:     getEnvAsDuration("RECYCLE_BIN_RETENTION", 30*24*time.Hour),
+		PurgeInterval: getEnvAsDuration("RECYCLE_BIN_PURGE_INTERVAL", 6*time.Hour),
+	}
+}
+
+// Validate checks if the recycle bin configuration is valid
+func (c *RecycleBinConfig) Validate() error {
+	if c.Retention < 24*time.Hour {
+		return &ConfigError{Field: "retention", Message: "recycle bin retention must be at least one day"}
+	}
+
+	if c.PurgeInterval < time.Minute {
+		return &ConfigError{Field: "purge_interval", Message: "purge interval must be at least one minute"}
+	}
+
+	return nil
+}
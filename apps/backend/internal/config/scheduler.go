@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchedulerConfig holds configuration for the in-process background job scheduler
+type SchedulerConfig struct {
+	// Enabled turns the scheduler on for this instance (off unless SCHEDULER_ENABLED is set)
+	Enabled bool `json:"enabled"`
+
+	// Interval between scheduler runs
+	Interval time.Duration `json:"interval"`
+
+	// EvaluationReminderDays lists how many days before an evaluation due date reminders are sent
+	EvaluationReminderDays []int `json:"evaluation_reminder_days"`
+
+	// CleanupInterval between cleanup runs for expired notifications and tokens
+	CleanupInterval time.Duration `json:"cleanup_interval"`
+
+	// LeaderLockKey is the PostgreSQL advisory lock key shared by all replicas
+	LeaderLockKey int64 `json:"leader_lock_key"`
+
+	// BatchSize limits how many due schedule notifications are sent per run
+	BatchSize int `json:"batch_size"`
+}
+
+// LoadSchedulerConfig loads scheduler configuration from environment variables
+func LoadSchedulerConfig() *SchedulerConfig {
+	return &SchedulerConfig{
+		Enabled:                getEnvAsBool("SCHEDULER_ENABLED", false),
+		Interval:               getEnvAsDuration("SCHEDULER_INTERVAL", time.Minute),
+		EvaluationReminderDays: getEnvAsIntList("EVALUATION_REMINDER_DAYS", []int{7, 3, 1}),
+		CleanupInterval:        getEnvAsDuration("SCHEDULER_CLEANUP_INTERVAL", 24*time.Hour),
+		LeaderLockKey:          int64(getEnvAsInt("SCHEDULER_LOCK_KEY", 727001)),
+		BatchSize:              getEnvAsInt("SCHEDULER_BATCH_SIZE", 500),
+	}
+}
+
+// Validate checks if the scheduler configuration is valid
+func (c *SchedulerConfig) Validate() error {
+	if c.Interval < time.Second {
+		return &ConfigError{Field: "interval", Message: "scheduler interval must be at least one second"}
+	}
+
+	if c.CleanupInterval < c.Interval {
+		return &ConfigError{Field: "cleanup_interval", Message: "cleanup interval cannot be shorter than the scheduler interval"}
+	}
+
+	for _, days := range c.EvaluationReminderDays {
+		if days < 0 {
+			return &ConfigError{Field: "evaluation_reminder_days", Message: "reminder days cannot be negative"}
+		}
+	}
+
+	if c.BatchSize < 1 {
+		return &ConfigError{Field: "batch_size", Message: "batch size must be at least 1"}
+	}
+
+	return nil
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsIntList parses a comma-separated list of integers such as "7,3,1"
+func getEnvAsIntList(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []int
+	for _, part := range strings.Split(value, ",") {
+		intValue, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, intValue)
+	}
+	return values
+}
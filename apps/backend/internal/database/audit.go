@@ -0,0 +1,369 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuditActor identifies who made a change recorded in the audit trail
+type AuditActor struct {
+	UserID    uint   // numeric user ID, 0 when the caller has none
+	ActorID   string // authenticated subject: student ID for users, admin ID for admins
+	UserType  string
+	IPAddress string
+	UserAgent string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a context carrying the actor of the changes made with it
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// WithAuditContext binds db to a request context so the audit callbacks record its changes under
+// the actor set by the auth middleware. Services expose it through their WithContext method.
+func WithAuditContext(db *gorm.DB, ctx context.Context) *gorm.DB {
+	if ctx == nil {
+		return db
+	}
+	return db.WithContext(ctx)
+}
+
+// AuditActorFromContext returns the actor carried by a context
+func AuditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	if ctx == nil {
+		return AuditActor{}, false
+	}
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
+
+// auditedTables maps the tables whose changes are recorded to their entity types
+var auditedTables = map[string]models.EntityType{
+	models.Student{}.TableName():                models.EntityTypeStudent,
+	models.Company{}.TableName():                models.EntityTypeCompany,
+	models.StudentTraining{}.TableName():        models.EntityTypeTraining,
+	models.InternshipApproval{}.TableName():     models.EntityTypeApproval,
+	models.Document{}.TableName():               models.EntityTypeDocument,
+	models.VisitorEvaluateStudent{}.TableName(): models.EntityTypeEvaluation,
+	models.VisitorEvaluateCompany{}.TableName(): models.EntityTypeEvaluation,
+	models.StudentEvaluateCompany{}.TableName(): models.EntityTypeEvaluation,
+	models.EvaluationSubmission{}.TableName():   models.EntityTypeEvaluation,
+}
+
+// auditSkippedColumns are left out of recorded changes
+var auditSkippedColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// auditRowLimit caps the rows captured for a single bulk update or delete
+const auditRowLimit = 500
+
+const auditBeforeKey = "audit:before"
+
+// AuditChange is the before and after value of one column
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditPlugin records creates, updates and deletes of the audited tables in activity_logs, with
+// the actor taken from the statement context and the changed columns. A failure to record is
+// logged and does not fail the change.
+type AuditPlugin struct{}
+
+// Name returns the plugin name
+func (AuditPlugin) Name() string {
+	return "audit"
+}
+
+// Initialize registers the audit callbacks
+func (p AuditPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Register("audit:after_create", p.afterCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("audit:before_update", p.captureBefore); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("audit:after_update", p.afterUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("audit:before_delete", p.captureBefore); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Register("audit:after_delete", p.afterDelete)
+}
+
+// afterCreate records the columns of newly created rows
+func (p AuditPlugin) afterCreate(db *gorm.DB) {
+	entityType, ok := auditedEntity(db)
+	if !ok || db.Error != nil {
+		return
+	}
+
+	ids := auditPrimaryKeys(db)
+	if len(ids) == 0 {
+		return
+	}
+	rows, err := loadAuditRows(db, ids, nil)
+	if err != nil {
+		p.logError(db, err)
+		return
+	}
+	for _, row := range rows {
+		p.record(db, models.ActivityActionCreate, entityType, row, diffAuditRows(nil, row))
+	}
+}
+
+// captureBefore loads the rows an update or delete is about to change
+func (p AuditPlugin) captureBefore(db *gorm.DB) {
+	if _, ok := auditedEntity(db); !ok || db.Error != nil {
+		return
+	}
+
+	var where clause.Expression
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		where = c.Expression
+	}
+	ids := auditPrimaryKeys(db)
+	if len(ids) == 0 && where == nil {
+		return
+	}
+
+	rows, err := loadAuditRows(db, ids, where)
+	if err != nil {
+		p.logError(db, err)
+		return
+	}
+	db.InstanceSet(auditBeforeKey, rows)
+}
+
+// afterUpdate records the columns an update changed
+func (p AuditPlugin) afterUpdate(db *gorm.DB) {
+	entityType, before, ok := p.capturedRows(db)
+	if !ok || len(before) == 0 {
+		return
+	}
+
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row[auditPrimaryColumn(db)])
+	}
+	after, err := loadAuditRows(db, ids, nil)
+	if err != nil {
+		p.logError(db, err)
+		return
+	}
+	afterByID := make(map[string]map[string]interface{}, len(after))
+	for _, row := range after {
+		afterByID[fmt.Sprint(row[auditPrimaryColumn(db)])] = row
+	}
+
+	for _, row := range before {
+		changes := diffAuditRows(row, afterByID[fmt.Sprint(row[auditPrimaryColumn(db)])])
+		if len(changes) > 0 {
+			p.record(db, models.ActivityActionUpdate, entityType, row, changes)
+		}
+	}
+}
+
+// afterDelete records the columns of deleted rows
+func (p AuditPlugin) afterDelete(db *gorm.DB) {
+	entityType, before, ok := p.capturedRows(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+	for _, row := range before {
+		p.record(db, models.ActivityActionDelete, entityType, row, diffAuditRows(row, nil))
+	}
+}
+
+// capturedRows returns the rows captured before a successful update or delete
+func (p AuditPlugin) capturedRows(db *gorm.DB) (models.EntityType, []map[string]interface{}, bool) {
+	entityType, ok := auditedEntity(db)
+	if !ok || db.Error != nil {
+		return "", nil, false
+	}
+	value, ok := db.InstanceGet(auditBeforeKey)
+	if !ok {
+		return "", nil, false
+	}
+	rows, ok := value.([]map[string]interface{})
+	return entityType, rows, ok
+}
+
+// record writes one activity log entry for a changed row
+func (p AuditPlugin) record(db *gorm.DB, action models.ActivityAction, entityType models.EntityType, row map[string]interface{}, changes map[string]AuditChange) {
+	actor, _ := AuditActorFromContext(db.Statement.Context)
+	entityID := auditUint(row[auditPrimaryColumn(db)])
+
+	fields := make([]string, 0, len(changes))
+	for column := range changes {
+		fields = append(fields, column)
+	}
+	sort.Strings(fields)
+	description := fmt.Sprintf("%s %s %d", action, entityType, entityID)
+	if action == models.ActivityActionUpdate {
+		description += ": " + strings.Join(fields, ", ")
+	}
+
+	entry := &models.ActivityLog{
+		UserID:      actor.UserID,
+		ActorID:     actor.ActorID,
+		UserType:    actor.UserType,
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Description: description,
+		IPAddress:   actor.IPAddress,
+		UserAgent:   actor.UserAgent,
+	}
+	if err := entry.SetMetadata(map[string]interface{}{
+		"table":   db.Statement.Table,
+		"changes": changes,
+	}); err != nil {
+		p.logError(db, err)
+		return
+	}
+
+	// A nested transaction runs in a savepoint, so a failed insert leaves the caller's transaction usable
+	err := db.Session(&gorm.Session{NewDB: true}).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		p.logError(db, err)
+	}
+}
+
+// logError reports a change that could not be audited
+func (p AuditPlugin) logError(db *gorm.DB, err error) {
+	db.Logger.Error(db.Statement.Context, "audit: failed to record change to %s: %v", db.Statement.Table, err)
+}
+
+// auditedEntity returns the entity type of the statement's table when it is audited
+func auditedEntity(db *gorm.DB) (models.EntityType, bool) {
+	if db.DryRun || db.Statement.Schema == nil || db.Statement.Schema.PrioritizedPrimaryField == nil {
+		return "", false
+	}
+	entityType, ok := auditedTables[db.Statement.Table]
+	return entityType, ok
+}
+
+// auditPrimaryColumn returns the primary key column of the statement's table
+func auditPrimaryColumn(db *gorm.DB) string {
+	return db.Statement.Schema.PrioritizedPrimaryField.DBName
+}
+
+// auditPrimaryKeys returns the non-zero primary keys of the records the statement works on
+func auditPrimaryKeys(db *gorm.DB) []interface{} {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	value := db.Statement.ReflectValue
+
+	var ids []interface{}
+	switch value.Kind() {
+	case reflect.Struct:
+		if id, zero := field.ValueOf(db.Statement.Context, value); !zero {
+			ids = append(ids, id)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if id, zero := field.ValueOf(db.Statement.Context, reflect.Indirect(value.Index(i))); !zero {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// loadAuditRows reads the current columns of the rows matching the primary keys and conditions
+func loadAuditRows(db *gorm.DB, ids []interface{}, where clause.Expression) ([]map[string]interface{}, error) {
+	query := db.Session(&gorm.Session{NewDB: true}).Table(db.Statement.Table)
+	if len(ids) > 0 {
+		query = query.Where(clause.IN{Column: clause.Column{Name: auditPrimaryColumn(db)}, Values: ids})
+	}
+	if where != nil {
+		query = query.Clauses(where)
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Limit(auditRowLimit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// diffAuditRows returns the columns that differ between two versions of a row; a nil row stands
+// for one that does not exist
+func diffAuditRows(before, after map[string]interface{}) map[string]AuditChange {
+	columns := make(map[string]bool, len(before)+len(after))
+	for column := range before {
+		columns[column] = true
+	}
+	for column := range after {
+		columns[column] = true
+	}
+
+	changes := make(map[string]AuditChange)
+	for column := range columns {
+		if auditSkippedColumns[column] {
+			continue
+		}
+		old, value := auditValue(before[column]), auditValue(after[column])
+		if reflect.DeepEqual(old, value) {
+			continue
+		}
+		changes[column] = AuditChange{Before: old, After: value}
+	}
+	return changes
+}
+
+// auditValue normalizes a scanned column value so equal values compare equal and encode readably
+func auditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		if json.Valid(v) {
+			return json.RawMessage(append([]byte(nil), v...))
+		}
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+// auditUint converts a scanned integer key to uint
+func auditUint(value interface{}) uint {
+	switch v := value.(type) {
+	case int64:
+		return uint(v)
+	case int32:
+		return uint(v)
+	case int:
+		return uint(v)
+	case uint:
+		return v
+	case uint32:
+		return uint(v)
+	case uint64:
+		return uint(v)
+	}
+	return 0
+}
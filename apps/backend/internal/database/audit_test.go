@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditTrail(t *testing.T) {
+	t.Run("diff keeps changed columns only", func(t *testing.T) {
+		before := map[string]interface{}{"id": int64(3), "name": "Acme", "phone": "0800", "updated_at": time.Now()}
+		after := map[string]interface{}{"id": int64(3), "name": "Acme Co", "phone": "0800", "updated_at": time.Now().Add(time.Second)}
+
+		changes := diffAuditRows(before, after)
+		assert.Equal(t, map[string]AuditChange{"name": {Before: "Acme", After: "Acme Co"}}, changes)
+	})
+
+	t.Run("created and deleted rows report every column", func(t *testing.T) {
+		row := map[string]interface{}{"id": int64(3), "name": "Acme", "created_at": time.Now()}
+
+		created := diffAuditRows(nil, row)
+		assert.Len(t, created, 2)
+		assert.Equal(t, AuditChange{Before: nil, After: "Acme"}, created["name"])
+
+		deleted := diffAuditRows(row, nil)
+		assert.Equal(t, AuditChange{Before: int64(3), After: nil}, deleted["id"])
+	})
+
+	t.Run("scanned values are normalized", func(t *testing.T) {
+		at := time.Date(2026, 3, 1, 16, 0, 0, 0, time.FixedZone("ICT", 7*3600))
+		assert.Equal(t, "2026-03-01T09:00:00Z", auditValue(at))
+		assert.Equal(t, "text", auditValue([]byte("text")))
+		assert.Equal(t, json.RawMessage(`{"a":1}`), auditValue([]byte(`{"a":1}`)))
+		assert.Nil(t, auditValue((*time.Time)(nil)))
+
+		assert.Equal(t, uint(7), auditUint(int64(7)))
+		assert.Equal(t, uint(0), auditUint("7"))
+	})
+
+	t.Run("actor travels with the context", func(t *testing.T) {
+		_, ok := AuditActorFromContext(context.Background())
+		assert.False(t, ok)
+
+		actor := AuditActor{UserID: 5, ActorID: "6400000001", UserType: "student"}
+		got, ok := AuditActorFromContext(WithAuditActor(context.Background(), actor))
+		assert.True(t, ok)
+		assert.Equal(t, actor, got)
+	})
+}
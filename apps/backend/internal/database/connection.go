@@ -137,6 +137,11 @@ func ConnectWithConfig(config *DatabaseConfig) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
 
+	// Record changes to core entities in the activity log
+	if err := db.Use(AuditPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register audit plugin: %w", err)
+	}
+
 	// Configure connection pool (optimized for PostgreSQL)
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -19,6 +19,9 @@ func Migrate(db *gorm.DB) error {
 		&models.SuperAdmin{},        // New enhanced authentication model
 		&models.AccessToken{},       // New token management model
 		&models.SecurityLog{},       // New security logging model
+		&models.UserRole{},          // Role assignments for users and admins
+		&models.TwoFactorCredential{}, // TOTP secrets and lockout state
+		&models.TwoFactorBackupCode{}, // Hashed single-use backup codes
 		&models.Campus{},
 		&models.Faculty{},
 		&models.Program{},
@@ -31,9 +34,17 @@ func Migrate(db *gorm.DB) error {
 		&models.CourseSection{},
 		&models.CourseInstructor{},
 		&models.CourseCommittee{},
+		&models.CommitteeVotingPolicy{},
+		&models.ApprovalWorkflow{},
 		&models.StudentEnroll{},
 		&models.StudentEnrollStatus{},
+		&models.GradingScheme{},
+		&models.GradeSheet{},
+		&models.StudentGrade{},
 		&models.StudentTraining{},
+		&models.Timesheet{},
+		&models.AttendanceEntry{},
+		&models.AttendanceRequirement{},
 		&models.Company{},
 		&models.CompanyPicture{},
 		&models.VisitorTraining{},
@@ -42,12 +53,76 @@ func Migrate(db *gorm.DB) error {
 		&models.VisitorEvaluateCompany{},
 		&models.StudentEvaluateCompany{},
 		&models.VisitsPicture{},
+		&models.Document{},
+		&models.DocumentApproval{},
+		&models.DocumentComment{},
+		&models.DocumentTemplate{},
+		&models.Schedule{},
+		&models.ScheduleParticipant{},
+		&models.ScheduleNotification{},
+		&models.Appointment{},
+		&models.Calendar{},
+		&models.CalendarSchedule{},
+		&models.ScheduleException{},
+		&models.CalendarSubscription{},
+		&models.EvaluationStatusTracker{},
+		&models.EvaluationReminder{},
+		&models.EvaluationForm{},
+		&models.EvaluationSubmission{},
+		&models.Dashboard{},
+		&models.DashboardWidget{},
+		&models.Metric{},
+		&models.MetricValue{},
+		&models.Report{},
+		&models.ExportJob{},
+		&models.ActivityLog{},
 	)
 	
 	if err != nil {
 		return fmt.Errorf("failed to migrate models: %w", err)
 	}
 
+	// Calendar subscriptions used to be unique per user_id alone, which collides across account types
+	if db.Migrator().HasIndex(&models.CalendarSubscription{}, "idx_calendar_subscriptions_user_id") {
+		if err := db.Migrator().DropIndex(&models.CalendarSubscription{}, "idx_calendar_subscriptions_user_id"); err != nil {
+			return fmt.Errorf("failed to drop obsolete calendar subscription index: %w", err)
+		}
+	}
+
+	// Schedule participants used to be unique per user_id alone, which stops a student and an instructor
+	// with the same ID from joining the same schedule; CreateIndexes adds the account type aware index
+	if db.Migrator().HasIndex(&models.ScheduleParticipant{}, "idx_schedule_participants_user") {
+		if err := db.Migrator().DropIndex(&models.ScheduleParticipant{}, "idx_schedule_participants_user"); err != nil {
+			return fmt.Errorf("failed to drop obsolete schedule participant index: %w", err)
+		}
+	}
+
+	// Schedule, appointment and calendar account IDs may belong to any account type, so the
+	// foreign keys to users that earlier versions created no longer apply
+	legacyUserConstraints := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.Schedule{}, "fk_schedules_creator"},
+		{&models.ScheduleParticipant{}, "fk_schedule_participants_user"},
+		{&models.ScheduleNotification{}, "fk_schedule_notifications_user"},
+		{&models.Appointment{}, "fk_appointments_requester"},
+		{&models.Appointment{}, "fk_appointments_approver"},
+		{&models.Calendar{}, "fk_calendars_owner"},
+	}
+	for _, constraint := range legacyUserConstraints {
+		if !db.Migrator().HasConstraint(constraint.model, constraint.name) {
+			continue
+		}
+		if err := db.Migrator().DropConstraint(constraint.model, constraint.name); err != nil {
+			return fmt.Errorf("failed to drop obsolete constraint %s: %w", constraint.name, err)
+		}
+	}
+
+	if err := SeedRolesAndPermissions(db); err != nil {
+		return err
+	}
+
 	log.Println("Database migration completed successfully")
 	return nil
 }
@@ -91,6 +166,12 @@ func CreateIndexes(db *gorm.DB) error {
 		"CREATE INDEX IF NOT EXISTS idx_student_enrolls_student_course ON student_enrolls(student_id, course_section_id)",
 		"CREATE INDEX IF NOT EXISTS idx_visitor_schedules_date ON visitor_schedules(date)",
 		"CREATE INDEX IF NOT EXISTS idx_student_trainings_status ON student_trainings(status)",
+		"CREATE INDEX IF NOT EXISTS idx_documents_training_type_version ON documents(student_training_id, document_type, version)",
+		"CREATE INDEX IF NOT EXISTS idx_documents_status_due_date ON documents(status, due_date)",
+		"CREATE INDEX IF NOT EXISTS idx_schedules_time_range ON schedules(start_time, end_time)",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_schedule_participants_account ON schedule_participants(schedule_id, user_type, user_id)",
+		"CREATE INDEX IF NOT EXISTS idx_schedule_notifications_due ON schedule_notifications(is_sent, notify_at)",
+		"CREATE INDEX IF NOT EXISTS idx_appointments_training_date ON appointments(student_training_id, appointment_date)",
 	}
 
 	for _, indexSQL := range indexes {
@@ -139,10 +220,13 @@ func DropAllTables(db *gorm.DB) error {
 func SeedDatabase(db *gorm.DB) error {
 	log.Println("Seeding database with initial data...")
 
-	// This will be implemented when models are created
-	// Example seeding:
+	// Create default roles and permissions
+	if err := SeedRolesAndPermissions(db); err != nil {
+		return err
+	}
+
+	// Still to be implemented:
 	// - Create default admin user
-	// - Create default roles and permissions
 	// - Create sample campus/faculty data
 
 	log.Println("Database seeding completed")
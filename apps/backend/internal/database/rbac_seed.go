@@ -0,0 +1,72 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"backend-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// defaultPermissions lists the permissions checked by route middleware (resource:action)
+var defaultPermissions = []string{
+	"students:delete",
+	"students:bulk_delete",
+	"students:import",
+	"approvals:advisor_approve",
+	"approvals:committee_vote",
+	"approvals:update_status",
+	"approvals:transition",
+	"audit:read",
+	"documents:approve",
+	"schedules:manage",
+	"schedules:manage_any",
+	"appointments:manage",
+	"appointments:approve",
+}
+
+// defaultRolePermissions maps the built-in roles to their default permissions.
+// Admins get everything; additional grants can be managed in the database.
+var defaultRolePermissions = map[string][]string{
+	models.RoleNameAdmin:      defaultPermissions,
+	models.RoleNameInstructor: {"approvals:advisor_approve", "approvals:committee_vote", "approvals:transition", "documents:approve", "schedules:manage", "appointments:manage", "appointments:approve"},
+	models.RoleNameStudent:    {},
+}
+
+// SeedRolesAndPermissions creates the built-in roles and permissions if they are missing.
+// Existing grants are never removed and deleted roles are not restored, so it is
+// safe to run on every migration.
+func SeedRolesAndPermissions(db *gorm.DB) error {
+	log.Println("Seeding default roles and permissions...")
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		permissions := make(map[string]models.Permission, len(defaultPermissions))
+		for _, name := range defaultPermissions {
+			parts := strings.SplitN(name, ":", 2)
+			permission := models.Permission{Name: name}
+			if err := tx.Unscoped().Where(models.Permission{Name: name}).
+				Attrs(models.Permission{Resource: parts[0], Action: parts[1]}).
+				FirstOrCreate(&permission).Error; err != nil {
+				return fmt.Errorf("failed to seed permission %s: %w", name, err)
+			}
+			permissions[name] = permission
+		}
+
+		for roleName, grants := range defaultRolePermissions {
+			role := models.Role{Name: roleName}
+			if err := tx.Unscoped().Where(models.Role{Name: roleName}).FirstOrCreate(&role).Error; err != nil {
+				return fmt.Errorf("failed to seed role %s: %w", roleName, err)
+			}
+
+			for _, grant := range grants {
+				rolePermission := models.RolePermission{RoleID: role.ID, PermissionID: permissions[grant].ID}
+				if err := tx.Where(rolePermission).FirstOrCreate(&rolePermission).Error; err != nil {
+					return fmt.Errorf("failed to grant %s to role %s: %w", grant, roleName, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
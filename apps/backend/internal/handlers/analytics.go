@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
 	"backend-go/internal/services"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -11,13 +16,21 @@ import (
 // AnalyticsHandler handles analytics and reporting HTTP requests
 type AnalyticsHandler struct {
 	analyticsService *services.AnalyticsService
+	exportService    *services.ExportService
+	reportService    *services.ReportService
+	dashboardService *services.AnalyticsDashboardService
+	metricService    *services.MetricService
 	validator        *validator.Validate
 }
 
 // NewAnalyticsHandler creates a new analytics handler instance
-func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService, exportService *services.ExportService, reportService *services.ReportService, dashboardService *services.AnalyticsDashboardService, metricService *services.MetricService) *AnalyticsHandler {
 	return &AnalyticsHandler{
 		analyticsService: analyticsService,
+		exportService:    exportService,
+		reportService:    reportService,
+		dashboardService: dashboardService,
+		metricService:    metricService,
 		validator:        validator.New(),
 	}
 }
@@ -82,6 +95,72 @@ func (h *AnalyticsHandler) GetApprovalAnalytics(c *fiber.Ctx) error {
 	return c.JSON(analytics)
 }
 
+// GetApprovalFunnel handles GET /api/v1/analytics/approvals/funnel?statuses=registered,t.approved
+func (h *AnalyticsHandler) GetApprovalFunnel(c *fiber.Ctx) error {
+	var statuses []models.InternshipApprovalStatus
+	if value := c.Query("statuses"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				statuses = append(statuses, models.InternshipApprovalStatus(status))
+			}
+		}
+	}
+
+	funnel, err := h.analyticsService.GetApprovalFunnel(approvalAnalyticsRequest(c), statuses)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve approval funnel",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"steps": funnel,
+	})
+}
+
+// GetApprovalStatusDurations handles GET /api/v1/analytics/approvals/durations
+func (h *AnalyticsHandler) GetApprovalStatusDurations(c *fiber.Ctx) error {
+	durations, err := h.analyticsService.GetApprovalStatusDurations(approvalAnalyticsRequest(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve approval status durations",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"statuses": durations,
+	})
+}
+
+// GetApprovalTurnaround handles GET /api/v1/analytics/approvals/turnaround?limit=10
+func (h *AnalyticsHandler) GetApprovalTurnaround(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	leaderboard, err := h.analyticsService.GetApprovalTurnaround(approvalAnalyticsRequest(c), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve approval turnaround",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(leaderboard)
+}
+
+// approvalAnalyticsRequest reads the optional start_date and end_date (YYYY-MM-DD) query parameters
+func approvalAnalyticsRequest(c *fiber.Ctx) services.AnalyticsRequest {
+	req := services.AnalyticsRequest{}
+	if parsed, err := time.Parse("2006-01-02", c.Query("start_date")); err == nil {
+		req.StartDate = parsed
+	}
+	if parsed, err := time.Parse("2006-01-02", c.Query("end_date")); err == nil {
+		req.EndDate = parsed
+	}
+	return req
+}
+
 // GetCompanyAnalytics handles GET /api/v1/analytics/companies
 func (h *AnalyticsHandler) GetCompanyAnalytics(c *fiber.Ctx) error {
 	req := services.AnalyticsRequest{}
@@ -131,27 +210,20 @@ func (h *AnalyticsHandler) GenerateReport(c *fiber.Ctx) error {
 
 	reportData, filename, err := h.analyticsService.GenerateReport(req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "unsupported report") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+				"code":  "UNSUPPORTED_REPORT",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to generate report",
 			"code":  "INTERNAL_ERROR",
 		})
 	}
 
-	// Set appropriate content type based on format
-	var contentType string
-	switch req.Format {
-	case services.ReportFormatPDF:
-		contentType = "application/pdf"
-	case services.ReportFormatExcel:
-		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
-	case services.ReportFormatCSV:
-		contentType = "text/csv"
-	default:
-		contentType = "application/octet-stream"
-	}
-
-	c.Set("Content-Type", contentType)
-	c.Set("Content-Disposition", "attachment; filename="+filename)
+	c.Set("Content-Type", services.ReportContentType(req.Format))
+	c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
 
 	return c.Send(reportData)
 }
@@ -242,6 +314,910 @@ func (h *AnalyticsHandler) GetCustomAnalytics(c *fiber.Ctx) error {
 	return c.JSON(analytics)
 }
 
+// GetReports handles GET /api/v1/reports
+func (h *AnalyticsHandler) GetReports(c *fiber.Ctx) error {
+	generatedBy, generatorType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	var req services.ReportListRequest
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "10"))
+	req.Status = c.Query("status")
+	req.ReportType = c.Query("report_type")
+	req.OwnedOnly = c.Query("owned_only") == "true"
+	req.Search = c.Query("search")
+
+	response, err := h.reportService.GetReports(req, generatedBy, generatorType)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve reports",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// GetReport handles GET /api/v1/reports/:id
+func (h *AnalyticsHandler) GetReport(c *fiber.Ctx) error {
+	generatedBy, generatorType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	report, err := h.reportService.GetReport(uint(id), generatedBy, generatorType)
+	if err != nil {
+		if err.Error() == "report not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Report not found",
+				"code":  "REPORT_NOT_FOUND",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve report",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// CreateReport handles POST /api/v1/reports
+func (h *AnalyticsHandler) CreateReport(c *fiber.Ctx) error {
+	generatedBy, generatorType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	var req services.CreateReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	report, err := h.reportService.CreateReport(req, generatedBy, generatorType)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unsupported report") || err.Error() == "end date must be after start date" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+				"code":  "UNSUPPORTED_REPORT",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create report",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
+
+// GenerateSavedReport handles POST /api/v1/reports/:id/generate
+func (h *AnalyticsHandler) GenerateSavedReport(c *fiber.Ctx) error {
+	generatedBy, generatorType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	report, err := h.reportService.GenerateReport(uint(id), generatedBy, generatorType)
+	if err != nil {
+		switch {
+		case err.Error() == "report not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Report not found",
+				"code":  "REPORT_NOT_FOUND",
+			})
+		case err.Error() == "report is already generating":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Report is already being generated",
+				"code":  "REPORT_GENERATING",
+			})
+		case strings.HasPrefix(err.Error(), "unsupported report"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+				"code":  "UNSUPPORTED_REPORT",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate report",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// DownloadReport handles GET /api/v1/reports/:id/download
+func (h *AnalyticsHandler) DownloadReport(c *fiber.Ctx) error {
+	generatedBy, generatorType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	filePath, fileName, err := h.reportService.GetReportFile(uint(id), generatedBy, generatorType)
+	if err != nil {
+		switch err.Error() {
+		case "report not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Report not found",
+				"code":  "REPORT_NOT_FOUND",
+			})
+		case "report not generated":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Report has not been generated",
+				"code":  "REPORT_NOT_READY",
+			})
+		case "report expired":
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{
+				"error": "Report file has expired, generate it again",
+				"code":  "REPORT_EXPIRED",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get report file",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.Download(filePath, fileName)
+}
+
+// DeleteReport handles DELETE /api/v1/reports/:id
+func (h *AnalyticsHandler) DeleteReport(c *fiber.Ctx) error {
+	generatedBy, generatorType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	if err := h.reportService.DeleteReport(uint(id), generatedBy, generatorType); err != nil {
+		if err.Error() == "report not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Report not found",
+				"code":  "REPORT_NOT_FOUND",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete report",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Report deleted successfully",
+	})
+}
+
+// GetExportJobs handles GET /api/v1/exports
+func (h *AnalyticsHandler) GetExportJobs(c *fiber.Ctx) error {
+	requestedBy, requesterType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	var req services.ExportJobListRequest
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "10"))
+	req.Status = c.Query("status")
+	req.JobType = c.Query("job_type")
+
+	response, err := h.exportService.GetJobs(req, requestedBy, requesterType)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve export jobs",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// CreateExportJob handles POST /api/v1/exports
+func (h *AnalyticsHandler) CreateExportJob(c *fiber.Ctx) error {
+	requestedBy, requesterType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	// The worker runs outside this request, so the caller's data scope travels with the job
+	scope, ok := middleware.GetDataScope(c)
+	if !ok {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Data scope not resolved",
+			"code":  "ACCESS_DENIED",
+		})
+	}
+
+	var req services.CreateExportJobRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	job, err := h.exportService.CreateJob(req, requestedBy, requesterType, scope)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unsupported export") || err.Error() == "end date must be after start date" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+				"code":  "INVALID_EXPORT",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to queue export job",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetExportJob handles GET /api/v1/exports/:id
+func (h *AnalyticsHandler) GetExportJob(c *fiber.Ctx) error {
+	requestedBy, requesterType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid export job ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	job, err := h.exportService.GetJob(uint(id), requestedBy, requesterType)
+	if err != nil {
+		if err.Error() == "export job not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Export job not found",
+				"code":  "EXPORT_NOT_FOUND",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve export job",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(job)
+}
+
+// DownloadExport handles GET /api/v1/exports/:id/download
+func (h *AnalyticsHandler) DownloadExport(c *fiber.Ctx) error {
+	requestedBy, requesterType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid export job ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	filePath, fileName, err := h.exportService.GetExportFile(uint(id), requestedBy, requesterType)
+	if err != nil {
+		switch err.Error() {
+		case "export job not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Export job not found",
+				"code":  "EXPORT_NOT_FOUND",
+			})
+		case "export not ready":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Export has not finished",
+				"code":  "EXPORT_NOT_READY",
+			})
+		case "export expired":
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{
+				"error": "Export file has expired",
+				"code":  "EXPORT_EXPIRED",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get export file",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.Download(filePath, fileName)
+}
+
+// GetDashboards handles GET /api/v1/dashboards
+func (h *AnalyticsHandler) GetDashboards(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	var req services.DashboardListRequest
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "10"))
+	req.OwnedOnly = c.Query("owned_only") == "true"
+	req.Search = c.Query("search")
+
+	response, err := h.dashboardService.GetDashboards(req, ownerID, ownerType)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve dashboards",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// GetDashboard handles GET /api/v1/dashboards/:id
+func (h *AnalyticsHandler) GetDashboard(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dashboard ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	dashboard, err := h.dashboardService.GetDashboard(uint(id), ownerID, ownerType)
+	if err != nil {
+		return dashboardError(c, err, "Failed to retrieve dashboard")
+	}
+
+	return c.JSON(dashboard)
+}
+
+// CreateDashboard handles POST /api/v1/dashboards
+func (h *AnalyticsHandler) CreateDashboard(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	var req services.CreateDashboardRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	dashboard, err := h.dashboardService.CreateDashboard(req, ownerID, ownerType)
+	if err != nil {
+		return dashboardError(c, err, "Failed to create dashboard")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dashboard)
+}
+
+// UpdateDashboard handles PUT /api/v1/dashboards/:id
+func (h *AnalyticsHandler) UpdateDashboard(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dashboard ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req services.UpdateDashboardRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	dashboard, err := h.dashboardService.UpdateDashboard(uint(id), req, ownerID, ownerType)
+	if err != nil {
+		return dashboardError(c, err, "Failed to update dashboard")
+	}
+
+	return c.JSON(dashboard)
+}
+
+// DeleteDashboard handles DELETE /api/v1/dashboards/:id
+func (h *AnalyticsHandler) DeleteDashboard(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dashboard ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	if err := h.dashboardService.DeleteDashboard(uint(id), ownerID, ownerType); err != nil {
+		return dashboardError(c, err, "Failed to delete dashboard")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Dashboard deleted successfully",
+	})
+}
+
+// AddWidget handles POST /api/v1/dashboards/:id/widgets
+func (h *AnalyticsHandler) AddWidget(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dashboard ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req services.CreateWidgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	widget, err := h.dashboardService.AddWidget(uint(id), req, ownerID, ownerType)
+	if err != nil {
+		return dashboardError(c, err, "Failed to add widget")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(widget)
+}
+
+// UpdateWidget handles PUT /api/v1/dashboards/:id/widgets/:widgetId
+func (h *AnalyticsHandler) UpdateWidget(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dashboard ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	widgetID, err := strconv.ParseUint(c.Params("widgetId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid widget ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req services.UpdateWidgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	widget, err := h.dashboardService.UpdateWidget(uint(id), uint(widgetID), req, ownerID, ownerType)
+	if err != nil {
+		return dashboardError(c, err, "Failed to update widget")
+	}
+
+	return c.JSON(widget)
+}
+
+// DeleteWidget handles DELETE /api/v1/dashboards/:id/widgets/:widgetId
+func (h *AnalyticsHandler) DeleteWidget(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dashboard ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	widgetID, err := strconv.ParseUint(c.Params("widgetId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid widget ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	if err := h.dashboardService.DeleteWidget(uint(id), uint(widgetID), ownerID, ownerType); err != nil {
+		return dashboardError(c, err, "Failed to delete widget")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Widget deleted successfully",
+	})
+}
+
+// GetWidgetData handles GET /api/v1/dashboards/:id/widgets/:widgetId/data
+func (h *AnalyticsHandler) GetWidgetData(c *fiber.Ctx) error {
+	ownerID, ownerType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+			"code":  "UNAUTHORIZED",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dashboard ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	widgetID, err := strconv.ParseUint(c.Params("widgetId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid widget ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	data, err := h.dashboardService.GetWidgetData(uint(id), uint(widgetID), ownerID, ownerType)
+	if err != nil {
+		return dashboardError(c, err, "Failed to resolve widget data")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+// dashboardError maps dashboard service errors onto HTTP responses
+func dashboardError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case err.Error() == "dashboard not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Dashboard not found",
+			"code":  "DASHBOARD_NOT_FOUND",
+		})
+	case err.Error() == "widget not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Widget not found",
+			"code":  "WIDGET_NOT_FOUND",
+		})
+	case err.Error() == "dashboard template not found":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dashboard template not found",
+			"code":  "TEMPLATE_NOT_FOUND",
+		})
+	case strings.HasPrefix(err.Error(), "invalid widget config"), strings.HasPrefix(err.Error(), "unsupported widget type"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  "INVALID_WIDGET_CONFIG",
+		})
+	case strings.HasPrefix(err.Error(), "invalid "), err.Error() == "choose either a template or a template dashboard":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  "INVALID_DASHBOARD",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+		"code":  "INTERNAL_ERROR",
+	})
+}
+
+// GetMetrics handles GET /api/v1/metrics/definitions
+func (h *AnalyticsHandler) GetMetrics(c *fiber.Ctx) error {
+	var req services.MetricListRequest
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "10"))
+	req.MetricType = c.Query("metric_type")
+	req.Search = c.Query("search")
+	req.ActiveOnly = c.Query("active_only") == "true"
+
+	response, err := h.metricService.GetMetrics(req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve metrics",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// CreateMetric handles POST /api/v1/metrics
+func (h *AnalyticsHandler) CreateMetric(c *fiber.Ctx) error {
+	var req services.CreateMetricRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	metric, err := h.metricService.CreateMetric(req)
+	if err != nil {
+		return metricError(c, err, "Failed to create metric")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(metric)
+}
+
+// GetMetricValues handles GET /api/v1/metrics/:name/values
+func (h *AnalyticsHandler) GetMetricValues(c *fiber.Ctx) error {
+	req := services.MetricValuesRequest{
+		Interval: c.Query("interval", "day"),
+		GroupBy:  c.Query("group_by"),
+	}
+
+	for param, target := range map[string]*time.Time{"start_date": &req.StartDate, "end_date": &req.EndDate} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseMetricTime(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Invalid %s, use YYYY-MM-DD or RFC 3339", param),
+				"code":  "INVALID_DATE",
+			})
+		}
+		*target = parsed
+	}
+
+	// Label filters are passed as labels=key:value,key:value
+	if labels := c.Query("labels"); labels != "" {
+		req.Labels = make(map[string]string)
+		for _, pair := range strings.Split(labels, ",") {
+			key, value, ok := strings.Cut(pair, ":")
+			if !ok || strings.TrimSpace(key) == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid labels, use key:value pairs separated by commas",
+					"code":  "INVALID_LABELS",
+				})
+			}
+			req.Labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	response, err := h.metricService.GetValues(c.Params("name"), req)
+	if err != nil {
+		return metricError(c, err, "Failed to retrieve metric values")
+	}
+
+	return c.JSON(response)
+}
+
+// RecordMetricValue handles POST /api/v1/metrics/:name/values
+func (h *AnalyticsHandler) RecordMetricValue(c *fiber.Ctx) error {
+	var req services.RecordMetricValueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	value, err := h.metricService.RecordValue(c.Params("name"), req)
+	if err != nil {
+		return metricError(c, err, "Failed to record metric value")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(value)
+}
+
+// metricError maps metric service errors onto HTTP responses
+func metricError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case err.Error() == "metric not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Metric not found",
+			"code":  "METRIC_NOT_FOUND",
+		})
+	case err.Error() == "metric already exists":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Metric already exists",
+			"code":  "METRIC_EXISTS",
+		})
+	case err.Error() == "metric is inactive":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Metric is inactive",
+			"code":  "METRIC_INACTIVE",
+		})
+	case strings.HasPrefix(err.Error(), "invalid metric"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  "INVALID_METRIC",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+		"code":  "INTERNAL_ERROR",
+	})
+}
+
+// parseMetricTime accepts a date or a full RFC 3339 timestamp
+func parseMetricTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
 // Helper function for min
 func min(a, b int) int {
 	if a < b {
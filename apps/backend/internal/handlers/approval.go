@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"backend-go/internal/middleware"
 	"backend-go/internal/models"
 	"backend-go/internal/services"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -78,9 +80,6 @@ func (h *ApprovalHandler) AdvisorApproval(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get current user from context (set by auth middleware)
-	userID := c.Locals("userID").(uint)
-
 	var request struct {
 		Approved bool   `json:"approved"`
 		Remarks  string `json:"remarks"`
@@ -92,11 +91,9 @@ func (h *ApprovalHandler) AdvisorApproval(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.approvalService.AdvisorApproval(uint(studentEnrollID), userID, request.Approved, request.Remarks)
+	err = h.approvalService.WithContext(c.UserContext()).AdvisorApproval(uint(studentEnrollID), approvalActor(c), request.Approved, request.Remarks)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return respondWorkflowError(c, err, "Failed to record advisor decision")
 	}
 
 	action := "rejected"
@@ -111,7 +108,7 @@ func (h *ApprovalHandler) AdvisorApproval(c *fiber.Ctx) error {
 	})
 }
 
-// CommitteeMemberVote handles committee member voting
+// CommitteeMemberVote handles committee member voting. A member voting again changes their vote.
 // POST /api/v1/approvals/committee-vote/:studentEnrollId
 func (h *ApprovalHandler) CommitteeMemberVote(c *fiber.Ctx) error {
 	studentEnrollID, err := strconv.ParseUint(c.Params("studentEnrollId"), 10, 32)
@@ -121,8 +118,13 @@ func (h *ApprovalHandler) CommitteeMemberVote(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get current user from context (set by auth middleware)
-	userID := c.Locals("userID").(uint)
+	// Committee members vote as instructors; the data scope carries their instructor ID
+	scope, _ := middleware.GetDataScope(c)
+	if scope == nil || scope.Level != services.ScopeInstructor {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only committee members can vote",
+		})
+	}
 
 	var request struct {
 		Vote    string `json:"vote"`
@@ -135,8 +137,18 @@ func (h *ApprovalHandler) CommitteeMemberVote(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.approvalService.CommitteeMemberVote(uint(studentEnrollID), userID, request.Vote, request.Remarks)
+	err = h.approvalService.WithContext(c.UserContext()).CommitteeMemberVote(uint(studentEnrollID), scope.InstructorID, request.Vote, request.Remarks)
 	if err != nil {
+		switch err.Error() {
+		case "instructor is not a committee member for this course":
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case "committee voting has closed":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -149,6 +161,316 @@ func (h *ApprovalHandler) CommitteeMemberVote(c *fiber.Ctx) error {
 	})
 }
 
+// GetVotingPolicy gets the committee voting policy of a course
+// GET /api/v1/approvals/voting-policies/:courseId
+func (h *ApprovalHandler) GetVotingPolicy(c *fiber.Ctx) error {
+	courseID, err := strconv.ParseUint(c.Params("courseId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid course ID",
+		})
+	}
+
+	policy, err := h.approvalService.GetVotingPolicy(uint(courseID))
+	if err != nil {
+		if err.Error() == "course not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Course not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve voting policy",
+		})
+	}
+
+	return c.JSON(policy)
+}
+
+// SetVotingPolicy sets the committee voting policy of a course (admin function)
+// PUT /api/v1/approvals/voting-policies/:courseId
+func (h *ApprovalHandler) SetVotingPolicy(c *fiber.Ctx) error {
+	courseID, err := strconv.ParseUint(c.Params("courseId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid course ID",
+		})
+	}
+
+	// Get current user from context (set by auth middleware)
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+
+	var request services.CommitteeVotingPolicyRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	policy, err := h.approvalService.SetVotingPolicy(uint(courseID), request, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "course not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Course not found",
+			})
+		case strings.HasPrefix(err.Error(), "invalid voting policy"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save voting policy",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Voting policy updated successfully",
+		"policy":  policy,
+	})
+}
+
+// ListWorkflows lists approval workflow definitions
+// GET /api/v1/approvals/workflows?include_inactive=true
+func (h *ApprovalHandler) ListWorkflows(c *fiber.Ctx) error {
+	workflows, err := h.approvalService.ListWorkflows(c.QueryBool("include_inactive", false))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve approval workflows",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": workflows,
+	})
+}
+
+// GetWorkflow gets an approval workflow definition
+// GET /api/v1/approvals/workflows/:id
+func (h *ApprovalHandler) GetWorkflow(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid workflow ID",
+		})
+	}
+
+	workflow, err := h.approvalService.GetWorkflow(uint(id))
+	if err != nil {
+		return respondWorkflowError(c, err, "Failed to retrieve approval workflow")
+	}
+
+	return c.JSON(workflow)
+}
+
+// CreateWorkflow creates an approval workflow for a course, a program or as the default (admin function)
+// POST /api/v1/approvals/workflows
+func (h *ApprovalHandler) CreateWorkflow(c *fiber.Ctx) error {
+	// Get current user from context (set by auth middleware)
+	userID, _ := c.Locals("userID").(uint)
+
+	var request services.ApprovalWorkflowRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	workflow, err := h.approvalService.CreateWorkflow(request, userID)
+	if err != nil {
+		return respondWorkflowError(c, err, "Failed to create approval workflow")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(workflow)
+}
+
+// UpdateWorkflow replaces an approval workflow definition (admin function)
+// PUT /api/v1/approvals/workflows/:id
+func (h *ApprovalHandler) UpdateWorkflow(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid workflow ID",
+		})
+	}
+
+	var request services.ApprovalWorkflowRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	workflow, err := h.approvalService.UpdateWorkflow(uint(id), request)
+	if err != nil {
+		return respondWorkflowError(c, err, "Failed to update approval workflow")
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  "Approval workflow updated successfully",
+		"workflow": workflow,
+	})
+}
+
+// DeactivateWorkflow stops an approval workflow from applying to new approval records (admin function)
+// DELETE /api/v1/approvals/workflows/:id
+func (h *ApprovalHandler) DeactivateWorkflow(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid workflow ID",
+		})
+	}
+
+	if err := h.approvalService.DeactivateWorkflow(uint(id)); err != nil {
+		return respondWorkflowError(c, err, "Failed to deactivate approval workflow")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Approval workflow deactivated successfully",
+	})
+}
+
+// GetEnrollmentWorkflow gets the workflow an enrollment follows and the actions available from its status
+// GET /api/v1/approvals/workflow/:studentEnrollId
+func (h *ApprovalHandler) GetEnrollmentWorkflow(c *fiber.Ctx) error {
+	studentEnrollID, err := strconv.ParseUint(c.Params("studentEnrollId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid student enrollment ID",
+		})
+	}
+
+	workflow, err := h.approvalService.GetEnrollmentWorkflow(uint(studentEnrollID))
+	if err != nil {
+		return respondWorkflowError(c, err, "Failed to retrieve enrollment workflow")
+	}
+
+	return c.JSON(workflow)
+}
+
+// TransitionApproval takes a workflow action on an enrollment's approval record
+// POST /api/v1/approvals/transition/:studentEnrollId
+func (h *ApprovalHandler) TransitionApproval(c *fiber.Ctx) error {
+	studentEnrollID, err := strconv.ParseUint(c.Params("studentEnrollId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid student enrollment ID",
+		})
+	}
+
+	var request struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	approval, err := h.approvalService.WithContext(c.UserContext()).TransitionApproval(uint(studentEnrollID), request.Action, approvalActor(c), request.Reason)
+	if err != nil {
+		return respondWorkflowError(c, err, "Failed to update approval status")
+	}
+
+	return c.JSON(fiber.Map{
+		"message":           "Approval status updated successfully",
+		"student_enroll_id": studentEnrollID,
+		"action":            request.Action,
+		"new_status":        approval.Status,
+	})
+}
+
+// GetStatusTransitions gets the status transitions of an enrollment's approval record
+// GET /api/v1/approvals/history/:studentEnrollId
+func (h *ApprovalHandler) GetStatusTransitions(c *fiber.Ctx) error {
+	studentEnrollID, err := strconv.ParseUint(c.Params("studentEnrollId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid student enrollment ID",
+		})
+	}
+
+	transitions, err := h.approvalService.GetStatusTransitions(uint(studentEnrollID))
+	if err != nil {
+		if err.Error() == "approval record not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Approval record not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve approval history",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": transitions,
+	})
+}
+
+// BackfillStatusTransitions copies existing status histories into the approval history table (admin function)
+// POST /api/v1/approvals/history/backfill
+func (h *ApprovalHandler) BackfillStatusTransitions(c *fiber.Ctx) error {
+	report, err := h.approvalService.BackfillStatusTransitions()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to backfill approval history",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Approval history backfilled",
+		"report":  report,
+	})
+}
+
+// approvalActor identifies the caller taking a workflow action. Instructors are identified by the
+// data scope; the roles come from the access resolved by the route's permission check.
+func approvalActor(c *fiber.Ctx) services.ApprovalActor {
+	var actor services.ApprovalActor
+	actor.UserID, _ = c.Locals("userID").(uint)
+	if scope, ok := middleware.GetDataScope(c); ok && scope != nil && scope.Level == services.ScopeInstructor {
+		actor.InstructorID = scope.InstructorID
+	}
+	if access, ok := middleware.GetAccess(c); ok {
+		actor.Roles = access.Roles
+	}
+	return actor
+}
+
+// respondWorkflowError writes the response for a failed approval workflow operation
+func respondWorkflowError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err.Error() == "insufficient role for this transition",
+		err.Error() == "user is not the assigned advisor for this enrollment",
+		err.Error() == "this transition is decided by committee voting":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid approval workflow"),
+		strings.HasPrefix(err.Error(), "action "),
+		err.Error() == "enrollment is not in a state that allows advisor approval",
+		err.Error() == "remarks are required for this transition",
+		err.Error() == "student has no training placement":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
 // UpdateApprovalStatus updates approval status (admin function)
 // PUT /api/v1/approvals/status/:studentEnrollId
 func (h *ApprovalHandler) UpdateApprovalStatus(c *fiber.Ctx) error {
@@ -160,7 +482,12 @@ func (h *ApprovalHandler) UpdateApprovalStatus(c *fiber.Ctx) error {
 	}
 
 	// Get current user from context (set by auth middleware)
-	userID := c.Locals("userID").(uint)
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
 
 	var request struct {
 		Status models.InternshipApprovalStatus `json:"status"`
@@ -173,7 +500,7 @@ func (h *ApprovalHandler) UpdateApprovalStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.approvalService.UpdateApprovalStatus(uint(studentEnrollID), request.Status, userID, request.Reason)
+	err = h.approvalService.WithContext(c.UserContext()).UpdateApprovalStatus(uint(studentEnrollID), request.Status, userID, request.Reason)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
@@ -201,8 +528,13 @@ func (h *ApprovalHandler) CreateApprovalRecord(c *fiber.Ctx) error {
 		})
 	}
 
-	approval, err := h.approvalService.CreateApprovalRecord(request.StudentEnrollID, request.AdvisorID)
+	approval, err := h.approvalService.WithContext(c.UserContext()).CreateApprovalRecord(request.StudentEnrollID, request.AdvisorID)
 	if err != nil {
+		if err.Error() == "student enrollment not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Student enrollment not found",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create approval record",
 		})
@@ -225,7 +557,8 @@ func (h *ApprovalHandler) GetApprovalsByStatus(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset := (page - 1) * limit
 
-	approvals, err := h.approvalService.GetApprovalsByStatus(models.InternshipApprovalStatus(status), limit, offset)
+	scope, _ := middleware.GetDataScope(c)
+	approvals, err := h.approvalService.GetApprovalsByStatus(scope, models.InternshipApprovalStatus(status), limit, offset)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve approvals",
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"backend-go/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AttendanceHandler handles internship attendance, time sheet and required-hours HTTP requests
+type AttendanceHandler struct {
+	attendanceService *services.AttendanceService
+	validator         *validator.Validate
+}
+
+// NewAttendanceHandler creates a new attendance handler instance
+func NewAttendanceHandler(attendanceService *services.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{
+		attendanceService: attendanceService,
+		validator:         validator.New(),
+	}
+}
+
+// attendanceErrors maps attendance service error messages to HTTP statuses and error codes
+var attendanceErrors = map[string]struct {
+	status int
+	code   string
+}{
+	"student training not found":               {fiber.StatusNotFound, "STUDENT_TRAINING_NOT_FOUND"},
+	"attendance entry not found":               {fiber.StatusNotFound, "ATTENDANCE_ENTRY_NOT_FOUND"},
+	"timesheet not found":                      {fiber.StatusNotFound, "TIMESHEET_NOT_FOUND"},
+	"attendance requirement not found":         {fiber.StatusNotFound, "ATTENDANCE_REQUIREMENT_NOT_FOUND"},
+	"course not found":                         {fiber.StatusNotFound, "COURSE_NOT_FOUND"},
+	"student not found":                        {fiber.StatusNotFound, "STUDENT_NOT_FOUND"},
+	"company not found":                        {fiber.StatusNotFound, "COMPANY_NOT_FOUND"},
+	"timesheet is locked":                      {fiber.StatusConflict, "TIMESHEET_LOCKED"},
+	"timesheet has already been submitted":     {fiber.StatusConflict, "TIMESHEET_ALREADY_SUBMITTED"},
+	"timesheet has no entries":                 {fiber.StatusBadRequest, "TIMESHEET_EMPTY"},
+	"timesheet is not awaiting review":         {fiber.StatusConflict, "TIMESHEET_NOT_SUBMITTED"},
+	"student training has no supervisor email": {fiber.StatusUnprocessableEntity, "SUPERVISOR_EMAIL_MISSING"},
+	"supervisor email is not configured":       {fiber.StatusServiceUnavailable, "EMAIL_NOT_CONFIGURED"},
+}
+
+// respondAttendanceError maps an attendance service error through attendanceErrors, falling back to a 500
+func respondAttendanceError(c *fiber.Ctx, err error, fallback string) error {
+	if mapped, ok := attendanceErrors[err.Error()]; ok {
+		return c.Status(mapped.status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    mapped.code,
+		})
+	}
+	if strings.HasPrefix(err.Error(), "invalid attendance") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    "VALIDATION_ERROR",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"success": false,
+		"error":   fallback,
+		"code":    "INTERNAL_ERROR",
+	})
+}
+
+// attendanceParam parses a numeric route parameter, writing the error response when it is invalid
+func attendanceParam(c *fiber.Ctx, name, label string) (uint, bool, error) {
+	id, err := strconv.ParseUint(c.Params(name), 10, 32)
+	if err != nil {
+		return 0, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid " + label + " ID",
+			"code":    "INVALID_ID",
+		})
+	}
+	return uint(id), true, nil
+}
+
+// parseAttendanceBody parses and validates a request body, writing the error response when it is invalid
+func (h *AttendanceHandler) parseAttendanceBody(c *fiber.Ctx, req interface{}) (bool, error) {
+	if err := c.BodyParser(req); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST",
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+	return true, nil
+}
+
+// RecordEntry handles POST /api/v1/attendance/trainings/:trainingId/entries
+func (h *AttendanceHandler) RecordEntry(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "trainingId", "student training")
+	if !ok {
+		return err
+	}
+
+	var req services.RecordAttendanceRequest
+	if ok, err := h.parseAttendanceBody(c, &req); !ok {
+		return err
+	}
+	if userID, userType, ok := requesterFromCtx(c); ok {
+		req.RecordedBy = &userID
+		req.RecorderType = userType
+	}
+
+	entry, err := h.attendanceService.RecordEntry(trainingID, req)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to record attendance")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Attendance recorded successfully",
+		"data":    entry,
+	})
+}
+
+// DeleteEntry handles DELETE /api/v1/attendance/trainings/:trainingId/entries/:entryId
+func (h *AttendanceHandler) DeleteEntry(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "trainingId", "student training")
+	if !ok {
+		return err
+	}
+	entryID, ok, err := attendanceParam(c, "entryId", "attendance entry")
+	if !ok {
+		return err
+	}
+
+	if err := h.attendanceService.DeleteEntry(trainingID, entryID); err != nil {
+		return respondAttendanceError(c, err, "Failed to delete attendance entry")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Attendance entry deleted successfully",
+	})
+}
+
+// GetTimesheets handles GET /api/v1/attendance/trainings/:trainingId/timesheets
+func (h *AttendanceHandler) GetTimesheets(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "trainingId", "student training")
+	if !ok {
+		return err
+	}
+
+	timesheets, err := h.attendanceService.GetTimesheets(trainingID)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to retrieve timesheets")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    timesheets,
+	})
+}
+
+// SubmitTimesheet handles POST /api/v1/attendance/trainings/:trainingId/timesheets/:timesheetId/submit.
+// The review link is emailed to the company supervisor and is not part of the response.
+func (h *AttendanceHandler) SubmitTimesheet(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "trainingId", "student training")
+	if !ok {
+		return err
+	}
+	timesheetID, ok, err := attendanceParam(c, "timesheetId", "timesheet")
+	if !ok {
+		return err
+	}
+
+	timesheet, err := h.attendanceService.SubmitTimesheet(trainingID, timesheetID)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to submit timesheet")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Timesheet sent to the supervisor for review",
+		"data":    timesheet,
+	})
+}
+
+// ReviewTimesheet handles PUT /api/v1/attendance/trainings/:trainingId/timesheets/:timesheetId/review,
+// recording the supervisor's decision on their behalf
+func (h *AttendanceHandler) ReviewTimesheet(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "trainingId", "student training")
+	if !ok {
+		return err
+	}
+	timesheetID, ok, err := attendanceParam(c, "timesheetId", "timesheet")
+	if !ok {
+		return err
+	}
+
+	var req services.ReviewTimesheetRequest
+	if ok, err := h.parseAttendanceBody(c, &req); !ok {
+		return err
+	}
+
+	timesheet, err := h.attendanceService.ReviewTimesheet(trainingID, timesheetID, req)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to review timesheet")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Timesheet reviewed successfully",
+		"data":    timesheet,
+	})
+}
+
+// GetSupervisorReview handles GET /api/v1/timesheets/review/:token (no authentication, the token is the credential)
+func (h *AttendanceHandler) GetSupervisorReview(c *fiber.Ctx) error {
+	review, err := h.attendanceService.GetTimesheetForReview(c.Params("token"))
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to retrieve timesheet")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    review,
+	})
+}
+
+// SubmitSupervisorReview handles POST /api/v1/timesheets/review/:token (no authentication, the token is the credential)
+func (h *AttendanceHandler) SubmitSupervisorReview(c *fiber.Ctx) error {
+	var req services.ReviewTimesheetRequest
+	if ok, err := h.parseAttendanceBody(c, &req); !ok {
+		return err
+	}
+
+	timesheet, err := h.attendanceService.ReviewTimesheetByToken(c.Params("token"), req)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to review timesheet")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Timesheet reviewed successfully",
+		"data":    timesheet,
+	})
+}
+
+// GetTrainingSummary handles GET /api/v1/attendance/trainings/:trainingId/summary
+func (h *AttendanceHandler) GetTrainingSummary(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "trainingId", "student training")
+	if !ok {
+		return err
+	}
+
+	summary, err := h.attendanceService.GetTrainingSummary(trainingID)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to retrieve attendance summary")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    summary,
+	})
+}
+
+// GetRequirement handles GET /api/v1/attendance/requirements/:courseId
+func (h *AttendanceHandler) GetRequirement(c *fiber.Ctx) error {
+	courseID, ok, err := attendanceParam(c, "courseId", "course")
+	if !ok {
+		return err
+	}
+
+	requirement, err := h.attendanceService.GetRequirement(courseID)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to retrieve attendance requirement")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    requirement,
+	})
+}
+
+// SetRequirement handles PUT /api/v1/attendance/requirements/:courseId
+func (h *AttendanceHandler) SetRequirement(c *fiber.Ctx) error {
+	courseID, ok, err := attendanceParam(c, "courseId", "course")
+	if !ok {
+		return err
+	}
+
+	var req services.AttendanceRequirementRequest
+	if ok, err := h.parseAttendanceBody(c, &req); !ok {
+		return err
+	}
+
+	requirement, err := h.attendanceService.SetRequirement(courseID, req)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to save attendance requirement")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Attendance requirement saved successfully",
+		"data":    requirement,
+	})
+}
+
+// GetStudentReport handles GET /api/v1/attendance/reports/students/:studentId
+func (h *AttendanceHandler) GetStudentReport(c *fiber.Ctx) error {
+	studentID, ok, err := attendanceParam(c, "studentId", "student")
+	if !ok {
+		return err
+	}
+
+	report, err := h.attendanceService.GetStudentReport(studentID)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to build student attendance report")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetCompanyReport handles GET /api/v1/attendance/reports/companies/:companyId
+func (h *AttendanceHandler) GetCompanyReport(c *fiber.Ctx) error {
+	companyID, ok, err := attendanceParam(c, "companyId", "company")
+	if !ok {
+		return err
+	}
+
+	report, err := h.attendanceService.GetCompanyReport(companyID)
+	if err != nil {
+		return respondAttendanceError(c, err, "Failed to build company attendance report")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"backend-go/internal/models"
+	"backend-go/internal/services"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditHandler handles audit trail HTTP requests
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new audit handler instance
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// auditErrors maps audit service error messages to HTTP statuses and error codes
+var auditErrors = map[string]struct {
+	status int
+	code   string
+}{
+	"audit log not found":               {fiber.StatusNotFound, "AUDIT_LOG_NOT_FOUND"},
+	"invalid entity type":               {fiber.StatusBadRequest, "INVALID_ENTITY_TYPE"},
+	"end date must be after start date": {fiber.StatusBadRequest, "INVALID_DATE_RANGE"},
+}
+
+// respondAuditError maps an audit service error through auditErrors, falling back to a 500
+func respondAuditError(c *fiber.Ctx, err error, fallback string) error {
+	if mapped, ok := auditErrors[err.Error()]; ok {
+		return c.Status(mapped.status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    mapped.code,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"success": false,
+		"error":   fallback,
+		"code":    "INTERNAL_ERROR",
+	})
+}
+
+// GetAuditLogs handles GET /api/v1/audit
+func (h *AuditHandler) GetAuditLogs(c *fiber.Ctx) error {
+	var req services.AuditLogListRequest
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "20"))
+	req.EntityType = models.EntityType(c.Query("entity_type"))
+	req.Action = models.ActivityAction(c.Query("action"))
+	req.ActorID = c.Query("actor_id")
+	req.UserType = c.Query("user_type")
+
+	if entityID := c.Query("entity_id"); entityID != "" {
+		id, err := strconv.ParseUint(entityID, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid entity ID",
+				"code":    "INVALID_ID",
+			})
+		}
+		req.EntityID = uint(id)
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &req.From}, {"to", &req.To}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseScheduleQueryTime(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid " + bound.name + " date, expected YYYY-MM-DD or RFC3339",
+				"code":    "INVALID_DATE",
+			})
+		}
+		*bound.target = &parsed
+	}
+
+	response, err := h.auditService.GetAuditLogs(req)
+	if err != nil {
+		return respondAuditError(c, err, "Failed to retrieve audit logs")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// GetEntityHistory handles GET /api/v1/audit/entities/:entityType/:entityId
+func (h *AuditHandler) GetEntityHistory(c *fiber.Ctx) error {
+	entityID, ok, err := attendanceParam(c, "entityId", "entity")
+	if !ok {
+		return err
+	}
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+
+	response, err := h.auditService.GetEntityHistory(models.EntityType(c.Params("entityType")), entityID, page, limit)
+	if err != nil {
+		return respondAuditError(c, err, "Failed to retrieve entity history")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// GetAuditLog handles GET /api/v1/audit/:id
+func (h *AuditHandler) GetAuditLog(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "id", "audit log")
+	if !ok {
+		return err
+	}
+
+	log, err := h.auditService.GetAuditLog(id)
+	if err != nil {
+		return respondAuditError(c, err, "Failed to retrieve audit log")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    log,
+	})
+}
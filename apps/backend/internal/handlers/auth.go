@@ -73,6 +73,64 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	})
 }
 
+// AdminLogin handles super admin login requests. Admins with 2FA enabled receive
+// a challenge token to complete at POST /api/v1/login/2fa.
+// POST /api/v1/admin/login
+func (h *AuthHandler) AdminLogin(c *fiber.Ctx) error {
+	var req services.AdminLoginRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+			"message": "ข้อมูลที่ส่งมาไม่ถูกต้อง",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"message": "ข้อมูลไม่ครบถ้วนหรือไม่ถูกต้อง",
+			"details": err.Error(),
+		})
+	}
+
+	// Authenticate admin
+	response, err := h.authService.AdminLogin(req)
+	if err != nil {
+		if err.Error() == "invalid credentials" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid email or password",
+				"code":    "INVALID_CREDENTIALS",
+				"message": "อีเมลหรือรหัสผ่านไม่ถูกต้อง",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Internal server error",
+			"code":    "INTERNAL_ERROR",
+			"message": "เกิดข้อผิดพลาดภายในระบบ",
+		})
+	}
+
+	message := "Login successful"
+	if response.TwoFactorRequired {
+		message = "Two-factor verification required"
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    response,
+	})
+}
+
 // Register handles user registration requests
 // POST /api/v1/register
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
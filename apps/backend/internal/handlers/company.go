@@ -92,7 +92,7 @@ func (h *CompanyHandler) CreateCompany(c *fiber.Ctx) error {
 		})
 	}
 
-	company, err := h.companyService.CreateCompany(req)
+	company, err := h.companyService.WithContext(c.UserContext()).CreateCompany(req)
 	if err != nil {
 		if err.Error() == "company with this register number already exists" {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
@@ -135,7 +135,7 @@ func (h *CompanyHandler) UpdateCompany(c *fiber.Ctx) error {
 		})
 	}
 
-	company, err := h.companyService.UpdateCompany(uint(id), req)
+	company, err := h.companyService.WithContext(c.UserContext()).UpdateCompany(uint(id), req)
 	if err != nil {
 		switch err.Error() {
 		case "company not found":
@@ -169,7 +169,7 @@ func (h *CompanyHandler) DeleteCompany(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.companyService.DeleteCompany(uint(id))
+	err = h.companyService.WithContext(c.UserContext()).DeleteCompany(uint(id))
 	if err != nil {
 		switch err.Error() {
 		case "company not found":
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
 	"backend-go/internal/services"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -22,8 +26,44 @@ func NewDocumentHandler(documentService *services.DocumentService) *DocumentHand
 	}
 }
 
+// documentActor identifies the caller for document ownership and visibility checks.
+// The data scope comes from the ScopeData middleware; admins are unrestricted.
+func documentActor(c *fiber.Ctx) (services.DocumentActor, bool, error) {
+	userID, accountType, ok := requesterFromCtx(c)
+	if !ok {
+		return services.DocumentActor{}, false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+			"code":    "UNAUTHORIZED",
+		})
+	}
+	scope, ok := middleware.GetDataScope(c)
+	if !ok {
+		return services.DocumentActor{}, false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "Data scope not resolved",
+			"code":    "ACCESS_DENIED",
+		})
+	}
+	return services.DocumentActor{UserID: userID, AccountType: accountType, Scope: scope}, true, nil
+}
+
+// respondDocumentAccessDenied writes the response for a change to a document uploaded by someone else
+func respondDocumentAccessDenied(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"success": false,
+		"error":   "Only the uploader or an admin may change this document",
+		"code":    "DOCUMENT_ACCESS_DENIED",
+	})
+}
+
 // GetDocuments handles GET /api/v1/documents
 func (h *DocumentHandler) GetDocuments(c *fiber.Ctx) error {
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
 	var req services.DocumentListRequest
 
 	// Parse query parameters
@@ -34,6 +74,8 @@ func (h *DocumentHandler) GetDocuments(c *fiber.Ctx) error {
 	req.Status = c.Query("status", "")
 	req.SortBy = c.Query("sort_by", "")
 	req.SortDesc = c.Query("sort_desc", "") == "true"
+	req.Overdue = c.Query("overdue", "") == "true"
+	req.LatestOnly = c.Query("latest_only", "") == "true"
 
 	if studentTrainingID := c.Query("student_training_id"); studentTrainingID != "" {
 		if id, err := strconv.ParseUint(studentTrainingID, 10, 32); err == nil {
@@ -49,7 +91,7 @@ func (h *DocumentHandler) GetDocuments(c *fiber.Ctx) error {
 		}
 	}
 
-	response, err := h.documentService.GetDocuments(req)
+	response, err := h.documentService.GetDocuments(req, actor)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
@@ -75,7 +117,12 @@ func (h *DocumentHandler) GetDocument(c *fiber.Ctx) error {
 		})
 	}
 
-	document, err := h.documentService.GetDocumentByID(uint(id))
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
+	document, err := h.documentService.GetDocumentByID(uint(id), actor)
 	if err != nil {
 		if err.Error() == "document not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -122,9 +169,19 @@ func (h *DocumentHandler) UploadDocument(c *fiber.Ctx) error {
 		}
 	}
 
-	// Get user ID from context
-	userID := c.Locals("userID").(uint)
-	req.UploadedByID = userID
+	req.SaveAsDraft = c.FormValue("save_as_draft") == "true"
+
+	if dueDate := c.FormValue("due_date"); dueDate != "" {
+		parsed, err := parseDocumentDueDate(dueDate)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid due date, expected YYYY-MM-DD or RFC3339",
+				"code":    "INVALID_DUE_DATE",
+			})
+		}
+		req.DueDate = &parsed
+	}
 
 	if err := h.validator.Struct(req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -135,8 +192,20 @@ func (h *DocumentHandler) UploadDocument(c *fiber.Ctx) error {
 		})
 	}
 
-	document, err := h.documentService.UploadDocument(req, file)
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
+	document, err := h.documentService.WithContext(c.UserContext()).UploadDocument(req, file, actor)
 	if err != nil {
+		if err.Error() == "student training not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Student training not found",
+				"code":    "STUDENT_TRAINING_NOT_FOUND",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to upload document",
@@ -180,7 +249,12 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 		})
 	}
 
-	document, err := h.documentService.UpdateDocument(uint(id), req)
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
+	document, err := h.documentService.WithContext(c.UserContext()).UpdateDocument(uint(id), req, actor)
 	if err != nil {
 		if err.Error() == "document not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -189,6 +263,16 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 				"code":    "DOCUMENT_NOT_FOUND",
 			})
 		}
+		if err.Error() == "document access denied" {
+			return respondDocumentAccessDenied(c)
+		}
+		if strings.HasPrefix(err.Error(), "invalid status transition") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+				"code":    "INVALID_STATUS_TRANSITION",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to update document",
@@ -214,7 +298,12 @@ func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.documentService.DeleteDocument(uint(id))
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
+	err = h.documentService.WithContext(c.UserContext()).DeleteDocument(uint(id), actor)
 	if err != nil {
 		if err.Error() == "document not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -223,6 +312,9 @@ func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
 				"code":    "DOCUMENT_NOT_FOUND",
 			})
 		}
+		if err.Error() == "document access denied" {
+			return respondDocumentAccessDenied(c)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to delete document",
@@ -256,11 +348,21 @@ func (h *DocumentHandler) ApproveDocument(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get user ID from context
-	userID := c.Locals("userID").(uint)
-	req.ApproverID = userID
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
 
-	err = h.documentService.ApproveDocument(uint(id), req)
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	err = h.documentService.WithContext(c.UserContext()).ApproveDocument(uint(id), req, actor)
 	if err != nil {
 		if err.Error() == "document not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -269,6 +371,13 @@ func (h *DocumentHandler) ApproveDocument(c *fiber.Ctx) error {
 				"code":    "DOCUMENT_NOT_FOUND",
 			})
 		}
+		if strings.HasPrefix(err.Error(), "invalid status transition") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+				"code":    "INVALID_STATUS_TRANSITION",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to approve document",
@@ -276,9 +385,17 @@ func (h *DocumentHandler) ApproveDocument(c *fiber.Ctx) error {
 		})
 	}
 
+	message := "Document approved successfully"
+	switch req.Status {
+	case models.DocStatusRejected:
+		message = "Document rejected"
+	case models.DocStatusRevision:
+		message = "Document returned for revision"
+	}
+
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Document approved successfully",
+		"message": message,
 	})
 }
 
@@ -302,13 +419,30 @@ func (h *DocumentHandler) AddComment(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get user ID from context
-	userID := c.Locals("userID").(uint)
-	req.UserID = userID
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
 	req.DocumentID = uint(id)
 
-	comment, err := h.documentService.AddComment(req)
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	comment, err := h.documentService.AddComment(req, actor)
 	if err != nil {
+		if err.Error() == "document not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Document not found",
+				"code":    "DOCUMENT_NOT_FOUND",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to add comment",
@@ -323,6 +457,44 @@ func (h *DocumentHandler) AddComment(c *fiber.Ctx) error {
 	})
 }
 
+// GetDocumentVersions handles GET /api/v1/documents/:id/versions
+func (h *DocumentHandler) GetDocumentVersions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid document ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
+	versions, err := h.documentService.GetDocumentVersions(uint(id), actor)
+	if err != nil {
+		if err.Error() == "document not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Document not found",
+				"code":    "DOCUMENT_NOT_FOUND",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve document versions",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    versions,
+	})
+}
+
 // DownloadDocument handles GET /api/v1/documents/:id/download
 func (h *DocumentHandler) DownloadDocument(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
@@ -334,7 +506,12 @@ func (h *DocumentHandler) DownloadDocument(c *fiber.Ctx) error {
 		})
 	}
 
-	filePath, fileName, err := h.documentService.GetDocumentFile(uint(id))
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
+	filePath, fileName, err := h.documentService.GetDocumentFile(uint(id), actor)
 	if err != nil {
 		if err.Error() == "document not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -355,7 +532,12 @@ func (h *DocumentHandler) DownloadDocument(c *fiber.Ctx) error {
 
 // GetDocumentStats handles GET /api/v1/documents/stats
 func (h *DocumentHandler) GetDocumentStats(c *fiber.Ctx) error {
-	stats, err := h.documentService.GetDocumentStats()
+	actor, ok, err := documentActor(c)
+	if !ok {
+		return err
+	}
+
+	stats, err := h.documentService.GetDocumentStats(actor)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
@@ -368,4 +550,17 @@ func (h *DocumentHandler) GetDocumentStats(c *fiber.Ctx) error {
 		"success": true,
 		"data":    stats,
 	})
-}
\ No newline at end of file
+}
+
+// parseDocumentDueDate accepts either a plain date or an RFC3339 timestamp
+func parseDocumentDueDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// A plain date is due at the end of that day
+	return t.Add(24*time.Hour - time.Second), nil
+}
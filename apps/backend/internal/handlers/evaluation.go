@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"backend-go/internal/middleware"
 	"backend-go/internal/models"
 	"backend-go/internal/services"
 	"strconv"
@@ -188,7 +189,8 @@ func (h *EvaluationHandler) GetEvaluationsByType(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset := (page - 1) * limit
 
-	evaluations, err := h.evaluationService.GetEvaluationsByType(models.EvaluationType(evalType), status, limit, offset)
+	scope, _ := middleware.GetDataScope(c)
+	evaluations, err := h.evaluationService.GetEvaluationsByType(scope, models.EvaluationType(evalType), status, limit, offset)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve evaluations",
@@ -205,7 +207,8 @@ func (h *EvaluationHandler) GetEvaluationsByType(c *fiber.Ctx) error {
 // GetOverdueEvaluations gets all overdue evaluations
 // GET /api/v1/evaluations/overdue
 func (h *EvaluationHandler) GetOverdueEvaluations(c *fiber.Ctx) error {
-	evaluations, err := h.evaluationService.GetOverdueEvaluations()
+	scope, _ := middleware.GetDataScope(c)
+	evaluations, err := h.evaluationService.GetOverdueEvaluations(scope)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve overdue evaluations",
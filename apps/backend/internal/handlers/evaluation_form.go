@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
+	"backend-go/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EvaluationFormHandler handles evaluation form authoring and submission HTTP requests
+type EvaluationFormHandler struct {
+	formService   *services.EvaluationFormService
+	legacyService *services.LegacyEvaluationService
+	validator     *validator.Validate
+}
+
+// NewEvaluationFormHandler creates a new evaluation form handler instance
+func NewEvaluationFormHandler(formService *services.EvaluationFormService, legacyService *services.LegacyEvaluationService) *EvaluationFormHandler {
+	return &EvaluationFormHandler{
+		formService:   formService,
+		legacyService: legacyService,
+		validator:     validator.New(),
+	}
+}
+
+// evaluationFormErrors maps evaluation form service error messages to HTTP statuses and error codes
+var evaluationFormErrors = map[string]struct {
+	status int
+	code   string
+}{
+	"evaluation form not found":                      {fiber.StatusNotFound, "EVALUATION_FORM_NOT_FOUND"},
+	"evaluation submission not found":                {fiber.StatusNotFound, "EVALUATION_SUBMISSION_NOT_FOUND"},
+	"student training not found":                     {fiber.StatusNotFound, "STUDENT_TRAINING_NOT_FOUND"},
+	"faculty not found":                              {fiber.StatusNotFound, "FACULTY_NOT_FOUND"},
+	"no active evaluation form":                      {fiber.StatusNotFound, "NO_ACTIVE_EVALUATION_FORM"},
+	"evaluation form is inactive":                    {fiber.StatusConflict, "EVALUATION_FORM_INACTIVE"},
+	"evaluation has already been submitted":          {fiber.StatusConflict, "EVALUATION_ALREADY_SUBMITTED"},
+	"evaluation submission is not awaiting review":   {fiber.StatusConflict, "EVALUATION_NOT_SUBMITTED"},
+	"evaluation submission access denied":            {fiber.StatusForbidden, "EVALUATION_ACCESS_DENIED"},
+	"evaluation form type not allowed for evaluator": {fiber.StatusForbidden, "EVALUATION_FORM_TYPE_FORBIDDEN"},
+}
+
+// respondEvaluationFormError maps an evaluation form service error through evaluationFormErrors, falling back to a 500
+func respondEvaluationFormError(c *fiber.Ctx, err error, fallback string) error {
+	if mapped, ok := evaluationFormErrors[err.Error()]; ok {
+		return c.Status(mapped.status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    mapped.code,
+		})
+	}
+	if strings.HasPrefix(err.Error(), "invalid evaluation") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    "VALIDATION_ERROR",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"success": false,
+		"error":   fallback,
+		"code":    "INTERNAL_ERROR",
+	})
+}
+
+// parseEvaluationFormBody parses and validates a request body, writing the error response when it is invalid
+func (h *EvaluationFormHandler) parseEvaluationFormBody(c *fiber.Ctx, req interface{}) (bool, error) {
+	if err := c.BodyParser(req); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST",
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+	return true, nil
+}
+
+// ListForms handles GET /api/v1/evaluation-forms?type=company_student&faculty_id=1&include_inactive=true
+func (h *EvaluationFormHandler) ListForms(c *fiber.Ctx) error {
+	filter := services.EvaluationFormFilter{
+		FormType:        models.EvaluationFormType(c.Query("type")),
+		IncludeInactive: c.QueryBool("include_inactive"),
+	}
+	if facultyID := c.Query("faculty_id"); facultyID != "" {
+		id, err := strconv.ParseUint(facultyID, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid faculty ID",
+				"code":    "INVALID_ID",
+			})
+		}
+		faculty := uint(id)
+		filter.FacultyID = &faculty
+	}
+
+	forms, err := h.formService.ListForms(filter)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to retrieve evaluation forms")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    forms,
+	})
+}
+
+// GetForm handles GET /api/v1/evaluation-forms/:id
+func (h *EvaluationFormHandler) GetForm(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "id", "evaluation form")
+	if !ok {
+		return err
+	}
+
+	form, err := h.formService.GetForm(id)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to retrieve evaluation form")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    form,
+	})
+}
+
+// GetFormVersions handles GET /api/v1/evaluation-forms/:id/versions
+func (h *EvaluationFormHandler) GetFormVersions(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "id", "evaluation form")
+	if !ok {
+		return err
+	}
+
+	versions, err := h.formService.GetFormVersions(id)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to retrieve evaluation form versions")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    versions,
+	})
+}
+
+// CreateForm handles POST /api/v1/evaluation-forms
+func (h *EvaluationFormHandler) CreateForm(c *fiber.Ctx) error {
+	var req services.EvaluationFormRequest
+	if ok, err := h.parseEvaluationFormBody(c, &req); !ok {
+		return err
+	}
+	userID, _, _ := requesterFromCtx(c)
+
+	form, err := h.formService.CreateForm(req, userID)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to create evaluation form")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Evaluation form created successfully",
+		"data":    form,
+	})
+}
+
+// UpdateForm handles PUT /api/v1/evaluation-forms/:id. Forms that already have submissions get a
+// new version, returned with status 201.
+func (h *EvaluationFormHandler) UpdateForm(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "id", "evaluation form")
+	if !ok {
+		return err
+	}
+
+	var req services.UpdateEvaluationFormRequest
+	if ok, err := h.parseEvaluationFormBody(c, &req); !ok {
+		return err
+	}
+	userID, _, _ := requesterFromCtx(c)
+
+	form, versioned, err := h.formService.UpdateForm(id, req, userID)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to update evaluation form")
+	}
+
+	if versioned {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"success": true,
+			"message": "Evaluation form has submissions, a new version was created",
+			"data":    form,
+		})
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Evaluation form updated successfully",
+		"data":    form,
+	})
+}
+
+// DeactivateForm handles DELETE /api/v1/evaluation-forms/:id
+func (h *EvaluationFormHandler) DeactivateForm(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "id", "evaluation form")
+	if !ok {
+		return err
+	}
+
+	if err := h.formService.DeactivateForm(id); err != nil {
+		return respondEvaluationFormError(c, err, "Failed to deactivate evaluation form")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Evaluation form deactivated successfully",
+	})
+}
+
+// ResolveForm handles GET /api/v1/evaluation-forms/trainings/:studentTrainingId/form?type=company_student,
+// returning the form the student's faculty uses for the evaluation type
+func (h *EvaluationFormHandler) ResolveForm(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "studentTrainingId", "student training")
+	if !ok {
+		return err
+	}
+	formType := c.Query("type")
+	if formType == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Type parameter is required",
+			"code":    "VALIDATION_ERROR",
+		})
+	}
+
+	form, err := h.formService.ResolveForm(models.EvaluationFormType(formType), trainingID)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to resolve evaluation form")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    form,
+	})
+}
+
+// GetSubmissions handles GET /api/v1/evaluation-forms/trainings/:studentTrainingId/submissions
+func (h *EvaluationFormHandler) GetSubmissions(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "studentTrainingId", "student training")
+	if !ok {
+		return err
+	}
+
+	submissions, err := h.formService.GetSubmissions(trainingID)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to retrieve evaluation submissions")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    submissions,
+	})
+}
+
+// SaveSubmission handles POST /api/v1/evaluation-forms/trainings/:studentTrainingId/submissions
+func (h *EvaluationFormHandler) SaveSubmission(c *fiber.Ctx) error {
+	trainingID, ok, err := attendanceParam(c, "studentTrainingId", "student training")
+	if !ok {
+		return err
+	}
+
+	var req services.SaveEvaluationRequest
+	if ok, err := h.parseEvaluationFormBody(c, &req); !ok {
+		return err
+	}
+	userID, userType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+			"code":    "UNAUTHORIZED",
+		})
+	}
+
+	submission, err := h.formService.WithContext(c.UserContext()).SaveSubmission(trainingID, req, userID, services.AccountType(userType))
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to save evaluation")
+	}
+
+	message := "Evaluation draft saved successfully"
+	if req.Submit {
+		message = "Evaluation submitted successfully"
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    submission,
+	})
+}
+
+// GetSubmission handles GET /api/v1/evaluation-forms/submissions/:submissionId
+func (h *EvaluationFormHandler) GetSubmission(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "submissionId", "evaluation submission")
+	if !ok {
+		return err
+	}
+
+	scope, _ := middleware.GetDataScope(c)
+	submission, err := h.formService.GetSubmission(scope, id)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to retrieve evaluation submission")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    submission,
+	})
+}
+
+// SubmitSubmission handles POST /api/v1/evaluation-forms/submissions/:submissionId/submit
+func (h *EvaluationFormHandler) SubmitSubmission(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "submissionId", "evaluation submission")
+	if !ok {
+		return err
+	}
+	userID, userType, ok := requesterFromCtx(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+			"code":    "UNAUTHORIZED",
+		})
+	}
+
+	scope, _ := middleware.GetDataScope(c)
+	submission, err := h.formService.WithContext(c.UserContext()).SubmitSubmission(scope, id, userID, services.AccountType(userType))
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to submit evaluation")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Evaluation submitted successfully",
+		"data":    submission,
+	})
+}
+
+// ReviewSubmission handles POST /api/v1/evaluation-forms/submissions/:submissionId/review
+func (h *EvaluationFormHandler) ReviewSubmission(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "submissionId", "evaluation submission")
+	if !ok {
+		return err
+	}
+	userID, _, _ := requesterFromCtx(c)
+
+	scope, _ := middleware.GetDataScope(c)
+	submission, err := h.formService.WithContext(c.UserContext()).ReviewSubmission(scope, id, userID)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to review evaluation")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Evaluation reviewed successfully",
+		"data":    submission,
+	})
+}
+
+// GetQuestionAnalytics handles GET /api/v1/evaluation-forms/analytics/questions?type=visitor_student&form_id=1
+func (h *EvaluationFormHandler) GetQuestionAnalytics(c *fiber.Ctx) error {
+	filter := services.QuestionAnalyticsFilter{
+		FormType: models.EvaluationFormType(c.Query("type")),
+	}
+	if formID := c.Query("form_id"); formID != "" {
+		id, err := strconv.ParseUint(formID, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid evaluation form ID",
+				"code":    "INVALID_ID",
+			})
+		}
+		form := uint(id)
+		filter.FormID = &form
+	}
+
+	scope, _ := middleware.GetDataScope(c)
+	stats, err := h.formService.GetQuestionAnalytics(scope, filter)
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to retrieve question analytics")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// MigrateLegacy handles POST /api/v1/evaluation-forms/legacy/migrate, mirroring every legacy visitor
+// and student evaluation record as a form submission. Records already mirrored are refreshed.
+func (h *EvaluationFormHandler) MigrateLegacy(c *fiber.Ctx) error {
+	reports, err := h.legacyService.MigrateAll()
+	if err != nil {
+		return respondEvaluationFormError(c, err, "Failed to migrate legacy evaluations")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Legacy evaluations migrated",
+		"data":    reports,
+	})
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"backend-go/internal/middleware"
+	"backend-go/internal/services"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GradingHandler handles grading scheme and section grade sheet HTTP requests
+type GradingHandler struct {
+	gradingService *services.GradingService
+	validator      *validator.Validate
+}
+
+// NewGradingHandler creates a new grading handler instance
+func NewGradingHandler(gradingService *services.GradingService) *GradingHandler {
+	return &GradingHandler{
+		gradingService: gradingService,
+		validator:      validator.New(),
+	}
+}
+
+// gradingErrors maps grading service error messages to HTTP statuses and error codes
+var gradingErrors = map[string]struct {
+	status int
+	code   string
+}{
+	"grading scheme not found":                {fiber.StatusNotFound, "GRADING_SCHEME_NOT_FOUND"},
+	"course not found":                        {fiber.StatusNotFound, "COURSE_NOT_FOUND"},
+	"course section not found":                {fiber.StatusNotFound, "COURSE_SECTION_NOT_FOUND"},
+	"student enrollment not found":            {fiber.StatusNotFound, "ENROLLMENT_NOT_FOUND"},
+	"course section access denied":            {fiber.StatusForbidden, "SECTION_ACCESS_DENIED"},
+	"grades are locked":                       {fiber.StatusConflict, "GRADES_LOCKED"},
+	"grades are not locked":                   {fiber.StatusConflict, "GRADES_NOT_LOCKED"},
+	"grades must be locked before publishing": {fiber.StatusConflict, "GRADES_NOT_LOCKED"},
+	"grades are already published":            {fiber.StatusConflict, "GRADES_PUBLISHED"},
+}
+
+// respondGradingError maps a grading service error through gradingErrors, falling back to a 500
+func respondGradingError(c *fiber.Ctx, err error, fallback string) error {
+	if mapped, ok := gradingErrors[err.Error()]; ok {
+		return c.Status(mapped.status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    mapped.code,
+		})
+	}
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid grad"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    "VALIDATION_ERROR",
+		})
+	case strings.HasPrefix(err.Error(), "grades are incomplete"):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    "GRADES_INCOMPLETE",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"success": false,
+		"error":   fallback,
+		"code":    "INTERNAL_ERROR",
+	})
+}
+
+// parseGradingBody parses and validates a request body, writing the error response when it is invalid
+func (h *GradingHandler) parseGradingBody(c *fiber.Ctx, req interface{}) (bool, error) {
+	if err := c.BodyParser(req); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST",
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+	return true, nil
+}
+
+// GetScheme handles GET /api/v1/grading/schemes/:courseId
+func (h *GradingHandler) GetScheme(c *fiber.Ctx) error {
+	courseID, ok, err := attendanceParam(c, "courseId", "course")
+	if !ok {
+		return err
+	}
+
+	scheme, err := h.gradingService.GetScheme(courseID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to retrieve grading scheme")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    scheme,
+	})
+}
+
+// SetScheme handles PUT /api/v1/grading/schemes/:courseId
+func (h *GradingHandler) SetScheme(c *fiber.Ctx) error {
+	courseID, ok, err := attendanceParam(c, "courseId", "course")
+	if !ok {
+		return err
+	}
+
+	var req services.GradingSchemeRequest
+	if ok, err := h.parseGradingBody(c, &req); !ok {
+		return err
+	}
+	userID, _, _ := requesterFromCtx(c)
+
+	scheme, err := h.gradingService.SetScheme(courseID, req, userID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to save grading scheme")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Grading scheme saved successfully",
+		"data":    scheme,
+	})
+}
+
+// GetSectionGrades handles GET /api/v1/grading/sections/:sectionId, previewing computed grades
+// while the section's grades are a draft
+func (h *GradingHandler) GetSectionGrades(c *fiber.Ctx) error {
+	sectionID, ok, err := attendanceParam(c, "sectionId", "course section")
+	if !ok {
+		return err
+	}
+
+	scope, _ := middleware.GetDataScope(c)
+	sheet, err := h.gradingService.GetSectionGrades(scope, sectionID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to retrieve section grades")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    sheet,
+	})
+}
+
+// OverrideGrade handles PUT /api/v1/grading/sections/:sectionId/overrides/:enrollId
+func (h *GradingHandler) OverrideGrade(c *fiber.Ctx) error {
+	sectionID, ok, err := attendanceParam(c, "sectionId", "course section")
+	if !ok {
+		return err
+	}
+	enrollID, ok, err := attendanceParam(c, "enrollId", "student enrollment")
+	if !ok {
+		return err
+	}
+
+	var req services.GradeOverrideRequest
+	if ok, err := h.parseGradingBody(c, &req); !ok {
+		return err
+	}
+	userID, _, _ := requesterFromCtx(c)
+
+	scope, _ := middleware.GetDataScope(c)
+	sheet, err := h.gradingService.OverrideGrade(scope, sectionID, enrollID, req, userID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to override grade")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Grade overridden successfully",
+		"data":    sheet,
+	})
+}
+
+// ClearOverride handles DELETE /api/v1/grading/sections/:sectionId/overrides/:enrollId
+func (h *GradingHandler) ClearOverride(c *fiber.Ctx) error {
+	sectionID, ok, err := attendanceParam(c, "sectionId", "course section")
+	if !ok {
+		return err
+	}
+	enrollID, ok, err := attendanceParam(c, "enrollId", "student enrollment")
+	if !ok {
+		return err
+	}
+
+	scope, _ := middleware.GetDataScope(c)
+	sheet, err := h.gradingService.ClearOverride(scope, sectionID, enrollID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to clear grade override")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Grade override cleared successfully",
+		"data":    sheet,
+	})
+}
+
+// LockSection handles POST /api/v1/grading/sections/:sectionId/lock
+func (h *GradingHandler) LockSection(c *fiber.Ctx) error {
+	sectionID, ok, err := attendanceParam(c, "sectionId", "course section")
+	if !ok {
+		return err
+	}
+	userID, _, _ := requesterFromCtx(c)
+
+	scope, _ := middleware.GetDataScope(c)
+	sheet, err := h.gradingService.LockSection(scope, sectionID, userID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to lock grades")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Grades locked successfully",
+		"data":    sheet,
+	})
+}
+
+// UnlockSection handles POST /api/v1/grading/sections/:sectionId/unlock
+func (h *GradingHandler) UnlockSection(c *fiber.Ctx) error {
+	sectionID, ok, err := attendanceParam(c, "sectionId", "course section")
+	if !ok {
+		return err
+	}
+
+	scope, _ := middleware.GetDataScope(c)
+	sheet, err := h.gradingService.UnlockSection(scope, sectionID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to unlock grades")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Grades unlocked successfully",
+		"data":    sheet,
+	})
+}
+
+// PublishSection handles POST /api/v1/grading/sections/:sectionId/publish
+func (h *GradingHandler) PublishSection(c *fiber.Ctx) error {
+	sectionID, ok, err := attendanceParam(c, "sectionId", "course section")
+	if !ok {
+		return err
+	}
+	userID, _, _ := requesterFromCtx(c)
+
+	scope, _ := middleware.GetDataScope(c)
+	sheet, err := h.gradingService.PublishSection(scope, sectionID, userID)
+	if err != nil {
+		return respondGradingError(c, err, "Failed to publish grades")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Grades published successfully",
+		"data":    sheet,
+	})
+}
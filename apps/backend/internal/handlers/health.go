@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"backend-go/internal/config"
+	"backend-go/internal/database"
+	"backend-go/internal/models"
 	"backend-go/internal/services"
+	"bytes"
 	"fmt"
 	"os"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -377,9 +382,28 @@ func (h *HealthHandler) checkExternalServices() map[string]interface{} {
 	}
 }
 
-// Metrics handles application metrics endpoint
+// Metrics handles application metrics endpoint. It returns JSON unless the Prometheus
+// text format is asked for with ?format=prometheus or an Accept header of text/plain.
 // GET /api/v1/metrics
 func (h *HealthHandler) Metrics(c *fiber.Ctx) error {
+	if metricsFormat(c, "json") == "prometheus" {
+		return h.prometheusMetrics(c)
+	}
+	return h.jsonMetrics(c)
+}
+
+// PrometheusMetrics handles the Prometheus scrape endpoint. It returns the Prometheus text
+// format unless JSON is asked for with ?format=json.
+// GET /metrics
+func (h *HealthHandler) PrometheusMetrics(c *fiber.Ctx) error {
+	if metricsFormat(c, "prometheus") == "json" {
+		return h.jsonMetrics(c)
+	}
+	return h.prometheusMetrics(c)
+}
+
+// jsonMetrics writes the metrics as a JSON document
+func (h *HealthHandler) jsonMetrics(c *fiber.Ctx) error {
 	requestStart := time.Now()
 	
 	// Get runtime metrics
@@ -424,6 +448,11 @@ func (h *HealthHandler) Metrics(c *fiber.Ctx) error {
 			"cpus":        runtime.NumCPU(),
 		},
 		"database": dbStats,
+		"http":     httpMetricsSummary(),
+	}
+
+	if business, err := h.businessMetrics(); err == nil {
+		metrics["business"] = business
 	}
 	
 	// Set cache headers
@@ -480,4 +509,238 @@ func (h *HealthHandler) Live(c *fiber.Ctx) error {
 		"timestamp": time.Now().UTC().Format(time.RFC3339),
 		"uptime":    time.Since(startTime).Seconds(),
 	})
-}
\ No newline at end of file
+}
+// businessMetrics holds operational counts exposed as gauges
+type businessMetrics struct {
+	ApprovalsByStatus   map[string]int64 `json:"approvals_by_status"`
+	PendingApprovals    int64            `json:"pending_approvals"`
+	EvaluationsByStatus map[string]int64 `json:"evaluations_by_status"`
+	OverdueEvaluations  int64            `json:"overdue_evaluations"`
+	QueuedExportJobs    int64            `json:"queued_export_jobs"`
+}
+
+// finalApprovalStatuses are the approval states that need no further action
+var finalApprovalStatuses = []models.InternshipApprovalStatus{
+	models.StatusApprove,
+	models.StatusDenied,
+	models.StatusDocCancel,
+}
+
+// dbPoolMetrics maps database.GetConnectionStats keys onto Prometheus metric names
+var dbPoolMetrics = []struct {
+	key, name, help, metricType string
+}{
+	{"max_open_connections", "db_pool_max_open_connections", "Maximum number of open connections to the database.", "gauge"},
+	{"open_connections", "db_pool_open_connections", "Number of established connections, in use and idle.", "gauge"},
+	{"in_use", "db_pool_in_use_connections", "Number of connections currently in use.", "gauge"},
+	{"idle", "db_pool_idle_connections", "Number of idle connections.", "gauge"},
+	{"wait_count", "db_pool_wait_count_total", "Total number of connections waited for.", "counter"},
+	{"wait_duration", "db_pool_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", "counter"},
+	{"max_idle_closed", "db_pool_max_idle_closed_total", "Total connections closed due to the idle connection limit.", "counter"},
+	{"max_idle_time_closed", "db_pool_max_idle_time_closed_total", "Total connections closed due to the idle time limit.", "counter"},
+	{"max_lifetime_closed", "db_pool_max_lifetime_closed_total", "Total connections closed due to the connection lifetime limit.", "counter"},
+}
+
+// prometheusMetrics writes the metrics in the Prometheus text exposition format
+func (h *HealthHandler) prometheusMetrics(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	w := services.NewPrometheusWriter(&buf)
+
+	services.GetHTTPMetrics().WritePrometheus(w)
+	h.writeDatabaseMetrics(w)
+	writeRuntimeMetrics(w)
+	h.writeBusinessMetrics(w)
+
+	if err := w.Flush(); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("failed to write metrics")
+	}
+
+	c.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	c.Set(fiber.HeaderContentType, services.PrometheusContentType)
+	return c.Send(buf.Bytes())
+}
+
+// writeDatabaseMetrics writes the connection pool statistics
+func (h *HealthHandler) writeDatabaseMetrics(w *services.PrometheusWriter) {
+	stats, err := database.GetConnectionStats(h.db)
+	w.Gauge("db_up", "Whether connection pool statistics could be read.", boolGauge(err == nil))
+	if err != nil {
+		return
+	}
+
+	for _, metric := range dbPoolMetrics {
+		var value float64
+		switch v := stats[metric.key].(type) {
+		case int:
+			value = float64(v)
+		case int64:
+			value = float64(v)
+		case string: // durations are reported as strings
+			duration, err := time.ParseDuration(v)
+			if err != nil {
+				continue
+			}
+			value = duration.Seconds()
+		default:
+			continue
+		}
+
+		w.Family(metric.name, metric.help, metric.metricType)
+		w.Sample(metric.name, value)
+	}
+}
+
+// writeRuntimeMetrics writes Go runtime and process statistics
+func writeRuntimeMetrics(w *services.PrometheusWriter) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	w.Family("go_info", "Information about the Go environment.", "gauge")
+	w.Sample("go_info", 1, "version", runtime.Version())
+	w.Gauge("go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()))
+	w.Gauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(m.Alloc))
+	w.Counter("go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", float64(m.TotalAlloc))
+	w.Gauge("go_memstats_sys_bytes", "Number of bytes obtained from the system.", float64(m.Sys))
+	w.Gauge("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", float64(m.HeapInuse))
+	w.Gauge("go_memstats_heap_objects", "Number of allocated objects.", float64(m.HeapObjects))
+	w.Counter("go_gc_cycles_total", "Number of completed GC cycles.", float64(m.NumGC))
+	w.Counter("go_gc_pause_seconds_total", "Total time spent in GC stop-the-world pauses.", float64(m.PauseTotalNs)/float64(time.Second))
+	w.Gauge("process_start_time_seconds", "Start time of the process since unix epoch in seconds.", float64(startTime.Unix()))
+	w.Gauge("process_uptime_seconds", "Time since the process started.", time.Since(startTime).Seconds())
+}
+
+// writeBusinessMetrics writes the operational gauges
+func (h *HealthHandler) writeBusinessMetrics(w *services.PrometheusWriter) {
+	business, err := h.businessMetrics()
+	w.Gauge("business_metrics_up", "Whether the business gauges could be read from the database.", boolGauge(err == nil))
+	if err != nil {
+		h.logger.Warn("Failed to collect business metrics", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.Family("internship_approvals", "Internship approvals by status.", "gauge")
+	for _, status := range sortedKeys(business.ApprovalsByStatus) {
+		w.Sample("internship_approvals", float64(business.ApprovalsByStatus[status]), "status", status)
+	}
+	w.Gauge("internship_approvals_pending", "Internship approvals still waiting for a decision.", float64(business.PendingApprovals))
+
+	w.Family("evaluations", "Evaluations by status.", "gauge")
+	for _, status := range sortedKeys(business.EvaluationsByStatus) {
+		w.Sample("evaluations", float64(business.EvaluationsByStatus[status]), "status", status)
+	}
+	w.Gauge("evaluations_overdue", "Evaluations past their due date that are not completed.", float64(business.OverdueEvaluations))
+
+	w.Gauge("export_jobs_queued", "Export jobs waiting for a worker.", float64(business.QueuedExportJobs))
+}
+
+// businessMetrics counts approvals, evaluations and export jobs
+func (h *HealthHandler) businessMetrics() (*businessMetrics, error) {
+	metrics := &businessMetrics{
+		ApprovalsByStatus:   make(map[string]int64),
+		EvaluationsByStatus: make(map[string]int64),
+	}
+
+	type statusCount struct {
+		Status string
+		Count  int64
+	}
+
+	var approvals []statusCount
+	if err := h.db.Model(&models.InternshipApproval{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&approvals).Error; err != nil {
+		return nil, err
+	}
+	for _, approval := range approvals {
+		metrics.ApprovalsByStatus[approval.Status] = approval.Count
+	}
+
+	if err := h.db.Model(&models.InternshipApproval{}).
+		Where("status NOT IN ?", finalApprovalStatuses).
+		Count(&metrics.PendingApprovals).Error; err != nil {
+		return nil, err
+	}
+
+	var evaluations []statusCount
+	if err := h.db.Model(&models.EvaluationStatusTracker{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&evaluations).Error; err != nil {
+		return nil, err
+	}
+	for _, evaluation := range evaluations {
+		metrics.EvaluationsByStatus[evaluation.Status] = evaluation.Count
+	}
+
+	if err := h.db.Model(&models.EvaluationStatusTracker{}).
+		Where("due_date < ? AND status != ?", time.Now(), models.EvalStatusCompleted).
+		Count(&metrics.OverdueEvaluations).Error; err != nil {
+		return nil, err
+	}
+
+	if err := h.db.Model(&models.ExportJob{}).
+		Where("status = ?", models.ReportStatusPending).
+		Count(&metrics.QueuedExportJobs).Error; err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// httpMetricsSummary summarizes request counts and average latency per route for the JSON format
+func httpMetricsSummary() []map[string]interface{} {
+	snapshot := services.GetHTTPMetrics().Snapshot()
+	summary := make([]map[string]interface{}, 0, len(snapshot))
+	for _, series := range snapshot {
+		var averageMs float64
+		if series.Count > 0 {
+			averageMs = series.LatencySum / float64(series.Count) * 1000
+		}
+		summary = append(summary, map[string]interface{}{
+			"method":         series.Method,
+			"route":          series.Route,
+			"status":         series.Status,
+			"count":          series.Count,
+			"avg_latency_ms": averageMs,
+		})
+	}
+	return summary
+}
+
+// metricsFormat picks the metrics format from ?format, then the Accept header, then the fallback
+func metricsFormat(c *fiber.Ctx, fallback string) string {
+	switch c.Query("format") {
+	case "json":
+		return "json"
+	case "prometheus":
+		return "prometheus"
+	}
+
+	accept := c.Get(fiber.HeaderAccept)
+	if strings.Contains(accept, "application/json") {
+		return "json"
+	}
+	if strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text") {
+		return "prometheus"
+	}
+	return fallback
+}
+
+func boolGauge(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"backend-go/internal/middleware"
+	"backend-go/internal/services"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxICalImportSize is the largest .ics file accepted for import
+const maxICalImportSize = 5 * 1024 * 1024
+
+// ICalHandler handles iCalendar feed, subscription and import HTTP requests
+type ICalHandler struct {
+	icalService *services.ICalService
+}
+
+// NewICalHandler creates a new iCalendar handler instance
+func NewICalHandler(icalService *services.ICalService) *ICalHandler {
+	return &ICalHandler{
+		icalService: icalService,
+	}
+}
+
+// ExportCalendar handles GET /api/v1/calendars/:id/ics
+func (h *ICalHandler) ExportCalendar(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid calendar ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	feed, err := h.icalService.CalendarFeed(uint(id), actor)
+	if err != nil {
+		return respondScheduleError(c, err, "Failed to export calendar")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="calendar-%d.ics"`, id))
+	return c.Send(feed)
+}
+
+// ImportCalendar handles POST /api/v1/calendars/:id/import
+func (h *ICalHandler) ImportCalendar(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid calendar ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "No file uploaded",
+			"code":    "NO_FILE",
+		})
+	}
+
+	if strings.ToLower(filepath.Ext(file.Filename)) != ".ics" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Only .ics files are allowed",
+			"code":    "INVALID_FILE_TYPE",
+		})
+	}
+
+	if file.Size > maxICalImportSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "File size exceeds 5MB limit",
+			"code":    "FILE_TOO_LARGE",
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to read uploaded file",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+	defer src.Close()
+
+	result, err := h.icalService.ImportICS(uint(id), actor, src)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid iCalendar file") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+				"code":    "INVALID_ICALENDAR",
+			})
+		}
+		return respondScheduleError(c, err, "Failed to import calendar")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Calendar imported successfully",
+		"data":    result,
+	})
+}
+
+// GetSubscription handles GET /api/v1/calendars/subscription
+func (h *ICalHandler) GetSubscription(c *fiber.Ctx) error {
+	account, ok := middleware.GetAccount(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+			"code":    "UNAUTHORIZED",
+		})
+	}
+
+	subscription, err := h.icalService.GetSubscription(account)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve calendar subscription",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"url":              h.feedURL(c, subscription.Token),
+			"last_accessed_at": subscription.LastAccessedAt,
+			"created_at":       subscription.CreatedAt,
+		},
+	})
+}
+
+// RotateSubscription handles POST /api/v1/calendars/subscription/rotate
+func (h *ICalHandler) RotateSubscription(c *fiber.Ctx) error {
+	account, ok := middleware.GetAccount(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+			"code":    "UNAUTHORIZED",
+		})
+	}
+
+	subscription, err := h.icalService.RotateSubscription(account)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to rotate calendar subscription",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Calendar subscription URL regenerated successfully",
+		"data": fiber.Map{
+			"url":        h.feedURL(c, subscription.Token),
+			"created_at": subscription.CreatedAt,
+		},
+	})
+}
+
+// GetSubscriptionFeed handles GET /api/v1/calendars/feed/:token (no authentication, the token is the credential)
+func (h *ICalHandler) GetSubscriptionFeed(c *fiber.Ctx) error {
+	token := strings.TrimSuffix(c.Params("token"), ".ics")
+
+	feed, err := h.icalService.SubscriptionFeed(token)
+	if err != nil {
+		return respondScheduleError(c, err, "Failed to build calendar feed")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Send(feed)
+}
+
+// feedURL builds the public subscription URL for a feed token
+func (h *ICalHandler) feedURL(c *fiber.Ctx, token string) string {
+	return c.BaseURL() + "/api/v1/calendars/feed/" + token + ".ics"
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"strconv"
+	"strings"
 
 	"backend-go/internal/services"
 	"github.com/go-playground/validator/v10"
@@ -334,6 +335,11 @@ func (h *InstructorHandler) UpdateCourseAssignment(c *fiber.Ctx) error {
 		})
 	}
 
+	return h.updateCourseAssignment(c, uint(id))
+}
+
+// updateCourseAssignment applies the request body to the given course assignment
+func (h *InstructorHandler) updateCourseAssignment(c *fiber.Ctx, id uint) error {
 	var req services.UpdateCourseAssignmentRequest
 
 	// Parse request body
@@ -353,7 +359,7 @@ func (h *InstructorHandler) UpdateCourseAssignment(c *fiber.Ctx) error {
 		})
 	}
 
-	assignment, err := h.instructorService.UpdateCourseAssignment(uint(id), req)
+	assignment, err := h.instructorService.UpdateCourseAssignment(id, req)
 	if err != nil {
 		if err.Error() == "course assignment not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -384,7 +390,12 @@ func (h *InstructorHandler) RemoveCourseAssignment(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.instructorService.RemoveCourseAssignment(uint(id))
+	return h.removeCourseAssignment(c, uint(id))
+}
+
+// removeCourseAssignment deletes the given course assignment
+func (h *InstructorHandler) removeCourseAssignment(c *fiber.Ctx, id uint) error {
+	err := h.instructorService.RemoveCourseAssignment(id)
 	if err != nil {
 		if err.Error() == "course assignment not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -456,11 +467,17 @@ func (h *InstructorHandler) ManageInstructorGrade(c *fiber.Ctx) error {
 		})
 	}
 
+	return h.manageGrade(c, req, fiber.StatusBadRequest)
+}
+
+// manageGrade records the validated grade request. instructorNotFound is the status reported when the
+// instructor does not exist: a bad request when it came from the body, not found when it came from the path.
+func (h *InstructorHandler) manageGrade(c *fiber.Ctx, req services.InstructorGradeRequest, instructorNotFound int) error {
 	enrollStatus, err := h.instructorService.ManageInstructorGrade(req)
 	if err != nil {
 		switch err.Error() {
 		case "instructor not found":
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			return c.Status(instructorNotFound).JSON(fiber.Map{
 				"error": "Instructor not found",
 				"code":  "INSTRUCTOR_NOT_FOUND",
 			})
@@ -469,6 +486,11 @@ func (h *InstructorHandler) ManageInstructorGrade(c *fiber.Ctx) error {
 				"error": "Student enrollment not found",
 				"code":  "ENROLLMENT_NOT_FOUND",
 			})
+		case "course section access denied":
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Instructor is not assigned to the enrollment's course section",
+				"code":  "SECTION_ACCESS_DENIED",
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to manage instructor grade",
@@ -505,11 +527,17 @@ func (h *InstructorHandler) RecordTrainingAttendance(c *fiber.Ctx) error {
 		})
 	}
 
+	return h.recordAttendance(c, req, fiber.StatusBadRequest)
+}
+
+// recordAttendance records the validated attendance request, reporting a missing instructor with the
+// given status as in manageGrade
+func (h *InstructorHandler) recordAttendance(c *fiber.Ctx, req services.TrainingAttendanceRequest, instructorNotFound int) error {
 	err := h.instructorService.RecordTrainingAttendance(req)
 	if err != nil {
 		switch err.Error() {
 		case "instructor not found":
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			return c.Status(instructorNotFound).JSON(fiber.Map{
 				"error": "Instructor not found",
 				"code":  "INSTRUCTOR_NOT_FOUND",
 			})
@@ -518,7 +546,19 @@ func (h *InstructorHandler) RecordTrainingAttendance(c *fiber.Ctx) error {
 				"error": "Student training not found",
 				"code":  "TRAINING_NOT_FOUND",
 			})
+		case "timesheet is locked":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "The timesheet for this week has been submitted and can no longer change",
+				"code":  "TIMESHEET_LOCKED",
+			})
 		default:
+			if strings.HasPrefix(err.Error(), "invalid attendance entry") {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   "Invalid attendance entry",
+					"code":    "INVALID_ATTENDANCE_ENTRY",
+					"details": err.Error(),
+				})
+			}
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to record training attendance",
 				"code":  "RECORD_ATTENDANCE_ERROR",
@@ -546,4 +586,200 @@ func (h *InstructorHandler) GetInstructorStats(c *fiber.Ctx) error {
 		"message": "Instructor statistics retrieved successfully",
 		"data":    stats,
 	})
-}
\ No newline at end of file
+}
+
+// AssignCourseToInstructor handles course assignment requests for the instructor in the path
+// POST /api/v1/instructors/:id/course-assignments
+func (h *InstructorHandler) AssignCourseToInstructor(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid instructor ID",
+			"code":  "INVALID_INSTRUCTOR_ID",
+		})
+	}
+
+	var req services.CourseAssignmentRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+	req.InstructorID = uint(id)
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	assignment, err := h.instructorService.AssignInstructorToCourse(req)
+	if err != nil {
+		switch err.Error() {
+		case "instructor not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Instructor not found",
+				"code":  "INSTRUCTOR_NOT_FOUND",
+			})
+		case "course section not found":
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Course section not found",
+				"code":  "COURSE_SECTION_NOT_FOUND",
+			})
+		case "instructor is already assigned to this course section":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Instructor is already assigned to this course section",
+				"code":  "ALREADY_ASSIGNED",
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to assign instructor to course",
+				"code":  "ASSIGN_COURSE_ERROR",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Instructor assigned to course successfully",
+		"data":    assignment,
+	})
+}
+
+// UpdateInstructorCourseAssignment handles updates to one of the path instructor's course assignments
+// PUT /api/v1/instructors/:id/course-assignments/:assignmentId
+func (h *InstructorHandler) UpdateInstructorCourseAssignment(c *fiber.Ctx) error {
+	assignmentID, ok, err := h.ownedCourseAssignment(c)
+	if !ok {
+		return err
+	}
+	return h.updateCourseAssignment(c, assignmentID)
+}
+
+// RemoveInstructorCourseAssignment handles removal of one of the path instructor's course assignments
+// DELETE /api/v1/instructors/:id/course-assignments/:assignmentId
+func (h *InstructorHandler) RemoveInstructorCourseAssignment(c *fiber.Ctx) error {
+	assignmentID, ok, err := h.ownedCourseAssignment(c)
+	if !ok {
+		return err
+	}
+	return h.removeCourseAssignment(c, assignmentID)
+}
+
+// ownedCourseAssignment returns the :assignmentId course assignment ID after checking that it belongs
+// to the :id instructor. When ok is false the error response has already been written.
+func (h *InstructorHandler) ownedCourseAssignment(c *fiber.Ctx) (uint, bool, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid instructor ID",
+			"code":  "INVALID_INSTRUCTOR_ID",
+		})
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Params("assignmentId"), 10, 32)
+	if err != nil {
+		return 0, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid assignment ID",
+			"code":  "INVALID_ASSIGNMENT_ID",
+		})
+	}
+
+	assignments, err := h.instructorService.GetInstructorCourseAssignments(uint(id))
+	if err != nil {
+		if err.Error() == "instructor not found" {
+			return 0, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Instructor not found",
+				"code":  "INSTRUCTOR_NOT_FOUND",
+			})
+		}
+		return 0, false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch instructor course assignments",
+			"code":  "FETCH_ASSIGNMENTS_ERROR",
+		})
+	}
+
+	for _, assignment := range assignments {
+		if assignment.ID == uint(assignmentID) {
+			return assignment.ID, true, nil
+		}
+	}
+
+	return 0, false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"error": "Course assignment not found",
+		"code":  "ASSIGNMENT_NOT_FOUND",
+	})
+}
+
+// ManageGrade handles grade management requests for the instructor in the path
+// POST /api/v1/instructors/:id/grades
+func (h *InstructorHandler) ManageGrade(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid instructor ID",
+			"code":  "INVALID_INSTRUCTOR_ID",
+		})
+	}
+
+	var req services.InstructorGradeRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+	req.InstructorID = uint(id)
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	return h.manageGrade(c, req, fiber.StatusNotFound)
+}
+
+// RecordAttendance handles training attendance requests for the instructor in the path
+// POST /api/v1/instructors/:id/attendance
+func (h *InstructorHandler) RecordAttendance(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid instructor ID",
+			"code":  "INVALID_INSTRUCTOR_ID",
+		})
+	}
+
+	var req services.TrainingAttendanceRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST_BODY",
+		})
+	}
+	req.InstructorID = uint(id)
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	return h.recordAttendance(c, req, fiber.StatusNotFound)
+}
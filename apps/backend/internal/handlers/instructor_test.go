@@ -474,4 +474,46 @@ func TestInstructorHandler_GetInstructorStats(t *testing.T) {
 			mockService.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestInstructorHandler_NestedCourseAssignments(t *testing.T) {
+	t.Run("assignment takes the instructor from the path", func(t *testing.T) {
+		mockService := new(MockInstructorService)
+		handler := NewInstructorHandler(mockService)
+		app := fiber.New()
+		app.Post("/instructors/:id/course-assignments", handler.AssignCourseToInstructor)
+
+		mockService.On("AssignInstructorToCourse", services.CourseAssignmentRequest{InstructorID: 7, CourseSectionID: 3, Role: "assistant"}).
+			Return(nil, errors.New("instructor not found"))
+
+		body, _ := json.Marshal(map[string]interface{}{"instructor_id": 1, "course_section_id": 3, "role": "assistant"})
+		req := httptest.NewRequest("POST", "/instructors/7/course-assignments", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("assignments of other instructors are not found", func(t *testing.T) {
+		mockService := new(MockInstructorService)
+		handler := NewInstructorHandler(mockService)
+		app := fiber.New()
+		app.Delete("/instructors/:id/course-assignments/:assignmentId", handler.RemoveInstructorCourseAssignment)
+
+		mockService.On("GetInstructorCourseAssignments", uint(7)).
+			Return([]models.CourseInstructor{{ID: 1, InstructorID: 7}}, nil)
+		mockService.On("RemoveCourseAssignment", uint(1)).Return(nil)
+
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/instructors/7/course-assignments/2", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+		mockService.AssertNotCalled(t, "RemoveCourseAssignment", uint(2))
+
+		resp, err = app.Test(httptest.NewRequest("DELETE", "/instructors/7/course-assignments/1", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+}
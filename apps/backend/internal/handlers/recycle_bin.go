@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"backend-go/internal/models"
+	"backend-go/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RecycleBinHandler handles recycle bin HTTP requests
+type RecycleBinHandler struct {
+	recycleBinService *services.RecycleBinService
+}
+
+// NewRecycleBinHandler creates a new recycle bin handler instance
+func NewRecycleBinHandler(recycleBinService *services.RecycleBinService) *RecycleBinHandler {
+	return &RecycleBinHandler{
+		recycleBinService: recycleBinService,
+	}
+}
+
+// recycleBinErrors maps recycle bin service error messages to HTTP statuses and error codes
+var recycleBinErrors = map[string]struct {
+	status int
+	code   string
+}{
+	"deleted record not found": {fiber.StatusNotFound, "DELETED_RECORD_NOT_FOUND"},
+	"invalid entity type":      {fiber.StatusBadRequest, "INVALID_ENTITY_TYPE"},
+	"the training's student is deleted, restore the student first": {fiber.StatusConflict, "PARENT_RECORD_DELETED"},
+}
+
+// respondRecycleBinError maps a recycle bin service error through recycleBinErrors, falling back to a 500
+func respondRecycleBinError(c *fiber.Ctx, err error, fallback string) error {
+	if mapped, ok := recycleBinErrors[err.Error()]; ok {
+		return c.Status(mapped.status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    mapped.code,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"success": false,
+		"error":   fallback,
+		"code":    "INTERNAL_ERROR",
+	})
+}
+
+// ListDeleted handles GET /api/v1/recycle-bin
+func (h *RecycleBinHandler) ListDeleted(c *fiber.Ctx) error {
+	var req services.RecycleBinListRequest
+	req.EntityType = models.EntityType(c.Query("entity_type", string(models.EntityTypeStudent)))
+	req.Search = strings.TrimSpace(c.Query("search"))
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "20"))
+
+	response, err := h.recycleBinService.ListDeleted(req)
+	if err != nil {
+		return respondRecycleBinError(c, err, "Failed to retrieve deleted records")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// Restore handles POST /api/v1/recycle-bin/:entityType/:id/restore
+func (h *RecycleBinHandler) Restore(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "id", "record")
+	if !ok {
+		return err
+	}
+
+	result, err := h.recycleBinService.WithContext(c.UserContext()).Restore(models.EntityType(c.Params("entityType")), id)
+	if err != nil {
+		return respondRecycleBinError(c, err, "Failed to restore record")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Record restored successfully",
+		"data":    result,
+	})
+}
+
+// Purge handles DELETE /api/v1/recycle-bin/:entityType/:id
+func (h *RecycleBinHandler) Purge(c *fiber.Ctx) error {
+	id, ok, err := attendanceParam(c, "id", "record")
+	if !ok {
+		return err
+	}
+
+	if err := h.recycleBinService.WithContext(c.UserContext()).Purge(models.EntityType(c.Params("entityType")), id); err != nil {
+		return respondRecycleBinError(c, err, "Failed to permanently delete record")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Record permanently deleted",
+	})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"backend-go/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requesterFromCtx identifies the authenticated caller by numeric ID and account type, as set by
+// the auth middleware. Records owned by a caller (export jobs, saved reports, dashboards,
+// submissions) must store both, since the IDs of different account types overlap.
+func requesterFromCtx(c *fiber.Ctx) (uint, string, bool) {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return 0, "", false
+	}
+	accountType, ok := c.Locals("userIDType").(services.AccountType)
+	if !ok {
+		return 0, "", false
+	}
+	return userID, string(accountType), true
+}
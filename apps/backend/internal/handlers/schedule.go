@@ -0,0 +1,1012 @@
+package handlers
+
+import (
+	"backend-go/internal/middleware"
+	"backend-go/internal/services"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ScheduleHandler handles schedule, appointment and calendar HTTP requests (Green Flow)
+type ScheduleHandler struct {
+	scheduleService *services.ScheduleService
+	validator       *validator.Validate
+}
+
+// NewScheduleHandler creates a new schedule handler instance
+func NewScheduleHandler(scheduleService *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		validator:       validator.New(),
+	}
+}
+
+// scheduleErrors maps service error messages to HTTP statuses and error codes
+var scheduleErrors = map[string]struct {
+	status int
+	code   string
+}{
+	"schedule not found":                                      {fiber.StatusNotFound, "SCHEDULE_NOT_FOUND"},
+	"appointment not found":                                   {fiber.StatusNotFound, "APPOINTMENT_NOT_FOUND"},
+	"calendar not found":                                      {fiber.StatusNotFound, "CALENDAR_NOT_FOUND"},
+	"participant not found":                                   {fiber.StatusNotFound, "PARTICIPANT_NOT_FOUND"},
+	"schedule not in calendar":                                {fiber.StatusNotFound, "SCHEDULE_NOT_IN_CALENDAR"},
+	"student training not found":                              {fiber.StatusBadRequest, "STUDENT_TRAINING_NOT_FOUND"},
+	"calendar access denied":                                  {fiber.StatusForbidden, "CALENDAR_ACCESS_DENIED"},
+	"schedule access denied":                                  {fiber.StatusForbidden, "SCHEDULE_ACCESS_DENIED"},
+	"appointment access denied":                               {fiber.StatusForbidden, "APPOINTMENT_ACCESS_DENIED"},
+	"end time must be after start time":                       {fiber.StatusBadRequest, "INVALID_TIME_RANGE"},
+	"recurrence end must be after start time":                 {fiber.StatusBadRequest, "INVALID_RECURRENCE_END"},
+	"user is already a participant":                           {fiber.StatusConflict, "PARTICIPANT_EXISTS"},
+	"cannot remove the last organizer":                        {fiber.StatusBadRequest, "LAST_ORGANIZER"},
+	"appointment is not awaiting approval":                    {fiber.StatusBadRequest, "APPOINTMENT_NOT_PENDING"},
+	"appointment cannot be approved by its requester":         {fiber.StatusForbidden, "SELF_APPROVAL_NOT_ALLOWED"},
+	"appointment time conflicts with an existing appointment": {fiber.StatusConflict, "APPOINTMENT_CONFLICT"},
+	"schedule exception not found":                            {fiber.StatusNotFound, "SCHEDULE_EXCEPTION_NOT_FOUND"},
+	"occurrence not found":                                    {fiber.StatusNotFound, "OCCURRENCE_NOT_FOUND"},
+	"schedule is not recurring":                               {fiber.StatusBadRequest, "SCHEDULE_NOT_RECURRING"},
+	"start time and end time must be provided together":       {fiber.StatusBadRequest, "INVALID_TIME_RANGE"},
+	"exception must cancel, move or relocate the occurrence":  {fiber.StatusBadRequest, "EMPTY_EXCEPTION"},
+	"invalid occurrence range":                                {fiber.StatusBadRequest, "INVALID_TIME_RANGE"},
+	"occurrence range must not exceed 366 days":               {fiber.StatusBadRequest, "RANGE_TOO_LARGE"},
+	"calendar subscription not found":                         {fiber.StatusNotFound, "SUBSCRIPTION_NOT_FOUND"},
+}
+
+// handleScheduleError writes the error response for a schedule service error
+func (h *ScheduleHandler) handleScheduleError(c *fiber.Ctx, err error, fallback string) error {
+	return respondScheduleError(c, err, fallback)
+}
+
+// respondScheduleError maps a schedule service error through scheduleErrors, falling back to a 500
+func respondScheduleError(c *fiber.Ctx, err error, fallback string) error {
+	if mapped, ok := scheduleErrors[err.Error()]; ok {
+		return c.Status(mapped.status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+			"code":    mapped.code,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"success": false,
+		"error":   fallback,
+		"code":    "INTERNAL_ERROR",
+	})
+}
+
+// scheduleActor identifies the caller for organizer and requester checks.
+// Holders of schedules:manage_any may change schedules and appointments of others.
+func scheduleActor(c *fiber.Ctx) (services.ScheduleActor, bool, error) {
+	userID, accountType, ok := requesterFromCtx(c)
+	if !ok {
+		return services.ScheduleActor{}, false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+			"code":    "UNAUTHORIZED",
+		})
+	}
+	return services.ScheduleActor{
+		UserID:      userID,
+		AccountType: accountType,
+		IsAdmin:     middleware.HasPermission(c, "schedules:manage_any"),
+	}, true, nil
+}
+
+// GetSchedules handles GET /api/v1/schedules
+func (h *ScheduleHandler) GetSchedules(c *fiber.Ctx) error {
+	var req services.ScheduleListRequest
+
+	// Parse query parameters
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "10"))
+	req.Search = c.Query("search", "")
+	req.ScheduleType = c.Query("schedule_type", "")
+	req.Status = c.Query("status", "")
+	req.SortBy = c.Query("sort_by", "")
+	req.SortDesc = c.Query("sort_desc", "") == "true"
+
+	if studentTrainingID := c.Query("student_training_id"); studentTrainingID != "" {
+		if id, err := strconv.ParseUint(studentTrainingID, 10, 32); err == nil {
+			studentTrainingIDUint := uint(id)
+			req.StudentTrainingID = &studentTrainingIDUint
+		}
+	}
+
+	if c.Query("mine", "") == "true" {
+		if userID, accountType, ok := requesterFromCtx(c); ok {
+			req.UserID = &userID
+			req.UserType = accountType
+		}
+	}
+
+	if from := c.Query("from"); from != "" {
+		parsed, err := parseScheduleQueryTime(from)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid from date, expected YYYY-MM-DD or RFC3339",
+				"code":    "INVALID_DATE",
+			})
+		}
+		req.From = &parsed
+	}
+
+	if to := c.Query("to"); to != "" {
+		parsed, err := parseScheduleQueryTime(to)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid to date, expected YYYY-MM-DD or RFC3339",
+				"code":    "INVALID_DATE",
+			})
+		}
+		req.To = &parsed
+	}
+
+	// A bounded range returns expanded occurrences unless the series list is explicitly requested
+	if req.From != nil && req.To != nil && c.Query("expand", "true") != "false" {
+		occurrences, err := h.scheduleService.ExpandSchedules(req, c.Query("include_cancelled", "") == "true")
+		if err != nil {
+			return h.handleScheduleError(c, err, "Failed to retrieve schedules")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    occurrences,
+		})
+	}
+
+	response, err := h.scheduleService.GetSchedules(req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve schedules",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// GetSchedule handles GET /api/v1/schedules/:id
+func (h *ScheduleHandler) GetSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	schedule, err := h.scheduleService.GetScheduleByID(uint(id))
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to retrieve schedule")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    schedule,
+	})
+}
+
+// CreateSchedule handles POST /api/v1/schedules
+func (h *ScheduleHandler) CreateSchedule(c *fiber.Ctx) error {
+	var req services.CreateScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+	req.CreatedBy = actor.UserID
+	req.CreatorType = actor.AccountType
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	schedule, err := h.scheduleService.CreateSchedule(req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to create schedule")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Schedule created successfully",
+		"data":    schedule,
+	})
+}
+
+// UpdateSchedule handles PUT /api/v1/schedules/:id
+func (h *ScheduleHandler) UpdateSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.UpdateScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	schedule, err := h.scheduleService.UpdateSchedule(uint(id), actor, req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to update schedule")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Schedule updated successfully",
+		"data":    schedule,
+	})
+}
+
+// DeleteSchedule handles DELETE /api/v1/schedules/:id
+func (h *ScheduleHandler) DeleteSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.scheduleService.DeleteSchedule(uint(id), actor); err != nil {
+		return h.handleScheduleError(c, err, "Failed to delete schedule")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Schedule deleted successfully",
+	})
+}
+
+// AddParticipant handles POST /api/v1/schedules/:id/participants
+func (h *ScheduleHandler) AddParticipant(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.AddParticipantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	participant, err := h.scheduleService.AddParticipant(uint(id), actor, req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to add participant")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Participant added successfully",
+		"data":    participant,
+	})
+}
+
+// RemoveParticipant handles DELETE /api/v1/schedules/:id/participants/:userId?user_type=Instructor
+func (h *ScheduleHandler) RemoveParticipant(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	userID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid user ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	participant := services.ScheduleAccount{
+		UserID:   uint(userID),
+		UserType: c.Query("user_type", string(services.AccountTypeStudent)),
+	}
+	if err := h.scheduleService.RemoveParticipant(uint(id), participant, actor); err != nil {
+		return h.handleScheduleError(c, err, "Failed to remove participant")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Participant removed successfully",
+	})
+}
+
+// RespondToSchedule handles POST /api/v1/schedules/:id/respond
+func (h *ScheduleHandler) RespondToSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.RespondParticipantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	// Participants can only answer for themselves
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	participant, err := h.scheduleService.RespondToSchedule(uint(id), actor, req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to record response")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Response recorded successfully",
+		"data":    participant,
+	})
+}
+
+// SetOccurrenceException handles POST /api/v1/schedules/:id/exceptions
+func (h *ScheduleHandler) SetOccurrenceException(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.ScheduleExceptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+	req.CreatedBy = actor.UserID
+	req.CreatorType = actor.AccountType
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	exception, err := h.scheduleService.SetOccurrenceException(uint(id), actor, req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to update occurrence")
+	}
+
+	message := "Occurrence updated successfully"
+	if exception.IsCancelled {
+		message = "Occurrence cancelled successfully"
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    exception,
+	})
+}
+
+// DeleteOccurrenceException handles DELETE /api/v1/schedules/:id/exceptions/:exceptionId
+func (h *ScheduleHandler) DeleteOccurrenceException(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	exceptionID, err := strconv.ParseUint(c.Params("exceptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid exception ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.scheduleService.DeleteOccurrenceException(uint(id), uint(exceptionID), actor); err != nil {
+		return h.handleScheduleError(c, err, "Failed to restore occurrence")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Occurrence restored successfully",
+	})
+}
+
+// GetAppointments handles GET /api/v1/appointments
+func (h *ScheduleHandler) GetAppointments(c *fiber.Ctx) error {
+	var req services.AppointmentListRequest
+
+	// Parse query parameters
+	req.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit", "10"))
+	req.Status = c.Query("status", "")
+
+	if studentTrainingID := c.Query("student_training_id"); studentTrainingID != "" {
+		if id, err := strconv.ParseUint(studentTrainingID, 10, 32); err == nil {
+			studentTrainingIDUint := uint(id)
+			req.StudentTrainingID = &studentTrainingIDUint
+		}
+	}
+
+	if requestedBy := c.Query("requested_by"); requestedBy != "" {
+		if id, err := strconv.ParseUint(requestedBy, 10, 32); err == nil {
+			requestedByUint := uint(id)
+			req.RequestedBy = &requestedByUint
+		}
+	}
+	req.RequesterType = c.Query("requester_type", string(services.AccountTypeStudent))
+
+	if from := c.Query("from"); from != "" {
+		if parsed, err := parseScheduleQueryTime(from); err == nil {
+			req.From = &parsed
+		}
+	}
+
+	if to := c.Query("to"); to != "" {
+		if parsed, err := parseScheduleQueryTime(to); err == nil {
+			req.To = &parsed
+		}
+	}
+
+	response, err := h.scheduleService.GetAppointments(req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve appointments",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// GetAppointment handles GET /api/v1/appointments/:id
+func (h *ScheduleHandler) GetAppointment(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid appointment ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	appointment, err := h.scheduleService.GetAppointmentByID(uint(id))
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to retrieve appointment")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    appointment,
+	})
+}
+
+// CreateAppointment handles POST /api/v1/appointments
+func (h *ScheduleHandler) CreateAppointment(c *fiber.Ctx) error {
+	var req services.CreateAppointmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+	req.RequestedBy = actor.UserID
+	req.RequesterType = actor.AccountType
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	appointment, err := h.scheduleService.CreateAppointment(req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to create appointment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Appointment requested successfully",
+		"data":    appointment,
+	})
+}
+
+// UpdateAppointment handles PUT /api/v1/appointments/:id
+func (h *ScheduleHandler) UpdateAppointment(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid appointment ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.UpdateAppointmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	appointment, err := h.scheduleService.UpdateAppointment(uint(id), actor, req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to update appointment")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Appointment updated successfully",
+		"data":    appointment,
+	})
+}
+
+// ApproveAppointment handles POST /api/v1/appointments/:id/approve
+func (h *ScheduleHandler) ApproveAppointment(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid appointment ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.ApproveAppointmentRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+				"code":    "INVALID_REQUEST_BODY",
+			})
+		}
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+	req.ApproverID = actor.UserID
+	req.ApproverType = actor.AccountType
+
+	appointment, err := h.scheduleService.ApproveAppointment(uint(id), req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to approve appointment")
+	}
+
+	message := "Appointment approved successfully"
+	if req.Approved != nil && !*req.Approved {
+		message = "Appointment rejected"
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    appointment,
+	})
+}
+
+// DeleteAppointment handles DELETE /api/v1/appointments/:id
+func (h *ScheduleHandler) DeleteAppointment(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid appointment ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.scheduleService.DeleteAppointment(uint(id), actor); err != nil {
+		return h.handleScheduleError(c, err, "Failed to delete appointment")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Appointment deleted successfully",
+	})
+}
+
+// GetCalendars handles GET /api/v1/calendars
+func (h *ScheduleHandler) GetCalendars(c *fiber.Ctx) error {
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	calendars, err := h.scheduleService.GetCalendars(services.CalendarListRequest{
+		OwnerID:       actor.UserID,
+		OwnerType:     actor.AccountType,
+		IncludePublic: c.Query("include_public", "true") == "true",
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to retrieve calendars",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    calendars,
+	})
+}
+
+// GetCalendar handles GET /api/v1/calendars/:id
+func (h *ScheduleHandler) GetCalendar(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid calendar ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	calendar, err := h.scheduleService.GetCalendarByID(uint(id), actor)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to retrieve calendar")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    calendar,
+	})
+}
+
+// CreateCalendar handles POST /api/v1/calendars
+func (h *ScheduleHandler) CreateCalendar(c *fiber.Ctx) error {
+	var req services.CreateCalendarRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+	req.OwnerID = actor.UserID
+	req.OwnerType = actor.AccountType
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	calendar, err := h.scheduleService.CreateCalendar(req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to create calendar",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Calendar created successfully",
+		"data":    calendar,
+	})
+}
+
+// UpdateCalendar handles PUT /api/v1/calendars/:id
+func (h *ScheduleHandler) UpdateCalendar(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid calendar ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.UpdateCalendarRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	calendar, err := h.scheduleService.UpdateCalendar(uint(id), actor, req)
+	if err != nil {
+		return h.handleScheduleError(c, err, "Failed to update calendar")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Calendar updated successfully",
+		"data":    calendar,
+	})
+}
+
+// DeleteCalendar handles DELETE /api/v1/calendars/:id
+func (h *ScheduleHandler) DeleteCalendar(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid calendar ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.scheduleService.DeleteCalendar(uint(id), actor); err != nil {
+		return h.handleScheduleError(c, err, "Failed to delete calendar")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Calendar deleted successfully",
+	})
+}
+
+// AddCalendarSchedule handles POST /api/v1/calendars/:id/schedules
+func (h *ScheduleHandler) AddCalendarSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid calendar ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	var req services.CalendarScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.scheduleService.AddScheduleToCalendar(uint(id), actor, req); err != nil {
+		return h.handleScheduleError(c, err, "Failed to add schedule to calendar")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Schedule added to calendar successfully",
+	})
+}
+
+// RemoveCalendarSchedule handles DELETE /api/v1/calendars/:id/schedules/:scheduleId
+func (h *ScheduleHandler) RemoveCalendarSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid calendar ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	scheduleID, err := strconv.ParseUint(c.Params("scheduleId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid schedule ID",
+			"code":    "INVALID_ID",
+		})
+	}
+
+	actor, ok, err := scheduleActor(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.scheduleService.RemoveScheduleFromCalendar(uint(id), uint(scheduleID), actor); err != nil {
+		return h.handleScheduleError(c, err, "Failed to remove schedule from calendar")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Schedule removed from calendar successfully",
+	})
+}
+
+// parseScheduleQueryTime accepts either a plain date or an RFC3339 timestamp
+func parseScheduleQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"backend-go/internal/middleware"
 	"backend-go/internal/services"
 	"strconv"
 
@@ -61,6 +62,9 @@ func (h *StudentHandler) GetStudents(c *fiber.Ctx) error {
 		}
 	}
 
+	// Restrict the list to the caller's visible students
+	req.Scope, _ = middleware.GetDataScope(c)
+
 	response, err := h.studentService.GetStudents(req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -118,7 +122,7 @@ func (h *StudentHandler) CreateStudent(c *fiber.Ctx) error {
 		})
 	}
 
-	student, err := h.studentService.CreateStudent(req)
+	student, err := h.studentService.WithContext(c.UserContext()).CreateStudent(req)
 	if err != nil {
 		switch err.Error() {
 		case "user not found":
@@ -131,6 +135,11 @@ func (h *StudentHandler) CreateStudent(c *fiber.Ctx) error {
 				"error": "Student with this student ID already exists",
 				"code":  "STUDENT_ID_EXISTS",
 			})
+		case "student with this student ID is in the recycle bin":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Student with this student ID is in the recycle bin, restore it instead",
+				"code":  "STUDENT_IN_RECYCLE_BIN",
+			})
 		case "user is already a student":
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"error": "User is already a student",
@@ -173,7 +182,7 @@ func (h *StudentHandler) UpdateStudent(c *fiber.Ctx) error {
 		})
 	}
 
-	student, err := h.studentService.UpdateStudent(uint(id), req)
+	student, err := h.studentService.WithContext(c.UserContext()).UpdateStudent(uint(id), req)
 	if err != nil {
 		switch err.Error() {
 		case "student not found":
@@ -207,7 +216,7 @@ func (h *StudentHandler) DeleteStudent(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.studentService.DeleteStudent(uint(id))
+	err = h.studentService.WithContext(c.UserContext()).DeleteStudent(uint(id))
 	if err != nil {
 		if err.Error() == "student not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -338,7 +347,8 @@ func (h *StudentHandler) GetStudentEnrollments(c *fiber.Ctx) error {
 
 // GetStudentStats handles GET /api/v1/students/stats
 func (h *StudentHandler) GetStudentStats(c *fiber.Ctx) error {
-	stats, err := h.studentService.GetStudentStats()
+	scope, _ := middleware.GetDataScope(c)
+	stats, err := h.studentService.GetStudentStats(scope)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve student statistics",
@@ -368,7 +378,7 @@ func (h *StudentHandler) BulkDeleteStudents(c *fiber.Ctx) error {
 		})
 	}
 
-	err := h.studentService.BulkDeleteStudents(req)
+	err := h.studentService.WithContext(c.UserContext()).BulkDeleteStudents(req)
 	if err != nil {
 		if err.Error() == "cannot delete students with active trainings" {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
@@ -399,6 +409,9 @@ func (h *StudentHandler) AdvancedSearch(c *fiber.Ctx) error {
 		})
 	}
 
+	// Restrict the search to the caller's visible students
+	req.Scope, _ = middleware.GetDataScope(c)
+
 	students, err := h.studentService.AdvancedSearch(req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -415,7 +428,8 @@ func (h *StudentHandler) AdvancedSearch(c *fiber.Ctx) error {
 
 // GetStudentAnalytics handles GET /api/v1/students/analytics
 func (h *StudentHandler) GetStudentAnalytics(c *fiber.Ctx) error {
-	analytics, err := h.studentService.GetStudentAnalytics()
+	scope, _ := middleware.GetDataScope(c)
+	analytics, err := h.studentService.GetStudentAnalytics(scope)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve student analytics",
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"backend-go/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxStudentImportFileSize caps uploaded roster files (10 MB)
+const maxStudentImportFileSize = 10 * 1024 * 1024
+
+// StudentImportHandler handles roster import HTTP requests
+type StudentImportHandler struct {
+	importService *services.StudentImportService
+}
+
+// NewStudentImportHandler creates a new student import handler instance
+func NewStudentImportHandler(importService *services.StudentImportService) *StudentImportHandler {
+	return &StudentImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportStudents handles POST /api/v1/students/import
+// The roster is validated without writing anything unless dry_run=false is given.
+func (h *StudentImportHandler) ImportStudents(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "File is required",
+			"code":    "FILE_REQUIRED",
+		})
+	}
+	if file.Size > maxStudentImportFileSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"success": false,
+			"error":   "File is larger than 10 MB",
+			"code":    "FILE_TOO_LARGE",
+		})
+	}
+
+	dryRun := true
+	if value := c.Query("dry_run", c.FormValue("dry_run")); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "dry_run must be true or false",
+				"code":    "VALIDATION_ERROR",
+			})
+		}
+		dryRun = parsed
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to read file",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+	defer content.Close()
+
+	result, err := h.importService.WithContext(c.UserContext()).ImportStudents(file.Filename, content, dryRun)
+	if err != nil {
+		switch {
+		case err.Error() == "import has invalid rows":
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"success": false,
+				"error":   "Import has invalid rows, nothing was imported",
+				"code":    "IMPORT_INVALID_ROWS",
+				"data":    result,
+			})
+		case strings.HasPrefix(err.Error(), "invalid import file"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+				"code":    "INVALID_IMPORT_FILE",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to import students",
+			"code":    "INTERNAL_ERROR",
+		})
+	}
+
+	message := "Roster validated, nothing was imported"
+	if result.Committed {
+		message = "Students imported successfully"
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    result,
+	})
+}
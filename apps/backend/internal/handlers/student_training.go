@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"backend-go/internal/middleware"
 	"backend-go/internal/services"
 	"strconv"
 
@@ -47,6 +48,9 @@ func (h *StudentTrainingHandler) GetStudentTrainings(c *fiber.Ctx) error {
 		}
 	}
 
+	// Restrict the list to the caller's visible students
+	req.Scope, _ = middleware.GetDataScope(c)
+
 	response, err := h.studentTrainingService.GetStudentTrainings(req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -116,7 +120,7 @@ func (h *StudentTrainingHandler) CreateStudentTraining(c *fiber.Ctx) error {
 		})
 	}
 
-	training, err := h.studentTrainingService.CreateStudentTraining(req)
+	training, err := h.studentTrainingService.WithContext(c.UserContext()).CreateStudentTraining(req)
 	if err != nil {
 		switch err.Error() {
 		case "student enrollment not found":
@@ -182,7 +186,7 @@ func (h *StudentTrainingHandler) UpdateStudentTraining(c *fiber.Ctx) error {
 		})
 	}
 
-	training, err := h.studentTrainingService.UpdateStudentTraining(uint(id), req)
+	training, err := h.studentTrainingService.WithContext(c.UserContext()).UpdateStudentTraining(uint(id), req)
 	if err != nil {
 		switch err.Error() {
 		case "student training not found":
@@ -224,7 +228,7 @@ func (h *StudentTrainingHandler) DeleteStudentTraining(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.studentTrainingService.DeleteStudentTraining(uint(id))
+	err = h.studentTrainingService.WithContext(c.UserContext()).DeleteStudentTraining(uint(id))
 	if err != nil {
 		if err.Error() == "student training not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
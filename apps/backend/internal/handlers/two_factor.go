@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"backend-go/internal/middleware"
+	"backend-go/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TwoFactorHandler handles TOTP enrollment, backup code and 2FA login HTTP requests
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+	validator        *validator.Validate
+}
+
+// NewTwoFactorHandler creates a new two-factor handler instance
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		validator:        validator.New(),
+	}
+}
+
+// VerifyLogin handles POST /api/v1/login/2fa
+func (h *TwoFactorHandler) VerifyLogin(c *fiber.Ctx) error {
+	var req services.TwoFactorLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	response, err := h.twoFactorService.CompleteLogin(req, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return respondTwoFactorError(c, err, "Failed to verify two-factor code")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Login successful",
+		"data":    response,
+	})
+}
+
+// GetStatus handles GET /api/v1/2fa
+func (h *TwoFactorHandler) GetStatus(c *fiber.Ctx) error {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		return respondTwoFactorUnauthenticated(c)
+	}
+
+	status, err := h.twoFactorService.GetStatus(claims.UserType, claims.UserID)
+	if err != nil {
+		return respondTwoFactorError(c, err, "Failed to retrieve two-factor status")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// Enroll handles POST /api/v1/2fa/enroll
+func (h *TwoFactorHandler) Enroll(c *fiber.Ctx) error {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		return respondTwoFactorUnauthenticated(c)
+	}
+
+	enrollment, err := h.twoFactorService.BeginEnrollment(claims.UserType, claims.UserID)
+	if err != nil {
+		return respondTwoFactorError(c, err, "Failed to start two-factor enrollment")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Scan the QR code and confirm with a code from your authenticator app",
+		"data":    enrollment,
+	})
+}
+
+// ConfirmEnrollment handles POST /api/v1/2fa/confirm
+func (h *TwoFactorHandler) ConfirmEnrollment(c *fiber.Ctx) error {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		return respondTwoFactorUnauthenticated(c)
+	}
+
+	var req services.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	codes, err := h.twoFactorService.ConfirmEnrollment(claims.UserType, claims.UserID, req.Code, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return respondTwoFactorError(c, err, "Failed to enable two-factor authentication")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Two-factor authentication enabled. Store the backup codes somewhere safe; they are shown only once",
+		"data":    fiber.Map{"backup_codes": codes},
+	})
+}
+
+// Disable handles POST /api/v1/2fa/disable
+func (h *TwoFactorHandler) Disable(c *fiber.Ctx) error {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		return respondTwoFactorUnauthenticated(c)
+	}
+
+	var req services.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	if err := h.twoFactorService.Disable(claims.UserType, claims.UserID, req.Code, c.IP(), c.Get(fiber.HeaderUserAgent)); err != nil {
+		return respondTwoFactorError(c, err, "Failed to disable two-factor authentication")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateBackupCodes handles POST /api/v1/2fa/backup-codes
+func (h *TwoFactorHandler) RegenerateBackupCodes(c *fiber.Ctx) error {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		return respondTwoFactorUnauthenticated(c)
+	}
+
+	var req services.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST_BODY",
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"code":    "VALIDATION_ERROR",
+			"details": err.Error(),
+		})
+	}
+
+	codes, err := h.twoFactorService.RegenerateBackupCodes(claims.UserType, claims.UserID, req.Code, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return respondTwoFactorError(c, err, "Failed to regenerate backup codes")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Backup codes regenerated; previous codes no longer work",
+		"data":    fiber.Map{"backup_codes": codes},
+	})
+}
+
+// respondTwoFactorUnauthenticated writes the response for a request without claims
+func respondTwoFactorUnauthenticated(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"success": false,
+		"error":   "User not authenticated",
+		"code":    "UNAUTHORIZED",
+	})
+}
+
+// respondTwoFactorError maps two-factor service errors to HTTP responses
+func respondTwoFactorError(c *fiber.Ctx, err error, message string) error {
+	status, code := fiber.StatusInternalServerError, "INTERNAL_ERROR"
+	switch err.Error() {
+	case "invalid two-factor code":
+		status, code = fiber.StatusUnauthorized, "INVALID_TWO_FACTOR_CODE"
+	case "invalid challenge token":
+		status, code = fiber.StatusUnauthorized, "INVALID_CHALLENGE_TOKEN"
+	case "two-factor verification locked":
+		status, code = fiber.StatusLocked, "TWO_FACTOR_LOCKED"
+	case "two-factor already enabled":
+		status, code = fiber.StatusConflict, "TWO_FACTOR_ALREADY_ENABLED"
+	case "two-factor not enrolled", "two-factor not enabled":
+		status, code = fiber.StatusBadRequest, "TWO_FACTOR_NOT_ENABLED"
+	case "two-factor not available for this account", "unsupported user type":
+		status, code = fiber.StatusForbidden, "TWO_FACTOR_NOT_AVAILABLE"
+	case "user not found":
+		status, code = fiber.StatusNotFound, "USER_NOT_FOUND"
+	}
+
+	if status == fiber.StatusInternalServerError {
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   message,
+			"code":    code,
+		})
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"error":   err.Error(),
+		"code":    code,
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"backend-go/internal/middleware"
 	"backend-go/internal/services"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -24,6 +25,15 @@ func NewVisitorHandler(visitorService services.VisitorServiceInterface) *Visitor
 	}
 }
 
+// service returns the visitor service bound to the request context, so evaluation changes are
+// audited under the request's user
+func (h *VisitorHandler) service(c *fiber.Ctx) services.VisitorServiceInterface {
+	if visitorService, ok := h.visitorService.(*services.VisitorService); ok {
+		return visitorService.WithContext(c.UserContext())
+	}
+	return h.visitorService
+}
+
 // GetValidator returns the validator instance (for testing purposes)
 func (h *VisitorHandler) GetValidator() *validator.Validate {
 	return h.validator
@@ -64,6 +74,8 @@ func (h *VisitorHandler) GetVisitorTrainings(c *fiber.Ctx) error {
 		SortBy:              sortBy,
 		SortDesc:            sortDesc,
 	}
+	// Restrict the list to the caller's visible students
+	req.Scope, _ = middleware.GetDataScope(c)
 
 	response, err := h.visitorService.GetVisitorTrainings(req)
 	if err != nil {
@@ -285,6 +297,8 @@ func (h *VisitorHandler) GetVisitorSchedules(c *fiber.Ctx) error {
 		SortBy:            sortBy,
 		SortDesc:          sortDesc,
 	}
+	// Restrict the list to the caller's visible students
+	req.Scope, _ = middleware.GetDataScope(c)
 
 	response, err := h.visitorService.GetVisitorSchedules(req)
 	if err != nil {
@@ -530,6 +544,7 @@ func (h *VisitorHandler) CreateVisitorEvaluateStudent(c *fiber.Ctx) error {
 			"code":  "INVALID_REQUEST_BODY",
 		})
 	}
+	req.Scope, _ = middleware.GetDataScope(c)
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
@@ -540,7 +555,7 @@ func (h *VisitorHandler) CreateVisitorEvaluateStudent(c *fiber.Ctx) error {
 		})
 	}
 
-	evaluation, err := h.visitorService.CreateVisitorEvaluateStudent(req)
+	evaluation, err := h.service(c).CreateVisitorEvaluateStudent(req)
 	if err != nil {
 		if err.Error() == "visitor training not found" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -548,6 +563,9 @@ func (h *VisitorHandler) CreateVisitorEvaluateStudent(c *fiber.Ctx) error {
 				"code":  "VISITOR_TRAINING_NOT_FOUND",
 			})
 		}
+		if handled, err := respondEvaluationAnswerError(c, err); handled {
+			return err
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create visitor evaluate student",
 			"code":  "CREATE_VISITOR_EVALUATE_STUDENT_ERROR",
@@ -590,7 +608,7 @@ func (h *VisitorHandler) UpdateVisitorEvaluateStudent(c *fiber.Ctx) error {
 		})
 	}
 
-	evaluation, err := h.visitorService.UpdateVisitorEvaluateStudent(uint(id), req)
+	evaluation, err := h.service(c).UpdateVisitorEvaluateStudent(uint(id), req)
 	if err != nil {
 		if err.Error() == "visitor evaluate student not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -598,6 +616,9 @@ func (h *VisitorHandler) UpdateVisitorEvaluateStudent(c *fiber.Ctx) error {
 				"code":  "VISITOR_EVALUATE_STUDENT_NOT_FOUND",
 			})
 		}
+		if handled, err := respondEvaluationAnswerError(c, err); handled {
+			return err
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update visitor evaluate student",
 			"code":  "UPDATE_VISITOR_EVALUATE_STUDENT_ERROR",
@@ -621,7 +642,7 @@ func (h *VisitorHandler) DeleteVisitorEvaluateStudent(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.visitorService.DeleteVisitorEvaluateStudent(uint(id))
+	err = h.service(c).DeleteVisitorEvaluateStudent(uint(id))
 	if err != nil {
 		if err.Error() == "visitor evaluate student not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -710,6 +731,7 @@ func (h *VisitorHandler) CreateVisitorEvaluateCompany(c *fiber.Ctx) error {
 			"code":  "INVALID_REQUEST_BODY",
 		})
 	}
+	req.Scope, _ = middleware.GetDataScope(c)
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
@@ -720,7 +742,7 @@ func (h *VisitorHandler) CreateVisitorEvaluateCompany(c *fiber.Ctx) error {
 		})
 	}
 
-	evaluation, err := h.visitorService.CreateVisitorEvaluateCompany(req)
+	evaluation, err := h.service(c).CreateVisitorEvaluateCompany(req)
 	if err != nil {
 		switch err.Error() {
 		case "visitor training not found":
@@ -734,6 +756,9 @@ func (h *VisitorHandler) CreateVisitorEvaluateCompany(c *fiber.Ctx) error {
 				"code":  "STUDENT_TRAINING_NOT_FOUND",
 			})
 		default:
+			if handled, err := respondEvaluationAnswerError(c, err); handled {
+				return err
+			}
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to create visitor evaluate company",
 				"code":  "CREATE_VISITOR_EVALUATE_COMPANY_ERROR",
@@ -777,7 +802,7 @@ func (h *VisitorHandler) UpdateVisitorEvaluateCompany(c *fiber.Ctx) error {
 		})
 	}
 
-	evaluation, err := h.visitorService.UpdateVisitorEvaluateCompany(uint(id), req)
+	evaluation, err := h.service(c).UpdateVisitorEvaluateCompany(uint(id), req)
 	if err != nil {
 		switch err.Error() {
 		case "visitor evaluate company not found":
@@ -791,6 +816,9 @@ func (h *VisitorHandler) UpdateVisitorEvaluateCompany(c *fiber.Ctx) error {
 				"code":  "STUDENT_TRAINING_NOT_FOUND",
 			})
 		default:
+			if handled, err := respondEvaluationAnswerError(c, err); handled {
+				return err
+			}
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to update visitor evaluate company",
 				"code":  "UPDATE_VISITOR_EVALUATE_COMPANY_ERROR",
@@ -815,7 +843,7 @@ func (h *VisitorHandler) DeleteVisitorEvaluateCompany(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.visitorService.DeleteVisitorEvaluateCompany(uint(id))
+	err = h.service(c).DeleteVisitorEvaluateCompany(uint(id))
 	if err != nil {
 		if err.Error() == "visitor evaluate company not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -1074,4 +1102,27 @@ func isImageFile(filename string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// respondEvaluationAnswerError writes a 400 when structured evaluation answers cannot be recorded
+// on the student's evaluation form
+func respondEvaluationAnswerError(c *fiber.Ctx, err error) (bool, error) {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid evaluation answer"):
+		return true, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  "INVALID_EVALUATION_ANSWERS",
+		})
+	case err.Error() == "no active evaluation form":
+		return true, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No active evaluation form applies to this student",
+			"code":  "NO_ACTIVE_EVALUATION_FORM",
+		})
+	case err.Error() == "legacy evaluation has no student training":
+		return true, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Student has no training to evaluate",
+			"code":  "STUDENT_TRAINING_NOT_FOUND",
+		})
+	}
+	return false, nil
+}
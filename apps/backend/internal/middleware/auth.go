@@ -3,6 +3,7 @@ package middleware
 import (
 	"strings"
 
+	"backend-go/internal/database"
 	"backend-go/internal/services"
 	"github.com/gofiber/fiber/v2"
 )
@@ -45,11 +46,32 @@ func AuthMiddleware(jwtService *services.JWTService) fiber.Handler {
 			})
 		}
 
+		// A 2FA challenge token only proves the password step
+		if claims.Claims.TokenType == services.TokenTypeTwoFactor {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Two-factor verification required",
+				"code":  "TWO_FACTOR_REQUIRED",
+			})
+		}
+
+		// Refresh, password reset and email verification tokens must not authenticate API requests
+		if claims.Claims.TokenType != services.TokenTypeAccess {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Access token required",
+				"code":  "INVALID_TOKEN_TYPE",
+			})
+		}
+
 		// Store user information in context for use in handlers
 		c.Locals("user_id", claims.Claims.UserID)
 		c.Locals("user_email", claims.Claims.Email)
 		c.Locals("user_type", claims.Claims.UserType)
 		c.Locals("claims", claims.Claims)
+		if claims.Account != nil {
+			c.Locals("userID", claims.Account.ID)
+			c.Locals("userIDType", claims.Account.Type)
+		}
+		setAuditActor(c, claims.Claims)
 
 		return c.Next()
 	}
@@ -80,8 +102,8 @@ func OptionalAuthMiddleware(jwtService *services.JWTService) fiber.Handler {
 
 		// Validate the token
 		claims, err := jwtService.ValidateToken(token)
-		if err != nil {
-			// Invalid token, continue without authentication
+		if err != nil || claims.Claims.TokenType != services.TokenTypeAccess {
+			// Invalid token, pending 2FA challenge or not an access token, continue without authentication
 			return c.Next()
 		}
 
@@ -90,11 +112,33 @@ func OptionalAuthMiddleware(jwtService *services.JWTService) fiber.Handler {
 		c.Locals("user_email", claims.Claims.Email)
 		c.Locals("user_type", claims.Claims.UserType)
 		c.Locals("claims", claims.Claims)
+		if claims.Account != nil {
+			c.Locals("userID", claims.Account.ID)
+			c.Locals("userIDType", claims.Account.Type)
+		}
+		setAuditActor(c, claims.Claims)
 
 		return c.Next()
 	}
 }
 
+// setAuditActor attaches the caller to the request context, so changes made with it are audited
+// under their name. Must run after the user locals are set.
+func setAuditActor(c *fiber.Ctx, claims *services.JWTClaims) {
+	userType := string(claims.UserType)
+	account, ok := GetAccount(c)
+	if ok {
+		userType = string(account.Type)
+	}
+	c.SetUserContext(database.WithAuditActor(c.UserContext(), database.AuditActor{
+		UserID:    account.ID,
+		ActorID:   claims.UserID,
+		UserType:  userType,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	}))
+}
+
 // GetUserID extracts the user ID from the context
 func GetUserID(c *fiber.Ctx) (string, bool) {
 	userID, ok := c.Locals("user_id").(string)
@@ -113,6 +157,20 @@ func GetUserType(c *fiber.Ctx) (services.UserType, bool) {
 	return userType, ok
 }
 
+// GetAccount extracts the numeric account of the user from the context. The "userID" local holds
+// students.id, instructors.id, staffs.id or super_admins.id, told apart by the "userIDType" local.
+func GetAccount(c *fiber.Ctx) (services.Account, bool) {
+	id, ok := c.Locals("userID").(uint)
+	if !ok {
+		return services.Account{}, false
+	}
+	accountType, ok := c.Locals("userIDType").(services.AccountType)
+	if !ok {
+		return services.Account{}, false
+	}
+	return services.Account{Type: accountType, ID: id}, true
+}
+
 // GetClaims extracts the JWT claims from the context
 func GetClaims(c *fiber.Ctx) (*services.JWTClaims, bool) {
 	claims, ok := c.Locals("claims").(*services.JWTClaims)
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"strconv"
+
+	"backend-go/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ScopeData creates a middleware that resolves which student records the caller may read
+// and stores the scope in context for list queries. Must run after AuthMiddleware.
+func ScopeData(authService *services.AuthorizationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, err := resolveDataScope(c, authService); err != nil {
+			return respondAuthorizationError(c, err, "Failed to resolve data scope")
+		}
+		return c.Next()
+	}
+}
+
+// RequireScopedAccess creates a middleware that rejects requests for a record outside the
+// caller's data scope. The record ID is read from the named route parameter.
+func RequireScopedAccess(authService *services.AuthorizationService, resource services.ScopedResource, param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scope, err := resolveDataScope(c, authService)
+		if err != nil {
+			return respondAuthorizationError(c, err, "Failed to resolve data scope")
+		}
+
+		id, parseErr := strconv.ParseUint(c.Params(param), 10, 32)
+		if parseErr != nil {
+			// Let the handler report the malformed ID
+			return c.Next()
+		}
+
+		allowed, checkErr := authService.CanAccess(scope, resource, uint(id))
+		if checkErr != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check data access",
+				"code":  "AUTHORIZATION_ERROR",
+			})
+		}
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied: this record is outside your data scope",
+				"code":  "ACCESS_DENIED",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// resolveDataScope loads the caller's data scope once per request and stores it in context
+func resolveDataScope(c *fiber.Ctx, authService *services.AuthorizationService) (*services.DataScope, error) {
+	if scope, ok := GetDataScope(c); ok {
+		return scope, nil
+	}
+
+	claims, ok := GetClaims(c)
+	if !ok {
+		return nil, errNotAuthenticated
+	}
+
+	scope, err := authService.ResolveDataScope(claims.UserType, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Locals("data_scope", scope)
+	return scope, nil
+}
+
+// GetDataScope extracts the caller's data scope from the context
+func GetDataScope(c *fiber.Ctx) (*services.DataScope, bool) {
+	scope, ok := c.Locals("data_scope").(*services.DataScope)
+	return scope, ok
+}
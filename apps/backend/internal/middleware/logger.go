@@ -174,7 +174,8 @@ func SecurityLogger(logger *services.Logger) fiber.Handler {
 	}
 }
 
-// MetricsLogger creates a middleware that logs performance metrics
+// MetricsLogger creates a middleware that logs performance metrics and records
+// request counts and latencies for the Prometheus endpoint
 func MetricsLogger(logger *services.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
@@ -184,6 +185,8 @@ func MetricsLogger(logger *services.Logger) fiber.Handler {
 		
 		latency := time.Since(start)
 		
+		services.GetHTTPMetrics().Observe(c.Method(), metricsRoute(c), metricsStatus(c, err), latency)
+
 		// Log slow requests (> 1 second)
 		if latency > time.Second {
 			contextLogger := logger.WithRequestContext(c)
@@ -208,6 +211,28 @@ func MetricsLogger(logger *services.Logger) fiber.Handler {
 	}
 }
 
+// metricsRoute returns the route pattern that handled the request, so paths with IDs share a series.
+// Requests that matched no route are grouped together.
+func metricsRoute(c *fiber.Ctx) string {
+	route := c.Route().Path
+	if route == "" || (route == "/" && c.Path() != "/") {
+		return "unmatched"
+	}
+	return route
+}
+
+// metricsStatus returns the response status, taking into account errors that the
+// error handler has not turned into a response yet
+func metricsStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	if e, ok := err.(*fiber.Error); ok {
+		return e.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
 // ErrorLogger creates a middleware that logs application errors
 func ErrorLogger(logger *services.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
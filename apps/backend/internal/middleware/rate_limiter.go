@@ -102,4 +102,20 @@ func NotificationRateLimit() fiber.Handler {
 			})
 		},
 	})
-}
\ No newline at end of file
+}
+// TwoFactorRateLimit creates a rate limiter for 2FA code verification endpoints
+func TwoFactorRateLimit(max int, window time.Duration) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: window,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(429).JSON(fiber.Map{
+				"error": "Too many two-factor attempts, please try again later",
+				"code":  "TWO_FACTOR_RATE_LIMITED",
+			})
+		},
+	})
+}
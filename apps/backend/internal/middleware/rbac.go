@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"backend-go/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission creates a middleware that requires every given permission.
+// Permissions come from the user's roles only: tokens are issued without
+// abilities, so token abilities are not checked. Must run after AuthMiddleware.
+func RequirePermission(authService *services.AuthorizationService, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := resolveAccess(c, authService)
+		if err != nil {
+			return respondAuthorizationError(c, err, "Failed to resolve permissions")
+		}
+
+		for _, permission := range permissions {
+			if !authService.HasPermission(access, permission) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":               "Insufficient permissions",
+					"code":                "INSUFFICIENT_PERMISSIONS",
+					"required_permission": permission,
+				})
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireRole creates a middleware that requires any of the given roles.
+// Must run after AuthMiddleware.
+func RequireRole(authService *services.AuthorizationService, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := resolveAccess(c, authService)
+		if err != nil {
+			return respondAuthorizationError(c, err, "Failed to resolve permissions")
+		}
+
+		if !access.HasRole(roles...) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Insufficient permissions",
+				"code":  "INSUFFICIENT_PERMISSIONS",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// errNotAuthenticated is returned when authorization runs without AuthMiddleware claims
+var errNotAuthenticated = errors.New("authentication required")
+
+// resolveAccess loads the caller's access once per request and stores it in context
+func resolveAccess(c *fiber.Ctx, authService *services.AuthorizationService) (*services.UserAccess, error) {
+	claims, ok := GetClaims(c)
+	if !ok {
+		return nil, errNotAuthenticated
+	}
+
+	if access, ok := GetAccess(c); ok {
+		return access, nil
+	}
+
+	access, err := authService.ResolveAccess(claims.UserType, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Locals("access", access)
+	c.Locals("roles", access.Roles)
+	c.Locals("role_name", access.PrimaryRole())
+	c.Locals("permissions", access.PermissionSet())
+
+	return access, nil
+}
+
+// respondAuthorizationError writes the response for a failed role or scope resolution
+func respondAuthorizationError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, errNotAuthenticated) || err.Error() == "user not found" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Authentication required",
+			"code":  "AUTH_REQUIRED",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+		"code":  "AUTHORIZATION_ERROR",
+	})
+}
+
+// GetAccess extracts the resolved roles and permissions from the context
+func GetAccess(c *fiber.Ctx) (*services.UserAccess, bool) {
+	access, ok := c.Locals("access").(*services.UserAccess)
+	return access, ok
+}
+
+// GetRoleName extracts the primary role name from the context
+func GetRoleName(c *fiber.Ctx) (string, bool) {
+	roleName, ok := c.Locals("role_name").(string)
+	return roleName, ok
+}
+
+// GetRoles extracts all role names from the context
+func GetRoles(c *fiber.Ctx) ([]string, bool) {
+	roles, ok := c.Locals("roles").([]string)
+	return roles, ok
+}
+
+// GetPermissions extracts the permissions map from the context
+func GetPermissions(c *fiber.Ctx) (map[string]bool, bool) {
+	permissions, ok := c.Locals("permissions").(map[string]bool)
+	return permissions, ok
+}
+
+// HasPermission checks if the current user has a specific permission,
+// honouring wildcard grants such as "*" and "students:*"
+func HasPermission(c *fiber.Ctx, permission string) bool {
+	permissions, ok := GetPermissions(c)
+	if !ok {
+		return false
+	}
+	if permissions[permission] {
+		return true
+	}
+	for granted := range permissions {
+		if strings.HasSuffix(granted, "*") && strings.HasPrefix(permission, strings.TrimSuffix(granted, "*")) {
+			return true
+		}
+	}
+	return false
+}
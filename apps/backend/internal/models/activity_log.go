@@ -40,21 +40,22 @@ const (
 	EntityTypeUser              EntityType = "user"
 )
 
-// ActivityLog represents the activity_logs table
+// ActivityLog represents the activity_logs table. UserID is the numeric ID of the acting student or
+// admin, 0 for instructors and changes made by the system; ActorID and UserType identify every
+// authenticated actor.
 type ActivityLog struct {
 	ID          uint                   `gorm:"primaryKey;autoIncrement" json:"id"`
 	UserID      uint                   `gorm:"not null;index" json:"user_id"`
-	Action      ActivityAction         `gorm:"not null" json:"action"`
-	EntityType  EntityType             `gorm:"not null" json:"entity_type"`
-	EntityID    uint                   `json:"entity_id"`
+	ActorID     string                 `gorm:"column:actor_id;index" json:"actor_id"`
+	UserType    string                 `gorm:"column:user_type" json:"user_type"`
+	Action      ActivityAction         `gorm:"not null;index" json:"action"`
+	EntityType  EntityType             `gorm:"not null;index:idx_activity_logs_entity" json:"entity_type"`
+	EntityID    uint                   `gorm:"index:idx_activity_logs_entity" json:"entity_id"`
 	Description string                 `gorm:"type:text" json:"description"`
 	IPAddress   string                 `json:"ip_address"`
 	UserAgent   string                 `json:"user_agent"`
 	Metadata    json.RawMessage        `gorm:"type:json" json:"metadata"`
-	CreatedAt   time.Time              `gorm:"autoCreateTime" json:"created_at"`
-
-	// Relationships
-	User User `gorm:"foreignKey:UserID;references:StudentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+	CreatedAt   time.Time              `gorm:"autoCreateTime;index" json:"created_at"`
 }
 
 // TableName specifies the table name for ActivityLog model
@@ -165,8 +166,7 @@ func GetUserActivities(db *gorm.DB, userID uint, limit, offset int) ([]ActivityL
 // GetRecentActivities retrieves recent activities across all users
 func GetRecentActivities(db *gorm.DB, limit int) ([]ActivityLog, error) {
 	var activities []ActivityLog
-	err := db.Preload("User").
-		Order("created_at DESC").
+	err := db.Order("created_at DESC").
 		Limit(limit).
 		Find(&activities).Error
 	return activities, err
@@ -176,7 +176,6 @@ func GetRecentActivities(db *gorm.DB, limit int) ([]ActivityLog, error) {
 func GetActivitiesByEntity(db *gorm.DB, entityType EntityType, entityID uint, limit int) ([]ActivityLog, error) {
 	var activities []ActivityLog
 	err := db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
-		Preload("User").
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&activities).Error
@@ -187,7 +186,6 @@ func GetActivitiesByEntity(db *gorm.DB, entityType EntityType, entityID uint, li
 func GetActivitiesByAction(db *gorm.DB, action ActivityAction, limit int) ([]ActivityLog, error) {
 	var activities []ActivityLog
 	err := db.Where("action = ?", action).
-		Preload("User").
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&activities).Error
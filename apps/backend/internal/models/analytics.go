@@ -48,9 +48,10 @@ type Report struct {
 	Format      ReportFormat `gorm:"not null" json:"format"`
 	Status      ReportStatus `gorm:"not null;default:pending" json:"status"`
 	Parameters  json.RawMessage `gorm:"type:json" json:"parameters"`
-	FilePath    string       `json:"file_path"`
+	FilePath    string       `json:"-"`
 	FileSize    int64        `json:"file_size"`
-	GeneratedBy uint         `gorm:"not null" json:"generated_by"`
+	GeneratedBy uint         `gorm:"not null;index:idx_reports_generator" json:"generated_by"`
+	GeneratorType string     `gorm:"size:50;not null;default:User;index:idx_reports_generator" json:"generator_type"` // account type of GeneratedBy ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
 	GeneratedAt *time.Time   `json:"generated_at"`
 	ExpiresAt   *time.Time   `json:"expires_at"`
 	DownloadCount int        `gorm:"default:0" json:"download_count"`
@@ -75,7 +76,8 @@ type Dashboard struct {
 	Layout      json.RawMessage `gorm:"type:json;not null" json:"layout"`
 	IsDefault   bool            `gorm:"default:false" json:"is_default"`
 	IsPublic    bool            `gorm:"default:false" json:"is_public"`
-	OwnerID     uint            `gorm:"not null" json:"owner_id"`
+	OwnerID     uint            `gorm:"not null;index:idx_dashboards_owner" json:"owner_id"`
+	OwnerType   string          `gorm:"size:50;not null;default:User;index:idx_dashboards_owner" json:"owner_type"` // account type of OwnerID ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
 	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -92,7 +94,7 @@ func (Dashboard) TableName() string {
 // DashboardWidget represents the dashboard_widgets table
 type DashboardWidget struct {
 	ID          uint            `gorm:"primaryKey;autoIncrement" json:"id"`
-	DashboardID uint            `gorm:"not null" json:"dashboard_id"`
+	DashboardID uint            `gorm:"not null;index" json:"dashboard_id"`
 	WidgetType  string          `gorm:"not null" json:"widget_type"`
 	Title       string          `gorm:"not null" json:"title"`
 	Position    json.RawMessage `gorm:"type:json;not null" json:"position"`
@@ -123,6 +125,8 @@ type Metric struct {
 	Query       string          `gorm:"type:text" json:"query"`
 	Config      json.RawMessage `gorm:"type:json" json:"config"`
 	IsActive    bool            `gorm:"default:true" json:"is_active"`
+	CollectInterval int         `gorm:"default:0" json:"collect_interval"` // seconds between runs of Query, 0 when values are only recorded
+	LastCollectedAt *time.Time  `json:"last_collected_at"`
 	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -138,10 +142,10 @@ func (Metric) TableName() string {
 // MetricValue represents the metric_values table
 type MetricValue struct {
 	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	MetricID  uint      `gorm:"not null" json:"metric_id"`
+	MetricID  uint      `gorm:"not null;index:idx_metric_values_metric_time" json:"metric_id"`
 	Value     float64   `gorm:"not null" json:"value"`
 	Labels    json.RawMessage `gorm:"type:json" json:"labels"`
-	Timestamp time.Time `gorm:"not null" json:"timestamp"`
+	Timestamp time.Time `gorm:"not null;index:idx_metric_values_metric_time" json:"timestamp"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relationships
@@ -179,13 +183,14 @@ func (Analytics) TableName() string {
 type ExportJob struct {
 	ID          uint         `gorm:"primaryKey;autoIncrement" json:"id"`
 	JobType     string       `gorm:"not null" json:"job_type"`
-	Status      ReportStatus `gorm:"not null;default:pending" json:"status"`
+	Status      ReportStatus `gorm:"not null;default:pending;index" json:"status"`
 	Parameters  json.RawMessage `gorm:"type:json" json:"parameters"`
-	FilePath    string       `json:"file_path"`
+	FilePath    string       `json:"-"`
 	FileSize    int64        `json:"file_size"`
 	Progress    int          `gorm:"default:0" json:"progress"` // 0-100
 	ErrorMessage string      `gorm:"type:text" json:"error_message"`
-	RequestedBy uint         `gorm:"not null" json:"requested_by"`
+	RequestedBy uint         `gorm:"not null;index:idx_export_jobs_requester" json:"requested_by"`
+	RequesterType string     `gorm:"size:50;not null;default:User;index:idx_export_jobs_requester" json:"requester_type"` // account type of RequestedBy ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
 	StartedAt   *time.Time   `json:"started_at"`
 	CompletedAt *time.Time   `json:"completed_at"`
 	ExpiresAt   *time.Time   `json:"expires_at"`
@@ -305,6 +310,14 @@ func (r *Report) IsExpired() bool {
 	return r.ExpiresAt.Before(time.Now())
 }
 
+// IsExpired checks if the export file is past its retention period
+func (e *ExportJob) IsExpired() bool {
+	if e.ExpiresAt == nil {
+		return false
+	}
+	return e.ExpiresAt.Before(time.Now())
+}
+
 // GetTypeDisplayText returns Thai display text for report type
 func (r *Report) GetTypeDisplayText() string {
 	typeTexts := map[ReportType]string{
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApprovalStatusTransition represents the approval_status_transitions table, one row per entry of an
+// approval record's status history. The rows mirror InternshipApproval.StatusHistory so that time
+// spent in each status can be queried in SQL.
+type ApprovalStatusTransition struct {
+	ID              uint                     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ApprovalID      uint                     `gorm:"column:approval_id;not null;uniqueIndex:idx_approval_transition_sequence" json:"approval_id"`
+	Sequence        int                      `gorm:"not null;uniqueIndex:idx_approval_transition_sequence" json:"sequence"` // position in the status history
+	StudentEnrollID uint                     `gorm:"column:student_enroll_id;not null;index" json:"student_enroll_id"`
+	FromStatus      InternshipApprovalStatus `gorm:"column:from_status;not null;index" json:"from_status"`
+	ToStatus        InternshipApprovalStatus `gorm:"column:to_status;not null;index" json:"to_status"`
+	Action          string                   `gorm:"size:50" json:"action"` // workflow action, empty when no transition of the workflow matches
+	ChangedBy       uint                     `gorm:"column:changed_by" json:"changed_by"`
+	EnteredAt       time.Time                `gorm:"column:entered_at;not null" json:"entered_at"` // when the approval entered FromStatus
+	ChangedAt       time.Time                `gorm:"column:changed_at;not null;index" json:"changed_at"`
+	DurationSeconds int64                    `gorm:"column:duration_seconds;not null" json:"duration_seconds"` // time spent in FromStatus
+	Reason          string                   `gorm:"type:text" json:"reason"`
+	CreatedAt       time.Time                `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	Approval InternshipApproval `gorm:"foreignKey:ApprovalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for ApprovalStatusTransition model
+func (ApprovalStatusTransition) TableName() string {
+	return "approval_status_transitions"
+}
+
+// BuildStatusTransitions converts an approval record's status history into transition rows. The
+// first status is entered when the record is created; each later one when the previous transition
+// happened. Actions are looked up in the given workflow.
+func BuildStatusTransitions(approval *InternshipApproval, workflow *ApprovalWorkflow) ([]ApprovalStatusTransition, error) {
+	history, err := approval.GetStatusHistory()
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := workflow.GetTransitions()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ApprovalStatusTransition, 0, len(history))
+	enteredAt := approval.CreatedAt
+	for i, entry := range history {
+		if enteredAt.IsZero() || enteredAt.After(entry.ChangedAt) {
+			enteredAt = entry.ChangedAt
+		}
+
+		action := ""
+		for _, transition := range transitions {
+			if transition.From == entry.FromStatus && transition.To == entry.ToStatus {
+				action = transition.Action
+				break
+			}
+		}
+
+		rows = append(rows, ApprovalStatusTransition{
+			ApprovalID:      approval.ID,
+			Sequence:        i,
+			StudentEnrollID: approval.StudentEnrollID,
+			FromStatus:      entry.FromStatus,
+			ToStatus:        entry.ToStatus,
+			Action:          action,
+			ChangedBy:       entry.ChangedBy,
+			EnteredAt:       enteredAt,
+			ChangedAt:       entry.ChangedAt,
+			DurationSeconds: int64(entry.ChangedAt.Sub(enteredAt) / time.Second),
+			Reason:          entry.Reason,
+		})
+		enteredAt = entry.ChangedAt
+	}
+	return rows, nil
+}
+
+// SyncStatusTransitions stores the status history entries of an approval record that have no
+// transition row yet. It returns the number of rows created.
+func SyncStatusTransitions(tx *gorm.DB, approval *InternshipApproval) (int64, error) {
+	if approval.ID == 0 || len(approval.StatusHistory) == 0 {
+		return 0, nil
+	}
+
+	workflow := approval.Workflow
+	if workflow == nil && approval.WorkflowID != nil {
+		workflow = &ApprovalWorkflow{}
+		if err := tx.First(workflow, *approval.WorkflowID).Error; err != nil {
+			return 0, err
+		}
+	}
+	if workflow == nil {
+		workflow = DefaultApprovalWorkflow()
+	}
+
+	rows, err := BuildStatusTransitions(approval, workflow)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+	return result.RowsAffected, result.Error
+}
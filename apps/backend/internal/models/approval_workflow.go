@@ -0,0 +1,202 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Approval workflow actions with a dedicated endpoint. Advisor actions are taken through advisor
+// approval and committee actions by closing committee voting; any other action is taken through
+// the generic transition endpoint.
+const (
+	ActionAdvisorApprove   = "advisor_approve"
+	ActionAdvisorReject    = "advisor_reject"
+	ActionCommitteeApprove = "committee_approve"
+	ActionCommitteeReject  = "committee_reject"
+)
+
+// Approval workflow guard conditions
+const (
+	GuardAssignedAdvisor = "assigned_advisor" // the actor must be the enrollment's advisor
+	GuardCommitteeVote   = "committee_vote"   // only closing committee voting takes the transition
+	GuardRemarksRequired = "remarks_required" // the actor must give a reason
+	GuardHasTraining     = "has_training"     // the student must have a training placement
+)
+
+// WorkflowState represents a state of an approval workflow
+type WorkflowState struct {
+	Status InternshipApprovalStatus `json:"status"`
+	Label  string                   `json:"label"`
+	Final  bool                     `json:"final"`
+}
+
+// WorkflowTransition represents an action moving an approval from one state to another. Role names
+// the role the actor needs, empty for none; admins may take every transition their guards allow.
+type WorkflowTransition struct {
+	Action string                   `json:"action"`
+	From   InternshipApprovalStatus `json:"from"`
+	To     InternshipApprovalStatus `json:"to"`
+	Role   string                   `json:"role,omitempty"`
+	Guards []string                 `json:"guards,omitempty"`
+}
+
+// ApprovalWorkflow represents the approval_workflows table. A workflow applies to a course, to a
+// program, or as the default for enrollments without either. Approval records keep the workflow
+// they were created with; records without one follow DefaultApprovalWorkflow.
+type ApprovalWorkflow struct {
+	ID            uint                     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name          string                   `gorm:"not null" json:"name"`
+	Description   string                   `gorm:"type:text" json:"description"`
+	CourseID      *uint                    `gorm:"column:course_id;index" json:"course_id"`
+	ProgramID     *uint                    `gorm:"column:program_id;index" json:"program_id"`
+	IsDefault     bool                     `gorm:"column:is_default;not null" json:"is_default"`
+	IsActive      bool                     `gorm:"column:is_active;not null" json:"is_active"`
+	InitialStatus InternshipApprovalStatus `gorm:"column:initial_status;not null" json:"initial_status"`
+	States        json.RawMessage          `gorm:"type:json;not null" json:"states"`      // []WorkflowState
+	Transitions   json.RawMessage          `gorm:"type:json;not null" json:"transitions"` // []WorkflowTransition
+	CreatedBy     *uint                    `gorm:"column:created_by" json:"created_by"`
+	CreatedAt     time.Time                `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time                `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Course  *Course  `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"course,omitempty"`
+	Program *Program `gorm:"foreignKey:ProgramID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"program,omitempty"`
+}
+
+// TableName specifies the table name for ApprovalWorkflow model
+func (ApprovalWorkflow) TableName() string {
+	return "approval_workflows"
+}
+
+// GetStates returns parsed workflow states
+func (w *ApprovalWorkflow) GetStates() ([]WorkflowState, error) {
+	if w.States == nil {
+		return []WorkflowState{}, nil
+	}
+
+	var states []WorkflowState
+	if err := json.Unmarshal(w.States, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// SetStates sets workflow states as JSON
+func (w *ApprovalWorkflow) SetStates(states []WorkflowState) error {
+	data, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	w.States = data
+	return nil
+}
+
+// GetTransitions returns parsed workflow transitions
+func (w *ApprovalWorkflow) GetTransitions() ([]WorkflowTransition, error) {
+	if w.Transitions == nil {
+		return []WorkflowTransition{}, nil
+	}
+
+	var transitions []WorkflowTransition
+	if err := json.Unmarshal(w.Transitions, &transitions); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// SetTransitions sets workflow transitions as JSON
+func (w *ApprovalWorkflow) SetTransitions(transitions []WorkflowTransition) error {
+	data, err := json.Marshal(transitions)
+	if err != nil {
+		return err
+	}
+	w.Transitions = data
+	return nil
+}
+
+// FindTransition returns the transition an action takes from a status, or nil when the action is not
+// available there
+func (w *ApprovalWorkflow) FindTransition(from InternshipApprovalStatus, action string) (*WorkflowTransition, error) {
+	transitions, err := w.GetTransitions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range transitions {
+		if transitions[i].From == from && transitions[i].Action == action {
+			return &transitions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Allows reports whether any transition leads from one status to another
+func (w *ApprovalWorkflow) Allows(from, to InternshipApprovalStatus) (bool, error) {
+	transitions, err := w.GetTransitions()
+	if err != nil {
+		return false, err
+	}
+	for _, transition := range transitions {
+		if transition.From == from && transition.To == to {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AvailableTransitions returns the transitions leaving a status
+func (w *ApprovalWorkflow) AvailableTransitions(from InternshipApprovalStatus) ([]WorkflowTransition, error) {
+	transitions, err := w.GetTransitions()
+	if err != nil {
+		return nil, err
+	}
+	available := []WorkflowTransition{}
+	for _, transition := range transitions {
+		if transition.From == from {
+			available = append(available, transition)
+		}
+	}
+	return available, nil
+}
+
+// DefaultApprovalWorkflow returns the built-in workflow: advisor approval, committee voting, then
+// document approval by an admin. Approval records without a workflow follow it.
+func DefaultApprovalWorkflow() *ApprovalWorkflow {
+	workflow := &ApprovalWorkflow{
+		Name:          "Default internship approval",
+		IsDefault:     true,
+		IsActive:      true,
+		InitialStatus: StatusRegistered,
+	}
+
+	states := []WorkflowState{
+		{Status: StatusRegistered, Label: "ลงทะเบียนแล้ว"},
+		{Status: StatusPending, Label: "รอดำเนินการ"},
+		{Status: StatusTApproved, Label: "อนุมัติโดยอาจารย์ที่ปรึกษา"},
+		{Status: StatusCApproved, Label: "อนุมัติโดยคณะกรรมการ"},
+		{Status: StatusDocApproved, Label: "อนุมัติเอกสาร"},
+		{Status: StatusDocCancel, Label: "ยกเลิกเอกสาร", Final: true},
+		{Status: StatusApprove, Label: "อนุมัติ", Final: true},
+		{Status: StatusDenied, Label: "ปฏิเสธ"},
+	}
+
+	advisor := []string{GuardAssignedAdvisor}
+	committee := []string{GuardCommitteeVote}
+	transitions := []WorkflowTransition{
+		{Action: ActionAdvisorApprove, From: StatusRegistered, To: StatusTApproved, Guards: advisor},
+		{Action: ActionAdvisorReject, From: StatusRegistered, To: StatusDenied, Guards: advisor},
+		{Action: ActionAdvisorApprove, From: StatusPending, To: StatusTApproved, Guards: advisor},
+		{Action: ActionAdvisorReject, From: StatusPending, To: StatusDenied, Guards: advisor},
+		{Action: ActionCommitteeApprove, From: StatusTApproved, To: StatusCApproved, Guards: committee},
+		{Action: ActionCommitteeReject, From: StatusTApproved, To: StatusDenied, Guards: committee},
+		{Action: "approve_documents", From: StatusCApproved, To: StatusDocApproved, Role: RoleNameAdmin},
+		{Action: "cancel_documents", From: StatusCApproved, To: StatusDocCancel, Role: RoleNameAdmin},
+		{Action: "approve", From: StatusDocApproved, To: StatusApprove, Role: RoleNameAdmin},
+		{Action: "cancel_documents", From: StatusDocApproved, To: StatusDocCancel, Role: RoleNameAdmin},
+		{Action: "restart", From: StatusDenied, To: StatusRegistered, Role: RoleNameAdmin},
+	}
+
+	// The definition is static, so encoding cannot fail
+	_ = workflow.SetStates(states)
+	_ = workflow.SetTransitions(transitions)
+	return workflow
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+)
+
+// AttendanceEntryType represents the kind of day recorded on a time sheet
+type AttendanceEntryType string
+
+const (
+	AttendanceEntryWork   AttendanceEntryType = "work"
+	AttendanceEntryLeave  AttendanceEntryType = "leave"
+	AttendanceEntryAbsent AttendanceEntryType = "absent"
+)
+
+// LeaveType represents the reason for a leave day
+type LeaveType string
+
+const (
+	LeaveTypeSick     LeaveType = "sick"
+	LeaveTypePersonal LeaveType = "personal"
+	LeaveTypeVacation LeaveType = "vacation"
+	LeaveTypeHoliday  LeaveType = "holiday"
+	LeaveTypeOther    LeaveType = "other"
+)
+
+// TimesheetStatus represents the supervisor approval state of a weekly time sheet
+type TimesheetStatus string
+
+const (
+	TimesheetStatusDraft     TimesheetStatus = "draft"
+	TimesheetStatusSubmitted TimesheetStatus = "submitted"
+	TimesheetStatusApproved  TimesheetStatus = "approved"
+	TimesheetStatusRejected  TimesheetStatus = "rejected"
+)
+
+// AttendanceEntry represents the attendance_entries table.
+// There is at most one entry per student training and day; worked hours come from the
+// check-in and check-out times when both are given.
+type AttendanceEntry struct {
+	ID                uint                `gorm:"primaryKey;autoIncrement" json:"id"`
+	StudentTrainingID uint                `gorm:"column:student_training_id;not null;uniqueIndex:idx_attendance_entries_training_date" json:"student_training_id"`
+	TimesheetID       uint                `gorm:"column:timesheet_id;not null;index" json:"timesheet_id"`
+	WorkDate          time.Time           `gorm:"column:work_date;type:date;not null;uniqueIndex:idx_attendance_entries_training_date" json:"work_date"`
+	EntryType         AttendanceEntryType `gorm:"column:entry_type;type:varchar(20);not null;default:work" json:"entry_type"`
+	LeaveType         *LeaveType          `gorm:"column:leave_type;type:varchar(20)" json:"leave_type"`
+	CheckIn           *string             `gorm:"column:check_in;size:5" json:"check_in"`   // HH:MM
+	CheckOut          *string             `gorm:"column:check_out;size:5" json:"check_out"` // HH:MM
+	BreakMinutes      int                 `gorm:"column:break_minutes;default:0" json:"break_minutes"`
+	Hours             float64             `gorm:"not null;default:0" json:"hours"`
+	Notes             string              `gorm:"type:text" json:"notes"`
+	RecordedBy        *uint               `gorm:"column:recorded_by" json:"recorded_by"`
+	RecorderType      string              `gorm:"column:recorder_type;size:50" json:"recorder_type"` // account type of RecordedBy ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
+	CreatedAt         time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	StudentTraining StudentTraining `gorm:"foreignKey:StudentTrainingID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for AttendanceEntry model
+func (AttendanceEntry) TableName() string {
+	return "attendance_entries"
+}
+
+// Timesheet represents the timesheets table, one week of attendance for a student training.
+// Submitting a time sheet emails the company supervisor a review token they use to approve it
+// without an account; entries are locked while the time sheet is submitted or approved.
+type Timesheet struct {
+	ID                uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	StudentTrainingID uint            `gorm:"column:student_training_id;not null;uniqueIndex:idx_timesheets_training_week" json:"student_training_id"`
+	WeekStart         time.Time       `gorm:"column:week_start;type:date;not null;uniqueIndex:idx_timesheets_training_week" json:"week_start"` // Monday
+	Status            TimesheetStatus `gorm:"type:varchar(20);not null;default:draft;index" json:"status"`
+	ReviewToken       *string         `gorm:"column:review_token;size:64;uniqueIndex" json:"-"` // only ever sent to the supervisor
+	SubmittedAt       *time.Time      `gorm:"column:submitted_at" json:"submitted_at"`
+	ReviewedAt        *time.Time      `gorm:"column:reviewed_at" json:"reviewed_at"`
+	ReviewerName      string          `gorm:"column:reviewer_name;size:255" json:"reviewer_name"`
+	ReviewComment     string          `gorm:"column:review_comment;type:text" json:"review_comment"`
+	CreatedAt         time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	StudentTraining StudentTraining   `gorm:"foreignKey:StudentTrainingID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Entries         []AttendanceEntry `gorm:"foreignKey:TimesheetID" json:"entries,omitempty"`
+}
+
+// TableName specifies the table name for Timesheet model
+func (Timesheet) TableName() string {
+	return "timesheets"
+}
+
+// IsLocked reports whether entries of the time sheet can no longer change
+func (t *Timesheet) IsLocked() bool {
+	return t.Status == TimesheetStatusSubmitted || t.Status == TimesheetStatusApproved
+}
+
+// AttendanceRequirement represents the attendance_requirements table, the hours a course's
+// internships must reach. Co-op courses also set a minimum number of qualifying weeks, where a
+// week qualifies when its approved hours reach MinimumWeeklyHours.
+type AttendanceRequirement struct {
+	ID                 uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID           uint      `gorm:"column:course_id;not null;uniqueIndex" json:"course_id"`
+	RequiredHours      float64   `gorm:"column:required_hours;not null" json:"required_hours"`
+	MinimumWeeks       int       `gorm:"column:minimum_weeks;default:0" json:"minimum_weeks"`
+	MinimumWeeklyHours float64   `gorm:"column:minimum_weekly_hours;default:0" json:"minimum_weekly_hours"`
+	MaxDailyHours      float64   `gorm:"column:max_daily_hours;default:12" json:"max_daily_hours"`
+	CreatedAt          time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Course Course `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for AttendanceRequirement model
+func (AttendanceRequirement) TableName() string {
+	return "attendance_requirements"
+}
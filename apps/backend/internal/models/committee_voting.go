@@ -0,0 +1,140 @@
+package models
+
+import "time"
+
+// Committee vote values
+const (
+	VoteApprove = "approve"
+	VoteReject  = "reject"
+	VoteAbstain = "abstain"
+)
+
+// Committee member roles with a special meaning in voting
+const (
+	CommitteeRoleChair = "chair"
+)
+
+// CommitteeMajorityRule represents the share of decisive votes needed to approve an application
+type CommitteeMajorityRule string
+
+const (
+	MajoritySimple    CommitteeMajorityRule = "simple"     // more approvals than rejections
+	MajorityTwoThirds CommitteeMajorityRule = "two_thirds" // at least two thirds of the decisive votes
+)
+
+// CommitteeVotingPolicy represents the committee_voting_policies table. Courses without a policy
+// use DefaultCommitteeVotingPolicy.
+type CommitteeVotingPolicy struct {
+	ID               uint                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID         uint                  `gorm:"column:course_id;not null;uniqueIndex" json:"course_id"`
+	QuorumPercent    int                   `gorm:"column:quorum_percent;not null" json:"quorum_percent"` // share of members who must vote, abstentions included
+	MajorityRule     CommitteeMajorityRule `gorm:"column:majority_rule;not null" json:"majority_rule"`
+	AllowAbstain     bool                  `gorm:"column:allow_abstain;not null" json:"allow_abstain"`
+	ChairTieBreak    bool                  `gorm:"column:chair_tie_break;not null" json:"chair_tie_break"`
+	VotingPeriodDays int                   `gorm:"column:voting_period_days;not null" json:"voting_period_days"` // 0 keeps voting open until every member voted
+	UpdatedBy        *uint                 `gorm:"column:updated_by" json:"updated_by"`
+	CreatedAt        time.Time             `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Course Course `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for CommitteeVotingPolicy model
+func (CommitteeVotingPolicy) TableName() string {
+	return "committee_voting_policies"
+}
+
+// DefaultCommitteeVotingPolicy returns the policy used by courses that have not configured one
+func DefaultCommitteeVotingPolicy(courseID uint) *CommitteeVotingPolicy {
+	return &CommitteeVotingPolicy{
+		CourseID:         courseID,
+		QuorumPercent:    50,
+		MajorityRule:     MajoritySimple,
+		AllowAbstain:     true,
+		ChairTieBreak:    true,
+		VotingPeriodDays: 14,
+	}
+}
+
+// Deadline returns when voting that opens at start closes, or nil when the policy has no voting period
+func (p *CommitteeVotingPolicy) Deadline(start time.Time) *time.Time {
+	if p.VotingPeriodDays <= 0 {
+		return nil
+	}
+	deadline := start.AddDate(0, 0, p.VotingPeriodDays)
+	return &deadline
+}
+
+// CommitteeTally summarizes committee votes under a voting policy
+type CommitteeTally struct {
+	Members            int    `json:"members"`
+	Approve            int    `json:"approve"`
+	Reject             int    `json:"reject"`
+	Abstain            int    `json:"abstain"`
+	Pending            int    `json:"pending"`
+	QuorumMet          bool   `json:"quorum_met"`
+	ApprovalPercentage int    `json:"approval_percentage"` // approvals among decisive votes
+	Outcome            string `json:"outcome"`             // "approved" or "rejected" if voting closed now
+	TieBrokenByChair   bool   `json:"tie_broken_by_chair"`
+}
+
+// TallyCommitteeVotes counts the votes of the current committee members under a policy. Votes of
+// instructors who have left the committee are ignored. Under a simple majority a tie is settled by the
+// chair's own vote when the policy allows it; otherwise an application without the required majority
+// is rejected.
+func TallyCommitteeVotes(votes []CommitteeVote, members []CourseCommittee, policy *CommitteeVotingPolicy) CommitteeTally {
+	roles := make(map[uint]string, len(members))
+	for _, member := range members {
+		roles[member.InstructorID] = member.Role
+	}
+	tally := CommitteeTally{Members: len(roles)}
+
+	chairVote := ""
+	for _, vote := range votes {
+		role, isMember := roles[vote.InstructorID]
+		if !isMember {
+			continue
+		}
+		switch vote.Vote {
+		case VoteApprove:
+			tally.Approve++
+		case VoteReject:
+			tally.Reject++
+		case VoteAbstain:
+			tally.Abstain++
+		default:
+			continue
+		}
+		if role == CommitteeRoleChair {
+			chairVote = vote.Vote
+		}
+	}
+
+	cast := tally.Approve + tally.Reject + tally.Abstain
+	tally.Pending = tally.Members - cast
+	tally.QuorumMet = tally.Members > 0 && cast*100 >= policy.QuorumPercent*tally.Members
+
+	decisive := tally.Approve + tally.Reject
+	if decisive > 0 {
+		tally.ApprovalPercentage = tally.Approve * 100 / decisive
+	}
+
+	approved := false
+	switch policy.MajorityRule {
+	case MajorityTwoThirds:
+		approved = decisive > 0 && tally.Approve*3 >= decisive*2
+	default:
+		approved = tally.Approve > tally.Reject
+		if decisive > 0 && tally.Approve == tally.Reject && policy.ChairTieBreak && (chairVote == VoteApprove || chairVote == VoteReject) {
+			approved = chairVote == VoteApprove
+			tally.TieBrokenByChair = true
+		}
+	}
+
+	tally.Outcome = "rejected"
+	if approved {
+		tally.Outcome = "approved"
+	}
+	return tally
+}
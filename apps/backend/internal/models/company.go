@@ -19,6 +19,7 @@ type Company struct {
 	CompanyType            string `gorm:"column:company_type" json:"company_type"`
 	CreatedAt              time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt              time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt              gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relationships
 	CompanyPictures []CompanyPicture `gorm:"foreignKey:CompanyID" json:"company_pictures,omitempty"`
@@ -30,8 +31,13 @@ func (Company) TableName() string {
 	return "companies"
 }
 
-// BeforeDelete hook to clean up related records when company is deleted
+// BeforeDelete hook to clean up related records when company is permanently deleted. Pictures and
+// training references are kept while the company is in the recycle bin.
 func (c *Company) BeforeDelete(tx *gorm.DB) error {
+	if !tx.Statement.Unscoped {
+		return nil
+	}
+
 	// Delete all company pictures for this company
 	if err := tx.Where("company_id = ?", c.ID).Delete(&CompanyPicture{}).Error; err != nil {
 		return err
@@ -46,7 +46,9 @@ type Document struct {
 	Version           int            `gorm:"default:1" json:"version"`
 	StudentTrainingID *uint          `gorm:"column:student_training_id" json:"student_training_id"`
 	UploadedByID      uint           `gorm:"column:uploaded_by_id;not null" json:"uploaded_by_id"`
+	UploadedByType    string         `gorm:"column:uploaded_by_type;size:50;not null;default:User" json:"uploaded_by_type"` // account type of UploadedByID ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
 	ApprovedByID      *uint          `gorm:"column:approved_by_id" json:"approved_by_id"`
+	ApprovedByType    *string        `gorm:"column:approved_by_type;size:50" json:"approved_by_type"` // account type of ApprovedByID
 	ApprovedAt        *time.Time     `gorm:"column:approved_at" json:"approved_at"`
 	DueDate           *time.Time     `gorm:"column:due_date" json:"due_date"`
 	SubmittedAt       *time.Time     `gorm:"column:submitted_at" json:"submitted_at"`
@@ -71,6 +73,7 @@ type DocumentApproval struct {
 	ID           uint           `gorm:"primaryKey;autoIncrement" json:"id"`
 	DocumentID   uint           `gorm:"not null" json:"document_id"`
 	ApproverID   uint           `gorm:"not null" json:"approver_id"`
+	ApproverType string         `gorm:"size:50;not null;default:User" json:"approver_type"` // account type of ApproverID, the IDs overlap between account types
 	Status       DocumentStatus `gorm:"not null" json:"status"`
 	Comments     string         `gorm:"type:text" json:"comments"`
 	ApprovedAt   time.Time      `gorm:"autoCreateTime" json:"approved_at"`
@@ -92,6 +95,7 @@ type DocumentComment struct {
 	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
 	DocumentID uint      `gorm:"not null" json:"document_id"`
 	UserID     uint      `gorm:"not null" json:"user_id"`
+	UserType   string    `gorm:"size:50;not null;default:User" json:"user_type"` // account type of UserID, the IDs overlap between account types
 	Comment    string    `gorm:"type:text;not null" json:"comment"`
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
@@ -138,6 +142,41 @@ func (d *Document) BeforeDelete(tx *gorm.DB) error {
 	return nil
 }
 
+// CanTransitionTo checks if document status transition is valid
+func (d *Document) CanTransitionTo(newStatus DocumentStatus) bool {
+	validTransitions := map[DocumentStatus][]DocumentStatus{
+		DocStatusDraft:    {DocStatusPending, DocStatusArchived},
+		DocStatusPending:  {DocStatusApproved, DocStatusRejected, DocStatusRevision, DocStatusDraft},
+		DocStatusRevision: {DocStatusPending, DocStatusArchived},
+		DocStatusRejected: {DocStatusPending, DocStatusArchived},
+		DocStatusApproved: {DocStatusArchived},
+		DocStatusArchived: {}, // Final state
+	}
+
+	allowedTransitions, exists := validTransitions[d.Status]
+	if !exists {
+		return false
+	}
+
+	for _, allowed := range allowedTransitions {
+		if allowed == newStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOverdue checks if the document is past its due date without being approved
+func (d *Document) IsOverdue() bool {
+	if d.DueDate == nil {
+		return false
+	}
+	if d.Status == DocStatusApproved || d.Status == DocStatusArchived {
+		return false
+	}
+	return time.Now().After(*d.DueDate)
+}
+
 // GetStatusDisplayText returns Thai display text for document status
 func (d *Document) GetStatusDisplayText() string {
 	statusTexts := map[DocumentStatus]string{
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentModel(t *testing.T) {
+	t.Run("Document TableName", func(t *testing.T) {
+		document := Document{}
+		assert.Equal(t, "documents", document.TableName())
+	})
+
+	t.Run("CanTransitionTo Method", func(t *testing.T) {
+		draft := Document{Status: DocStatusDraft}
+		assert.True(t, draft.CanTransitionTo(DocStatusPending))
+		assert.False(t, draft.CanTransitionTo(DocStatusApproved))
+
+		pending := Document{Status: DocStatusPending}
+		assert.True(t, pending.CanTransitionTo(DocStatusApproved))
+		assert.True(t, pending.CanTransitionTo(DocStatusRejected))
+		assert.True(t, pending.CanTransitionTo(DocStatusRevision))
+
+		revision := Document{Status: DocStatusRevision}
+		assert.True(t, revision.CanTransitionTo(DocStatusPending))
+		assert.False(t, revision.CanTransitionTo(DocStatusApproved))
+
+		archived := Document{Status: DocStatusArchived}
+		assert.False(t, archived.CanTransitionTo(DocStatusPending))
+	})
+
+	t.Run("IsOverdue Method", func(t *testing.T) {
+		past := time.Now().Add(-24 * time.Hour)
+		future := time.Now().Add(24 * time.Hour)
+
+		assert.False(t, (&Document{Status: DocStatusPending}).IsOverdue())
+		assert.True(t, (&Document{Status: DocStatusPending, DueDate: &past}).IsOverdue())
+		assert.False(t, (&Document{Status: DocStatusPending, DueDate: &future}).IsOverdue())
+		assert.False(t, (&Document{Status: DocStatusApproved, DueDate: &past}).IsOverdue())
+	})
+}
@@ -3,8 +3,6 @@ package models
 import (
 	"encoding/json"
 	"time"
-
-	"gorm.io/gorm"
 )
 
 // EvaluationFormType represents the evaluation form type enum
@@ -30,6 +28,22 @@ const (
 	QuestionTypeScale    QuestionType = "scale"
 )
 
+// EvaluationSubmissionStatus represents the evaluation submission status enum
+type EvaluationSubmissionStatus string
+
+const (
+	SubmissionStatusDraft     EvaluationSubmissionStatus = "draft"
+	SubmissionStatusSubmitted EvaluationSubmissionStatus = "submitted"
+	SubmissionStatusReviewed  EvaluationSubmissionStatus = "reviewed"
+)
+
+// Legacy evaluation tables whose records are mirrored as evaluation submissions
+const (
+	LegacySourceVisitorEvaluateStudent = "visitor_evaluate_students"
+	LegacySourceVisitorEvaluateCompany = "visitor_evaluate_companies"
+	LegacySourceStudentEvaluateCompany = "student_evaluate_companies"
+)
+
 // EvaluationQuestion represents a single question in an evaluation form
 type EvaluationQuestion struct {
 	ID          string       `json:"id"`
@@ -51,21 +65,26 @@ type EvaluationAnswer struct {
 	Score      *float64    `json:"score,omitempty"`
 }
 
-// EvaluationForm represents the evaluation_forms table
+// EvaluationForm represents the evaluation_forms table. A form without a faculty is the
+// questionnaire used by faculties that have none of their own. Editing a form that already has
+// submissions creates a new version sharing the first version's ParentFormID.
 type EvaluationForm struct {
-	ID          uint               `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name        string             `gorm:"not null" json:"name"`
-	Description string             `gorm:"type:text" json:"description"`
-	FormType    EvaluationFormType `gorm:"not null" json:"form_type"`
-	Questions   json.RawMessage    `gorm:"type:json;not null" json:"questions"`
-	IsActive    bool               `gorm:"default:true" json:"is_active"`
-	Version     int                `gorm:"default:1" json:"version"`
-	CreatedBy   uint               `gorm:"not null" json:"created_by"`
-	CreatedAt   time.Time          `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           uint               `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string             `gorm:"not null" json:"name"`
+	Description  string             `gorm:"type:text" json:"description"`
+	FormType     EvaluationFormType `gorm:"not null" json:"form_type"`
+	FacultyID    *uint              `gorm:"column:faculty_id;index" json:"faculty_id"`
+	ParentFormID *uint              `gorm:"column:parent_form_id;index" json:"parent_form_id"` // first version of this form, nil on the first version
+	Questions    json.RawMessage    `gorm:"type:json;not null" json:"questions"`
+	IsActive     bool               `gorm:"default:true" json:"is_active"`
+	Version      int                `gorm:"default:1" json:"version"`
+	CreatedBy    uint               `gorm:"not null" json:"created_by"`
+	CreatedAt    time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relationships
-	Creator     User                `gorm:"foreignKey:CreatedBy;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"creator,omitempty"`
+	Creator     User                   `gorm:"foreignKey:CreatedBy;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"creator,omitempty"`
+	Faculty     *Faculty               `gorm:"foreignKey:FacultyID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"faculty,omitempty"`
 	Submissions []EvaluationSubmission `gorm:"foreignKey:FormID" json:"submissions,omitempty"`
 }
 
@@ -76,21 +95,23 @@ func (EvaluationForm) TableName() string {
 
 // EvaluationSubmission represents the evaluation_submissions table
 type EvaluationSubmission struct {
-	ID                uint            `gorm:"primaryKey;autoIncrement" json:"id"`
-	FormID            uint            `gorm:"not null" json:"form_id"`
-	StudentTrainingID uint            `gorm:"not null" json:"student_training_id"`
-	EvaluatorID       uint            `gorm:"not null" json:"evaluator_id"`
-	Answers           json.RawMessage `gorm:"type:json;not null" json:"answers"`
-	TotalScore        float64         `json:"total_score"`
-	MaxScore          float64         `json:"max_score"`
-	Percentage        float64         `json:"percentage"`
-	Comments          string          `gorm:"type:text" json:"comments"`
-	Status            string          `gorm:"default:draft" json:"status"` // draft, submitted, reviewed
-	SubmittedAt       *time.Time      `json:"submitted_at"`
-	ReviewedAt        *time.Time      `json:"reviewed_at"`
-	ReviewedBy        *uint           `json:"reviewed_by"`
-	CreatedAt         time.Time       `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt         time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                uint                       `gorm:"primaryKey;autoIncrement" json:"id"`
+	FormID            uint                       `gorm:"not null" json:"form_id"`
+	StudentTrainingID uint                       `gorm:"not null" json:"student_training_id"`
+	EvaluatorID       uint                       `gorm:"not null" json:"evaluator_id"`
+	Answers           json.RawMessage            `gorm:"type:json;not null" json:"answers"`
+	TotalScore        float64                    `json:"total_score"`
+	MaxScore          float64                    `json:"max_score"`
+	Percentage        float64                    `json:"percentage"`
+	Comments          string                     `gorm:"type:text" json:"comments"`
+	Status            EvaluationSubmissionStatus `gorm:"default:draft" json:"status"`
+	SubmittedAt       *time.Time                 `json:"submitted_at"`
+	ReviewedAt        *time.Time                 `json:"reviewed_at"`
+	ReviewedBy        *uint                      `json:"reviewed_by"`
+	LegacySource      *string                    `gorm:"column:legacy_source;size:50;uniqueIndex:idx_evaluation_submissions_legacy" json:"legacy_source,omitempty"` // table of the imported legacy record
+	LegacyID          *uint                      `gorm:"column:legacy_id;uniqueIndex:idx_evaluation_submissions_legacy" json:"legacy_id,omitempty"`
+	CreatedAt         time.Time                  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time                  `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relationships
 	Form            EvaluationForm  `gorm:"foreignKey:FormID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"form,omitempty"`
@@ -168,6 +189,19 @@ func (ef *EvaluationForm) SetQuestions(questions []EvaluationQuestion) error {
 	return nil
 }
 
+// IsScored reports whether answers to the question count toward the submission score
+func (q *EvaluationQuestion) IsScored() bool {
+	return q.Type == QuestionTypeRating || q.Type == QuestionTypeScale
+}
+
+// RootFormID returns the ID shared by every version of the form
+func (ef *EvaluationForm) RootFormID() uint {
+	if ef.ParentFormID != nil {
+		return *ef.ParentFormID
+	}
+	return ef.ID
+}
+
 // GetAnswers returns parsed answers from JSON
 func (es *EvaluationSubmission) GetAnswers() ([]EvaluationAnswer, error) {
 	if es.Answers == nil {
@@ -214,6 +248,9 @@ func (es *EvaluationSubmission) CalculateScore(form *EvaluationForm) error {
 
 	// Calculate scores based on questions and answers
 	for _, question := range questions {
+		if !question.IsScored() {
+			continue
+		}
 		if answer, exists := answerMap[question.ID]; exists {
 			if answer.Score != nil {
 				totalScore += *answer.Score * question.Weight
@@ -48,6 +48,23 @@ func (EvaluationStatusTracker) TableName() string {
 	return "evaluation_status_trackers"
 }
 
+// EvaluationReminder represents the evaluation_reminders table.
+// It records which due-date reminders were sent so each one goes out exactly once.
+type EvaluationReminder struct {
+	ID                        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EvaluationStatusTrackerID uint      `gorm:"column:evaluation_status_tracker_id;not null;uniqueIndex:idx_evaluation_reminder_once" json:"evaluation_status_tracker_id"`
+	DaysBefore                int       `gorm:"column:days_before;not null;uniqueIndex:idx_evaluation_reminder_once" json:"days_before"`
+	SentAt                    time.Time `gorm:"column:sent_at;not null" json:"sent_at"`
+
+	// Relationships
+	Tracker EvaluationStatusTracker `gorm:"foreignKey:EvaluationStatusTrackerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"tracker,omitempty"`
+}
+
+// TableName specifies the table name for EvaluationReminder model
+func (EvaluationReminder) TableName() string {
+	return "evaluation_reminders"
+}
+
 // IsOverdue checks if the evaluation is overdue
 func (est *EvaluationStatusTracker) IsOverdue() bool {
 	if est.DueDate == nil || est.Status == EvalStatusCompleted {
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GradeSource identifies where a grading component's score comes from
+type GradeSource string
+
+const (
+	GradeSourceVisitorEvaluation    GradeSource = "visitor_evaluation"    // visitor_evaluate_students scores
+	GradeSourceCompanyEvaluation    GradeSource = "company_evaluation"    // company_student evaluation form submissions
+	GradeSourceInstructorEvaluation GradeSource = "instructor_evaluation" // instructor_student evaluation form submissions
+	GradeSourceSelfEvaluation       GradeSource = "self_evaluation"       // student_self evaluation form submissions
+	GradeSourceAttendance           GradeSource = "attendance"            // approved hours against the course's required hours
+)
+
+// GradeSheetStatus represents the state of a section's grades
+type GradeSheetStatus string
+
+const (
+	GradeSheetStatusDraft     GradeSheetStatus = "draft"
+	GradeSheetStatusLocked    GradeSheetStatus = "locked"
+	GradeSheetStatusPublished GradeSheetStatus = "published"
+)
+
+// GradingComponent is one weighted part of a grading scheme. Weights of a scheme add up to 100.
+type GradingComponent struct {
+	Source GradeSource `json:"source"`
+	Weight float64     `json:"weight"`
+	Label  string      `json:"label,omitempty"`
+}
+
+// GradeBand maps a minimum percentage to a letter grade and its grade points
+type GradeBand struct {
+	Grade       string  `json:"grade"`
+	MinPercent  float64 `json:"min_percent"`
+	GradePoints float64 `json:"grade_points"`
+}
+
+// GradingScheme represents the grading_schemes table, how a course's final internship grade is
+// computed from evaluation scores
+type GradingScheme struct {
+	ID         uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID   uint            `gorm:"column:course_id;not null;uniqueIndex" json:"course_id"`
+	Components json.RawMessage `gorm:"type:json;not null" json:"components"`                     // []GradingComponent
+	GradeScale json.RawMessage `gorm:"column:grade_scale;type:json;not null" json:"grade_scale"` // []GradeBand, highest first
+	UpdatedBy  *uint           `gorm:"column:updated_by" json:"updated_by"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Course Course `gorm:"foreignKey:CourseID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for GradingScheme model
+func (GradingScheme) TableName() string {
+	return "grading_schemes"
+}
+
+// GetComponents returns the parsed scheme components
+func (gs *GradingScheme) GetComponents() ([]GradingComponent, error) {
+	var components []GradingComponent
+	if len(gs.Components) == 0 {
+		return components, nil
+	}
+	err := json.Unmarshal(gs.Components, &components)
+	return components, err
+}
+
+// GetGradeScale returns the parsed grade scale
+func (gs *GradingScheme) GetGradeScale() ([]GradeBand, error) {
+	var scale []GradeBand
+	if len(gs.GradeScale) == 0 {
+		return scale, nil
+	}
+	err := json.Unmarshal(gs.GradeScale, &scale)
+	return scale, err
+}
+
+// GradeSheet represents the grade_sheets table, the grading state of a course section.
+// Grades can be overridden while the sheet is a draft; locking snapshots the computed grades and
+// publishing writes them to the student enrollments.
+type GradeSheet struct {
+	ID              uint             `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseSectionID uint             `gorm:"column:course_section_id;not null;uniqueIndex" json:"course_section_id"`
+	Status          GradeSheetStatus `gorm:"type:varchar(20);not null;default:draft" json:"status"`
+	LockedBy        *uint            `gorm:"column:locked_by" json:"locked_by"`
+	LockedAt        *time.Time       `gorm:"column:locked_at" json:"locked_at"`
+	PublishedBy     *uint            `gorm:"column:published_by" json:"published_by"`
+	PublishedAt     *time.Time       `gorm:"column:published_at" json:"published_at"`
+	CreatedAt       time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	CourseSection CourseSection  `gorm:"foreignKey:CourseSectionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Grades        []StudentGrade `gorm:"foreignKey:GradeSheetID" json:"grades,omitempty"`
+}
+
+// TableName specifies the table name for GradeSheet model
+func (GradeSheet) TableName() string {
+	return "grade_sheets"
+}
+
+// IsLocked reports whether the sheet's grades can no longer change
+func (gs *GradeSheet) IsLocked() bool {
+	return gs.Status == GradeSheetStatusLocked || gs.Status == GradeSheetStatusPublished
+}
+
+// StudentGrade represents the student_grades table, the computed and final grade of one enrollment
+type StudentGrade struct {
+	ID                  uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	GradeSheetID        uint            `gorm:"column:grade_sheet_id;not null;index" json:"grade_sheet_id"`
+	StudentEnrollID     uint            `gorm:"column:student_enroll_id;not null;uniqueIndex" json:"student_enroll_id"`
+	ComputedPercent     *float64        `gorm:"column:computed_percent" json:"computed_percent"`
+	ComputedGrade       *string         `gorm:"column:computed_grade;size:5" json:"computed_grade"`
+	ComputedGradePoints *float64        `gorm:"column:computed_grade_points" json:"computed_grade_points"`
+	Breakdown           json.RawMessage `gorm:"type:json" json:"breakdown"`
+	OverrideGrade       *string         `gorm:"column:override_grade;size:5" json:"override_grade"`
+	OverrideGradePoints *float64        `gorm:"column:override_grade_points" json:"override_grade_points"`
+	OverrideReason      string          `gorm:"column:override_reason;type:text" json:"override_reason"`
+	OverriddenBy        *uint           `gorm:"column:overridden_by" json:"overridden_by"`
+	OverriddenAt        *time.Time      `gorm:"column:overridden_at" json:"overridden_at"`
+	CreatedAt           time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	StudentEnroll StudentEnroll `gorm:"foreignKey:StudentEnrollID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for StudentGrade model
+func (StudentGrade) TableName() string {
+	return "student_grades"
+}
@@ -24,7 +24,7 @@ const (
 // CommitteeVote represents a single committee member vote
 type CommitteeVote struct {
 	InstructorID uint      `json:"instructor_id"`
-	Vote         string    `json:"vote"` // "approve" or "reject"
+	Vote         string    `json:"vote"` // "approve", "reject" or "abstain"
 	Remarks      string    `json:"remarks"`
 	VotedAt      time.Time `json:"voted_at"`
 }
@@ -44,16 +44,19 @@ type InternshipApproval struct {
 	StudentEnrollID   uint                     `gorm:"column:student_enroll_id;not null;uniqueIndex" json:"student_enroll_id"`
 	Status            InternshipApprovalStatus `gorm:"not null;default:registered" json:"status"`
 	AdvisorID         *uint                    `gorm:"column:advisor_id" json:"advisor_id"`
+	WorkflowID        *uint                    `gorm:"column:workflow_id;index" json:"workflow_id"`
 	AdvisorApprovedAt *time.Time               `gorm:"column:advisor_approved_at" json:"advisor_approved_at"`
 	CommitteeVotes    json.RawMessage          `gorm:"type:json" json:"committee_votes"`
+	VotingDeadline    *time.Time               `gorm:"column:voting_deadline;index" json:"voting_deadline"`
 	StatusHistory     json.RawMessage          `gorm:"type:json" json:"status_history"`
 	Remarks           string                   `gorm:"type:text" json:"remarks"`
 	CreatedAt         time.Time                `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt         time.Time                `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relationships
-	StudentEnroll StudentEnroll `gorm:"foreignKey:StudentEnrollID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"student_enroll,omitempty"`
-	Advisor       *Instructor   `gorm:"foreignKey:AdvisorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"advisor,omitempty"`
+	StudentEnroll StudentEnroll     `gorm:"foreignKey:StudentEnrollID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"student_enroll,omitempty"`
+	Advisor       *Instructor       `gorm:"foreignKey:AdvisorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"advisor,omitempty"`
+	Workflow      *ApprovalWorkflow `gorm:"foreignKey:WorkflowID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"workflow,omitempty"`
 }
 
 // TableName specifies the table name for InternshipApproval model
@@ -128,30 +131,25 @@ func (ia *InternshipApproval) AddStatusTransition(fromStatus, toStatus Internshi
 	return ia.SetStatusHistory(history)
 }
 
-// CanTransitionTo checks if status transition is valid
-func (ia *InternshipApproval) CanTransitionTo(newStatus InternshipApprovalStatus) bool {
-	validTransitions := map[InternshipApprovalStatus][]InternshipApprovalStatus{
-		StatusRegistered:  {StatusTApproved, StatusDenied},
-		StatusTApproved:   {StatusCApproved, StatusDenied},
-		StatusCApproved:   {StatusDocApproved, StatusDocCancel},
-		StatusDocApproved: {StatusApprove, StatusDocCancel},
-		StatusDocCancel:   {}, // Final state
-		StatusApprove:     {}, // Final state
-		StatusDenied:      {StatusRegistered}, // Can restart process
-		StatusPending:     {StatusTApproved, StatusDenied},
-	}
+// AfterSave keeps the approval_status_transitions rows in step with the status history
+func (ia *InternshipApproval) AfterSave(tx *gorm.DB) error {
+	_, err := SyncStatusTransitions(tx.Session(&gorm.Session{NewDB: true}), ia)
+	return err
+}
 
-	allowedTransitions, exists := validTransitions[ia.Status]
-	if !exists {
-		return false
+// ApprovalWorkflow returns the workflow the record follows: its own workflow when one is loaded,
+// otherwise the built-in default
+func (ia *InternshipApproval) ApprovalWorkflow() *ApprovalWorkflow {
+	if ia.Workflow != nil {
+		return ia.Workflow
 	}
+	return DefaultApprovalWorkflow()
+}
 
-	for _, allowed := range allowedTransitions {
-		if allowed == newStatus {
-			return true
-		}
-	}
-	return false
+// CanTransitionTo checks if status transition is valid in the record's workflow
+func (ia *InternshipApproval) CanTransitionTo(newStatus InternshipApprovalStatus) bool {
+	allowed, err := ia.ApprovalWorkflow().Allows(ia.Status, newStatus)
+	return err == nil && allowed
 }
 
 // GetStatusDisplayText returns Thai display text for status
@@ -170,28 +168,41 @@ func (ia *InternshipApproval) GetStatusDisplayText() string {
 	if text, exists := statusTexts[ia.Status]; exists {
 		return text
 	}
+
+	// Statuses added by custom workflows carry their own labels
+	if states, err := ia.ApprovalWorkflow().GetStates(); err == nil {
+		for _, state := range states {
+			if state.Status == ia.Status && state.Label != "" {
+				return state.Label
+			}
+		}
+	}
 	return string(ia.Status)
 }
 
-// CalculateApprovalPercentage calculates approval percentage from committee votes
+// CalculateApprovalPercentage calculates approval percentage from committee votes, leaving out abstentions
 func (ia *InternshipApproval) CalculateApprovalPercentage() (int, error) {
 	votes, err := ia.GetCommitteeVotes()
 	if err != nil {
 		return 0, err
 	}
 
-	if len(votes) == 0 {
-		return 0, nil
-	}
-
 	approveCount := 0
+	decisiveCount := 0
 	for _, vote := range votes {
-		if vote.Vote == "approve" {
+		switch vote.Vote {
+		case VoteApprove:
 			approveCount++
+			decisiveCount++
+		case VoteReject:
+			decisiveCount++
 		}
 	}
 
-	return (approveCount * 100) / len(votes), nil
+	if decisiveCount == 0 {
+		return 0, nil
+	}
+	return (approveCount * 100) / decisiveCount, nil
 }
 
 // HasInstructorVoted checks if an instructor has already voted
@@ -227,6 +238,24 @@ func (ia *InternshipApproval) AddCommitteeVote(instructorID uint, vote, remarks
 	return ia.SetCommitteeVotes(votes)
 }
 
+// SetCommitteeVote records an instructor's vote, replacing the vote they cast earlier
+func (ia *InternshipApproval) SetCommitteeVote(instructorID uint, vote, remarks string) error {
+	votes, err := ia.GetCommitteeVotes()
+	if err != nil {
+		return err
+	}
+
+	for i := range votes {
+		if votes[i].InstructorID == instructorID {
+			votes[i].Vote = vote
+			votes[i].Remarks = remarks
+			votes[i].VotedAt = time.Now()
+			return ia.SetCommitteeVotes(votes)
+		}
+	}
+	return ia.AddCommitteeVote(instructorID, vote, remarks)
+}
+
 // GetApprovalByStudentEnrollID finds approval record by student enrollment ID
 func GetApprovalByStudentEnrollID(db *gorm.DB, studentEnrollID uint) (*InternshipApproval, error) {
 	var approval InternshipApproval
@@ -235,6 +264,7 @@ func GetApprovalByStudentEnrollID(db *gorm.DB, studentEnrollID uint) (*Internshi
 		Preload("StudentEnroll.Student").
 		Preload("StudentEnroll.CourseSection").
 		Preload("Advisor").
+		Preload("Workflow").
 		First(&approval).Error
 	
 	if err != nil {
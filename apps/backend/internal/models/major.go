@@ -11,6 +11,7 @@ type Major struct {
 	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
 	MajorNameEN string `gorm:"column:major_name_en;not null" json:"major_name_en"`
 	MajorNameTH string `gorm:"column:major_name_th;not null" json:"major_name_th"`
+	Code        *string `gorm:"size:20;index" json:"code"` // registrar code, unique within the program
 	CurriculumID uint   `gorm:"column:curriculum_id;not null" json:"curriculum_id"`
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
@@ -9,11 +9,14 @@ func AllModels() []interface{} {
 		&Role{},
 		&Permission{},
 		&User{},
+		&UserRole{},
 		
 		// Enhanced Authentication System
 		&SuperAdmin{},
 		&AccessToken{},
 		&SecurityLog{},
+		&TwoFactorCredential{},
+		&TwoFactorBackupCode{},
 		
 		// Organizational structure
 		&Campus{},
@@ -33,12 +36,20 @@ func AllModels() []interface{} {
 		&StudentEnroll{},
 		&CourseInstructor{},
 		&CourseCommittee{},
+		&CommitteeVotingPolicy{},
+		&ApprovalWorkflow{},
 		&StudentEnrollStatus{},
+		&GradingScheme{},
+		&GradeSheet{},
+		&StudentGrade{},
 		
 		// Company and training
 		&Company{},
 		&CompanyPicture{},
 		&StudentTraining{},
+		&Timesheet{},
+		&AttendanceEntry{},
+		&AttendanceRequirement{},
 		
 		// Visitor and evaluation system
 		&Visitor{},
@@ -54,7 +65,36 @@ func AllModels() []interface{} {
 		
 		// Approval and evaluation tracking models
 		&InternshipApproval{},
+		&ApprovalStatusTransition{},
+		&ActivityLog{},
 		&EvaluationStatusTracker{},
+		&EvaluationReminder{},
+		&EvaluationForm{},
+		&EvaluationSubmission{},
+
+		// Document management
+		&Document{},
+		&DocumentApproval{},
+		&DocumentComment{},
+		&DocumentTemplate{},
+
+		// Schedule management
+		&Schedule{},
+		&ScheduleParticipant{},
+		&ScheduleNotification{},
+		&Appointment{},
+		&Calendar{},
+		&CalendarSchedule{},
+		&ScheduleException{},
+		&CalendarSubscription{},
+
+		// Analytics and exports
+		&Dashboard{},
+		&DashboardWidget{},
+		&Metric{},
+		&MetricValue{},
+		&Report{},
+		&ExportJob{},
 	}
 }
 
@@ -11,6 +11,7 @@ type Program struct {
 	ID            uint   `gorm:"primaryKey;autoIncrement" json:"id"`
 	ProgramNameEN string `gorm:"column:program_name_en;not null" json:"program_name_en"`
 	ProgramNameTH string `gorm:"column:program_name_th;not null" json:"program_name_th"`
+	Code          *string `gorm:"size:20;index" json:"code"` // registrar code, unique within the faculty
 	FacultyID     uint   `gorm:"column:faculty_id;not null" json:"faculty_id"`
 	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
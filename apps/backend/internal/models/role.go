@@ -34,10 +34,14 @@ func (r *Role) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// BeforeDelete hook to clean up role permissions when role is deleted
+// BeforeDelete hook to clean up role permissions and assignments when role is deleted
 func (r *Role) BeforeDelete(tx *gorm.DB) error {
 	// Delete all role permissions for this role
-	return tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", r.ID).Error
+	if err := tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", r.ID).Error; err != nil {
+		return err
+	}
+	// Delete all user assignments of this role
+	return tx.Exec("DELETE FROM user_roles WHERE role_id = ?", r.ID).Error
 }
 
 // IsActiveRole checks if the role is active
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// RolePermission represents the role_permissions join table
+type RolePermission struct {
+	RoleID       uint `gorm:"primaryKey;column:role_id" json:"role_id"`
+	PermissionID uint `gorm:"primaryKey;column:permission_id" json:"permission_id"`
+}
+
+// TableName specifies the table name for RolePermission model
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// Built-in role names. Roles with these names are granted implicitly from the
+// account type, so they only need rows in the roles table to attach permissions.
+const (
+	RoleNameAdmin      = "admin"
+	RoleNameInstructor = "instructor"
+	RoleNameStudent    = "student"
+)
+
+// UserRole assigns a role to a user or super admin (polymorphic, like access tokens)
+type UserRole struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserType  string    `gorm:"column:user_type;size:50;not null;uniqueIndex:idx_user_role_assignment" json:"user_type"`
+	UserID    string    `gorm:"column:user_id;size:255;not null;uniqueIndex:idx_user_role_assignment" json:"user_id"`
+	RoleID    uint      `gorm:"column:role_id;not null;uniqueIndex:idx_user_role_assignment" json:"role_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Role Role `gorm:"foreignKey:RoleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"role,omitempty"`
+}
+
+// TableName specifies the table name for UserRole model
+func (UserRole) TableName() string {
+	return "user_roles"
+}
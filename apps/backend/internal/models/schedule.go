@@ -40,6 +40,20 @@ const (
 	RecurrenceYearly  RecurrenceType = "yearly"
 )
 
+// Schedule participant roles
+const (
+	ParticipantRoleOrganizer   = "organizer"
+	ParticipantRoleParticipant = "participant"
+	ParticipantRoleOptional    = "optional"
+)
+
+// Schedule participant response statuses
+const (
+	ParticipantStatusPending  = "pending"
+	ParticipantStatusAccepted = "accepted"
+	ParticipantStatusDeclined = "declined"
+)
+
 // Schedule represents the schedules table
 type Schedule struct {
 	ID                uint           `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -54,15 +68,17 @@ type Schedule struct {
 	RecurrenceType    RecurrenceType `gorm:"default:none" json:"recurrence_type"`
 	RecurrenceEnd     *time.Time     `json:"recurrence_end"`
 	StudentTrainingID *uint          `json:"student_training_id"`
+	ICalUID           *string        `gorm:"column:ical_uid;index" json:"ical_uid,omitempty"` // UID of the VEVENT the schedule was imported from
 	CreatedBy         uint           `gorm:"not null" json:"created_by"`
+	CreatorType       string         `gorm:"size:50;not null;default:User" json:"creator_type"` // account type of CreatedBy ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
 	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relationships
 	StudentTraining *StudentTraining    `gorm:"foreignKey:StudentTrainingID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"student_training,omitempty"`
-	Creator         User                `gorm:"foreignKey:CreatedBy;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"creator,omitempty"`
 	Participants    []ScheduleParticipant `gorm:"foreignKey:ScheduleID" json:"participants,omitempty"`
 	Notifications   []ScheduleNotification `gorm:"foreignKey:ScheduleID" json:"notifications,omitempty"`
+	Exceptions      []ScheduleException    `gorm:"foreignKey:ScheduleID" json:"exceptions,omitempty"`
 }
 
 // TableName specifies the table name for Schedule model
@@ -75,6 +91,7 @@ type ScheduleParticipant struct {
 	ID         uint   `gorm:"primaryKey;autoIncrement" json:"id"`
 	ScheduleID uint   `gorm:"not null" json:"schedule_id"`
 	UserID     uint   `gorm:"not null" json:"user_id"`
+	UserType   string `gorm:"size:50;not null;default:User" json:"user_type"` // account type of UserID, the IDs overlap between account types
 	Role       string `gorm:"not null" json:"role"` // organizer, participant, optional
 	Status     string `gorm:"default:pending" json:"status"` // pending, accepted, declined
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
@@ -82,7 +99,6 @@ type ScheduleParticipant struct {
 
 	// Relationships
 	Schedule Schedule `gorm:"foreignKey:ScheduleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"schedule,omitempty"`
-	User     User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
 }
 
 // TableName specifies the table name for ScheduleParticipant model
@@ -95,6 +111,7 @@ type ScheduleNotification struct {
 	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
 	ScheduleID   uint      `gorm:"not null" json:"schedule_id"`
 	UserID       uint      `gorm:"not null" json:"user_id"`
+	UserType     string    `gorm:"size:50;not null;default:User" json:"user_type"` // account type of UserID
 	NotifyBefore int       `gorm:"not null" json:"notify_before"` // minutes before event
 	NotifyAt     time.Time `gorm:"not null" json:"notify_at"`
 	IsSent       bool      `gorm:"default:false" json:"is_sent"`
@@ -104,7 +121,6 @@ type ScheduleNotification struct {
 
 	// Relationships
 	Schedule Schedule `gorm:"foreignKey:ScheduleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"schedule,omitempty"`
-	User     User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
 }
 
 // TableName specifies the table name for ScheduleNotification model
@@ -112,6 +128,36 @@ func (ScheduleNotification) TableName() string {
 	return "schedule_notifications"
 }
 
+// ScheduleException represents the schedule_exceptions table.
+// It cancels or moves a single occurrence of a recurring schedule without touching the series.
+type ScheduleException struct {
+	ID              uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduleID      uint       `gorm:"not null;uniqueIndex:idx_schedule_exception_occurrence" json:"schedule_id"`
+	OccurrenceStart time.Time  `gorm:"not null;uniqueIndex:idx_schedule_exception_occurrence" json:"occurrence_start"` // original start of the occurrence
+	IsCancelled     bool       `gorm:"default:false" json:"is_cancelled"`
+	NewStartTime    *time.Time `json:"new_start_time"`
+	NewEndTime      *time.Time `json:"new_end_time"`
+	Location        *string    `json:"location"`
+	Reason          string     `gorm:"type:text" json:"reason"`
+	CreatedBy       uint       `gorm:"not null" json:"created_by"`
+	CreatorType     string     `gorm:"size:50;not null;default:User" json:"creator_type"` // account type of CreatedBy
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Schedule Schedule `gorm:"foreignKey:ScheduleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"schedule,omitempty"`
+}
+
+// TableName specifies the table name for ScheduleException model
+func (ScheduleException) TableName() string {
+	return "schedule_exceptions"
+}
+
+// IsMoved checks if the exception reschedules the occurrence
+func (e *ScheduleException) IsMoved() bool {
+	return !e.IsCancelled && e.NewStartTime != nil && e.NewEndTime != nil
+}
+
 // Appointment represents the appointments table
 type Appointment struct {
 	ID                uint           `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -123,7 +169,9 @@ type Appointment struct {
 	Status            ScheduleStatus `gorm:"not null;default:scheduled" json:"status"`
 	StudentTrainingID *uint          `json:"student_training_id"`
 	RequestedBy       uint           `gorm:"not null" json:"requested_by"`
+	RequesterType     string         `gorm:"size:50;not null;default:User" json:"requester_type"` // account type of RequestedBy ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
 	ApprovedBy        *uint          `json:"approved_by"`
+	ApproverType      *string        `gorm:"size:50" json:"approver_type"` // account type of ApprovedBy
 	ApprovedAt        *time.Time     `json:"approved_at"`
 	Notes             string         `gorm:"type:text" json:"notes"`
 	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
@@ -131,8 +179,6 @@ type Appointment struct {
 
 	// Relationships
 	StudentTraining *StudentTraining `gorm:"foreignKey:StudentTrainingID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"student_training,omitempty"`
-	Requester       User             `gorm:"foreignKey:RequestedBy;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"requester,omitempty"`
-	Approver        *User            `gorm:"foreignKey:ApprovedBy;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"approver,omitempty"`
 }
 
 // TableName specifies the table name for Appointment model
@@ -148,11 +194,11 @@ type Calendar struct {
 	Color       string    `gorm:"default:#3498db" json:"color"`
 	IsPublic    bool      `gorm:"default:false" json:"is_public"`
 	OwnerID     uint      `gorm:"not null" json:"owner_id"`
+	OwnerType   string    `gorm:"size:50;not null;default:User" json:"owner_type"` // account type of OwnerID ("User", "Instructor", "Staff" or "SuperAdmin"), the IDs overlap between types
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relationships
-	Owner     User       `gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"owner,omitempty"`
 	Schedules []Schedule `gorm:"many2many:calendar_schedules" json:"schedules,omitempty"`
 }
 
@@ -177,6 +223,23 @@ func (CalendarSchedule) TableName() string {
 	return "calendar_schedules"
 }
 
+// CalendarSubscription represents the calendar_subscriptions table.
+// The token authenticates a user's personal iCalendar feed for external calendar clients.
+type CalendarSubscription struct {
+	ID             uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserType       string     `gorm:"size:50;not null;default:User;uniqueIndex:idx_calendar_subscription_owner" json:"user_type"` // account type of UserID, the IDs overlap between account types
+	UserID         uint       `gorm:"not null;uniqueIndex:idx_calendar_subscription_owner" json:"user_id"`
+	Token          string     `gorm:"not null;uniqueIndex;size:64" json:"token"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for CalendarSubscription model
+func (CalendarSubscription) TableName() string {
+	return "calendar_subscriptions"
+}
+
 // BeforeDelete hook to clean up related records when schedule is deleted
 func (s *Schedule) BeforeDelete(tx *gorm.DB) error {
 	// Delete all schedule participants
@@ -194,6 +257,11 @@ func (s *Schedule) BeforeDelete(tx *gorm.DB) error {
 		return err
 	}
 
+	// Delete occurrence exceptions
+	if err := tx.Where("schedule_id = ?", s.ID).Delete(&ScheduleException{}).Error; err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -238,4 +306,120 @@ func (s *Schedule) IsUpcoming() bool {
 // IsOverdue checks if the schedule is overdue
 func (s *Schedule) IsOverdue() bool {
 	return s.EndTime.Before(time.Now()) && s.Status != ScheduleStatusCompleted && s.Status != ScheduleStatusCancelled
-}
\ No newline at end of file
+}
+
+// IsRecurring checks if the schedule repeats
+func (s *Schedule) IsRecurring() bool {
+	return s.RecurrenceType != "" && s.RecurrenceType != RecurrenceNone
+}
+
+// Duration returns the length of a single occurrence
+func (s *Schedule) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// maxScheduleOccurrences bounds expansion of a single series
+const maxScheduleOccurrences = 1000
+
+// Occurrences returns the start times of every occurrence that overlaps [from, to].
+// Monthly and yearly series skip periods where the start day does not exist
+// (e.g. the 31st in a 30-day month), matching RFC 5545 behaviour.
+func (s *Schedule) Occurrences(from, to time.Time) []time.Time {
+	duration := s.Duration()
+	overlaps := func(start time.Time) bool {
+		return !start.After(to) && (!start.Before(from) || start.Add(duration).After(from))
+	}
+
+	if !s.IsRecurring() {
+		if overlaps(s.StartTime) {
+			return []time.Time{s.StartTime}
+		}
+		return nil
+	}
+
+	var occurrences []time.Time
+	for i := s.firstPeriodBefore(from.Add(-duration)); ; i++ {
+		start, ok := s.nthOccurrence(i)
+		if start.After(to) {
+			break
+		}
+		if s.RecurrenceEnd != nil && start.After(*s.RecurrenceEnd) {
+			break
+		}
+		if !ok {
+			continue
+		}
+		if overlaps(start) {
+			occurrences = append(occurrences, start)
+			if len(occurrences) >= maxScheduleOccurrences {
+				break
+			}
+		}
+	}
+
+	return occurrences
+}
+
+// IsOccurrence checks if the given time is the start of an occurrence of the series
+func (s *Schedule) IsOccurrence(start time.Time) bool {
+	for _, occurrence := range s.Occurrences(start, start) {
+		if occurrence.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstPeriodBefore returns an index of a period starting no later than t, so expansion
+// of long-running series does not have to walk from the very first occurrence
+func (s *Schedule) firstPeriodBefore(t time.Time) int {
+	if !t.After(s.StartTime) {
+		return 0
+	}
+
+	var n int
+	switch s.RecurrenceType {
+	case RecurrenceDaily:
+		n = int(t.Sub(s.StartTime) / (24 * time.Hour))
+	case RecurrenceWeekly:
+		n = int(t.Sub(s.StartTime) / (7 * 24 * time.Hour))
+	case RecurrenceMonthly:
+		n = (t.Year()-s.StartTime.Year())*12 + int(t.Month()-s.StartTime.Month())
+	case RecurrenceYearly:
+		n = t.Year() - s.StartTime.Year()
+	}
+
+	// Step back one period to absorb DST shifts and month length differences
+	if n > 0 {
+		n--
+	}
+	return n
+}
+
+// nthOccurrence returns the start of the n-th period of the series and whether it is a valid occurrence
+func (s *Schedule) nthOccurrence(n int) (time.Time, bool) {
+	switch s.RecurrenceType {
+	case RecurrenceDaily:
+		return s.StartTime.AddDate(0, 0, n), true
+	case RecurrenceWeekly:
+		return s.StartTime.AddDate(0, 0, 7*n), true
+	case RecurrenceMonthly:
+		start := s.StartTime.AddDate(0, n, 0)
+		return start, start.Day() == s.StartTime.Day()
+	case RecurrenceYearly:
+		start := s.StartTime.AddDate(n, 0, 0)
+		return start, start.Day() == s.StartTime.Day()
+	default:
+		return s.StartTime, n == 0
+	}
+}
+
+// GetEndTime returns the time the appointment finishes
+func (a *Appointment) GetEndTime() time.Time {
+	return a.AppointmentDate.Add(time.Duration(a.Duration) * time.Minute)
+}
+
+// IsApproved checks if the appointment has been approved
+func (a *Appointment) IsApproved() bool {
+	return a.ApprovedBy != nil && a.ApprovedAt != nil
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleModel(t *testing.T) {
+	t.Run("TableNames", func(t *testing.T) {
+		assert.Equal(t, "schedules", Schedule{}.TableName())
+		assert.Equal(t, "schedule_exceptions", ScheduleException{}.TableName())
+	})
+
+	t.Run("Occurrences Non-Recurring", func(t *testing.T) {
+		start := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+		schedule := Schedule{StartTime: start, EndTime: start.Add(time.Hour), RecurrenceType: RecurrenceNone}
+
+		assert.Equal(t, []time.Time{start}, schedule.Occurrences(start.Add(-time.Hour), start.Add(2*time.Hour)))
+		assert.Equal(t, []time.Time{start}, schedule.Occurrences(start.Add(30*time.Minute), start.Add(2*time.Hour)))
+		assert.Empty(t, schedule.Occurrences(start.Add(time.Hour), start.Add(2*time.Hour)))
+	})
+
+	t.Run("Occurrences Weekly", func(t *testing.T) {
+		start := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+		schedule := Schedule{StartTime: start, EndTime: start.Add(time.Hour), RecurrenceType: RecurrenceWeekly, RecurrenceEnd: &end}
+
+		occurrences := schedule.Occurrences(time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 6, 17, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 6, 24, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC),
+		}, occurrences)
+	})
+
+	t.Run("Occurrences Daily Far From Start", func(t *testing.T) {
+		start := time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC)
+		schedule := Schedule{StartTime: start, EndTime: start.Add(time.Hour), RecurrenceType: RecurrenceDaily}
+
+		occurrences := schedule.Occurrences(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 23, 0, 0, 0, time.UTC))
+		assert.Len(t, occurrences, 3)
+		assert.Equal(t, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), occurrences[0])
+	})
+
+	t.Run("Occurrences Monthly Skips Short Months", func(t *testing.T) {
+		start := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+		schedule := Schedule{StartTime: start, EndTime: start.Add(time.Hour), RecurrenceType: RecurrenceMonthly}
+
+		occurrences := schedule.Occurrences(start, time.Date(2024, 5, 31, 23, 0, 0, 0, time.UTC))
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 31, 10, 0, 0, 0, time.UTC),
+		}, occurrences)
+	})
+
+	t.Run("IsOccurrence Method", func(t *testing.T) {
+		start := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+		schedule := Schedule{StartTime: start, EndTime: start.Add(time.Hour), RecurrenceType: RecurrenceWeekly}
+
+		assert.True(t, schedule.IsOccurrence(start.AddDate(0, 0, 14)))
+		assert.False(t, schedule.IsOccurrence(start.AddDate(0, 0, 15)))
+		assert.False(t, schedule.IsOccurrence(start.AddDate(0, 0, -7)))
+	})
+
+	t.Run("ScheduleException IsMoved", func(t *testing.T) {
+		moved := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+		movedEnd := moved.Add(time.Hour)
+
+		assert.True(t, (&ScheduleException{NewStartTime: &moved, NewEndTime: &movedEnd}).IsMoved())
+		assert.False(t, (&ScheduleException{IsCancelled: true, NewStartTime: &moved, NewEndTime: &movedEnd}).IsMoved())
+		assert.False(t, (&ScheduleException{}).IsMoved())
+	})
+}
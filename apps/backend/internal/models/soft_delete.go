@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SoftDeleteSession returns a session whose soft deletes all get the same deleted_at, including
+// the ones cascaded by BeforeDelete hooks, so a record can be restored together with the
+// dependents deleted along with it
+func SoftDeleteSession(db *gorm.DB) *gorm.DB {
+	now := db.NowFunc()
+	return db.Session(&gorm.Session{NowFunc: func() time.Time { return now }})
+}
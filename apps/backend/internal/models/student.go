@@ -24,6 +24,7 @@ type Student struct {
 	CampusID     uint    `gorm:"column:campus_id;not null" json:"campus_id"`
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relationships
 	User       User        `gorm:"foreignKey:StudentID;references:StudentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
@@ -53,11 +54,20 @@ func (s *Student) GetFullName() string {
 	return fullName
 }
 
-// BeforeDelete hook to clean up related records when student is deleted
+// BeforeDelete hook to clean up related records when student is deleted. A soft delete moves the
+// student's enrollments to the recycle bin with it; a permanent delete removes them.
 func (s *Student) BeforeDelete(tx *gorm.DB) error {
-	// Delete all student enrollments for this student
-	if err := tx.Where("student_id = ?", s.ID).Delete(&StudentEnroll{}).Error; err != nil {
+	if tx.Statement.Unscoped {
+		return tx.Unscoped().Where("student_id = ?", s.ID).Delete(&StudentEnroll{}).Error
+	}
+
+	// Enrollments are deleted one by one so their own hook moves their trainings along
+	var enrollments []StudentEnroll
+	if err := tx.Where("student_id = ?", s.ID).Find(&enrollments).Error; err != nil {
 		return err
 	}
-	return nil
+	if len(enrollments) == 0 {
+		return nil
+	}
+	return tx.Delete(&enrollments).Error
 }
\ No newline at end of file
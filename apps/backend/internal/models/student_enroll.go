@@ -17,6 +17,7 @@ type StudentEnroll struct {
 	GradePoints     *float64  `gorm:"column:grade_points" json:"grade_points"`
 	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relationships
 	Student       Student       `gorm:"foreignKey:StudentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"student,omitempty"`
@@ -34,4 +35,13 @@ func (se *StudentEnroll) BeforeCreate(tx *gorm.DB) error {
 		se.EnrollDate = time.Now()
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// BeforeDelete hook to move the enrollment's trainings to the recycle bin with it. A permanent
+// delete leaves them to the ON DELETE CASCADE constraint.
+func (se *StudentEnroll) BeforeDelete(tx *gorm.DB) error {
+	if tx.Statement.Unscoped {
+		return nil
+	}
+	return tx.Where("student_enroll_id = ?", se.ID).Delete(&StudentTraining{}).Error
+}
@@ -33,6 +33,7 @@ type StudentTraining struct {
 	CompanyID                *uint            `gorm:"column:company_id" json:"company_id"`
 	CreatedAt                time.Time        `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt                time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt                gorm.DeletedAt   `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relationships
 	StudentEnroll           StudentEnroll             `gorm:"foreignKey:StudentEnrollID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"student_enroll,omitempty"`
@@ -46,8 +47,13 @@ func (StudentTraining) TableName() string {
 	return "student_trainings"
 }
 
-// BeforeDelete hook to clean up related records when student training is deleted
+// BeforeDelete hook to clean up related records when student training is permanently deleted.
+// Evaluations are kept while the training is in the recycle bin.
 func (st *StudentTraining) BeforeDelete(tx *gorm.DB) error {
+	if !tx.Statement.Unscoped {
+		return nil
+	}
+
 	// Delete all student evaluations for this training
 	if err := tx.Where("student_training_id = ?", st.ID).Delete(&StudentEvaluateCompany{}).Error; err != nil {
 		return err
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// TwoFactorCredential stores the TOTP secret of a user or super admin (polymorphic, like access tokens).
+// A credential is created when enrollment starts and only enforced once EnabledAt is set.
+type TwoFactorCredential struct {
+	ID              uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserType        string     `gorm:"column:user_type;size:50;not null;uniqueIndex:idx_two_factor_principal" json:"user_type"` // "User" or "SuperAdmin"
+	UserID          string     `gorm:"column:user_id;size:50;not null;uniqueIndex:idx_two_factor_principal" json:"user_id"`     // student_id or admin id
+	SecretEncrypted string     `gorm:"column:secret_encrypted;size:255;not null" json:"-"`                                      // AES-256-GCM, base64
+	EnabledAt       *time.Time `gorm:"column:enabled_at" json:"enabled_at"`
+	FailedAttempts  int        `gorm:"column:failed_attempts;default:0" json:"failed_attempts"`
+	LockedUntil     *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	LastUsedStep    int64      `gorm:"column:last_used_step;default:0" json:"-"` // last accepted TOTP time step, prevents replay
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	BackupCodes []TwoFactorBackupCode `gorm:"foreignKey:CredentialID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for TwoFactorCredential model
+func (TwoFactorCredential) TableName() string {
+	return "two_factor_credentials"
+}
+
+// IsEnabled checks if enrollment has been confirmed
+func (c *TwoFactorCredential) IsEnabled() bool {
+	return c.EnabledAt != nil
+}
+
+// IsLocked checks if verification is locked after too many failed attempts
+func (c *TwoFactorCredential) IsLocked() bool {
+	return c.LockedUntil != nil && time.Now().Before(*c.LockedUntil)
+}
+
+// TwoFactorBackupCode is a single-use recovery code. Only the SHA-256 hash is stored.
+type TwoFactorBackupCode struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CredentialID uint       `gorm:"column:credential_id;not null;index" json:"credential_id"`
+	CodeHash     string     `gorm:"column:code_hash;size:64;not null" json:"-"`
+	UsedAt       *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for TwoFactorBackupCode model
+func (TwoFactorBackupCode) TableName() string {
+	return "two_factor_backup_codes"
+}
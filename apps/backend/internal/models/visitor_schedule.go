@@ -30,6 +30,8 @@ type VisitorSchedule struct {
 	VisitorTrainingID uint       `gorm:"column:visitor_training_id;not null" json:"visitor_training_id"`
 	VisitNo           int        `gorm:"column:visit_no;not null;check:visit_no >= 1 AND visit_no <= 4" json:"visit_no"`
 	VisitAt           *time.Time `gorm:"column:visit_at" json:"visit_at"`
+	VisitMode         VisitMode  `gorm:"column:visit_mode;type:varchar(10);default:onsite" json:"visit_mode"`
+	MeetingURL        *string    `gorm:"column:meeting_url" json:"meeting_url"`
 	Comment           *string    `gorm:"type:text" json:"comment"`
 	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
@@ -44,6 +46,11 @@ func (VisitorSchedule) TableName() string {
 	return "visitor_schedules"
 }
 
+// IsOnline checks if the visit is held online
+func (vs *VisitorSchedule) IsOnline() bool {
+	return vs.VisitMode == VisitModeOnline
+}
+
 // BeforeDelete hook to clean up related records when visitor schedule is deleted
 func (vs *VisitorSchedule) BeforeDelete(tx *gorm.DB) error {
 	// Delete all photos for this schedule
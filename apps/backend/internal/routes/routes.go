@@ -4,9 +4,11 @@ import (
 	"backend-go/internal/config"
 	"backend-go/internal/handlers"
 	"backend-go/internal/middleware"
+	"backend-go/internal/models"
 	"backend-go/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
+	"strings"
 )
 
 func Setup(app *fiber.App, db *gorm.DB, cfg *config.Config) {
@@ -34,6 +36,11 @@ func Setup(app *fiber.App, db *gorm.DB, cfg *config.Config) {
 	// API v1 routes
 	api := app.Group("/api/v1")
 
+	// Role and permission resolution, shared so every route group uses the same cache
+	authorizationService := services.NewAuthorizationService(db, services.NewJWTService(&services.JWTConfig{
+		SecretKey: cfg.JWTSecret,
+	}, db))
+
 	// Basic test endpoint
 	api.Get("/test", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -48,7 +55,7 @@ func Setup(app *fiber.App, db *gorm.DB, cfg *config.Config) {
 	api.Post("/register", simpleAuthHandler.SimpleRegister)
 
 	// Health check and monitoring endpoints
-	setupHealthRoutes(api, db, cfg, logger)
+	setupHealthRoutes(app, api, db, cfg, logger)
 
 	// Setup authentication routes
 	setupAuthRoutes(api, db, cfg)
@@ -63,48 +70,64 @@ func Setup(app *fiber.App, db *gorm.DB, cfg *config.Config) {
 	// setupUserRoutes(api, db, cfg) // Disabled - needs model alignment
 
 	// Setup student management routes
-	setupStudentRoutes(api, db, cfg)
+	setupStudentRoutes(api, db, cfg, authorizationService)
 
 	// Setup company management routes
 	setupCompanyRoutes(api, db, cfg)
 
 	// Setup dashboard routes
-	setupDashboardRoutes(api, db, cfg)
+	setupDashboardRoutes(api, db, cfg, authorizationService)
 
 	// Setup analytics routes
-	setupAnalyticsRoutes(api, db, cfg)
+	setupAnalyticsRoutes(api, db, cfg, authorizationService)
 
 	// Setup visitor management routes
-	setupVisitorRoutes(api, db, cfg)
+	setupVisitorRoutes(api, db, cfg, authorizationService)
 
 	// Setup course management routes
 	setupCourseRoutes(api, db, cfg)
 
+	// Setup instructor management routes
+	setupInstructorRoutes(api, db, cfg, authorizationService)
+
 	// Setup student training management routes
-	setupStudentTrainingRoutes(api, db, cfg)
+	setupStudentTrainingRoutes(api, db, cfg, authorizationService)
+
+	// Setup internship attendance and time sheet routes
+	setupAttendanceRoutes(api, db, cfg, authorizationService)
+
+	// Setup final grade computation routes
+	setupGradingRoutes(api, db, cfg, authorizationService)
 
 	// Setup document management routes (Yellow Flow)
-	setupDocumentRoutes(api, db, cfg)
+	setupDocumentRoutes(api, db, cfg, authorizationService)
 
 	// Setup schedule management routes (Green Flow)
-	setupScheduleRoutes(api, db, cfg)
+	setupScheduleRoutes(api, db, cfg, authorizationService)
 
 	// Setup analytics and reporting routes (Purple Flow)
-	setupAnalyticsRoutes(api, db, cfg)
+	setupReportingRoutes(api, db, cfg, authorizationService)
 
 	// Setup PDF generation routes
 	setupPDFRoutes(api, db, cfg)
 
 	// Setup approval and evaluation routes
-	setupApprovalRoutes(api, db, cfg)
-	setupEvaluationRoutes(api, db, cfg)
+	setupApprovalRoutes(api, db, cfg, authorizationService)
+	setupEvaluationRoutes(api, db, cfg, authorizationService)
+	setupEvaluationFormRoutes(api, db, cfg, authorizationService)
+
+	// Setup audit trail routes
+	setupAuditRoutes(api, db, cfg, authorizationService)
+
+	// Setup recycle bin routes
+	setupRecycleBinRoutes(api, db, cfg, authorizationService)
 
 	// TODO: Add more route groups as they are implemented
 	// etc.
 }
 
 // setupHealthRoutes sets up health check and monitoring routes
-func setupHealthRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, logger *services.Logger) {
+func setupHealthRoutes(app *fiber.App, api fiber.Router, db *gorm.DB, cfg *config.Config, logger *services.Logger) {
 	healthHandler := handlers.NewHealthHandler(db, cfg, logger)
 
 	// Health check routes (no auth required)
@@ -116,6 +139,9 @@ func setupHealthRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, logger
 	api.Get("/metrics", healthHandler.Metrics)
 	api.Get("/ready", healthHandler.Ready)
 	api.Get("/live", healthHandler.Live)
+
+	// Prometheus scrape endpoint at the conventional root path
+	app.Get("/metrics", healthHandler.PrometheusMetrics)
 }
 
 // setupNotificationRoutes sets up notification routes
@@ -169,9 +195,12 @@ func setupAuthRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	jwtService := services.NewJWTService(jwtConfig, db)
 	authService := services.NewAuthService(db, jwtService)
 	authHandler := handlers.NewAuthHandler(authService)
+	twoFactorService := services.NewTwoFactorService(db, jwtService, twoFactorSettings(cfg.TwoFactor))
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
 
 	// Public authentication routes (no auth required)
 	api.Post("/login", authHandler.Login)
+	api.Post("/admin/login", authHandler.AdminLogin)
 	api.Post("/register", authHandler.Register)
 	api.Post("/refresh-token", authHandler.RefreshToken)
 	api.Post("/request-password-reset", authHandler.RequestPasswordReset)
@@ -182,6 +211,40 @@ func setupAuthRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	api.Get("/me", authMiddleware, authHandler.Me)
 	api.Post("/change-password", authMiddleware, authHandler.ChangePassword)
 	api.Post("/logout", authMiddleware, authHandler.Logout)
+
+	// Two-factor authentication (super admins and instructors)
+	twoFactorLimit := middleware.AuthRateLimit()
+	if cfg.TwoFactor != nil && cfg.TwoFactor.RateLimitAttempts > 0 && cfg.TwoFactor.RateLimitWindow > 0 {
+		twoFactorLimit = middleware.TwoFactorRateLimit(cfg.TwoFactor.RateLimitAttempts, cfg.TwoFactor.RateLimitWindow)
+	}
+	api.Post("/login/2fa", twoFactorLimit, twoFactorHandler.VerifyLogin) // POST /api/v1/login/2fa
+
+	twoFactor := api.Group("/2fa", authMiddleware)
+	twoFactor.Get("/", twoFactorHandler.GetStatus)                                       // GET /api/v1/2fa
+	twoFactor.Post("/enroll", twoFactorHandler.Enroll)                                   // POST /api/v1/2fa/enroll
+	twoFactor.Post("/confirm", twoFactorLimit, twoFactorHandler.ConfirmEnrollment)       // POST /api/v1/2fa/confirm
+	twoFactor.Post("/disable", twoFactorLimit, twoFactorHandler.Disable)                 // POST /api/v1/2fa/disable
+	twoFactor.Post("/backup-codes", twoFactorLimit, twoFactorHandler.RegenerateBackupCodes) // POST /api/v1/2fa/backup-codes
+}
+
+// twoFactorSettings maps the 2FA configuration onto the service settings
+func twoFactorSettings(cfg *config.TwoFactorConfig) services.TwoFactorSettings {
+	if cfg == nil {
+		return services.TwoFactorSettings{}
+	}
+	return services.TwoFactorSettings{
+		Issuer:           cfg.Issuer,
+		SecretLength:     cfg.SecretLength,
+		CodeLength:       cfg.CodeLength,
+		Period:           cfg.Period,
+		Skew:             cfg.Skew,
+		BackupCodeCount:  cfg.BackupCodeCount,
+		BackupCodeLength: cfg.BackupCodeLength,
+		EncryptionKey:    cfg.EncryptionKey,
+		MaxAttempts:      cfg.MaxAttempts,
+		LockoutDuration:  cfg.LockoutDuration,
+		QRCodeSize:       cfg.QRCodeSize,
+	}
 }
 
 // setupUserRoutes sets up user management routes - DISABLED
@@ -214,7 +277,7 @@ func setupAuthRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 // }
 
 // setupStudentRoutes sets up student management routes
-func setupStudentRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupStudentRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -222,30 +285,32 @@ func setupStudentRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	jwtService := services.NewJWTService(jwtConfig, db)
 	studentService := services.NewStudentService(db)
 	studentHandler := handlers.NewStudentHandler(studentService)
+	importHandler := handlers.NewStudentImportHandler(services.NewStudentImportService(db))
 
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
 
-	// Student management routes (all require authentication)
-	students := api.Group("/students", authMiddleware)
+	// Student management routes (all require authentication, reads limited to the caller's data scope)
+	students := api.Group("/students", authMiddleware, middleware.ScopeData(authorizationService))
 	
 	// Basic CRUD operations
 	students.Get("/", studentHandler.GetStudents)           // GET /api/v1/students
 	students.Get("/stats", studentHandler.GetStudentStats)  // GET /api/v1/students/stats
 	students.Get("/analytics", studentHandler.GetStudentAnalytics) // GET /api/v1/students/analytics
-	students.Get("/:id", studentHandler.GetStudent)         // GET /api/v1/students/:id
+	students.Get("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedStudent, "id"), studentHandler.GetStudent) // GET /api/v1/students/:id
 	students.Post("/", studentHandler.CreateStudent)        // POST /api/v1/students
 	students.Put("/:id", studentHandler.UpdateStudent)      // PUT /api/v1/students/:id
-	students.Delete("/:id", studentHandler.DeleteStudent)   // DELETE /api/v1/students/:id
 	
 	// Advanced operations
 	students.Post("/search", studentHandler.AdvancedSearch)               // POST /api/v1/students/search
-	students.Delete("/bulk", studentHandler.BulkDeleteStudents)           // DELETE /api/v1/students/bulk
+	students.Post("/import", middleware.RequirePermission(authorizationService, "students:import"), importHandler.ImportStudents) // POST /api/v1/students/import?dry_run=false (multipart file: .csv or .xlsx)
+	students.Delete("/bulk", middleware.RequirePermission(authorizationService, "students:bulk_delete"), studentHandler.BulkDeleteStudents) // DELETE /api/v1/students/bulk (must precede /:id)
+	students.Delete("/:id", middleware.RequirePermission(authorizationService, "students:delete"), studentHandler.DeleteStudent)           // DELETE /api/v1/students/:id
 	
 	// Enrollment management
 	students.Post("/enroll", studentHandler.EnrollStudent)                    // POST /api/v1/students/enroll
 	students.Put("/enrollments/:id", studentHandler.UpdateEnrollment)         // PUT /api/v1/students/enrollments/:id
-	students.Get("/:id/enrollments", studentHandler.GetStudentEnrollments)    // GET /api/v1/students/:id/enrollments
+	students.Get("/:id/enrollments", middleware.RequireScopedAccess(authorizationService, services.ScopedStudent, "id"), studentHandler.GetStudentEnrollments) // GET /api/v1/students/:id/enrollments
 }
 
 // setupCompanyRoutes sets up company management routes
@@ -279,7 +344,7 @@ func setupCompanyRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 }
 
 // setupDashboardRoutes sets up dashboard routes
-func setupDashboardRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupDashboardRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -291,23 +356,27 @@ func setupDashboardRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
 
-	// Dashboard routes (all require authentication)
+	// Dashboard routes (all require authentication, scoped to the caller's own data)
 	dashboard := api.Group("/dashboard", authMiddleware)
 	
-	dashboard.Get("/student/:id", dashboardHandler.GetStudentDashboard)       // GET /api/v1/dashboard/student/:id
-	dashboard.Get("/instructor/:id", dashboardHandler.GetInstructorDashboard) // GET /api/v1/dashboard/instructor/:id
-	dashboard.Get("/admin", dashboardHandler.GetAdminDashboard)               // GET /api/v1/dashboard/admin
+	dashboard.Get("/student/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedStudent, "id"), dashboardHandler.GetStudentDashboard)          // GET /api/v1/dashboard/student/:id
+	dashboard.Get("/instructor/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedInstructor, "id"), dashboardHandler.GetInstructorDashboard) // GET /api/v1/dashboard/instructor/:id
+	dashboard.Get("/admin", middleware.RequireRole(authorizationService, models.RoleNameAdmin), dashboardHandler.GetAdminDashboard)                                 // GET /api/v1/dashboard/admin
 }
 
 // setupAnalyticsRoutes sets up analytics and reporting routes
-func setupAnalyticsRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupAnalyticsRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
 	}
 	jwtService := services.NewJWTService(jwtConfig, db)
 	analyticsService := services.NewAnalyticsService(db)
-	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	exportService := services.NewExportService(db, exportSettings(cfg.Export))
+	reportService := services.NewReportService(db, reportSettings(cfg.Export))
+	dashboardService := services.NewAnalyticsDashboardService(db)
+	metricService := services.NewMetricService(db)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, exportService, reportService, dashboardService, metricService)
 
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
@@ -318,6 +387,13 @@ func setupAnalyticsRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	analytics.Get("/stats", analyticsHandler.GetAnalyticsStats)                    // GET /api/v1/analytics/stats
 	analytics.Get("/internships", analyticsHandler.GetInternshipAnalytics)        // GET /api/v1/analytics/internships
 	analytics.Get("/approvals", analyticsHandler.GetApprovalAnalytics)            // GET /api/v1/analytics/approvals
+
+	// Approval workflow analytics cover every enrollment, so they are limited to admins
+	adminOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin)
+	analytics.Get("/approvals/funnel", adminOnly, analyticsHandler.GetApprovalFunnel)                   // GET /api/v1/analytics/approvals/funnel
+	analytics.Get("/approvals/durations", adminOnly, analyticsHandler.GetApprovalStatusDurations)       // GET /api/v1/analytics/approvals/durations
+	analytics.Get("/approvals/turnaround", adminOnly, analyticsHandler.GetApprovalTurnaround)           // GET /api/v1/analytics/approvals/turnaround
+
 	analytics.Get("/companies", analyticsHandler.GetCompanyAnalytics)             // GET /api/v1/analytics/companies
 	analytics.Get("/report-types", analyticsHandler.GetReportTypes)               // GET /api/v1/analytics/report-types
 	analytics.Post("/reports", analyticsHandler.GenerateReport)                   // POST /api/v1/analytics/reports
@@ -325,7 +401,7 @@ func setupAnalyticsRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 }
 
 // setupVisitorRoutes sets up visitor management routes
-func setupVisitorRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupVisitorRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -336,50 +412,54 @@ func setupVisitorRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
+	scopeMiddleware := middleware.ScopeData(authorizationService)
 
-	// Visitor Training routes
-	visitorTrainings := api.Group("/visitor-trainings", authMiddleware)
+	// Visitor Training routes (lists and reads are limited to the caller's data scope)
+	visitorTrainings := api.Group("/visitor-trainings", authMiddleware, scopeMiddleware)
 	visitorTrainings.Get("/", visitorHandler.GetVisitorTrainings)                                    // GET /api/v1/visitor-trainings
-	visitorTrainings.Get("/:id", visitorHandler.GetVisitorTraining)                                  // GET /api/v1/visitor-trainings/:id
+	visitorTrainings.Get("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorTraining, "id"), visitorHandler.GetVisitorTraining) // GET /api/v1/visitor-trainings/:id
 	visitorTrainings.Post("/", visitorHandler.CreateVisitorTraining)                                 // POST /api/v1/visitor-trainings
-	visitorTrainings.Put("/:id", visitorHandler.UpdateVisitorTraining)                               // PUT /api/v1/visitor-trainings/:id
-	visitorTrainings.Delete("/:id", visitorHandler.DeleteVisitorTraining)                            // DELETE /api/v1/visitor-trainings/:id
+	visitorTrainings.Put("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorTraining, "id"), visitorHandler.UpdateVisitorTraining)    // PUT /api/v1/visitor-trainings/:id
+	visitorTrainings.Delete("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorTraining, "id"), visitorHandler.DeleteVisitorTraining) // DELETE /api/v1/visitor-trainings/:id
 	
 	// Visitor Training nested routes for evaluations
-	visitorTrainings.Get("/:training_id/evaluate-students", visitorHandler.GetVisitorEvaluateStudents)   // GET /api/v1/visitor-trainings/:training_id/evaluate-students
-	visitorTrainings.Get("/:training_id/evaluate-companies", visitorHandler.GetVisitorEvaluateCompanies) // GET /api/v1/visitor-trainings/:training_id/evaluate-companies
+	visitorTrainings.Get("/:training_id/evaluate-students", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorTraining, "training_id"), visitorHandler.GetVisitorEvaluateStudents)   // GET /api/v1/visitor-trainings/:training_id/evaluate-students
+	visitorTrainings.Get("/:training_id/evaluate-companies", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorTraining, "training_id"), visitorHandler.GetVisitorEvaluateCompanies) // GET /api/v1/visitor-trainings/:training_id/evaluate-companies
 
 	// Visitor Schedule routes
-	visitorSchedules := api.Group("/visitor-schedules", authMiddleware)
+	visitorSchedules := api.Group("/visitor-schedules", authMiddleware, scopeMiddleware)
 	visitorSchedules.Get("/", visitorHandler.GetVisitorSchedules)                                    // GET /api/v1/visitor-schedules
-	visitorSchedules.Get("/:id", visitorHandler.GetVisitorSchedule)                                  // GET /api/v1/visitor-schedules/:id
+	visitorSchedules.Get("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorSchedule, "id"), visitorHandler.GetVisitorSchedule) // GET /api/v1/visitor-schedules/:id
 	visitorSchedules.Post("/", visitorHandler.CreateVisitorSchedule)                                 // POST /api/v1/visitor-schedules
 	visitorSchedules.Put("/:id", visitorHandler.UpdateVisitorSchedule)                               // PUT /api/v1/visitor-schedules/:id
 	visitorSchedules.Delete("/:id", visitorHandler.DeleteVisitorSchedule)                            // DELETE /api/v1/visitor-schedules/:id
 	
 	// Visitor Schedule nested routes for photos
-	visitorSchedules.Get("/:schedule_id/photos", visitorHandler.GetVisitPhotos)                      // GET /api/v1/visitor-schedules/:schedule_id/photos
-	visitorSchedules.Post("/:schedule_id/photos", visitorHandler.UploadVisitPhoto)                   // POST /api/v1/visitor-schedules/:schedule_id/photos
-
-	// Visitor Evaluate Student routes
-	visitorEvaluateStudents := api.Group("/visitor-evaluate-students", authMiddleware)
-	visitorEvaluateStudents.Get("/:id", visitorHandler.GetVisitorEvaluateStudent)                    // GET /api/v1/visitor-evaluate-students/:id
-	visitorEvaluateStudents.Post("/", visitorHandler.CreateVisitorEvaluateStudent)                   // POST /api/v1/visitor-evaluate-students
-	visitorEvaluateStudents.Put("/:id", visitorHandler.UpdateVisitorEvaluateStudent)                 // PUT /api/v1/visitor-evaluate-students/:id
-	visitorEvaluateStudents.Delete("/:id", visitorHandler.DeleteVisitorEvaluateStudent)              // DELETE /api/v1/visitor-evaluate-students/:id
+	visitorSchedules.Get("/:schedule_id/photos", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorSchedule, "schedule_id"), visitorHandler.GetVisitPhotos)    // GET /api/v1/visitor-schedules/:schedule_id/photos
+	visitorSchedules.Post("/:schedule_id/photos", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorSchedule, "schedule_id"), visitorHandler.UploadVisitPhoto) // POST /api/v1/visitor-schedules/:schedule_id/photos
+
+	// Visitor Evaluate Student routes (reads are limited to the caller's data scope, writes to instructors)
+	instructorOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor)
+	ownStudentEvaluation := middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorStudentEvaluation, "id")
+	visitorEvaluateStudents := api.Group("/visitor-evaluate-students", authMiddleware, scopeMiddleware)
+	visitorEvaluateStudents.Get("/:id", ownStudentEvaluation, visitorHandler.GetVisitorEvaluateStudent)                      // GET /api/v1/visitor-evaluate-students/:id
+	visitorEvaluateStudents.Post("/", instructorOnly, visitorHandler.CreateVisitorEvaluateStudent)                           // POST /api/v1/visitor-evaluate-students
+	visitorEvaluateStudents.Put("/:id", instructorOnly, ownStudentEvaluation, visitorHandler.UpdateVisitorEvaluateStudent)    // PUT /api/v1/visitor-evaluate-students/:id
+	visitorEvaluateStudents.Delete("/:id", instructorOnly, ownStudentEvaluation, visitorHandler.DeleteVisitorEvaluateStudent) // DELETE /api/v1/visitor-evaluate-students/:id
 
 	// Visitor Evaluate Company routes
-	visitorEvaluateCompanies := api.Group("/visitor-evaluate-companies", authMiddleware)
-	visitorEvaluateCompanies.Get("/:id", visitorHandler.GetVisitorEvaluateCompany)                   // GET /api/v1/visitor-evaluate-companies/:id
-	visitorEvaluateCompanies.Post("/", visitorHandler.CreateVisitorEvaluateCompany)                  // POST /api/v1/visitor-evaluate-companies
-	visitorEvaluateCompanies.Put("/:id", visitorHandler.UpdateVisitorEvaluateCompany)                // PUT /api/v1/visitor-evaluate-companies/:id
-	visitorEvaluateCompanies.Delete("/:id", visitorHandler.DeleteVisitorEvaluateCompany)             // DELETE /api/v1/visitor-evaluate-companies/:id
+	ownCompanyEvaluation := middleware.RequireScopedAccess(authorizationService, services.ScopedVisitorCompanyEvaluation, "id")
+	visitorEvaluateCompanies := api.Group("/visitor-evaluate-companies", authMiddleware, scopeMiddleware)
+	visitorEvaluateCompanies.Get("/:id", ownCompanyEvaluation, visitorHandler.GetVisitorEvaluateCompany)                      // GET /api/v1/visitor-evaluate-companies/:id
+	visitorEvaluateCompanies.Post("/", instructorOnly, visitorHandler.CreateVisitorEvaluateCompany)                           // POST /api/v1/visitor-evaluate-companies
+	visitorEvaluateCompanies.Put("/:id", instructorOnly, ownCompanyEvaluation, visitorHandler.UpdateVisitorEvaluateCompany)    // PUT /api/v1/visitor-evaluate-companies/:id
+	visitorEvaluateCompanies.Delete("/:id", instructorOnly, ownCompanyEvaluation, visitorHandler.DeleteVisitorEvaluateCompany) // DELETE /api/v1/visitor-evaluate-companies/:id
 
 	// Visit Photo routes
 	visitPhotos := api.Group("/visit-photos", authMiddleware)
-	visitPhotos.Get("/:id", visitorHandler.GetVisitPhoto)                                            // GET /api/v1/visit-photos/:id
-	visitPhotos.Put("/:id", visitorHandler.UpdateVisitPhoto)                                         // PUT /api/v1/visit-photos/:id
-	visitPhotos.Delete("/:id", visitorHandler.DeleteVisitPhoto)                                      // DELETE /api/v1/visit-photos/:id
+	visitPhotos.Get("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitPhoto, "id"), visitorHandler.GetVisitPhoto)       // GET /api/v1/visit-photos/:id
+	visitPhotos.Put("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitPhoto, "id"), visitorHandler.UpdateVisitPhoto)    // PUT /api/v1/visit-photos/:id
+	visitPhotos.Delete("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedVisitPhoto, "id"), visitorHandler.DeleteVisitPhoto) // DELETE /api/v1/visit-photos/:id
 }
 
 // setupCourseRoutes sets up course management routes
@@ -427,8 +507,52 @@ func setupCourseRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	enrollmentStatuses.Put("/:id", courseHandler.UpdateStudentEnrollmentStatusRecord) // PUT /api/v1/student-enrollment-statuses/:id
 }
 
+// setupInstructorRoutes sets up instructor management routes
+func setupInstructorRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
+	// Initialize services
+	jwtConfig := &services.JWTConfig{
+		SecretKey: cfg.JWTSecret,
+	}
+	jwtService := services.NewJWTService(jwtConfig, db)
+	instructorService := services.NewInstructorService(db)
+	instructorHandler := handlers.NewInstructorHandler(instructorService)
+
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(jwtService)
+	adminOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin)
+	ownInstructor := middleware.RequireScopedAccess(authorizationService, services.ScopedInstructor, "id")
+
+	// Instructor routes (staff only; instructors are limited to their own record by data scope)
+	instructors := api.Group("/instructors", authMiddleware, middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor), middleware.ScopeData(authorizationService))
+
+	// Basic CRUD operations
+	instructors.Get("/", instructorHandler.GetInstructors)                      // GET /api/v1/instructors?page=1&limit=10&search=&faculty_id=&program_id=&sort_by=&sort_desc=
+	instructors.Get("/stats", instructorHandler.GetInstructorStats)             // GET /api/v1/instructors/stats
+	instructors.Post("/", adminOnly, instructorHandler.CreateInstructor)        // POST /api/v1/instructors
+	instructors.Get("/:id", ownInstructor, instructorHandler.GetInstructor)     // GET /api/v1/instructors/:id
+	instructors.Put("/:id", adminOnly, instructorHandler.UpdateInstructor)      // PUT /api/v1/instructors/:id
+	instructors.Delete("/:id", adminOnly, instructorHandler.DeleteInstructor)   // DELETE /api/v1/instructors/:id
+
+	// Course assignments
+	instructors.Get("/:id/course-assignments", ownInstructor, instructorHandler.GetInstructorCourseAssignments)                  // GET /api/v1/instructors/:id/course-assignments
+	instructors.Post("/:id/course-assignments", adminOnly, instructorHandler.AssignCourseToInstructor)                           // POST /api/v1/instructors/:id/course-assignments
+	instructors.Put("/:id/course-assignments/:assignmentId", adminOnly, instructorHandler.UpdateInstructorCourseAssignment)      // PUT /api/v1/instructors/:id/course-assignments/:assignmentId
+	instructors.Delete("/:id/course-assignments/:assignmentId", adminOnly, instructorHandler.RemoveInstructorCourseAssignment)   // DELETE /api/v1/instructors/:id/course-assignments/:assignmentId
+
+	// Grades and attendance
+	instructors.Post("/:id/grades", ownInstructor, instructorHandler.ManageGrade)           // POST /api/v1/instructors/:id/grades
+	instructors.Post("/:id/attendance", ownInstructor, instructorHandler.RecordAttendance)  // POST /api/v1/instructors/:id/attendance
+
+	// Body-addressed operations kept for existing clients
+	instructors.Post("/assign-course", adminOnly, instructorHandler.AssignInstructorToCourse)                  // POST /api/v1/instructors/assign-course
+	instructors.Put("/course-assignments/:id", adminOnly, instructorHandler.UpdateCourseAssignment)            // PUT /api/v1/instructors/course-assignments/:id
+	instructors.Delete("/course-assignments/:id", adminOnly, instructorHandler.RemoveCourseAssignment)         // DELETE /api/v1/instructors/course-assignments/:id
+	instructors.Post("/manage-grade", adminOnly, instructorHandler.ManageInstructorGrade)                      // POST /api/v1/instructors/manage-grade
+	instructors.Post("/record-attendance", adminOnly, instructorHandler.RecordTrainingAttendance)              // POST /api/v1/instructors/record-attendance
+}
+
 // setupStudentTrainingRoutes sets up student training management routes
-func setupStudentTrainingRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupStudentTrainingRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -440,18 +564,89 @@ func setupStudentTrainingRoutes(api fiber.Router, db *gorm.DB, cfg *config.Confi
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
 
-	// Student Training routes
-	studentTrainings := api.Group("/student-trainings", authMiddleware)
+	// Student Training routes (lists and reads are limited to the caller's data scope)
+	studentTrainings := api.Group("/student-trainings", authMiddleware, middleware.ScopeData(authorizationService))
 	studentTrainings.Get("/", studentTrainingHandler.GetStudentTrainings)                  // GET /api/v1/student-trainings
-	studentTrainings.Get("/:id", studentTrainingHandler.GetStudentTraining)               // GET /api/v1/student-trainings/:id
+	studentTrainings.Get("/stats", studentTrainingHandler.GetStudentTrainingStats)        // GET /api/v1/student-trainings/stats (must precede /:id)
+	studentTrainings.Get("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentTraining, "id"), studentTrainingHandler.GetStudentTraining) // GET /api/v1/student-trainings/:id
 	studentTrainings.Post("/", studentTrainingHandler.CreateStudentTraining)              // POST /api/v1/student-trainings
-	studentTrainings.Put("/:id", studentTrainingHandler.UpdateStudentTraining)            // PUT /api/v1/student-trainings/:id
-	studentTrainings.Delete("/:id", studentTrainingHandler.DeleteStudentTraining)         // DELETE /api/v1/student-trainings/:id
-	studentTrainings.Get("/stats", studentTrainingHandler.GetStudentTrainingStats)        // GET /api/v1/student-trainings/stats
+	studentTrainings.Put("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentTraining, "id"), studentTrainingHandler.UpdateStudentTraining)    // PUT /api/v1/student-trainings/:id
+	studentTrainings.Delete("/:id", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentTraining, "id"), studentTrainingHandler.DeleteStudentTraining) // DELETE /api/v1/student-trainings/:id
+}
+
+// setupAttendanceRoutes sets up internship attendance, time sheet and required-hours routes
+func setupAttendanceRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
+	// Initialize services
+	jwtConfig := &services.JWTConfig{
+		SecretKey: cfg.JWTSecret,
+	}
+	jwtService := services.NewJWTService(jwtConfig, db)
+	attendanceService := services.NewAttendanceService(db, attendanceSettings(cfg.Mail))
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceService)
+
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(jwtService)
+	staffOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor)
+	ownTraining := middleware.RequireScopedAccess(authorizationService, services.ScopedStudentTraining, "trainingId")
+
+	// Public supervisor review of submitted time sheets, authenticated by the review token
+	api.Get("/timesheets/review/:token", attendanceHandler.GetSupervisorReview)     // GET /api/v1/timesheets/review/:token
+	api.Post("/timesheets/review/:token", attendanceHandler.SubmitSupervisorReview) // POST /api/v1/timesheets/review/:token
+
+	// Attendance routes (all require authentication, trainings limited to the caller's data scope)
+	attendance := api.Group("/attendance", authMiddleware, middleware.ScopeData(authorizationService))
+
+	// Daily entries and weekly time sheets
+	attendance.Get("/trainings/:trainingId/timesheets", ownTraining, attendanceHandler.GetTimesheets)                                   // GET /api/v1/attendance/trainings/:trainingId/timesheets
+	attendance.Get("/trainings/:trainingId/summary", ownTraining, attendanceHandler.GetTrainingSummary)                                 // GET /api/v1/attendance/trainings/:trainingId/summary
+	attendance.Post("/trainings/:trainingId/entries", ownTraining, attendanceHandler.RecordEntry)                                       // POST /api/v1/attendance/trainings/:trainingId/entries
+	attendance.Delete("/trainings/:trainingId/entries/:entryId", ownTraining, attendanceHandler.DeleteEntry)                            // DELETE /api/v1/attendance/trainings/:trainingId/entries/:entryId
+	attendance.Post("/trainings/:trainingId/timesheets/:timesheetId/submit", ownTraining, attendanceHandler.SubmitTimesheet)            // POST /api/v1/attendance/trainings/:trainingId/timesheets/:timesheetId/submit
+	attendance.Put("/trainings/:trainingId/timesheets/:timesheetId/review", staffOnly, ownTraining, attendanceHandler.ReviewTimesheet)  // PUT /api/v1/attendance/trainings/:trainingId/timesheets/:timesheetId/review
+
+	// Required hours per course
+	attendance.Get("/requirements/:courseId", attendanceHandler.GetRequirement)                                                        // GET /api/v1/attendance/requirements/:courseId
+	attendance.Put("/requirements/:courseId", middleware.RequireRole(authorizationService, models.RoleNameAdmin), attendanceHandler.SetRequirement) // PUT /api/v1/attendance/requirements/:courseId
+
+	// Reports
+	attendance.Get("/reports/students/:studentId", middleware.RequireScopedAccess(authorizationService, services.ScopedStudent, "studentId"), attendanceHandler.GetStudentReport) // GET /api/v1/attendance/reports/students/:studentId
+	attendance.Get("/reports/companies/:companyId", staffOnly, attendanceHandler.GetCompanyReport)                                     // GET /api/v1/attendance/reports/companies/:companyId
+}
+
+// setupGradingRoutes sets up grading scheme and section grade sheet routes
+func setupGradingRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
+	// Initialize services
+	jwtConfig := &services.JWTConfig{
+		SecretKey: cfg.JWTSecret,
+	}
+	jwtService := services.NewJWTService(jwtConfig, db)
+	gradingService := services.NewGradingService(db)
+	gradingHandler := handlers.NewGradingHandler(gradingService)
+
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(jwtService)
+	adminOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin)
+
+	// Grading routes (staff only, instructors limited to the sections they teach)
+	grading := api.Group("/grading", authMiddleware,
+		middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor),
+		middleware.ScopeData(authorizationService))
+
+	// Grading schemes per course
+	grading.Get("/schemes/:courseId", gradingHandler.GetScheme)            // GET /api/v1/grading/schemes/:courseId
+	grading.Put("/schemes/:courseId", adminOnly, gradingHandler.SetScheme) // PUT /api/v1/grading/schemes/:courseId
+
+	// Section grade sheets
+	grading.Get("/sections/:sectionId", gradingHandler.GetSectionGrades)                        // GET /api/v1/grading/sections/:sectionId
+	grading.Put("/sections/:sectionId/overrides/:enrollId", gradingHandler.OverrideGrade)       // PUT /api/v1/grading/sections/:sectionId/overrides/:enrollId
+	grading.Delete("/sections/:sectionId/overrides/:enrollId", gradingHandler.ClearOverride)    // DELETE /api/v1/grading/sections/:sectionId/overrides/:enrollId
+	grading.Post("/sections/:sectionId/lock", gradingHandler.LockSection)                       // POST /api/v1/grading/sections/:sectionId/lock
+	grading.Post("/sections/:sectionId/unlock", adminOnly, gradingHandler.UnlockSection)        // POST /api/v1/grading/sections/:sectionId/unlock
+	grading.Post("/sections/:sectionId/publish", gradingHandler.PublishSection)                 // POST /api/v1/grading/sections/:sectionId/publish
 }
 
 // setupDocumentRoutes sets up document management routes (Yellow Flow)
-func setupDocumentRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupDocumentRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -463,17 +658,19 @@ func setupDocumentRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
 
-	// Document routes
-	documents := api.Group("/documents", authMiddleware)
+	// Document routes; reads cover the caller's uploads and documents on trainings in its data scope,
+	// changes are limited to the uploader or an admin
+	documents := api.Group("/documents", authMiddleware, middleware.ScopeData(authorizationService))
 	documents.Get("/", documentHandler.GetDocuments)                          // GET /api/v1/documents
+	documents.Get("/stats", documentHandler.GetDocumentStats)                 // GET /api/v1/documents/stats
 	documents.Get("/:id", documentHandler.GetDocument)                        // GET /api/v1/documents/:id
 	documents.Post("/upload", documentHandler.UploadDocument)                 // POST /api/v1/documents/upload
 	documents.Put("/:id", documentHandler.UpdateDocument)                     // PUT /api/v1/documents/:id
 	documents.Delete("/:id", documentHandler.DeleteDocument)                  // DELETE /api/v1/documents/:id
-	documents.Post("/:id/approve", documentHandler.ApproveDocument)           // POST /api/v1/documents/:id/approve
+	documents.Post("/:id/approve", middleware.RequirePermission(authorizationService, "documents:approve"), documentHandler.ApproveDocument) // POST /api/v1/documents/:id/approve
 	documents.Post("/:id/comments", documentHandler.AddComment)               // POST /api/v1/documents/:id/comments
 	documents.Get("/:id/download", documentHandler.DownloadDocument)          // GET /api/v1/documents/:id/download
-	documents.Get("/stats", documentHandler.GetDocumentStats)                 // GET /api/v1/documents/stats
+	documents.Get("/:id/versions", documentHandler.GetDocumentVersions)       // GET /api/v1/documents/:id/versions
 
 	// Document Template routes
 	pdfService := services.NewPDFService("uploads/pdf")
@@ -491,7 +688,7 @@ func setupDocumentRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 }
 
 // setupScheduleRoutes sets up schedule management routes (Green Flow)
-func setupScheduleRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupScheduleRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -499,62 +696,87 @@ func setupScheduleRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	jwtService := services.NewJWTService(jwtConfig, db)
 	scheduleService := services.NewScheduleService(db)
 	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	icalService := services.NewICalService(db)
+	icalHandler := handlers.NewICalHandler(icalService)
 
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
 
+	// Writes need the permission; changing someone else's schedule or appointment
+	// is further limited to its organizers, requester or holders of schedules:manage_any
+	manageSchedules := middleware.RequirePermission(authorizationService, "schedules:manage")
+	manageAppointments := middleware.RequirePermission(authorizationService, "appointments:manage")
+	approveAppointments := middleware.RequirePermission(authorizationService, "appointments:approve")
+
 	// Schedule routes
 	schedules := api.Group("/schedules", authMiddleware)
 	schedules.Get("/", scheduleHandler.GetSchedules)                          // GET /api/v1/schedules
 	schedules.Get("/:id", scheduleHandler.GetSchedule)                        // GET /api/v1/schedules/:id
-	schedules.Post("/", scheduleHandler.CreateSchedule)                       // POST /api/v1/schedules
-	schedules.Put("/:id", scheduleHandler.UpdateSchedule)                     // PUT /api/v1/schedules/:id
-	schedules.Delete("/:id", scheduleHandler.DeleteSchedule)                  // DELETE /api/v1/schedules/:id
-	schedules.Post("/:id/participants", scheduleHandler.AddParticipant)       // POST /api/v1/schedules/:id/participants
-	schedules.Delete("/:id/participants/:userId", scheduleHandler.RemoveParticipant) // DELETE /api/v1/schedules/:id/participants/:userId
+	schedules.Post("/", manageSchedules, scheduleHandler.CreateSchedule)                       // POST /api/v1/schedules
+	schedules.Put("/:id", manageSchedules, scheduleHandler.UpdateSchedule)                     // PUT /api/v1/schedules/:id
+	schedules.Delete("/:id", manageSchedules, scheduleHandler.DeleteSchedule)                  // DELETE /api/v1/schedules/:id
+	schedules.Post("/:id/participants", manageSchedules, scheduleHandler.AddParticipant)       // POST /api/v1/schedules/:id/participants
+	schedules.Delete("/:id/participants/:userId", manageSchedules, scheduleHandler.RemoveParticipant) // DELETE /api/v1/schedules/:id/participants/:userId
+	schedules.Post("/:id/respond", scheduleHandler.RespondToSchedule)         // POST /api/v1/schedules/:id/respond
+	schedules.Post("/:id/exceptions", manageSchedules, scheduleHandler.SetOccurrenceException) // POST /api/v1/schedules/:id/exceptions
+	schedules.Delete("/:id/exceptions/:exceptionId", manageSchedules, scheduleHandler.DeleteOccurrenceException) // DELETE /api/v1/schedules/:id/exceptions/:exceptionId
 
 	// Appointment routes
 	appointments := api.Group("/appointments", authMiddleware)
 	appointments.Get("/", scheduleHandler.GetAppointments)                    // GET /api/v1/appointments
 	appointments.Get("/:id", scheduleHandler.GetAppointment)                  // GET /api/v1/appointments/:id
-	appointments.Post("/", scheduleHandler.CreateAppointment)                 // POST /api/v1/appointments
-	appointments.Put("/:id", scheduleHandler.UpdateAppointment)               // PUT /api/v1/appointments/:id
-	appointments.Post("/:id/approve", scheduleHandler.ApproveAppointment)     // POST /api/v1/appointments/:id/approve
-	appointments.Delete("/:id", scheduleHandler.DeleteAppointment)            // DELETE /api/v1/appointments/:id
+	appointments.Post("/", manageAppointments, scheduleHandler.CreateAppointment)                 // POST /api/v1/appointments
+	appointments.Put("/:id", manageAppointments, scheduleHandler.UpdateAppointment)               // PUT /api/v1/appointments/:id
+	appointments.Post("/:id/approve", approveAppointments, scheduleHandler.ApproveAppointment)     // POST /api/v1/appointments/:id/approve
+	appointments.Delete("/:id", manageAppointments, scheduleHandler.DeleteAppointment)            // DELETE /api/v1/appointments/:id
+
+	// Public calendar feed, authenticated by its token (registered before the authenticated group)
+	api.Get("/calendars/feed/:token", icalHandler.GetSubscriptionFeed) // GET /api/v1/calendars/feed/:token
 
 	// Calendar routes
 	calendars := api.Group("/calendars", authMiddleware)
 	calendars.Get("/", scheduleHandler.GetCalendars)                          // GET /api/v1/calendars
+	calendars.Get("/subscription", icalHandler.GetSubscription)               // GET /api/v1/calendars/subscription
+	calendars.Post("/subscription/rotate", icalHandler.RotateSubscription)    // POST /api/v1/calendars/subscription/rotate
 	calendars.Get("/:id", scheduleHandler.GetCalendar)                        // GET /api/v1/calendars/:id
 	calendars.Post("/", scheduleHandler.CreateCalendar)                       // POST /api/v1/calendars
 	calendars.Put("/:id", scheduleHandler.UpdateCalendar)                     // PUT /api/v1/calendars/:id
 	calendars.Delete("/:id", scheduleHandler.DeleteCalendar)                  // DELETE /api/v1/calendars/:id
+	calendars.Post("/:id/schedules", scheduleHandler.AddCalendarSchedule)     // POST /api/v1/calendars/:id/schedules
+	calendars.Delete("/:id/schedules/:scheduleId", scheduleHandler.RemoveCalendarSchedule) // DELETE /api/v1/calendars/:id/schedules/:scheduleId
+	calendars.Get("/:id/ics", icalHandler.ExportCalendar)                     // GET /api/v1/calendars/:id/ics
+	calendars.Post("/:id/import", icalHandler.ImportCalendar)                 // POST /api/v1/calendars/:id/import
 }
 
-// setupAnalyticsRoutes sets up analytics and reporting routes (Purple Flow)
-func setupAnalyticsRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+// setupReportingRoutes sets up analytics and reporting routes (Purple Flow)
+func setupReportingRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
 	}
 	jwtService := services.NewJWTService(jwtConfig, db)
 	analyticsService := services.NewAnalyticsService(db)
-	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	exportService := services.NewExportService(db, exportSettings(cfg.Export))
+	reportService := services.NewReportService(db, reportSettings(cfg.Export))
+	dashboardService := services.NewAnalyticsDashboardService(db)
+	metricService := services.NewMetricService(db)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, exportService, reportService, dashboardService, metricService)
 
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
+	scopeMiddleware := middleware.ScopeData(authorizationService)
 
-	// Report routes
-	reports := api.Group("/reports", authMiddleware)
+	// Saved report routes (staff only; public reports are shared with all staff)
+	reports := api.Group("/reports", authMiddleware, middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor))
 	reports.Get("/", analyticsHandler.GetReports)                             // GET /api/v1/reports
 	reports.Get("/:id", analyticsHandler.GetReport)                           // GET /api/v1/reports/:id
 	reports.Post("/", analyticsHandler.CreateReport)                          // POST /api/v1/reports
-	reports.Post("/:id/generate", analyticsHandler.GenerateReport)            // POST /api/v1/reports/:id/generate
+	reports.Post("/:id/generate", analyticsHandler.GenerateSavedReport)       // POST /api/v1/reports/:id/generate
 	reports.Get("/:id/download", analyticsHandler.DownloadReport)             // GET /api/v1/reports/:id/download
 	reports.Delete("/:id", analyticsHandler.DeleteReport)                     // DELETE /api/v1/reports/:id
 
-	// Dashboard routes
-	dashboards := api.Group("/dashboards", authMiddleware)
+	// Configurable dashboard routes (staff only; widgets resolve their data server-side)
+	dashboards := api.Group("/dashboards", authMiddleware, middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor))
 	dashboards.Get("/", analyticsHandler.GetDashboards)                       // GET /api/v1/dashboards
 	dashboards.Get("/:id", analyticsHandler.GetDashboard)                     // GET /api/v1/dashboards/:id
 	dashboards.Post("/", analyticsHandler.CreateDashboard)                    // POST /api/v1/dashboards
@@ -563,22 +785,66 @@ func setupAnalyticsRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	dashboards.Post("/:id/widgets", analyticsHandler.AddWidget)               // POST /api/v1/dashboards/:id/widgets
 	dashboards.Put("/:id/widgets/:widgetId", analyticsHandler.UpdateWidget)   // PUT /api/v1/dashboards/:id/widgets/:widgetId
 	dashboards.Delete("/:id/widgets/:widgetId", analyticsHandler.DeleteWidget) // DELETE /api/v1/dashboards/:id/widgets/:widgetId
+	dashboards.Get("/:id/widgets/:widgetId/data", analyticsHandler.GetWidgetData) // GET /api/v1/dashboards/:id/widgets/:widgetId/data
 
-	// Metrics routes
-	metrics := api.Group("/metrics", authMiddleware)
-	metrics.Get("/", analyticsHandler.GetMetrics)                             // GET /api/v1/metrics
+	// Business metric routes (staff only; defining a metric runs its query, so it is admin only).
+	// GET /api/v1/metrics itself is the monitoring endpoint, the registry is listed under /definitions.
+	metrics := api.Group("/metrics", authMiddleware, middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor))
+	metrics.Get("/definitions", analyticsHandler.GetMetrics)                  // GET /api/v1/metrics/definitions
 	metrics.Get("/:name/values", analyticsHandler.GetMetricValues)            // GET /api/v1/metrics/:name/values
-	metrics.Post("/", analyticsHandler.CreateMetric)                          // POST /api/v1/metrics
+	metrics.Post("/", middleware.RequireRole(authorizationService, models.RoleNameAdmin), analyticsHandler.CreateMetric) // POST /api/v1/metrics
 	metrics.Post("/:name/values", analyticsHandler.RecordMetricValue)         // POST /api/v1/metrics/:name/values
 
-	// Export routes
-	exports := api.Group("/exports", authMiddleware)
+	// Export routes (jobs run in the background worker pool and are limited to the caller's data scope)
+	exports := api.Group("/exports", authMiddleware, scopeMiddleware)
 	exports.Get("/", analyticsHandler.GetExportJobs)                          // GET /api/v1/exports
 	exports.Post("/", analyticsHandler.CreateExportJob)                       // POST /api/v1/exports
 	exports.Get("/:id", analyticsHandler.GetExportJob)                        // GET /api/v1/exports/:id
 	exports.Get("/:id/download", analyticsHandler.DownloadExport)             // GET /api/v1/exports/:id/download
 }
 
+// attendanceSettings emails time sheet review links through the configured SMTP server
+func attendanceSettings(cfg *config.MailConfig) services.AttendanceSettings {
+	if cfg == nil || !cfg.Enabled() {
+		return services.AttendanceSettings{}
+	}
+	return services.AttendanceSettings{
+		Mailer: services.NewSMTPMailer(services.SMTPSettings{
+			Host:     cfg.Host,
+			Port:     cfg.Port,
+			Username: cfg.Username,
+			Password: cfg.Password,
+			From:     cfg.FromAddress,
+		}),
+		ReviewURL: strings.TrimRight(cfg.AppURL, "/") + "/api/v1/timesheets/review",
+	}
+}
+
+// reportSettings maps the saved report file settings onto the report service settings
+func reportSettings(cfg *config.ExportConfig) services.ReportSettings {
+	if cfg == nil {
+		return services.ReportSettings{}
+	}
+	return services.ReportSettings{
+		Directory: cfg.ReportDirectory,
+		Retention: cfg.ReportRetention,
+	}
+}
+
+// exportSettings maps export configuration onto the export service settings
+func exportSettings(cfg *config.ExportConfig) services.ExportSettings {
+	if cfg == nil {
+		return services.ExportSettings{}
+	}
+	return services.ExportSettings{
+		Directory:    cfg.Directory,
+		Workers:      cfg.Workers,
+		PollInterval: cfg.PollInterval,
+		Retention:    cfg.Retention,
+		StaleAfter:   cfg.StaleAfter,
+	}
+}
+
 // setupPDFRoutes sets up PDF generation routes
 func setupPDFRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	// Initialize services
@@ -608,7 +874,7 @@ func setupPDFRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 }
 
 // setupApprovalRoutes sets up internship approval workflow routes
-func setupApprovalRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupApprovalRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -618,25 +884,39 @@ func setupApprovalRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
+	adminOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin)
 
-	// Approval routes (all require authentication)
-	approvals := api.Group("/approvals", authMiddleware)
+	// Approval routes (all require authentication, reads limited to the caller's data scope)
+	approvals := api.Group("/approvals", authMiddleware, middleware.ScopeData(authorizationService))
 	
 	// Status and information routes
-	approvals.Get("/status/:studentEnrollId", approvalHandler.GetApprovalStatus)           // GET /api/v1/approvals/status/:studentEnrollId
-	approvals.Get("/committee-voting/:studentEnrollId", approvalHandler.GetCommitteeVotingData) // GET /api/v1/approvals/committee-voting/:studentEnrollId
+	approvals.Get("/status/:studentEnrollId", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentEnroll, "studentEnrollId"), approvalHandler.GetApprovalStatus) // GET /api/v1/approvals/status/:studentEnrollId
+	approvals.Get("/committee-voting/:studentEnrollId", middleware.RequireScopedAccess(authorizationService, services.ScopedCommitteeEnroll, "studentEnrollId"), approvalHandler.GetCommitteeVotingData) // GET /api/v1/approvals/committee-voting/:studentEnrollId
 	approvals.Get("/statuses", approvalHandler.GetApprovalStatuses)                       // GET /api/v1/approvals/statuses
+	approvals.Get("/voting-policies/:courseId", middleware.RequireScopedAccess(authorizationService, services.ScopedCourse, "courseId"), approvalHandler.GetVotingPolicy) // GET /api/v1/approvals/voting-policies/:courseId
+	approvals.Get("/workflows", approvalHandler.ListWorkflows)                             // GET /api/v1/approvals/workflows
+	approvals.Get("/workflows/:id", approvalHandler.GetWorkflow)                           // GET /api/v1/approvals/workflows/:id
+	approvals.Get("/history/:studentEnrollId", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentEnroll, "studentEnrollId"), approvalHandler.GetStatusTransitions) // GET /api/v1/approvals/history/:studentEnrollId
+	approvals.Get("/workflow/:studentEnrollId", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentEnroll, "studentEnrollId"), approvalHandler.GetEnrollmentWorkflow) // GET /api/v1/approvals/workflow/:studentEnrollId
 	approvals.Get("/", approvalHandler.GetApprovalsByStatus)                              // GET /api/v1/approvals?status=registered&page=1&limit=10
 	
 	// Action routes
 	approvals.Post("/", approvalHandler.CreateApprovalRecord)                             // POST /api/v1/approvals
-	approvals.Post("/advisor/:studentEnrollId", approvalHandler.AdvisorApproval)          // POST /api/v1/approvals/advisor/:studentEnrollId
-	approvals.Post("/committee-vote/:studentEnrollId", approvalHandler.CommitteeMemberVote) // POST /api/v1/approvals/committee-vote/:studentEnrollId
-	approvals.Put("/status/:studentEnrollId", approvalHandler.UpdateApprovalStatus)       // PUT /api/v1/approvals/status/:studentEnrollId
+	approvals.Post("/advisor/:studentEnrollId", middleware.RequirePermission(authorizationService, "approvals:advisor_approve"), approvalHandler.AdvisorApproval)          // POST /api/v1/approvals/advisor/:studentEnrollId
+	approvals.Post("/committee-vote/:studentEnrollId", middleware.RequirePermission(authorizationService, "approvals:committee_vote"), approvalHandler.CommitteeMemberVote) // POST /api/v1/approvals/committee-vote/:studentEnrollId
+	approvals.Put("/status/:studentEnrollId", middleware.RequirePermission(authorizationService, "approvals:update_status"), approvalHandler.UpdateApprovalStatus)       // PUT /api/v1/approvals/status/:studentEnrollId
+	approvals.Put("/voting-policies/:courseId", adminOnly, approvalHandler.SetVotingPolicy)                                                                           // PUT /api/v1/approvals/voting-policies/:courseId
+	approvals.Post("/transition/:studentEnrollId", middleware.RequirePermission(authorizationService, "approvals:transition"), approvalHandler.TransitionApproval)       // POST /api/v1/approvals/transition/:studentEnrollId
+
+	// Workflow definition routes (admin only)
+	approvals.Post("/workflows", adminOnly, approvalHandler.CreateWorkflow)              // POST /api/v1/approvals/workflows
+	approvals.Put("/workflows/:id", adminOnly, approvalHandler.UpdateWorkflow)           // PUT /api/v1/approvals/workflows/:id
+	approvals.Delete("/workflows/:id", adminOnly, approvalHandler.DeactivateWorkflow)    // DELETE /api/v1/approvals/workflows/:id
+	approvals.Post("/history/backfill", adminOnly, approvalHandler.BackfillStatusTransitions) // POST /api/v1/approvals/history/backfill
 }
 
 // setupEvaluationRoutes sets up evaluation status tracking routes
-func setupEvaluationRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
+func setupEvaluationRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
 	// Initialize services
 	jwtConfig := &services.JWTConfig{
 		SecretKey: cfg.JWTSecret,
@@ -647,16 +927,16 @@ func setupEvaluationRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	// Authentication middleware
 	authMiddleware := middleware.AuthMiddleware(jwtService)
 
-	// Evaluation routes (all require authentication)
-	evaluations := api.Group("/evaluations", authMiddleware)
+	// Evaluation routes (all require authentication, reads limited to the caller's data scope)
+	evaluations := api.Group("/evaluations", authMiddleware, middleware.ScopeData(authorizationService))
 	
 	// Information and status routes
-	evaluations.Get("/summary/:studentTrainingId", evaluationHandler.GetEvaluationSummary)     // GET /api/v1/evaluations/summary/:studentTrainingId
+	evaluations.Get("/summary/:studentTrainingId", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentTraining, "studentTrainingId"), evaluationHandler.GetEvaluationSummary) // GET /api/v1/evaluations/summary/:studentTrainingId
 	evaluations.Get("/", evaluationHandler.GetEvaluationsByType)                              // GET /api/v1/evaluations?type=student_company&status=pending&page=1&limit=10
 	evaluations.Get("/overdue", evaluationHandler.GetOverdueEvaluations)                      // GET /api/v1/evaluations/overdue
 	evaluations.Get("/stats", evaluationHandler.GetEvaluationStats)                          // GET /api/v1/evaluations/stats
-	evaluations.Get("/student/:studentTrainingId/status", evaluationHandler.CheckStudentEvaluationStatus) // GET /api/v1/evaluations/student/:studentTrainingId/status
-	evaluations.Get("/instructor/:instructorId/assignments", evaluationHandler.GetInstructorAssignments) // GET /api/v1/evaluations/instructor/:instructorId/assignments
+	evaluations.Get("/student/:studentTrainingId/status", middleware.RequireScopedAccess(authorizationService, services.ScopedStudentTraining, "studentTrainingId"), evaluationHandler.CheckStudentEvaluationStatus) // GET /api/v1/evaluations/student/:studentTrainingId/status
+	evaluations.Get("/instructor/:instructorId/assignments", middleware.RequireScopedAccess(authorizationService, services.ScopedInstructor, "instructorId"), evaluationHandler.GetInstructorAssignments) // GET /api/v1/evaluations/instructor/:instructorId/assignments
 	evaluations.Get("/types", evaluationHandler.GetEvaluationTypes)                          // GET /api/v1/evaluations/types
 	evaluations.Get("/statuses", evaluationHandler.GetEvaluationStatuses)                    // GET /api/v1/evaluations/statuses
 	
@@ -668,6 +948,99 @@ func setupEvaluationRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	evaluations.Put("/:id/assign", evaluationHandler.AssignEvaluator)                      // PUT /api/v1/evaluations/:id/assign
 }
 
+// setupEvaluationFormRoutes sets up evaluation form authoring and submission routes
+func setupEvaluationFormRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
+	// Initialize services
+	jwtConfig := &services.JWTConfig{
+		SecretKey: cfg.JWTSecret,
+	}
+	jwtService := services.NewJWTService(jwtConfig, db)
+	formService := services.NewEvaluationFormService(db)
+	legacyService := services.NewLegacyEvaluationService(db)
+	formHandler := handlers.NewEvaluationFormHandler(formService, legacyService)
+
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(jwtService)
+	adminOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin)
+	staffOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin, models.RoleNameInstructor)
+	ownTraining := middleware.RequireScopedAccess(authorizationService, services.ScopedStudentTraining, "studentTrainingId")
+
+	// Evaluation form routes (all require authentication, submissions limited to the caller's data scope)
+	forms := api.Group("/evaluation-forms", authMiddleware, middleware.ScopeData(authorizationService))
+
+	// Form authoring
+	forms.Get("/", formHandler.ListForms)                          // GET /api/v1/evaluation-forms?type=company_student&faculty_id=1
+	forms.Post("/", adminOnly, formHandler.CreateForm)             // POST /api/v1/evaluation-forms
+	forms.Get("/analytics/questions", staffOnly, formHandler.GetQuestionAnalytics) // GET /api/v1/evaluation-forms/analytics/questions?type=visitor_student&form_id=1
+	forms.Post("/legacy/migrate", adminOnly, formHandler.MigrateLegacy)           // POST /api/v1/evaluation-forms/legacy/migrate
+	forms.Get("/:id/versions", formHandler.GetFormVersions)        // GET /api/v1/evaluation-forms/:id/versions
+	forms.Get("/:id", formHandler.GetForm)                         // GET /api/v1/evaluation-forms/:id
+	forms.Put("/:id", adminOnly, formHandler.UpdateForm)           // PUT /api/v1/evaluation-forms/:id
+	forms.Delete("/:id", adminOnly, formHandler.DeactivateForm)    // DELETE /api/v1/evaluation-forms/:id
+
+	// Submissions per student training
+	forms.Get("/trainings/:studentTrainingId/form", ownTraining, formHandler.ResolveForm)             // GET /api/v1/evaluation-forms/trainings/:studentTrainingId/form?type=company_student
+	forms.Get("/trainings/:studentTrainingId/submissions", ownTraining, formHandler.GetSubmissions)   // GET /api/v1/evaluation-forms/trainings/:studentTrainingId/submissions
+	forms.Post("/trainings/:studentTrainingId/submissions", ownTraining, formHandler.SaveSubmission)  // POST /api/v1/evaluation-forms/trainings/:studentTrainingId/submissions
+	forms.Get("/submissions/:submissionId", formHandler.GetSubmission)                                // GET /api/v1/evaluation-forms/submissions/:submissionId
+	forms.Post("/submissions/:submissionId/submit", formHandler.SubmitSubmission)                     // POST /api/v1/evaluation-forms/submissions/:submissionId/submit
+	forms.Post("/submissions/:submissionId/review", staffOnly, formHandler.ReviewSubmission)          // POST /api/v1/evaluation-forms/submissions/:submissionId/review
+}
+
+// setupAuditRoutes sets up audit trail browsing routes
+func setupAuditRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
+	// Initialize services
+	jwtConfig := &services.JWTConfig{
+		SecretKey: cfg.JWTSecret,
+	}
+	jwtService := services.NewJWTService(jwtConfig, db)
+	auditService := services.NewAuditService(db)
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(jwtService)
+
+	// Audit routes (all require authentication and the audit:read permission)
+	audit := api.Group("/audit", authMiddleware, middleware.RequirePermission(authorizationService, "audit:read"))
+
+	audit.Get("/", auditHandler.GetAuditLogs)                                         // GET /api/v1/audit?entity_type=student&action=update&from=2026-01-01
+	audit.Get("/entities/:entityType/:entityId", auditHandler.GetEntityHistory)       // GET /api/v1/audit/entities/:entityType/:entityId
+	audit.Get("/:id", auditHandler.GetAuditLog)                                       // GET /api/v1/audit/:id
+}
+
+// setupRecycleBinRoutes sets up routes to browse, restore and purge deleted records
+func setupRecycleBinRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config, authorizationService *services.AuthorizationService) {
+	// Initialize services
+	jwtConfig := &services.JWTConfig{
+		SecretKey: cfg.JWTSecret,
+	}
+	jwtService := services.NewJWTService(jwtConfig, db)
+	recycleBinService := services.NewRecycleBinService(db, recycleBinSettings(cfg.RecycleBin))
+	recycleBinHandler := handlers.NewRecycleBinHandler(recycleBinService)
+
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(jwtService)
+	adminOnly := middleware.RequireRole(authorizationService, models.RoleNameAdmin)
+
+	// Recycle bin routes (admin only)
+	recycleBin := api.Group("/recycle-bin", authMiddleware, adminOnly)
+
+	recycleBin.Get("/", recycleBinHandler.ListDeleted)                       // GET /api/v1/recycle-bin?entity_type=student&search=
+	recycleBin.Post("/:entityType/:id/restore", recycleBinHandler.Restore)   // POST /api/v1/recycle-bin/:entityType/:id/restore
+	recycleBin.Delete("/:entityType/:id", recycleBinHandler.Purge)           // DELETE /api/v1/recycle-bin/:entityType/:id
+}
+
+// recycleBinSettings maps recycle bin configuration onto the recycle bin service settings
+func recycleBinSettings(cfg *config.RecycleBinConfig) services.RecycleBinSettings {
+	if cfg == nil {
+		return services.RecycleBinSettings{}
+	}
+	return services.RecycleBinSettings{
+		Retention:     cfg.Retention,
+		PurgeInterval: cfg.PurgeInterval,
+	}
+}
+
 // setupStudentAuthRoutes sets up student-specific authentication routes
 func setupStudentAuthRoutes(api fiber.Router, db *gorm.DB, cfg *config.Config) {
 	// Initialize services
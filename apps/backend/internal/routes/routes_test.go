@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"testing"
+
+	"backend-go/internal/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportingRoutes(t *testing.T) {
+	app := fiber.New()
+	setupReportingRoutes(app.Group("/api/v1"), nil, &config.Config{JWTSecret: "test-secret"}, nil)
+
+	registered := make(map[string]bool)
+	for _, route := range app.GetRoutes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range []string{
+		"GET /api/v1/reports",
+		"GET /api/v1/reports/:id",
+		"POST /api/v1/reports",
+		"POST /api/v1/reports/:id/generate",
+		"GET /api/v1/reports/:id/download",
+		"DELETE /api/v1/reports/:id",
+		"GET /api/v1/dashboards",
+		"POST /api/v1/dashboards",
+		"PUT /api/v1/dashboards/:id",
+		"DELETE /api/v1/dashboards/:id",
+		"POST /api/v1/dashboards/:id/widgets",
+		"GET /api/v1/dashboards/:id/widgets/:widgetId/data",
+		"GET /api/v1/metrics/definitions",
+		"GET /api/v1/metrics/:name/values",
+		"POST /api/v1/metrics",
+		"POST /api/v1/metrics/:name/values",
+		"GET /api/v1/exports",
+		"POST /api/v1/exports",
+		"GET /api/v1/exports/:id/download",
+	} {
+		assert.True(t, registered[route], "%s is not registered", route)
+	}
+}
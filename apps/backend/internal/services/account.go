@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AccountType tells which table the numeric ID of an authenticated principal points into.
+// Student, instructor, staff and super admin IDs come from separate sequences and overlap,
+// so anything keyed on the numeric ID must store the account type next to it.
+type AccountType string
+
+const (
+	AccountTypeStudent    AccountType = "User" // students.id, same value as UserTypeStudent
+	AccountTypeInstructor AccountType = "Instructor"
+	AccountTypeStaff      AccountType = "Staff"
+	AccountTypeSuperAdmin AccountType = "SuperAdmin"
+)
+
+// Account is the numeric identity of an authenticated principal
+type Account struct {
+	Type AccountType `json:"type"`
+	ID   uint        `json:"id"`
+}
+
+// ResolveAccount finds the record behind a principal. Users are matched by their login ID
+// against students.student_id, then instructors.staff_id, then staffs.staff_id.
+// ok is false for users without any linked record.
+func ResolveAccount(db *gorm.DB, userType UserType, userID string) (Account, bool, error) {
+	switch userType {
+	case UserTypeSuperAdmin:
+		id, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			return Account{}, false, nil
+		}
+		return Account{Type: AccountTypeSuperAdmin, ID: uint(id)}, true, nil
+	case UserTypeStudent:
+		lookups := []struct {
+			accountType AccountType
+			model       interface{}
+			column      string
+		}{
+			{AccountTypeStudent, &models.Student{}, "student_id"},
+			{AccountTypeInstructor, &models.Instructor{}, "staff_id"},
+			{AccountTypeStaff, &models.Staff{}, "staff_id"},
+		}
+		for _, lookup := range lookups {
+			var ids []uint
+			if err := db.Model(lookup.model).Where(lookup.column+" = ?", userID).Limit(1).Pluck("id", &ids).Error; err != nil {
+				return Account{}, false, err
+			}
+			if len(ids) > 0 {
+				return Account{Type: lookup.accountType, ID: ids[0]}, true, nil
+			}
+		}
+		return Account{}, false, nil
+	}
+	return Account{}, false, errors.New("unsupported user type")
+}
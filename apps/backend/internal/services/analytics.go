@@ -84,6 +84,38 @@ type EvaluationMetrics struct {
 	AverageScore         float64 `json:"average_score"`
 }
 
+// ApprovalAnalytics represents approval workflow statistics
+type ApprovalAnalytics struct {
+	ByStatus                 []ApprovalStatusCount `json:"by_status"`
+	AverageApprovalTimeHours float64               `json:"average_approval_time_hours"`
+	MonthlyTrends            []MonthlyCount        `json:"monthly_trends"`
+}
+
+// ApprovalStatusCount represents the number of approvals in a status
+type ApprovalStatusCount struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// MonthlyCount represents a count for a month (YYYY-MM)
+type MonthlyCount struct {
+	Month string `json:"month"`
+	Count int64  `json:"count"`
+}
+
+// CompanyAnalytics represents company performance statistics
+type CompanyAnalytics struct {
+	CompanyPerformance []CompanyStats     `json:"company_performance"`
+	TypeDistribution   []CompanyTypeStats `json:"type_distribution"`
+}
+
+// CompanyTypeStats represents statistics for a company type
+type CompanyTypeStats struct {
+	CompanyType  string `json:"company_type"`
+	Count        int64  `json:"count"`
+	StudentCount int64  `json:"student_count"`
+}
+
 // GetInternshipAnalytics retrieves comprehensive internship analytics
 func (s *AnalyticsService) GetInternshipAnalytics(req AnalyticsRequest) (*AnalyticsResponse, error) {
 	response := &AnalyticsResponse{}
@@ -159,14 +191,11 @@ func (s *AnalyticsService) GetInternshipAnalytics(req AnalyticsRequest) (*Analyt
 }
 
 // GetApprovalAnalytics retrieves approval-specific analytics
-func (s *AnalyticsService) GetApprovalAnalytics(req AnalyticsRequest) (map[string]interface{}, error) {
-	analytics := make(map[string]interface{})
+func (s *AnalyticsService) GetApprovalAnalytics(req AnalyticsRequest) (*ApprovalAnalytics, error) {
+	analytics := &ApprovalAnalytics{}
 
 	// Approval statistics by status
-	var approvalStats []struct {
-		Status string `json:"status"`
-		Count  int64  `json:"count"`
-	}
+	var approvalStats []ApprovalStatusCount
 	
 	query := s.db.Model(&models.InternshipApproval{})
 	if !req.StartDate.IsZero() && !req.EndDate.IsZero() {
@@ -179,7 +208,7 @@ func (s *AnalyticsService) GetApprovalAnalytics(req AnalyticsRequest) (map[strin
 	if err != nil {
 		return nil, fmt.Errorf("failed to get approval statistics: %w", err)
 	}
-	analytics["by_status"] = approvalStats
+	analytics.ByStatus = approvalStats
 
 	// Average approval time
 	var avgApprovalTime float64
@@ -192,13 +221,10 @@ func (s *AnalyticsService) GetApprovalAnalytics(req AnalyticsRequest) (map[strin
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate average approval time: %w", err)
 	}
-	analytics["average_approval_time_hours"] = avgApprovalTime
+	analytics.AverageApprovalTimeHours = avgApprovalTime
 
 	// Approval trends by month
-	var approvalTrends []struct {
-		Month string `json:"month"`
-		Count int64  `json:"count"`
-	}
+	var approvalTrends []MonthlyCount
 	err = s.db.Raw(`
 		SELECT 
 			DATE_FORMAT(created_at, '%Y-%m') as month,
@@ -211,23 +237,17 @@ func (s *AnalyticsService) GetApprovalAnalytics(req AnalyticsRequest) (map[strin
 	if err != nil {
 		return nil, fmt.Errorf("failed to get approval trends: %w", err)
 	}
-	analytics["monthly_trends"] = approvalTrends
+	analytics.MonthlyTrends = approvalTrends
 
 	return analytics, nil
 }
 
 // GetCompanyAnalytics retrieves company-specific analytics
-func (s *AnalyticsService) GetCompanyAnalytics(req AnalyticsRequest) (map[string]interface{}, error) {
-	analytics := make(map[string]interface{})
+func (s *AnalyticsService) GetCompanyAnalytics(req AnalyticsRequest) (*CompanyAnalytics, error) {
+	analytics := &CompanyAnalytics{}
 
 	// Company performance metrics
-	var companyMetrics []struct {
-		CompanyID     uint    `json:"company_id"`
-		CompanyName   string  `json:"company_name"`
-		StudentCount  int64   `json:"student_count"`
-		SuccessRate   float64 `json:"success_rate"`
-		AverageRating float64 `json:"average_rating"`
-	}
+	var companyMetrics []CompanyStats
 
 	err := s.db.Raw(`
 		SELECT 
@@ -251,14 +271,10 @@ func (s *AnalyticsService) GetCompanyAnalytics(req AnalyticsRequest) (map[string
 	if err != nil {
 		return nil, fmt.Errorf("failed to get company metrics: %w", err)
 	}
-	analytics["company_performance"] = companyMetrics
+	analytics.CompanyPerformance = companyMetrics
 
 	// Company type distribution
-	var typeDistribution []struct {
-		CompanyType  string `json:"company_type"`
-		Count        int64  `json:"count"`
-		StudentCount int64  `json:"student_count"`
-	}
+	var typeDistribution []CompanyTypeStats
 	err = s.db.Raw(`
 		SELECT 
 			c.company_type,
@@ -273,7 +289,7 @@ func (s *AnalyticsService) GetCompanyAnalytics(req AnalyticsRequest) (map[string
 	if err != nil {
 		return nil, fmt.Errorf("failed to get company type distribution: %w", err)
 	}
-	analytics["type_distribution"] = typeDistribution
+	analytics.TypeDistribution = typeDistribution
 
 	return analytics, nil
 }
@@ -522,6 +538,21 @@ type ReportRequest struct {
 
 // GenerateReport generates a report based on the request
 func (s *AnalyticsService) GenerateReport(req ReportRequest) ([]byte, string, error) {
+	// Reject unknown formats before running any queries
+	if _, err := reportExtension(req.Format); err != nil {
+		return nil, "", err
+	}
+
+	doc, err := s.buildReportDocument(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return renderReport(doc, req.Format, string(req.Type))
+}
+
+// buildReportDocument collects the tables of a report without rendering them
+func (s *AnalyticsService) buildReportDocument(req ReportRequest) (*reportDocument, error) {
 	// Set default dates if not provided
 	if req.StartDate.IsZero() {
 		req.StartDate = time.Now().AddDate(-1, 0, 0)
@@ -538,24 +569,36 @@ func (s *AnalyticsService) GenerateReport(req ReportRequest) ([]byte, string, er
 			req.EndDate.Format("2006-01-02"))
 	}
 
+	doc := &reportDocument{
+		Title:       req.Title,
+		Period:      fmt.Sprintf("%s to %s", req.StartDate.Format("2006-01-02"), req.EndDate.Format("2006-01-02")),
+		GeneratedAt: time.Now(),
+	}
+
+	var err error
 	switch req.Type {
 	case AnalyticsReportTypeInternship:
-		return s.generateInternshipReport(req)
+		err = s.generateInternshipReport(req, doc)
 	case AnalyticsReportTypeApproval:
-		return s.generateApprovalReport(req)
+		err = s.generateApprovalReport(req, doc)
 	case AnalyticsReportTypeCompany:
-		return s.generateCompanyReport(req)
+		err = s.generateCompanyReport(req, doc)
 	case AnalyticsReportTypeStudent:
-		return s.generateStudentReport(req)
+		err = s.generateStudentReport(req, doc)
 	case AnalyticsReportTypeEvaluation:
-		return s.generateEvaluationReport(req)
+		err = s.generateEvaluationReport(req, doc)
 	default:
-		return nil, "", fmt.Errorf("unsupported report type: %s", req.Type)
+		return nil, fmt.Errorf("unsupported report type: %s", req.Type)
+	}
+	if err != nil {
+		return nil, err
 	}
+
+	return doc, nil
 }
 
-// generateInternshipReport generates internship summary report
-func (s *AnalyticsService) generateInternshipReport(req ReportRequest) ([]byte, string, error) {
+// generateInternshipReport adds the internship summary tables
+func (s *AnalyticsService) generateInternshipReport(req ReportRequest, doc *reportDocument) error {
 	// Get analytics data
 	analyticsReq := AnalyticsRequest{
 		StartDate: req.StartDate,
@@ -564,23 +607,54 @@ func (s *AnalyticsService) generateInternshipReport(req ReportRequest) ([]byte,
 	
 	analytics, err := s.GetInternshipAnalytics(analyticsReq)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get analytics data: %w", err)
+		return fmt.Errorf("failed to get analytics data: %w", err)
 	}
 
-	switch req.Format {
-	case ReportFormatCSV:
-		return s.generateCSVReport(analytics, req.Title)
-	case ReportFormatPDF:
-		return s.generatePDFReport(analytics, req.Title)
-	case ReportFormatExcel:
-		return s.generateExcelReport(analytics, req.Title)
-	default:
-		return nil, "", fmt.Errorf("unsupported report format: %s", req.Format)
+	doc.addTable("Summary", []string{"Metric", "Value"}, [][]interface{}{
+		{"Total Internships", analytics.TotalInternships},
+		{"Approval Rate (%)", analytics.ApprovalRate},
+		{"Completion Rate (%)", analytics.CompletionRate},
+	})
+
+	companies := make([][]interface{}, 0, len(analytics.TopCompanies))
+	for _, company := range analytics.TopCompanies {
+		companies = append(companies, []interface{}{company.CompanyName, company.StudentCount, company.SuccessRate, company.AverageRating})
 	}
+	doc.addTable("Top Companies", []string{"Company Name", "Student Count", "Success Rate (%)", "Average Rating"}, companies)
+
+	trends := make([][]interface{}, 0, len(analytics.MonthlyTrends))
+	for _, trend := range analytics.MonthlyTrends {
+		trends = append(trends, []interface{}{trend.Month, trend.InternshipCount, trend.ApprovalCount, trend.CompletionCount})
+	}
+	doc.addTable("Monthly Trends", []string{"Month", "Internships", "Approvals", "Completions"}, trends)
+
+	faculties := make([][]interface{}, 0, len(analytics.FacultyDistribution))
+	for _, faculty := range analytics.FacultyDistribution {
+		faculties = append(faculties, []interface{}{faculty.FacultyName, faculty.StudentCount, faculty.InternshipCount, faculty.SuccessRate})
+	}
+	doc.addTable("Faculty Distribution", []string{"Faculty", "Students", "Internships", "Success Rate (%)"}, faculties)
+
+	statuses := make([][]interface{}, 0, len(analytics.StatusDistribution))
+	for _, status := range analytics.StatusDistribution {
+		statuses = append(statuses, []interface{}{status.Status, status.Count, status.Percentage})
+	}
+	doc.addTable("Status Distribution", []string{"Status", "Count", "Percentage (%)"}, statuses)
+
+	metrics := analytics.EvaluationMetrics
+	doc.addTable("Evaluation Metrics", []string{"Metric", "Value"}, [][]interface{}{
+		{"Total Evaluations", metrics.TotalEvaluations},
+		{"Completed Evaluations", metrics.CompletedEvaluations},
+		{"Pending Evaluations", metrics.PendingEvaluations},
+		{"Overdue Evaluations", metrics.OverdueEvaluations},
+		{"Completion Rate (%)", metrics.CompletionRate},
+		{"Average Score", metrics.AverageScore},
+	})
+
+	return nil
 }
 
-// generateApprovalReport generates approval workflow report
-func (s *AnalyticsService) generateApprovalReport(req ReportRequest) ([]byte, string, error) {
+// generateApprovalReport adds the approval workflow tables
+func (s *AnalyticsService) generateApprovalReport(req ReportRequest, doc *reportDocument) error {
 	analyticsReq := AnalyticsRequest{
 		StartDate: req.StartDate,
 		EndDate:   req.EndDate,
@@ -588,15 +662,30 @@ func (s *AnalyticsService) generateApprovalReport(req ReportRequest) ([]byte, st
 	
 	analytics, err := s.GetApprovalAnalytics(analyticsReq)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get approval analytics: %w", err)
+		return fmt.Errorf("failed to get approval analytics: %w", err)
 	}
 
-	// Convert to CSV format (simplified implementation)
-	return s.generateSimpleCSV(analytics, "Approval Report")
+	doc.addTable("Summary", []string{"Metric", "Value"}, [][]interface{}{
+		{"Average Approval Time (hours)", analytics.AverageApprovalTimeHours},
+	})
+
+	statuses := make([][]interface{}, 0, len(analytics.ByStatus))
+	for _, status := range analytics.ByStatus {
+		statuses = append(statuses, []interface{}{status.Status, status.Count})
+	}
+	doc.addTable("Approvals by Status", []string{"Status", "Count"}, statuses)
+
+	trends := make([][]interface{}, 0, len(analytics.MonthlyTrends))
+	for _, trend := range analytics.MonthlyTrends {
+		trends = append(trends, []interface{}{trend.Month, trend.Count})
+	}
+	doc.addTable("Monthly Trends", []string{"Month", "Approvals"}, trends)
+
+	return nil
 }
 
-// generateCompanyReport generates company performance report
-func (s *AnalyticsService) generateCompanyReport(req ReportRequest) ([]byte, string, error) {
+// generateCompanyReport adds the company performance tables
+func (s *AnalyticsService) generateCompanyReport(req ReportRequest, doc *reportDocument) error {
 	analyticsReq := AnalyticsRequest{
 		StartDate: req.StartDate,
 		EndDate:   req.EndDate,
@@ -604,14 +693,26 @@ func (s *AnalyticsService) generateCompanyReport(req ReportRequest) ([]byte, str
 	
 	analytics, err := s.GetCompanyAnalytics(analyticsReq)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get company analytics: %w", err)
+		return fmt.Errorf("failed to get company analytics: %w", err)
+	}
+
+	companies := make([][]interface{}, 0, len(analytics.CompanyPerformance))
+	for _, company := range analytics.CompanyPerformance {
+		companies = append(companies, []interface{}{company.CompanyID, company.CompanyName, company.StudentCount, company.SuccessRate, company.AverageRating})
+	}
+	doc.addTable("Company Performance", []string{"Company ID", "Company Name", "Student Count", "Success Rate (%)", "Average Rating"}, companies)
+
+	types := make([][]interface{}, 0, len(analytics.TypeDistribution))
+	for _, companyType := range analytics.TypeDistribution {
+		types = append(types, []interface{}{companyType.CompanyType, companyType.Count, companyType.StudentCount})
 	}
+	doc.addTable("Company Types", []string{"Company Type", "Companies", "Students"}, types)
 
-	return s.generateSimpleCSV(analytics, "Company Report")
+	return nil
 }
 
-// generateStudentReport generates student statistics report
-func (s *AnalyticsService) generateStudentReport(req ReportRequest) ([]byte, string, error) {
+// generateStudentReport adds the student statistics table
+func (s *AnalyticsService) generateStudentReport(req ReportRequest, doc *reportDocument) error {
 	// Get student data
 	var students []struct {
 		StudentID   string  `json:"student_id"`
@@ -627,10 +728,10 @@ func (s *AnalyticsService) generateStudentReport(req ReportRequest) ([]byte, str
 		SELECT 
 			s.student_id,
 			CONCAT(s.name, ' ', s.surname) as name,
-			f.name as faculty,
-			m.name as major,
+			COALESCE(f.name, '') as faculty,
+			COALESCE(m.major_name_th, '') as major,
 			s.gpax,
-			c.company_name_en as company_name,
+			COALESCE(c.company_name_en, '') as company_name,
 			COALESCE(ia.status, 'not_applied') as status
 		FROM students s
 		LEFT JOIN faculties f ON s.faculty_id = f.id
@@ -643,32 +744,38 @@ func (s *AnalyticsService) generateStudentReport(req ReportRequest) ([]byte, str
 		ORDER BY s.student_id
 	`, req.StartDate, req.EndDate).Scan(&students).Error
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get student data: %w", err)
+		return fmt.Errorf("failed to get student data: %w", err)
 	}
 
-	return s.generateSimpleCSV(map[string]interface{}{"students": students}, "Student Report")
+	rows := make([][]interface{}, 0, len(students))
+	for _, student := range students {
+		rows = append(rows, []interface{}{student.StudentID, student.Name, student.Faculty, student.Major, student.GPAX, student.CompanyName, student.Status})
+	}
+	doc.addTable("Students", []string{"Student ID", "Name", "Faculty", "Major", "GPAX", "Company", "Status"}, rows)
+
+	return nil
 }
 
-// generateEvaluationReport generates evaluation status report
-func (s *AnalyticsService) generateEvaluationReport(req ReportRequest) ([]byte, string, error) {
+// generateEvaluationReport adds the evaluation status table
+func (s *AnalyticsService) generateEvaluationReport(req ReportRequest, doc *reportDocument) error {
 	// Get evaluation data
 	var evaluations []struct {
-		StudentName    string `json:"student_name"`
-		CompanyName    string `json:"company_name"`
-		EvaluationType string `json:"evaluation_type"`
-		Status         string `json:"status"`
-		DueDate        string `json:"due_date"`
-		CompletedAt    string `json:"completed_at"`
+		StudentName    string     `json:"student_name"`
+		CompanyName    string     `json:"company_name"`
+		EvaluationType string     `json:"evaluation_type"`
+		Status         string     `json:"status"`
+		DueDate        *time.Time `json:"due_date"`
+		CompletedAt    *time.Time `json:"completed_at"`
 	}
 
 	err := s.db.Raw(`
 		SELECT 
 			CONCAT(s.name, ' ', s.surname) as student_name,
-			c.company_name_en as company_name,
+			COALESCE(c.company_name_en, '') as company_name,
 			est.evaluation_type,
 			est.status,
-			DATE(est.due_date) as due_date,
-			DATE(est.completed_at) as completed_at
+			est.due_date,
+			est.completed_at
 		FROM evaluation_status_trackers est
 		JOIN student_trainings st ON est.student_training_id = st.id
 		JOIN student_enrolls se ON st.student_enroll_id = se.id
@@ -678,58 +785,14 @@ func (s *AnalyticsService) generateEvaluationReport(req ReportRequest) ([]byte,
 		ORDER BY est.due_date, s.student_id
 	`, req.StartDate, req.EndDate).Scan(&evaluations).Error
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get evaluation data: %w", err)
+		return fmt.Errorf("failed to get evaluation data: %w", err)
 	}
 
-	return s.generateSimpleCSV(map[string]interface{}{"evaluations": evaluations}, "Evaluation Report")
-}
-
-// Helper methods for report generation
-func (s *AnalyticsService) generateCSVReport(data *AnalyticsResponse, title string) ([]byte, string, error) {
-	csv := fmt.Sprintf("%s\n\n", title)
-	csv += "Summary Statistics\n"
-	csv += fmt.Sprintf("Total Internships,%d\n", data.TotalInternships)
-	csv += fmt.Sprintf("Approval Rate,%.2f%%\n", data.ApprovalRate)
-	csv += fmt.Sprintf("Completion Rate,%.2f%%\n", data.CompletionRate)
-	csv += "\n"
-
-	csv += "Top Companies\n"
-	csv += "Company Name,Student Count,Success Rate,Average Rating\n"
-	for _, company := range data.TopCompanies {
-		csv += fmt.Sprintf("%s,%d,%.2f%%,%.2f\n", 
-			company.CompanyName, company.StudentCount, company.SuccessRate, company.AverageRating)
+	rows := make([][]interface{}, 0, len(evaluations))
+	for _, evaluation := range evaluations {
+		rows = append(rows, []interface{}{evaluation.StudentName, evaluation.CompanyName, evaluation.EvaluationType, evaluation.Status, evaluation.DueDate, evaluation.CompletedAt})
 	}
+	doc.addTable("Evaluations", []string{"Student", "Company", "Evaluation Type", "Status", "Due Date", "Completed At"}, rows)
 
-	filename := fmt.Sprintf("internship_report_%s.csv", time.Now().Format("20060102_150405"))
-	return []byte(csv), filename, nil
-}
-
-func (s *AnalyticsService) generatePDFReport(data *AnalyticsResponse, title string) ([]byte, string, error) {
-	// Simplified PDF generation - in a real implementation, you'd use a PDF library
-	content := fmt.Sprintf("PDF Report: %s\n\nTotal Internships: %d\nApproval Rate: %.2f%%\nCompletion Rate: %.2f%%", 
-		title, data.TotalInternships, data.ApprovalRate, data.CompletionRate)
-	
-	filename := fmt.Sprintf("internship_report_%s.pdf", time.Now().Format("20060102_150405"))
-	return []byte(content), filename, nil
-}
-
-func (s *AnalyticsService) generateExcelReport(data *AnalyticsResponse, title string) ([]byte, string, error) {
-	// Simplified Excel generation - in a real implementation, you'd use an Excel library
-	content := fmt.Sprintf("Excel Report: %s\n\nTotal Internships: %d\nApproval Rate: %.2f%%\nCompletion Rate: %.2f%%", 
-		title, data.TotalInternships, data.ApprovalRate, data.CompletionRate)
-	
-	filename := fmt.Sprintf("internship_report_%s.xlsx", time.Now().Format("20060102_150405"))
-	return []byte(content), filename, nil
+	return nil
 }
-
-func (s *AnalyticsService) generateSimpleCSV(data map[string]interface{}, title string) ([]byte, string, error) {
-	csv := fmt.Sprintf("%s\n\n", title)
-	
-	// Simple CSV generation for any data structure
-	for key, value := range data {
-		csv += fmt.Sprintf("%s: %v\n", key, value)
-	}
-	
-	filename := fmt.Sprintf("report_%s.csv", time.Now().Format("20060102_150405"))
-	return []byte(csv), filename, nil
-}
\ No newline at end of file
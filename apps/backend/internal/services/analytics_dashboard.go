@@ -0,0 +1,580 @@
+package services
+
+import (
+	"backend-go/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnalyticsDashboardService manages configurable staff dashboards and resolves their widget data.
+// A dashboard is visible to its owner and, when public, to every other staff member; only the
+// owner can change it.
+type AnalyticsDashboardService struct {
+	db               *gorm.DB
+	analyticsService *AnalyticsService
+}
+
+// CreateDashboardRequest represents the request for creating a dashboard.
+// Template starts from a built-in template, TemplateID copies the widgets of a visible dashboard.
+type CreateDashboardRequest struct {
+	Name        string          `json:"name" validate:"required,max=255"`
+	Description string          `json:"description"`
+	Layout      json.RawMessage `json:"layout"`
+	IsDefault   bool            `json:"is_default"`
+	IsPublic    bool            `json:"is_public"`
+	Template    string          `json:"template"`
+	TemplateID  *uint           `json:"template_id"`
+}
+
+// UpdateDashboardRequest represents the request for updating a dashboard
+type UpdateDashboardRequest struct {
+	Name        *string         `json:"name" validate:"omitempty,min=1,max=255"`
+	Description *string         `json:"description"`
+	Layout      json.RawMessage `json:"layout"`
+	IsDefault   *bool           `json:"is_default"`
+	IsPublic    *bool           `json:"is_public"`
+}
+
+// CreateWidgetRequest represents the request for adding a widget to a dashboard
+type CreateWidgetRequest struct {
+	WidgetType  WidgetType      `json:"widget_type" validate:"required,oneof=kpi time_series bar_by_faculty table status_donut"`
+	Title       string          `json:"title" validate:"required,max=255"`
+	Position    json.RawMessage `json:"position"`
+	Config      json.RawMessage `json:"config"`
+	RefreshRate int             `json:"refresh_rate" validate:"min=0"`
+	IsVisible   *bool           `json:"is_visible"`
+}
+
+// UpdateWidgetRequest represents the request for updating a widget. The type of a widget cannot change.
+type UpdateWidgetRequest struct {
+	Title       *string         `json:"title" validate:"omitempty,min=1,max=255"`
+	Position    json.RawMessage `json:"position"`
+	Config      json.RawMessage `json:"config"`
+	RefreshRate *int            `json:"refresh_rate" validate:"omitempty,min=0"`
+	IsVisible   *bool           `json:"is_visible"`
+}
+
+// DashboardListRequest represents the request for listing dashboards
+type DashboardListRequest struct {
+	Page      int    `json:"page"`
+	Limit     int    `json:"limit"`
+	OwnedOnly bool   `json:"owned_only"`
+	Search    string `json:"search"`
+}
+
+// DashboardListResponse represents the response for listing dashboards, with the built-in templates
+// a new dashboard can start from
+type DashboardListResponse struct {
+	Data       []models.Dashboard  `json:"data"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+	Templates  []DashboardTemplate `json:"templates"`
+}
+
+// DashboardView is a dashboard with the data of each of its widgets resolved
+type DashboardView struct {
+	models.Dashboard
+	Widgets []ResolvedWidget `json:"widgets"`
+}
+
+// ResolvedWidget is a widget with its data. A widget that fails to resolve carries the error
+// instead, so one bad widget does not break the whole dashboard.
+type ResolvedWidget struct {
+	models.DashboardWidget
+	Data  interface{} `json:"data"`
+	Error string      `json:"error,omitempty"`
+}
+
+// DashboardTemplate is a built-in starting point for a new dashboard
+type DashboardTemplate struct {
+	Key         string                `json:"key"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Widgets     []CreateWidgetRequest `json:"widgets"`
+}
+
+// dashboardTemplates lists the built-in dashboard templates
+var dashboardTemplates = []DashboardTemplate{
+	{
+		Key:         "internship_overview",
+		Name:        "Internship Overview",
+		Description: "Internship volume, approval and completion over the last year",
+		Widgets: []CreateWidgetRequest{
+			{WidgetType: WidgetTypeKPI, Title: "Total Internships", Position: widgetPosition(0, 0, 3, 2), Config: json.RawMessage(`{"metric":"total_internships","compare_previous":true}`)},
+			{WidgetType: WidgetTypeKPI, Title: "Approval Rate", Position: widgetPosition(3, 0, 3, 2), Config: json.RawMessage(`{"metric":"approval_rate","compare_previous":true}`)},
+			{WidgetType: WidgetTypeKPI, Title: "Completion Rate", Position: widgetPosition(6, 0, 3, 2), Config: json.RawMessage(`{"metric":"completion_rate","compare_previous":true}`)},
+			{WidgetType: WidgetTypeKPI, Title: "Average Approval Time", Position: widgetPosition(9, 0, 3, 2), Config: json.RawMessage(`{"metric":"average_approval_time"}`)},
+			{WidgetType: WidgetTypeTimeSeries, Title: "Monthly Trends", Position: widgetPosition(0, 2, 8, 4), Config: json.RawMessage(`{"series":["internships","approvals","completions"]}`)},
+			{WidgetType: WidgetTypeStatusDonut, Title: "Approval Status", Position: widgetPosition(8, 2, 4, 4), Config: json.RawMessage(`{"source":"approval_status"}`)},
+			{WidgetType: WidgetTypeBarByFaculty, Title: "Internships by Faculty", Position: widgetPosition(0, 6, 6, 4), Config: json.RawMessage(`{"metric":"internship_count"}`)},
+			{WidgetType: WidgetTypeTable, Title: "Top Companies", Position: widgetPosition(6, 6, 6, 4), Config: json.RawMessage(`{"source":"top_companies","limit":10}`)},
+		},
+	},
+	{
+		Key:         "evaluation_tracking",
+		Name:        "Evaluation Tracking",
+		Description: "Evaluation progress and faculty outcomes for the current term",
+		Widgets: []CreateWidgetRequest{
+			{WidgetType: WidgetTypeKPI, Title: "Pending Evaluations", Position: widgetPosition(0, 0, 4, 2), Config: json.RawMessage(`{"metric":"pending_evaluations","period_days":180}`)},
+			{WidgetType: WidgetTypeKPI, Title: "Overdue Evaluations", Position: widgetPosition(4, 0, 4, 2), Config: json.RawMessage(`{"metric":"overdue_evaluations","period_days":180}`)},
+			{WidgetType: WidgetTypeKPI, Title: "Evaluation Completion", Position: widgetPosition(8, 0, 4, 2), Config: json.RawMessage(`{"metric":"evaluation_completion_rate","period_days":180}`)},
+			{WidgetType: WidgetTypeStatusDonut, Title: "Evaluation Status", Position: widgetPosition(0, 2, 4, 4), Config: json.RawMessage(`{"source":"evaluation_status","period_days":180}`)},
+			{WidgetType: WidgetTypeBarByFaculty, Title: "Success Rate by Faculty", Position: widgetPosition(4, 2, 8, 4), Config: json.RawMessage(`{"metric":"success_rate","period_days":180}`)},
+			{WidgetType: WidgetTypeTable, Title: "Company Performance", Position: widgetPosition(0, 6, 12, 4), Config: json.RawMessage(`{"source":"company_performance","period_days":180,"limit":20}`)},
+		},
+	},
+}
+
+// widgetPosition builds the grid position JSON of a widget
+func widgetPosition(x, y, w, h int) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"x":%d,"y":%d,"w":%d,"h":%d}`, x, y, w, h))
+}
+
+// defaultDashboardLayout is the grid used when a dashboard is created without a layout
+var defaultDashboardLayout = json.RawMessage(`{"columns":12}`)
+
+// NewAnalyticsDashboardService creates a new analytics dashboard service instance
+func NewAnalyticsDashboardService(db *gorm.DB) *AnalyticsDashboardService {
+	return &AnalyticsDashboardService{
+		db:               db,
+		analyticsService: NewAnalyticsService(db),
+	}
+}
+
+// GetTemplates returns the built-in dashboard templates
+func (s *AnalyticsDashboardService) GetTemplates() []DashboardTemplate {
+	return dashboardTemplates
+}
+
+// GetDashboards retrieves the caller's dashboards and dashboards shared by other staff.
+// The caller's default dashboard is listed first.
+func (s *AnalyticsDashboardService) GetDashboards(req DashboardListRequest, ownerID uint, ownerType string) (*DashboardListResponse, error) {
+	var dashboards []models.Dashboard
+	var total int64
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	query := s.db.Model(&models.Dashboard{})
+	if req.OwnedOnly {
+		query = query.Scopes(ownedDashboards(ownerID, ownerType))
+	} else {
+		query = query.Scopes(visibleDashboards(ownerID, ownerType))
+	}
+
+	if req.Search != "" {
+		query = query.Where("(name ILIKE ? OR description ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order(clause.OrderBy{Expression: gorm.Expr(
+		"(owner_id = ? AND owner_type = ? AND is_default = ?) DESC, updated_at DESC", ownerID, ownerType, true,
+	)}).
+		Offset(offset).Limit(req.Limit).
+		Find(&dashboards).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &DashboardListResponse{
+		Data:       dashboards,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+		Templates:  dashboardTemplates,
+	}, nil
+}
+
+// GetDashboard retrieves a dashboard the caller owns or that has been shared, with the data of
+// its visible widgets resolved
+func (s *AnalyticsDashboardService) GetDashboard(id uint, ownerID uint, ownerType string) (*DashboardView, error) {
+	dashboard, err := s.findDashboard(id, visibleDashboards(ownerID, ownerType))
+	if err != nil {
+		return nil, err
+	}
+
+	data := newWidgetDataSet(s.analyticsService, time.Now())
+	view := &DashboardView{Dashboard: *dashboard, Widgets: make([]ResolvedWidget, 0, len(dashboard.Widgets))}
+	for _, widget := range dashboard.Widgets {
+		resolved := ResolvedWidget{DashboardWidget: widget}
+		if widget.IsVisible {
+			resolved.Data, err = resolveWidget(data, widget)
+			if err != nil {
+				resolved.Error = err.Error()
+			}
+		}
+		view.Widgets = append(view.Widgets, resolved)
+	}
+	view.Dashboard.Widgets = nil
+
+	return view, nil
+}
+
+// GetWidgetData resolves the data of a single widget, for refreshing it on its own refresh rate
+func (s *AnalyticsDashboardService) GetWidgetData(dashboardID, widgetID uint, ownerID uint, ownerType string) (interface{}, error) {
+	if _, err := s.findDashboard(dashboardID, visibleDashboards(ownerID, ownerType)); err != nil {
+		return nil, err
+	}
+
+	widget, err := s.findWidget(dashboardID, widgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveWidget(newWidgetDataSet(s.analyticsService, time.Now()), *widget)
+}
+
+// CreateDashboard creates a dashboard for the caller, optionally starting from a template
+func (s *AnalyticsDashboardService) CreateDashboard(req CreateDashboardRequest, ownerID uint, ownerType string) (*models.Dashboard, error) {
+	var widgets []CreateWidgetRequest
+	switch {
+	case req.Template != "" && req.TemplateID != nil:
+		return nil, errors.New("choose either a template or a template dashboard")
+	case req.Template != "":
+		template, ok := findDashboardTemplate(req.Template)
+		if !ok {
+			return nil, errors.New("dashboard template not found")
+		}
+		widgets = template.Widgets
+	case req.TemplateID != nil:
+		source, err := s.findDashboard(*req.TemplateID, visibleDashboards(ownerID, ownerType))
+		if err != nil {
+			return nil, errors.New("dashboard template not found")
+		}
+		for _, widget := range source.Widgets {
+			visible := widget.IsVisible
+			widgets = append(widgets, CreateWidgetRequest{
+				WidgetType:  WidgetType(widget.WidgetType),
+				Title:       widget.Title,
+				Position:    widget.Position,
+				Config:      widget.Config,
+				RefreshRate: widget.RefreshRate,
+				IsVisible:   &visible,
+			})
+		}
+		if len(req.Layout) == 0 {
+			req.Layout = source.Layout
+		}
+	}
+
+	layout := req.Layout
+	if len(layout) == 0 || string(layout) == "null" {
+		layout = defaultDashboardLayout
+	} else if !json.Valid(layout) {
+		return nil, errors.New("invalid dashboard layout")
+	}
+
+	dashboard := models.Dashboard{
+		Name:        req.Name,
+		Description: req.Description,
+		Layout:      layout,
+		IsDefault:   req.IsDefault,
+		IsPublic:    req.IsPublic,
+		OwnerID:     ownerID,
+		OwnerType:   ownerType,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if dashboard.IsDefault {
+			if err := clearDefaultDashboard(tx, ownerID, ownerType); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&dashboard).Error; err != nil {
+			return err
+		}
+
+		for _, widgetReq := range widgets {
+			widget, err := newDashboardWidget(dashboard.ID, widgetReq)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(widget).Error; err != nil {
+				return err
+			}
+			dashboard.Widgets = append(dashboard.Widgets, *widget)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dashboard, nil
+}
+
+// UpdateDashboard updates a dashboard owned by the caller
+func (s *AnalyticsDashboardService) UpdateDashboard(id uint, req UpdateDashboardRequest, ownerID uint, ownerType string) (*models.Dashboard, error) {
+	dashboard, err := s.findDashboard(id, ownedDashboards(ownerID, ownerType))
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if len(req.Layout) > 0 {
+		if !json.Valid(req.Layout) || string(req.Layout) == "null" {
+			return nil, errors.New("invalid dashboard layout")
+		}
+		updates["layout"] = req.Layout
+	}
+	if req.IsDefault != nil {
+		updates["is_default"] = *req.IsDefault
+	}
+	if req.IsPublic != nil {
+		updates["is_public"] = *req.IsPublic
+	}
+
+	if len(updates) > 0 {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if req.IsDefault != nil && *req.IsDefault {
+				if err := clearDefaultDashboard(tx, ownerID, ownerType); err != nil {
+					return err
+				}
+			}
+			return tx.Model(&models.Dashboard{}).Where("id = ?", dashboard.ID).Updates(updates).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.findDashboard(dashboard.ID, ownedDashboards(ownerID, ownerType))
+}
+
+// DeleteDashboard deletes a dashboard owned by the caller together with its widgets
+func (s *AnalyticsDashboardService) DeleteDashboard(id uint, ownerID uint, ownerType string) error {
+	dashboard, err := s.findDashboard(id, ownedDashboards(ownerID, ownerType))
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("dashboard_id = ?", dashboard.ID).Delete(&models.DashboardWidget{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Dashboard{}, dashboard.ID).Error
+	})
+}
+
+// AddWidget adds a widget to a dashboard owned by the caller
+func (s *AnalyticsDashboardService) AddWidget(dashboardID uint, req CreateWidgetRequest, ownerID uint, ownerType string) (*models.DashboardWidget, error) {
+	if _, err := s.findDashboard(dashboardID, ownedDashboards(ownerID, ownerType)); err != nil {
+		return nil, err
+	}
+
+	widget, err := newDashboardWidget(dashboardID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(widget).Error; err != nil {
+		return nil, err
+	}
+
+	return widget, nil
+}
+
+// UpdateWidget updates a widget of a dashboard owned by the caller
+func (s *AnalyticsDashboardService) UpdateWidget(dashboardID, widgetID uint, req UpdateWidgetRequest, ownerID uint, ownerType string) (*models.DashboardWidget, error) {
+	if _, err := s.findDashboard(dashboardID, ownedDashboards(ownerID, ownerType)); err != nil {
+		return nil, err
+	}
+
+	widget, err := s.findWidget(dashboardID, widgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if len(req.Position) > 0 {
+		if !json.Valid(req.Position) || string(req.Position) == "null" {
+			return nil, errors.New("invalid widget position")
+		}
+		updates["position"] = req.Position
+	}
+	if len(req.Config) > 0 {
+		cfg, err := parseWidgetConfig(req.Config)
+		if err != nil {
+			return nil, err
+		}
+		dataSource, err := validateWidgetConfig(WidgetType(widget.WidgetType), cfg)
+		if err != nil {
+			return nil, err
+		}
+		updates["config"] = req.Config
+		updates["data_source"] = dataSource
+	}
+	if req.RefreshRate != nil {
+		updates["refresh_rate"] = *req.RefreshRate
+	}
+	if req.IsVisible != nil {
+		updates["is_visible"] = *req.IsVisible
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(&models.DashboardWidget{}).Where("id = ?", widget.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.findWidget(dashboardID, widgetID)
+}
+
+// DeleteWidget removes a widget from a dashboard owned by the caller
+func (s *AnalyticsDashboardService) DeleteWidget(dashboardID, widgetID uint, ownerID uint, ownerType string) error {
+	if _, err := s.findDashboard(dashboardID, ownedDashboards(ownerID, ownerType)); err != nil {
+		return err
+	}
+
+	result := s.db.Where("id = ? AND dashboard_id = ?", widgetID, dashboardID).Delete(&models.DashboardWidget{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("widget not found")
+	}
+	return nil
+}
+
+// findDashboard loads a dashboard and its widgets within the given visibility scope
+func (s *AnalyticsDashboardService) findDashboard(id uint, scope func(*gorm.DB) *gorm.DB) (*models.Dashboard, error) {
+	var dashboard models.Dashboard
+	err := s.db.Scopes(scope).
+		Preload("Widgets", func(tx *gorm.DB) *gorm.DB { return tx.Order("id ASC") }).
+		First(&dashboard, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("dashboard not found")
+		}
+		return nil, err
+	}
+	return &dashboard, nil
+}
+
+// findWidget loads a widget of a dashboard
+func (s *AnalyticsDashboardService) findWidget(dashboardID, widgetID uint) (*models.DashboardWidget, error) {
+	var widget models.DashboardWidget
+	if err := s.db.Where("id = ? AND dashboard_id = ?", widgetID, dashboardID).First(&widget).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("widget not found")
+		}
+		return nil, err
+	}
+	return &widget, nil
+}
+
+// newDashboardWidget validates a widget request and builds the widget to store
+func newDashboardWidget(dashboardID uint, req CreateWidgetRequest) (*models.DashboardWidget, error) {
+	cfg, err := parseWidgetConfig(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	dataSource, err := validateWidgetConfig(req.WidgetType, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	position := req.Position
+	if len(position) == 0 || string(position) == "null" {
+		position = widgetPosition(0, 0, 4, 3)
+	} else if !json.Valid(position) {
+		return nil, errors.New("invalid widget position")
+	}
+
+	config := req.Config
+	if len(config) == 0 {
+		config = json.RawMessage(`{}`)
+	}
+
+	refreshRate := req.RefreshRate
+	if refreshRate == 0 {
+		refreshRate = 300
+	}
+
+	isVisible := true
+	if req.IsVisible != nil {
+		isVisible = *req.IsVisible
+	}
+
+	return &models.DashboardWidget{
+		DashboardID: dashboardID,
+		WidgetType:  string(req.WidgetType),
+		Title:       req.Title,
+		Position:    position,
+		Config:      config,
+		DataSource:  dataSource,
+		RefreshRate: refreshRate,
+		IsVisible:   isVisible,
+	}, nil
+}
+
+// resolveWidget computes a stored widget's data from its config
+func resolveWidget(data *widgetDataSet, widget models.DashboardWidget) (interface{}, error) {
+	cfg, err := parseWidgetConfig(widget.Config)
+	if err != nil {
+		return nil, err
+	}
+	return data.resolve(WidgetType(widget.WidgetType), cfg)
+}
+
+// findDashboardTemplate looks up a built-in template by key
+func findDashboardTemplate(key string) (DashboardTemplate, bool) {
+	for _, template := range dashboardTemplates {
+		if template.Key == key {
+			return template, true
+		}
+	}
+	return DashboardTemplate{}, false
+}
+
+// clearDefaultDashboard unsets the caller's current default dashboard so only one remains
+func clearDefaultDashboard(tx *gorm.DB, ownerID uint, ownerType string) error {
+	return tx.Model(&models.Dashboard{}).
+		Scopes(ownedDashboards(ownerID, ownerType)).
+		Where("is_default = ?", true).
+		Update("is_default", false).Error
+}
+
+// ownedDashboards restricts a query to dashboards owned by the caller
+func ownedDashboards(ownerID uint, ownerType string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("owner_id = ? AND owner_type = ?", ownerID, ownerType)
+	}
+}
+
+// visibleDashboards restricts a query to the caller's dashboards and dashboards shared with all staff
+func visibleDashboards(ownerID uint, ownerType string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("((owner_id = ? AND owner_type = ?) OR is_public = ?)", ownerID, ownerType, true)
+	}
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/col"
+	"github.com/johnfercher/maroto/v2/pkg/components/row"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/core/entity"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+	"github.com/johnfercher/maroto/v2/pkg/repository"
+	"github.com/xuri/excelize/v2"
+)
+
+// utf8BOM lets Excel detect UTF-8 so Thai text in CSV files opens correctly
+const utf8BOM = "\xEF\xBB\xBF"
+
+// reportFontFamily is the font family PDF reports are set in. The built-in PDF fonts
+// have no Thai glyphs, so student and company names need a Unicode TrueType font.
+const reportFontFamily = "sarabun"
+
+// reportFontFiles are the Sarabun styles used by PDF reports, looked up in
+// REPORT_FONT_DIRECTORY (assets/fonts by default)
+var reportFontFiles = map[fontstyle.Type]string{
+	fontstyle.Normal: "Sarabun-Regular.ttf",
+	fontstyle.Bold:   "Sarabun-Bold.ttf",
+	fontstyle.Italic: "Sarabun-Italic.ttf",
+}
+
+var (
+	reportFontsOnce sync.Once
+	reportFonts     []*entity.CustomFont
+)
+
+// loadReportFonts reads the Sarabun files once. When they are missing the reports fall back
+// to the built-in font, which renders Thai text as missing glyphs, so a warning is logged.
+func loadReportFonts() []*entity.CustomFont {
+	reportFontsOnce.Do(func() {
+		directory := os.Getenv("REPORT_FONT_DIRECTORY")
+		if directory == "" {
+			directory = "assets/fonts"
+		}
+
+		fonts := repository.New()
+		for style, file := range reportFontFiles {
+			fonts = fonts.AddUTF8Font(reportFontFamily, style, filepath.Join(directory, file))
+		}
+
+		loaded, err := fonts.Load()
+		if err != nil {
+			log.Printf("Warning: Thai report font not loaded from %s, PDF reports use the default font: %v", directory, err)
+			return
+		}
+		reportFonts = loaded
+	})
+	return reportFonts
+}
+
+// reportTable is one titled table of a report
+type reportTable struct {
+	Title   string
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// reportDocument is the format-independent content of an analytics report.
+// Every output format renders the same tables: CSV sections, XLSX sheets or PDF tables.
+type reportDocument struct {
+	Title       string
+	Period      string
+	GeneratedAt time.Time
+	Tables      []reportTable
+}
+
+// addTable appends a table to the document
+func (d *reportDocument) addTable(title string, headers []string, rows [][]interface{}) {
+	d.Tables = append(d.Tables, reportTable{Title: title, Headers: headers, Rows: rows})
+}
+
+// ReportContentType returns the MIME type of a report format
+func ReportContentType(format ReportFormat) string {
+	switch format {
+	case ReportFormatPDF:
+		return "application/pdf"
+	case ReportFormatExcel:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ReportFormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// reportExtension returns the file extension of a report format
+func reportExtension(format ReportFormat) (string, error) {
+	switch format {
+	case ReportFormatPDF:
+		return "pdf", nil
+	case ReportFormatExcel:
+		return "xlsx", nil
+	case ReportFormatCSV:
+		return "csv", nil
+	default:
+		return "", fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// renderReport renders the document in the requested format and names the file after the report type
+func renderReport(doc *reportDocument, format ReportFormat, name string) ([]byte, string, error) {
+	extension, err := reportExtension(format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var content []byte
+	switch format {
+	case ReportFormatCSV:
+		content, err = renderReportCSV(doc)
+	case ReportFormatExcel:
+		content, err = renderReportXLSX(doc)
+	case ReportFormatPDF:
+		content, err = renderReportPDF(doc)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("%s_report_%s.%s", name, doc.GeneratedAt.Format("20060102_150405"), extension)
+	return content, filename, nil
+}
+
+// renderReportCSV writes RFC 4180 CSV (quoted fields, CRLF line endings) prefixed with a UTF-8 BOM.
+// Tables follow each other, separated by an empty line and introduced by their title.
+func renderReportCSV(doc *reportDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(utf8BOM)
+
+	w := csv.NewWriter(&buf)
+	w.UseCRLF = true
+
+	records := [][]string{{doc.Title}}
+	if doc.Period != "" {
+		records = append(records, []string{"Period", doc.Period})
+	}
+	records = append(records, []string{"Generated", doc.GeneratedAt.Format("2006-01-02 15:04:05")})
+
+	for _, table := range doc.Tables {
+		records = append(records, []string{}, []string{table.Title}, table.Headers)
+		for _, values := range table.Rows {
+			record := make([]string, len(values))
+			for i, value := range values {
+				record[i] = formatReportValue(value)
+			}
+			records = append(records, record)
+		}
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("failed to write CSV report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderReportXLSX writes a workbook with one sheet per table. Numbers are stored as
+// numeric cells so they can be summed and charted in Excel.
+func renderReportXLSX(doc *reportDocument) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	titleStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return nil, err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9E1F2"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tables := doc.Tables
+	if len(tables) == 0 {
+		tables = []reportTable{{Title: "Report"}}
+	}
+
+	used := make(map[string]bool)
+	for i, table := range tables {
+		sheet := xlsxSheetName(table.Title, used)
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(sheet); err != nil {
+			return nil, err
+		}
+
+		f.SetCellValue(sheet, "A1", doc.Title)
+		f.SetCellStyle(sheet, "A1", "A1", titleStyle)
+		f.SetCellValue(sheet, "A2", table.Title)
+		if doc.Period != "" {
+			f.SetCellValue(sheet, "A3", "Period: "+doc.Period)
+		}
+
+		const headerRow = 5
+		if len(table.Headers) > 0 {
+			headers := make([]interface{}, len(table.Headers))
+			for j, header := range table.Headers {
+				headers[j] = header
+			}
+			if err := f.SetSheetRow(sheet, fmt.Sprintf("A%d", headerRow), &headers); err != nil {
+				return nil, err
+			}
+			lastCol, _ := excelize.ColumnNumberToName(len(table.Headers))
+			f.SetCellStyle(sheet, fmt.Sprintf("A%d", headerRow), fmt.Sprintf("%s%d", lastCol, headerRow), headerStyle)
+			f.SetColWidth(sheet, "A", lastCol, 20)
+		}
+
+		for j, values := range table.Rows {
+			cells := make([]interface{}, len(values))
+			for k, value := range values {
+				cells[k] = xlsxCellValue(value)
+			}
+			if err := f.SetSheetRow(sheet, fmt.Sprintf("A%d", headerRow+1+j), &cells); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write XLSX report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderReportPDF lays the tables out with maroto, the same engine PDFService uses,
+// set in Sarabun so Thai names render
+func renderReportPDF(doc *reportDocument) ([]byte, error) {
+	builder := config.NewBuilder().
+		WithPageNumber()
+	if fonts := loadReportFonts(); fonts != nil {
+		builder = builder.
+			WithCustomFonts(fonts).
+			WithDefaultFont(&props.Font{Family: reportFontFamily})
+	}
+	cfg := builder.Build()
+
+	m := maroto.New(cfg)
+
+	m.AddRows(
+		row.New(15).Add(
+			col.New(12).Add(text.New(doc.Title, props.Text{
+				Top:   3,
+				Style: fontstyle.Bold,
+				Align: align.Center,
+				Size:  16,
+			})),
+		),
+		row.New(8).Add(
+			col.New(6).Add(text.New(doc.Period, props.Text{Size: 10})),
+			col.New(6).Add(text.New(fmt.Sprintf("Generated: %s", doc.GeneratedAt.Format("2006-01-02 15:04:05")), props.Text{
+				Size:  10,
+				Align: align.Right,
+			})),
+		),
+	)
+
+	for _, table := range doc.Tables {
+		addPDFReportTable(m, table)
+	}
+
+	document, err := m.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+	return document.GetBytes(), nil
+}
+
+// addPDFReportTable adds a table title, a bold header row and auto-height data rows
+func addPDFReportTable(m core.Maroto, table reportTable) {
+	m.AddRows(
+		row.New(5), // Spacer
+		row.New(10).Add(
+			col.New(12).Add(text.New(table.Title, props.Text{Top: 2, Style: fontstyle.Bold, Size: 12})),
+		),
+	)
+
+	widths := pdfColumnWidths(len(table.Headers))
+
+	headers := make([]core.Col, len(table.Headers))
+	for i, header := range table.Headers {
+		headers[i] = col.New(widths[i]).Add(text.New(header, props.Text{Style: fontstyle.Bold, Size: 9}))
+	}
+	m.AddAutoRow(headers...)
+
+	if len(table.Rows) == 0 {
+		m.AddRow(8, col.New(12).Add(text.New("No data", props.Text{Size: 9, Style: fontstyle.Italic})))
+		return
+	}
+
+	for _, values := range table.Rows {
+		cols := make([]core.Col, len(widths))
+		for i := range widths {
+			value := ""
+			if i < len(values) {
+				value = formatReportValue(values[i])
+			}
+			cols[i] = col.New(widths[i]).Add(text.New(value, props.Text{Size: 8}))
+		}
+		m.AddAutoRow(cols...)
+	}
+}
+
+// pdfColumnWidths splits the 12-column grid between n columns, giving the remainder to the leading columns
+func pdfColumnWidths(n int) []int {
+	if n <= 0 {
+		return nil
+	}
+	if n > 12 {
+		n = 12
+	}
+
+	widths := make([]int, n)
+	for i := range widths {
+		widths[i] = 12 / n
+		if i < 12%n {
+			widths[i]++
+		}
+	}
+	return widths
+}
+
+// formatReportValue renders a cell value as text for CSV and PDF output
+func formatReportValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// xlsxCellValue keeps numbers numeric and renders everything else as text
+func xlsxCellValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int, int64, uint, float64:
+		return v
+	default:
+		return formatReportValue(v)
+	}
+}
+
+// xlsxSheetName makes a unique sheet name within Excel's 31 character limit
+func xlsxSheetName(title string, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, title)
+	if name == "" {
+		name = "Sheet"
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		runes := []rune(name)
+		if len(runes)+len(suffix) > 31 {
+			runes = runes[:31-len(suffix)]
+		}
+		candidate = string(runes) + suffix
+	}
+	used[candidate] = true
+	return candidate
+}
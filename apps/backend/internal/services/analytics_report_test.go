@@ -0,0 +1,84 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReportRendering(t *testing.T) {
+	doc := &reportDocument{
+		Title:       "Student Report",
+		Period:      "2024-01-01 to 2024-12-31",
+		GeneratedAt: time.Date(2024, 12, 31, 8, 0, 0, 0, time.UTC),
+	}
+	doc.addTable("Students", []string{"Student ID", "Name", "GPAX"}, [][]interface{}{
+		{"6400001", "สมชาย ใจดี", 3.5},
+		{"6400002", `Quote "and", comma`, 2.75},
+	})
+	doc.addTable("Summary", []string{"Metric", "Value"}, [][]interface{}{
+		{"Total", int64(2)},
+	})
+
+	t.Run("CSV is RFC 4180 with a UTF-8 BOM", func(t *testing.T) {
+		content, filename, err := renderReport(doc, ReportFormatCSV, "student")
+		require.NoError(t, err)
+		assert.Equal(t, "student_report_20241231_080000.csv", filename)
+		assert.True(t, bytes.HasPrefix(content, []byte(utf8BOM)))
+		assert.Contains(t, string(content), "\"Quote \"\"and\"\", comma\",2.75\r\n")
+
+		r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte(utf8BOM))))
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		require.NoError(t, err)
+		assert.Contains(t, records, []string{"6400001", "สมชาย ใจดี", "3.50"})
+	})
+
+	t.Run("XLSX has one sheet per table with numeric cells", func(t *testing.T) {
+		content, filename, err := renderReport(doc, ReportFormatExcel, "student")
+		require.NoError(t, err)
+		assert.Equal(t, "student_report_20241231_080000.xlsx", filename)
+
+		f, err := excelize.OpenReader(bytes.NewReader(content))
+		require.NoError(t, err)
+		defer f.Close()
+
+		assert.Equal(t, []string{"Students", "Summary"}, f.GetSheetList())
+		name, err := f.GetCellValue("Students", "B6")
+		require.NoError(t, err)
+		assert.Equal(t, "สมชาย ใจดี", name)
+
+		cellType, err := f.GetCellType("Students", "C6")
+		require.NoError(t, err)
+		assert.NotEqual(t, excelize.CellTypeSharedString, cellType)
+	})
+
+	t.Run("PDF is a real PDF document", func(t *testing.T) {
+		content, filename, err := renderReport(doc, ReportFormatPDF, "student")
+		require.NoError(t, err)
+		assert.Equal(t, "student_report_20241231_080000.pdf", filename)
+		assert.True(t, bytes.HasPrefix(content, []byte("%PDF-")))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, _, err := renderReport(doc, ReportFormat("docx"), "student")
+		assert.EqualError(t, err, "unsupported report format: docx")
+	})
+
+	t.Run("sheet names are sanitized and unique", func(t *testing.T) {
+		used := make(map[string]bool)
+		assert.Equal(t, "Approvals - Status", xlsxSheetName("Approvals / Status", used))
+		assert.Equal(t, "Approvals - Status (2)", xlsxSheetName("Approvals / Status", used))
+		assert.Len(t, []rune(xlsxSheetName("A very long table title that exceeds the limit", used)), 31)
+	})
+
+	t.Run("PDF column widths fill the grid", func(t *testing.T) {
+		assert.Equal(t, []int{2, 2, 2, 2, 2, 1, 1}, pdfColumnWidths(7))
+		assert.Equal(t, []int{6, 6}, pdfColumnWidths(2))
+	})
+}
@@ -1,11 +1,15 @@
 package services
 
 import (
+	"backend-go/internal/database"
 	"backend-go/internal/models"
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ApprovalService handles internship approval workflow operations
@@ -22,6 +26,18 @@ func NewApprovalService(db *gorm.DB) *ApprovalService {
 	}
 }
 
+// WithContext returns a copy of the service bound to the request context
+func (s *ApprovalService) WithContext(ctx context.Context) *ApprovalService {
+	return s.withDB(database.WithAuditContext(s.db, ctx))
+}
+
+// withDB returns a copy of the service running its queries on db, such as an open transaction
+func (s *ApprovalService) withDB(db *gorm.DB) *ApprovalService {
+	bound := *s
+	bound.db = db
+	return &bound
+}
+
 // ApprovalStatusResponse represents the response for approval status
 type ApprovalStatusResponse struct {
 	StudentEnrollID     uint                                `json:"student_enroll_id"`
@@ -38,12 +54,24 @@ type ApprovalStatusResponse struct {
 
 // CommitteeVotingData represents committee voting information
 type CommitteeVotingData struct {
-	StudentEnrollID        uint                   `json:"student_enroll_id"`
-	TotalCommitteeMembers  int                    `json:"total_committee_members"`
-	CurrentVotes           []models.CommitteeVote `json:"current_votes"`
-	ApprovalPercentage     int                    `json:"approval_percentage"`
-	VotingComplete         bool                   `json:"voting_complete"`
-	FinalDecision          *string                `json:"final_decision"`
+	StudentEnrollID       uint                          `json:"student_enroll_id"`
+	TotalCommitteeMembers int                           `json:"total_committee_members"`
+	CurrentVotes          []models.CommitteeVote        `json:"current_votes"`
+	ApprovalPercentage    int                           `json:"approval_percentage"`
+	VotingComplete        bool                          `json:"voting_complete"`
+	FinalDecision         *string                       `json:"final_decision"`
+	VotingDeadline        *time.Time                    `json:"voting_deadline"`
+	Policy                *models.CommitteeVotingPolicy `json:"policy"`
+	Tally                 models.CommitteeTally         `json:"tally"`
+}
+
+// CommitteeVotingPolicyRequest represents the request for setting a course's committee voting rules
+type CommitteeVotingPolicyRequest struct {
+	QuorumPercent    int                          `json:"quorum_percent"`
+	MajorityRule     models.CommitteeMajorityRule `json:"majority_rule"`
+	AllowAbstain     bool                         `json:"allow_abstain"`
+	ChairTieBreak    bool                         `json:"chair_tie_break"`
+	VotingPeriodDays int                          `json:"voting_period_days"`
 }
 
 // GetApprovalStatus gets the current approval status for a student enrollment
@@ -97,7 +125,8 @@ func (s *ApprovalService) GetApprovalStatus(studentEnrollID uint) (*ApprovalStat
 	return response, nil
 }
 
-// GetCommitteeVotingData gets committee voting information
+// GetCommitteeVotingData gets committee voting information. Only votes of current committee members
+// are counted, under the voting policy of the enrollment's course.
 func (s *ApprovalService) GetCommitteeVotingData(studentEnrollID uint) (*CommitteeVotingData, error) {
 	approval, err := models.GetApprovalByStudentEnrollID(s.db, studentEnrollID)
 	if err != nil {
@@ -109,193 +138,400 @@ func (s *ApprovalService) GetCommitteeVotingData(studentEnrollID uint) (*Committ
 		return nil, err
 	}
 
-	// Get total committee members from course committee
-	var committeeCount int64
-	err = s.db.Model(&models.CourseCommittee{}).
-		Where("course_section_id = ?", approval.StudentEnroll.CourseSectionID).
-		Count(&committeeCount).Error
+	policy, members, err := s.committeeVoting(approval)
 	if err != nil {
 		return nil, err
 	}
+	tally := models.TallyCommitteeVotes(votes, members, policy)
 
-	approvalPercentage, err := approval.CalculateApprovalPercentage()
+	// Voting is complete once it can be resolved, or after the committee already decided
+	inCommittee, err := committeeStage(approval)
 	if err != nil {
 		return nil, err
 	}
 
-	// Determine if voting is complete (simple majority)
-	votingComplete := len(votes) >= int(committeeCount)/2+1
-	
 	var finalDecision *string
-	if votingComplete {
-		approveCount := 0
-		rejectCount := 0
-		for _, vote := range votes {
-			if vote.Vote == "approve" {
-				approveCount++
-			} else if vote.Vote == "reject" {
-				rejectCount++
-			}
-		}
-		
-		if approveCount > rejectCount {
-			decision := "approved"
-			finalDecision = &decision
-		} else {
+	votingComplete := false
+	switch {
+	case inCommittee:
+		if tally.Pending == 0 || votingDeadlinePassed(approval, time.Now()) {
+			votingComplete = true
+			action, _ := committeeDecision(tally)
 			decision := "rejected"
+			if action == models.ActionCommitteeApprove {
+				decision = "approved"
+			}
 			finalDecision = &decision
 		}
+	case len(votes) > 0:
+		votingComplete = true
+		decision := "approved"
+		if approval.Status == models.StatusDenied {
+			decision = "rejected"
+		}
+		finalDecision = &decision
 	}
 
 	return &CommitteeVotingData{
 		StudentEnrollID:       studentEnrollID,
-		TotalCommitteeMembers: int(committeeCount),
+		TotalCommitteeMembers: tally.Members,
 		CurrentVotes:          votes,
-		ApprovalPercentage:    approvalPercentage,
+		ApprovalPercentage:    tally.ApprovalPercentage,
 		VotingComplete:        votingComplete,
 		FinalDecision:         finalDecision,
+		VotingDeadline:        approval.VotingDeadline,
+		Policy:                policy,
+		Tally:                 tally,
 	}, nil
 }
 
-// AdvisorApproval handles advisor approval or rejection
-func (s *ApprovalService) AdvisorApproval(studentEnrollID uint, advisorID uint, approved bool, remarks string) error {
+// AdvisorApproval handles advisor approval or rejection, taking the advisor action the enrollment's
+// workflow defines for its current status
+func (s *ApprovalService) AdvisorApproval(studentEnrollID uint, actor ApprovalActor, approved bool, remarks string) error {
 	approval, err := models.GetApprovalByStudentEnrollID(s.db, studentEnrollID)
 	if err != nil {
 		return err
 	}
 
-	// Validate current status allows advisor approval
-	if approval.Status != models.StatusRegistered && approval.Status != models.StatusPending {
-		return errors.New("enrollment is not in a state that allows advisor approval")
+	action := models.ActionAdvisorReject
+	if approved {
+		action = models.ActionAdvisorApprove
 	}
 
-	// Validate advisor
-	if approval.AdvisorID == nil || *approval.AdvisorID != advisorID {
-		return errors.New("user is not the assigned advisor for this enrollment")
+	// Validate current status allows advisor approval
+	transition, err := approval.ApprovalWorkflow().FindTransition(approval.Status, action)
+	if err != nil {
+		return err
 	}
-
-	// Determine new status
-	var newStatus models.InternshipApprovalStatus
-	if approved {
-		newStatus = models.StatusTApproved
-	} else {
-		newStatus = models.StatusDenied
+	if transition == nil {
+		return errors.New("enrollment is not in a state that allows advisor approval")
 	}
 
-	// Check if transition is valid
-	if !approval.CanTransitionTo(newStatus) {
-		return errors.New("invalid status transition")
+	// Validate advisor and the transition's other conditions
+	if err := s.authorizeTransition(approval, transition, actor, remarks); err != nil {
+		return err
 	}
 
 	// Update approval record
-	oldStatus := approval.Status
-	approval.Status = newStatus
+	now := time.Now()
 	approval.Remarks = remarks
-	
 	if approved {
-		now := time.Now()
 		approval.AdvisorApprovedAt = &now
 	}
 
-	// Add status transition
-	err = approval.AddStatusTransition(oldStatus, newStatus, advisorID, remarks)
-	if err != nil {
+	if err := s.enterStatus(approval, transition.To, actor.historyID(), remarks, now); err != nil {
 		return err
 	}
 
 	return s.db.Save(approval).Error
 }
 
-// CommitteeMemberVote handles committee member voting
+// CommitteeMemberVote records a committee member's vote. Members may change their vote until voting
+// closes, which happens once every member has voted or the voting deadline passes. Closing takes the
+// workflow's committee approve or reject action according to the course's voting policy.
 func (s *ApprovalService) CommitteeMemberVote(studentEnrollID uint, instructorID uint, vote, remarks string) error {
-	if vote != "approve" && vote != "reject" {
-		return errors.New("vote must be either 'approve' or 'reject'")
-	}
-
-	approval, err := models.GetApprovalByStudentEnrollID(s.db, studentEnrollID)
+	if vote != models.VoteApprove && vote != models.VoteReject && vote != models.VoteAbstain {
+		return errors.New("vote must be either 'approve', 'reject' or 'abstain'")
+	}
+
+	// The approval row stays locked until the vote is saved, so concurrent votes cannot overwrite
+	// each other's ballot or close voting twice
+	votingClosed := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txService := s.withDB(tx)
+		approval, err := models.GetApprovalByStudentEnrollID(tx.Clauses(clause.Locking{Strength: "UPDATE"}), studentEnrollID)
+		if err != nil {
+			return err
+		}
+		votingClosed, err = txService.castCommitteeVote(approval, instructorID, vote, remarks, time.Now())
+		return err
+	})
 	if err != nil {
 		return err
 	}
+	if votingClosed {
+		return errors.New("committee voting has closed")
+	}
+	return nil
+}
 
+// castCommitteeVote records a vote on a locked approval record and saves it. closed is true when the
+// voting deadline had already passed, in which case voting is closed instead of the vote recorded.
+func (s *ApprovalService) castCommitteeVote(approval *models.InternshipApproval, instructorID uint, vote, remarks string, now time.Time) (bool, error) {
 	// Validate current status allows committee voting
-	if approval.Status != models.StatusTApproved {
-		return errors.New("enrollment is not in a state that allows committee voting")
+	inCommittee, err := committeeStage(approval)
+	if err != nil {
+		return false, err
+	}
+	if !inCommittee {
+		return false, errors.New("enrollment is not in a state that allows committee voting")
 	}
 
-	// Verify instructor is a committee member
-	var committee models.CourseCommittee
-	err = s.db.Where("course_section_id = ? AND instructor_id = ?", 
-		approval.StudentEnroll.CourseSectionID, instructorID).
-		First(&committee).Error
+	policy, members, err := s.committeeVoting(approval)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("instructor is not a committee member for this course")
+		return false, err
+	}
+
+	// A deadline that passed before the scheduler resolved it closes voting now
+	if votingDeadlinePassed(approval, now) {
+		votes, err := approval.GetCommitteeVotes()
+		if err != nil {
+			return false, err
 		}
-		return err
+		if err := s.closeCommitteeVoting(approval, models.TallyCommitteeVotes(votes, members, policy), 0); err != nil {
+			return false, err
+		}
+		return true, s.db.Save(approval).Error
 	}
 
-	// Check if instructor has already voted
-	hasVoted, err := approval.HasInstructorVoted(instructorID)
-	if err != nil {
-		return err
+	// Verify instructor is a committee member
+	isMember := false
+	for _, member := range members {
+		if member.InstructorID == instructorID {
+			isMember = true
+			break
+		}
 	}
-	if hasVoted {
-		return errors.New("instructor has already voted on this application")
+	if !isMember {
+		return false, errors.New("instructor is not a committee member for this course")
 	}
 
-	// Add the vote
-	err = approval.AddCommitteeVote(instructorID, vote, remarks)
-	if err != nil {
-		return err
+	if vote == models.VoteAbstain && !policy.AllowAbstain {
+		return false, errors.New("abstaining is not allowed for this course")
 	}
 
-	// Check if voting is complete and update status if needed
-	votes, err := approval.GetCommitteeVotes()
-	if err != nil {
-		return err
+	// Record the vote, replacing the member's earlier vote
+	if err := approval.SetCommitteeVote(instructorID, vote, remarks); err != nil {
+		return false, err
+	}
+	if approval.VotingDeadline == nil {
+		approval.VotingDeadline = policy.Deadline(now)
 	}
 
-	// Get total committee members
-	var committeeCount int64
-	err = s.db.Model(&models.CourseCommittee{}).
-		Where("course_section_id = ?", approval.StudentEnroll.CourseSectionID).
-		Count(&committeeCount).Error
+	// Close voting once every member has voted
+	votes, err := approval.GetCommitteeVotes()
 	if err != nil {
-		return err
+		return false, err
+	}
+	tally := models.TallyCommitteeVotes(votes, members, policy)
+	if tally.Pending == 0 {
+		if err := s.closeCommitteeVoting(approval, tally, instructorID); err != nil {
+			return false, err
+		}
 	}
 
-	// Check if we have enough votes for a decision (simple majority)
-	if len(votes) >= int(committeeCount)/2+1 {
-		approveCount := 0
-		for _, v := range votes {
-			if v.Vote == "approve" {
-				approveCount++
-			}
+	return false, s.db.Save(approval).Error
+}
+
+// GetVotingPolicy returns a course's committee voting policy, or the default policy when the course
+// has not configured one
+func (s *ApprovalService) GetVotingPolicy(courseID uint) (*models.CommitteeVotingPolicy, error) {
+	var course models.Course
+	if err := s.db.First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
 		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return s.votingPolicy(courseID)
+}
 
-		var newStatus models.InternshipApprovalStatus
-		if approveCount > len(votes)/2 {
-			newStatus = models.StatusCApproved
-		} else {
-			newStatus = models.StatusDenied
+// SetVotingPolicy creates or replaces a course's committee voting policy. Applications already in
+// committee voting keep their voting deadline.
+func (s *ApprovalService) SetVotingPolicy(courseID uint, req CommitteeVotingPolicyRequest, userID uint) (*models.CommitteeVotingPolicy, error) {
+	if err := validateVotingPolicy(req); err != nil {
+		return nil, err
+	}
+
+	var course models.Course
+	if err := s.db.First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
 		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	var policy models.CommitteeVotingPolicy
+	err := s.db.Where("course_id = ?", courseID).First(&policy).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
 
-		// Update status if it changed
-		if approval.Status != newStatus {
-			oldStatus := approval.Status
-			approval.Status = newStatus
-			
-			err = approval.AddStatusTransition(oldStatus, newStatus, instructorID, "Committee voting completed")
+	policy.CourseID = courseID
+	policy.QuorumPercent = req.QuorumPercent
+	policy.MajorityRule = req.MajorityRule
+	policy.AllowAbstain = req.AllowAbstain
+	policy.ChairTieBreak = req.ChairTieBreak
+	policy.VotingPeriodDays = req.VotingPeriodDays
+	policy.UpdatedBy = &userID
+	if err := s.db.Save(&policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to save voting policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// ResolveExpiredVoting closes committee voting whose deadline has passed, returning how many
+// applications were resolved
+func (s *ApprovalService) ResolveExpiredVoting(now time.Time) (int, error) {
+	var approvalIDs []uint
+	err := s.db.Model(&models.InternshipApproval{}).
+		Where("voting_deadline IS NOT NULL AND voting_deadline <= ?", now).
+		Pluck("id", &approvalIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	resolved := 0
+	var errs []error
+	for _, approvalID := range approvalIDs {
+		closed := false
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			// Lock the row like CommitteeMemberVote does, then check the deadline again since a vote
+			// may have closed voting after the IDs were listed
+			var approval models.InternshipApproval
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Preload("StudentEnroll.CourseSection").
+				Preload("Workflow").
+				First(&approval, approvalID).Error
 			if err != nil {
 				return err
 			}
+			if !votingDeadlinePassed(&approval, now) {
+				return nil
+			}
+			closed, err = s.withDB(tx).resolveExpiredApproval(&approval)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("approval %d: %w", approvalID, err))
+			continue
+		}
+		if closed {
+			resolved++
 		}
 	}
+	return resolved, errors.Join(errs...)
+}
 
-	return s.db.Save(approval).Error
+// resolveExpiredApproval closes voting on one application whose deadline passed. Applications that
+// left committee voting another way only have their deadline cleared.
+func (s *ApprovalService) resolveExpiredApproval(approval *models.InternshipApproval) (bool, error) {
+	inCommittee, err := committeeStage(approval)
+	if err != nil {
+		return false, err
+	}
+	if !inCommittee {
+		approval.VotingDeadline = nil
+		return false, s.db.Save(approval).Error
+	}
+
+	votes, err := approval.GetCommitteeVotes()
+	if err != nil {
+		return false, err
+	}
+	policy, members, err := s.committeeVoting(approval)
+	if err != nil {
+		return false, err
+	}
+	if err := s.closeCommitteeVoting(approval, models.TallyCommitteeVotes(votes, members, policy), 0); err != nil {
+		return false, err
+	}
+	return true, s.db.Save(approval).Error
+}
+
+// committeeVoting loads the voting policy and committee members for an application's course section
+func (s *ApprovalService) committeeVoting(approval *models.InternshipApproval) (*models.CommitteeVotingPolicy, []models.CourseCommittee, error) {
+	policy, err := s.votingPolicy(approval.StudentEnroll.CourseSection.CourseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var members []models.CourseCommittee
+	err = s.db.Where("course_section_id = ?", approval.StudentEnroll.CourseSectionID).Find(&members).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, members, nil
+}
+
+// votingPolicy returns the voting policy of a course, falling back to the default policy
+func (s *ApprovalService) votingPolicy(courseID uint) (*models.CommitteeVotingPolicy, error) {
+	var policy models.CommitteeVotingPolicy
+	err := s.db.Where("course_id = ?", courseID).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DefaultCommitteeVotingPolicy(courseID), nil
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &policy, nil
 }
 
-// UpdateApprovalStatus updates the approval status manually (admin function)
+// openCommitteeVoting starts a new round of committee voting, clearing votes from earlier rounds and
+// setting the deadline from the course's voting policy
+func (s *ApprovalService) openCommitteeVoting(approval *models.InternshipApproval, now time.Time) error {
+	policy, err := s.votingPolicy(approval.StudentEnroll.CourseSection.CourseID)
+	if err != nil {
+		return err
+	}
+	if err := approval.SetCommitteeVotes([]models.CommitteeVote{}); err != nil {
+		return err
+	}
+	approval.VotingDeadline = policy.Deadline(now)
+	return nil
+}
+
+// closeCommitteeVoting applies the committee's decision to an application by taking the workflow's
+// committee action. changedBy is 0 when the scheduler closes voting at the deadline.
+func (s *ApprovalService) closeCommitteeVoting(approval *models.InternshipApproval, tally models.CommitteeTally, changedBy uint) error {
+	action, reason := committeeDecision(tally)
+	transition, err := approval.ApprovalWorkflow().FindTransition(approval.Status, action)
+	if err != nil {
+		return err
+	}
+	if transition == nil {
+		return errors.New("invalid status transition")
+	}
+	return s.enterStatus(approval, transition.To, changedBy, reason, time.Now())
+}
+
+// committeeDecision maps a committee tally to the workflow action it takes and the reason recorded
+// in the status history. Voting that closes without quorum rejects the application.
+func committeeDecision(tally models.CommitteeTally) (string, string) {
+	switch {
+	case !tally.QuorumMet:
+		return models.ActionCommitteeReject, "Committee voting closed without quorum"
+	case tally.Outcome == "approved" && tally.TieBrokenByChair:
+		return models.ActionCommitteeApprove, "Committee voting completed, tie broken by the chair"
+	case tally.Outcome == "approved":
+		return models.ActionCommitteeApprove, "Committee voting completed"
+	case tally.TieBrokenByChair:
+		return models.ActionCommitteeReject, "Committee voting completed, tie broken by the chair"
+	}
+	return models.ActionCommitteeReject, "Committee voting completed"
+}
+
+// votingDeadlinePassed reports whether an application's committee voting deadline has passed
+func votingDeadlinePassed(approval *models.InternshipApproval, now time.Time) bool {
+	return approval.VotingDeadline != nil && !now.Before(*approval.VotingDeadline)
+}
+
+// validateVotingPolicy checks the values of a committee voting policy
+func validateVotingPolicy(req CommitteeVotingPolicyRequest) error {
+	if req.QuorumPercent < 1 || req.QuorumPercent > 100 {
+		return errors.New("invalid voting policy: quorum_percent must be between 1 and 100")
+	}
+	if req.MajorityRule != models.MajoritySimple && req.MajorityRule != models.MajorityTwoThirds {
+		return fmt.Errorf("invalid voting policy: unknown majority rule %q", req.MajorityRule)
+	}
+	if req.VotingPeriodDays < 0 || req.VotingPeriodDays > 365 {
+		return errors.New("invalid voting policy: voting_period_days must be between 0 and 365")
+	}
+	return nil
+}
+
+// UpdateApprovalStatus updates the approval status manually (admin function). Any transition of the
+// record's workflow is allowed, without its role and guard conditions.
 func (s *ApprovalService) UpdateApprovalStatus(studentEnrollID uint, newStatus models.InternshipApprovalStatus, changedBy uint, reason string) error {
 	approval, err := models.GetApprovalByStudentEnrollID(s.db, studentEnrollID)
 	if err != nil {
@@ -307,11 +543,8 @@ func (s *ApprovalService) UpdateApprovalStatus(studentEnrollID uint, newStatus m
 		return errors.New("invalid status transition")
 	}
 
-	oldStatus := approval.Status
-	approval.Status = newStatus
-
-	// Add status transition
-	err = approval.AddStatusTransition(oldStatus, newStatus, changedBy, reason)
+	// Move to the new status, opening committee voting when it awaits a committee decision
+	err = s.enterStatus(approval, newStatus, changedBy, reason, time.Now())
 	if err != nil {
 		return err
 	}
@@ -319,15 +552,38 @@ func (s *ApprovalService) UpdateApprovalStatus(studentEnrollID uint, newStatus m
 	return s.db.Save(approval).Error
 }
 
-// CreateApprovalRecord creates a new approval record for student enrollment
+// CreateApprovalRecord creates a new approval record for student enrollment. The record follows the
+// workflow of its course or program, or the default workflow, from that workflow's initial status.
 func (s *ApprovalService) CreateApprovalRecord(studentEnrollID uint, advisorID *uint) (*models.InternshipApproval, error) {
-	return models.CreateApprovalRecord(s.db, studentEnrollID, advisorID)
+	workflow, err := s.resolveWorkflow(studentEnrollID)
+	if err != nil {
+		return nil, err
+	}
+	if workflow == nil {
+		return models.CreateApprovalRecord(s.db, studentEnrollID, advisorID)
+	}
+
+	approval := &models.InternshipApproval{
+		StudentEnrollID: studentEnrollID,
+		Status:          workflow.InitialStatus,
+		AdvisorID:       advisorID,
+		WorkflowID:      &workflow.ID,
+		Workflow:        workflow,
+	}
+	approval.SetCommitteeVotes([]models.CommitteeVote{})
+	approval.SetStatusHistory([]models.StatusTransition{})
+
+	if err := s.db.Omit("Workflow").Create(approval).Error; err != nil {
+		return nil, err
+	}
+	return approval, nil
 }
 
-// GetApprovalsByStatus gets approvals by status
-func (s *ApprovalService) GetApprovalsByStatus(status models.InternshipApprovalStatus, limit, offset int) ([]models.InternshipApproval, error) {
+// GetApprovalsByStatus gets approvals by status within the caller's data scope
+func (s *ApprovalService) GetApprovalsByStatus(scope *DataScope, status models.InternshipApprovalStatus, limit, offset int) ([]models.InternshipApproval, error) {
 	var approvals []models.InternshipApproval
-	err := s.db.Where("status = ?", status).
+	err := s.db.Scopes(scope.StudentEnrolls("internship_approvals.student_enroll_id")).
+		Where("status = ?", status).
 		Preload("StudentEnroll").
 		Preload("StudentEnroll.Student").
 		Preload("StudentEnroll.CourseSection").
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"backend-go/internal/models"
+)
+
+// DefaultApprovalFunnel lists the statuses an approval passes through in the default workflow
+var DefaultApprovalFunnel = []models.InternshipApprovalStatus{
+	models.StatusRegistered,
+	models.StatusTApproved,
+	models.StatusCApproved,
+	models.StatusDocApproved,
+	models.StatusApprove,
+}
+
+// ApprovalFunnelStep represents how many approvals reached a status of the funnel
+type ApprovalFunnelStep struct {
+	Status         models.InternshipApprovalStatus `json:"status"`
+	Reached        int64                           `json:"reached"`
+	ConversionRate float64                         `json:"conversion_rate"` // percentage of the previous step
+	OverallRate    float64                         `json:"overall_rate"`    // percentage of the first step
+}
+
+// ApprovalStatusDuration represents how long approvals stayed in a status before leaving it
+type ApprovalStatusDuration struct {
+	Status       models.InternshipApprovalStatus `json:"status"`
+	Transitions  int                             `json:"transitions"`
+	MedianHours  float64                         `json:"median_hours"`
+	P90Hours     float64                         `json:"p90_hours"`
+	AverageHours float64                         `json:"average_hours"`
+}
+
+// ApprovalTurnaround represents how quickly an advisor or a course committee decides
+type ApprovalTurnaround struct {
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Decisions    int     `json:"decisions"`
+	MedianHours  float64 `json:"median_hours"`
+	P90Hours     float64 `json:"p90_hours"`
+	AverageHours float64 `json:"average_hours"`
+}
+
+// ApprovalTurnaroundLeaderboard ranks advisors and course committees by median turnaround, fastest first
+type ApprovalTurnaroundLeaderboard struct {
+	Advisors   []ApprovalTurnaround `json:"advisors"`
+	Committees []ApprovalTurnaround `json:"committees"`
+}
+
+// approvalDuration is a time spent in a status, with the status or the decision maker it belongs to
+type approvalDuration struct {
+	Status          string
+	ID              uint
+	DurationSeconds int64
+}
+
+// GetApprovalFunnel counts the approvals created in the period that reached each status, in order.
+// An approval reached a status when it is in it or has a transition into or out of it.
+func (s *AnalyticsService) GetApprovalFunnel(req AnalyticsRequest, statuses []models.InternshipApprovalStatus) ([]ApprovalFunnelStep, error) {
+	if len(statuses) == 0 {
+		statuses = DefaultApprovalFunnel
+	}
+
+	steps := make([]ApprovalFunnelStep, 0, len(statuses))
+	for _, status := range statuses {
+		query := s.db.Model(&models.InternshipApproval{})
+		if !req.StartDate.IsZero() && !req.EndDate.IsZero() {
+			query = query.Where("created_at BETWEEN ? AND ?", req.StartDate, req.EndDate)
+		}
+
+		var reached int64
+		err := query.Where(`status = ? OR EXISTS (
+			SELECT 1 FROM approval_status_transitions ast
+			WHERE ast.approval_id = internship_approvals.id AND (ast.from_status = ? OR ast.to_status = ?)
+		)`, status, status, status).Count(&reached).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count approvals reaching %s: %w", status, err)
+		}
+		steps = append(steps, ApprovalFunnelStep{Status: status, Reached: reached})
+	}
+
+	for i := range steps {
+		steps[i].ConversionRate = 100
+		if i > 0 {
+			steps[i].ConversionRate = percentageOf(steps[i].Reached, steps[i-1].Reached)
+		}
+		steps[i].OverallRate = percentageOf(steps[i].Reached, steps[0].Reached)
+	}
+	return steps, nil
+}
+
+// GetApprovalStatusDurations reports the median, p90 and average time approvals spent in each status,
+// counting stays that ended in the period. Approvals still in a status are not counted.
+func (s *AnalyticsService) GetApprovalStatusDurations(req AnalyticsRequest) ([]ApprovalStatusDuration, error) {
+	var durations []approvalDuration
+	query := s.db.Model(&models.ApprovalStatusTransition{}).
+		Select("from_status AS status, duration_seconds")
+	if !req.StartDate.IsZero() && !req.EndDate.IsZero() {
+		query = query.Where("changed_at BETWEEN ? AND ?", req.StartDate, req.EndDate)
+	}
+	if err := query.Scan(&durations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get approval status durations: %w", err)
+	}
+
+	groups := make(map[string][]float64)
+	for _, duration := range durations {
+		groups[duration.Status] = append(groups[duration.Status], hoursOf(duration.DurationSeconds))
+	}
+
+	stats := make([]ApprovalStatusDuration, 0, len(groups))
+	for status, hours := range groups {
+		median, p90, average := durationStats(hours)
+		stats = append(stats, ApprovalStatusDuration{
+			Status:       models.InternshipApprovalStatus(status),
+			Transitions:  len(hours),
+			MedianHours:  median,
+			P90Hours:     p90,
+			AverageHours: average,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Status < stats[j].Status })
+	return stats, nil
+}
+
+// GetApprovalTurnaround ranks advisors by the time from an approval entering an advisor decision
+// to the decision, and course committees likewise for committee decisions. limit caps each list.
+func (s *AnalyticsService) GetApprovalTurnaround(req AnalyticsRequest, limit int) (*ApprovalTurnaroundLeaderboard, error) {
+	advisorQuery := s.db.Table("approval_status_transitions ast").
+		Select("ia.advisor_id AS id, ast.duration_seconds").
+		Joins("JOIN internship_approvals ia ON ia.id = ast.approval_id").
+		Where("ast.action IN ? AND ia.advisor_id IS NOT NULL", []string{models.ActionAdvisorApprove, models.ActionAdvisorReject})
+	committeeQuery := s.db.Table("approval_status_transitions ast").
+		Select("cs.course_id AS id, ast.duration_seconds").
+		Joins("JOIN student_enrolls se ON se.id = ast.student_enroll_id").
+		Joins("JOIN course_sections cs ON cs.id = se.course_section_id").
+		Where("ast.action IN ?", []string{models.ActionCommitteeApprove, models.ActionCommitteeReject})
+	if !req.StartDate.IsZero() && !req.EndDate.IsZero() {
+		advisorQuery = advisorQuery.Where("ast.changed_at BETWEEN ? AND ?", req.StartDate, req.EndDate)
+		committeeQuery = committeeQuery.Where("ast.changed_at BETWEEN ? AND ?", req.StartDate, req.EndDate)
+	}
+
+	var advisorDurations, committeeDurations []approvalDuration
+	if err := advisorQuery.Scan(&advisorDurations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get advisor turnaround: %w", err)
+	}
+	if err := committeeQuery.Scan(&committeeDurations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get committee turnaround: %w", err)
+	}
+
+	advisors := rankTurnaround(advisorDurations, limit)
+	if err := s.nameAdvisors(advisors); err != nil {
+		return nil, err
+	}
+	committees := rankTurnaround(committeeDurations, limit)
+	if err := s.nameCommittees(committees); err != nil {
+		return nil, err
+	}
+
+	return &ApprovalTurnaroundLeaderboard{
+		Advisors:   advisors,
+		Committees: committees,
+	}, nil
+}
+
+// nameAdvisors fills in the advisors' full names
+func (s *AnalyticsService) nameAdvisors(entries []ApprovalTurnaround) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+
+	var instructors []models.Instructor
+	if err := s.db.Where("id IN ?", ids).Find(&instructors).Error; err != nil {
+		return fmt.Errorf("failed to fetch advisors: %w", err)
+	}
+	names := make(map[uint]string, len(instructors))
+	for i := range instructors {
+		names[instructors[i].ID] = instructors[i].GetFullName()
+	}
+	for i := range entries {
+		entries[i].Name = names[entries[i].ID]
+	}
+	return nil
+}
+
+// nameCommittees fills in the names of the courses whose committees decided
+func (s *AnalyticsService) nameCommittees(entries []ApprovalTurnaround) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+
+	var courses []models.Course
+	if err := s.db.Where("id IN ?", ids).Find(&courses).Error; err != nil {
+		return fmt.Errorf("failed to fetch courses: %w", err)
+	}
+	names := make(map[uint]string, len(courses))
+	for _, course := range courses {
+		names[course.ID] = course.Code + " " + course.Name
+	}
+	for i := range entries {
+		entries[i].Name = names[entries[i].ID]
+	}
+	return nil
+}
+
+// rankTurnaround groups decision durations by decision maker and orders them by median, fastest
+// first, breaking ties by the number of decisions
+func rankTurnaround(durations []approvalDuration, limit int) []ApprovalTurnaround {
+	groups := make(map[uint][]float64)
+	for _, duration := range durations {
+		groups[duration.ID] = append(groups[duration.ID], hoursOf(duration.DurationSeconds))
+	}
+
+	ranked := make([]ApprovalTurnaround, 0, len(groups))
+	for id, hours := range groups {
+		median, p90, average := durationStats(hours)
+		ranked = append(ranked, ApprovalTurnaround{
+			ID:           id,
+			Decisions:    len(hours),
+			MedianHours:  median,
+			P90Hours:     p90,
+			AverageHours: average,
+		})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].MedianHours != ranked[j].MedianHours {
+			return ranked[i].MedianHours < ranked[j].MedianHours
+		}
+		if ranked[i].Decisions != ranked[j].Decisions {
+			return ranked[i].Decisions > ranked[j].Decisions
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// durationStats returns the median, 90th percentile and average of a set of durations, rounded to
+// two decimals
+func durationStats(hours []float64) (float64, float64, float64) {
+	if len(hours) == 0 {
+		return 0, 0, 0
+	}
+	sorted := append([]float64(nil), hours...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	for _, value := range sorted {
+		total += value
+	}
+	return roundHours(percentile(sorted, 0.5)), roundHours(percentile(sorted, 0.9)), roundHours(total / float64(len(sorted)))
+}
+
+// percentile interpolates the p-th quantile (0 to 1) of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// hoursOf converts seconds to hours
+func hoursOf(seconds int64) float64 {
+	return float64(seconds) / 3600
+}
+
+// roundHours rounds hours to two decimals
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// percentageOf returns part as a percentage of whole, rounded to two decimals
+func percentageOf(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(whole)) / 100
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalAnalytics(t *testing.T) {
+	t.Run("history entries become transition rows", func(t *testing.T) {
+		created := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+		approval := &models.InternshipApproval{ID: 4, StudentEnrollID: 9, CreatedAt: created}
+		require.NoError(t, approval.SetStatusHistory([]models.StatusTransition{
+			{FromStatus: models.StatusRegistered, ToStatus: models.StatusTApproved, ChangedBy: 2, ChangedAt: created.Add(48 * time.Hour)},
+			{FromStatus: models.StatusTApproved, ToStatus: models.StatusCApproved, ChangedAt: created.Add(60 * time.Hour), Reason: "Committee voting completed"},
+			{FromStatus: models.StatusCApproved, ToStatus: models.StatusApprove, ChangedBy: 1, ChangedAt: created.Add(61 * time.Hour)},
+		}))
+
+		rows, err := models.BuildStatusTransitions(approval, models.DefaultApprovalWorkflow())
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		assert.Equal(t, 0, rows[0].Sequence)
+		assert.Equal(t, created, rows[0].EnteredAt)
+		assert.Equal(t, int64(48*3600), rows[0].DurationSeconds)
+		assert.Equal(t, models.ActionAdvisorApprove, rows[0].Action)
+		assert.Equal(t, uint(9), rows[0].StudentEnrollID)
+
+		assert.Equal(t, int64(12*3600), rows[1].DurationSeconds)
+		assert.Equal(t, models.ActionCommitteeApprove, rows[1].Action)
+
+		// Admin overrides outside the workflow keep an empty action
+		assert.Equal(t, "", rows[2].Action)
+		assert.Equal(t, 2, rows[2].Sequence)
+	})
+
+	t.Run("percentiles interpolate between ranks", func(t *testing.T) {
+		median, p90, average := durationStats([]float64{10, 1, 4, 2, 3})
+		assert.Equal(t, 3.0, median)
+		assert.Equal(t, 7.6, p90)
+		assert.Equal(t, 4.0, average)
+
+		median, p90, average = durationStats(nil)
+		assert.Zero(t, median)
+		assert.Zero(t, p90)
+		assert.Zero(t, average)
+	})
+
+	t.Run("leaderboards rank the fastest median first", func(t *testing.T) {
+		hours := func(id uint, values ...int64) []approvalDuration {
+			durations := make([]approvalDuration, len(values))
+			for i, value := range values {
+				durations[i] = approvalDuration{ID: id, DurationSeconds: value * 3600}
+			}
+			return durations
+		}
+		var durations []approvalDuration
+		durations = append(durations, hours(1, 30, 50, 40)...)
+		durations = append(durations, hours(2, 10, 20)...)
+		durations = append(durations, hours(3, 15)...)
+
+		ranked := rankTurnaround(durations, 0)
+		require.Len(t, ranked, 3)
+		assert.Equal(t, uint(2), ranked[0].ID)
+		assert.Equal(t, 15.0, ranked[0].MedianHours)
+		assert.Equal(t, 2, ranked[0].Decisions)
+		assert.Equal(t, uint(3), ranked[1].ID)
+		assert.Equal(t, uint(1), ranked[2].ID)
+
+		assert.Len(t, rankTurnaround(durations, 2), 2)
+	})
+
+	t.Run("funnel rates are percentages", func(t *testing.T) {
+		assert.Equal(t, 66.67, percentageOf(2, 3))
+		assert.Zero(t, percentageOf(2, 0))
+	})
+}
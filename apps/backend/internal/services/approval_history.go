@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ApprovalHistoryBackfill summarizes copying status histories into approval_status_transitions
+type ApprovalHistoryBackfill struct {
+	Approvals   int      `json:"approvals"`
+	Transitions int64    `json:"transitions"` // rows created; rows stored earlier are left alone
+	Errors      []string `json:"errors"`
+}
+
+// BackfillStatusTransitions copies the status history of every approval record into the
+// approval_status_transitions table. Entries stored before are skipped, so the backfill can be run
+// again safely.
+func (s *ApprovalService) BackfillStatusTransitions() (*ApprovalHistoryBackfill, error) {
+	report := &ApprovalHistoryBackfill{Errors: []string{}}
+
+	var approvals []models.InternshipApproval
+	err := s.db.Preload("Workflow").FindInBatches(&approvals, 200, func(tx *gorm.DB, batch int) error {
+		for i := range approvals {
+			report.Approvals++
+			created, err := models.SyncStatusTransitions(s.db, &approvals[i])
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("approval %d: %v", approvals[i].ID, err))
+				continue
+			}
+			report.Transitions += created
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill approval history: %w", err)
+	}
+	return report, nil
+}
+
+// GetStatusTransitions returns the stored status transitions of an enrollment's approval record,
+// oldest first
+func (s *ApprovalService) GetStatusTransitions(studentEnrollID uint) ([]models.ApprovalStatusTransition, error) {
+	var approval models.InternshipApproval
+	if err := s.db.Select("id").Where("student_enroll_id = ?", studentEnrollID).First(&approval).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval record not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	var transitions []models.ApprovalStatusTransition
+	if err := s.db.Where("approval_id = ?", approval.ID).Order("sequence").Find(&transitions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch approval history: %w", err)
+	}
+	return transitions, nil
+}
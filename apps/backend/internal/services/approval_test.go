@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitteeVoting(t *testing.T) {
+	members := []models.CourseCommittee{
+		{InstructorID: 1, Role: models.CommitteeRoleChair},
+		{InstructorID: 2, Role: "member"},
+		{InstructorID: 3, Role: "member"},
+		{InstructorID: 4, Role: "secretary"},
+	}
+	votes := func(values ...string) []models.CommitteeVote {
+		cast := make([]models.CommitteeVote, 0, len(values))
+		for i, value := range values {
+			if value != "" {
+				cast = append(cast, models.CommitteeVote{InstructorID: uint(i + 1), Vote: value})
+			}
+		}
+		return cast
+	}
+	simple := models.DefaultCommitteeVotingPolicy(1)
+
+	t.Run("only current members count towards quorum", func(t *testing.T) {
+		tally := models.TallyCommitteeVotes(append(votes(models.VoteApprove), models.CommitteeVote{InstructorID: 9, Vote: models.VoteApprove}), members, simple)
+		assert.Equal(t, 1, tally.Approve)
+		assert.Equal(t, 3, tally.Pending)
+		assert.False(t, tally.QuorumMet)
+
+		action, reason := committeeDecision(tally)
+		assert.Equal(t, models.ActionCommitteeReject, action)
+		assert.Equal(t, "Committee voting closed without quorum", reason)
+	})
+
+	t.Run("abstentions count towards quorum but not the majority", func(t *testing.T) {
+		tally := models.TallyCommitteeVotes(votes(models.VoteAbstain, models.VoteApprove, models.VoteAbstain, ""), members, simple)
+		assert.True(t, tally.QuorumMet)
+		assert.Equal(t, 100, tally.ApprovalPercentage)
+		assert.Equal(t, "approved", tally.Outcome)
+
+		action, _ := committeeDecision(tally)
+		assert.Equal(t, models.ActionCommitteeApprove, action)
+	})
+
+	t.Run("the chair breaks ties under a simple majority", func(t *testing.T) {
+		tally := models.TallyCommitteeVotes(votes(models.VoteApprove, models.VoteReject, models.VoteReject, models.VoteApprove), members, simple)
+		assert.True(t, tally.TieBrokenByChair)
+		assert.Equal(t, "approved", tally.Outcome)
+
+		noTieBreak := *simple
+		noTieBreak.ChairTieBreak = false
+		tally = models.TallyCommitteeVotes(votes(models.VoteApprove, models.VoteReject, models.VoteReject, models.VoteApprove), members, &noTieBreak)
+		assert.False(t, tally.TieBrokenByChair)
+		assert.Equal(t, "rejected", tally.Outcome)
+	})
+
+	t.Run("two thirds majority", func(t *testing.T) {
+		twoThirds := *simple
+		twoThirds.MajorityRule = models.MajorityTwoThirds
+
+		tally := models.TallyCommitteeVotes(votes(models.VoteApprove, models.VoteApprove, models.VoteReject, ""), members, &twoThirds)
+		assert.Equal(t, "approved", tally.Outcome)
+
+		tally = models.TallyCommitteeVotes(votes(models.VoteApprove, models.VoteApprove, models.VoteReject, models.VoteReject), members, &twoThirds)
+		assert.False(t, tally.TieBrokenByChair)
+		assert.Equal(t, "rejected", tally.Outcome)
+	})
+
+	t.Run("members change their vote before close", func(t *testing.T) {
+		approval := &models.InternshipApproval{}
+		assert.NoError(t, approval.SetCommitteeVote(2, models.VoteReject, ""))
+		assert.NoError(t, approval.SetCommitteeVote(2, models.VoteApprove, "after interview"))
+
+		cast, err := approval.GetCommitteeVotes()
+		assert.NoError(t, err)
+		assert.Len(t, cast, 1)
+		assert.Equal(t, models.VoteApprove, cast[0].Vote)
+		assert.Equal(t, "after interview", cast[0].Remarks)
+	})
+
+	t.Run("deadlines follow the voting period", func(t *testing.T) {
+		start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+		assert.Equal(t, start.AddDate(0, 0, 14), *simple.Deadline(start))
+
+		open := *simple
+		open.VotingPeriodDays = 0
+		assert.Nil(t, open.Deadline(start))
+
+		approval := &models.InternshipApproval{VotingDeadline: simple.Deadline(start)}
+		assert.False(t, votingDeadlinePassed(approval, start.AddDate(0, 0, 13)))
+		assert.True(t, votingDeadlinePassed(approval, start.AddDate(0, 0, 14)))
+	})
+
+	t.Run("policies are validated", func(t *testing.T) {
+		assert.EqualError(t, validateVotingPolicy(CommitteeVotingPolicyRequest{QuorumPercent: 0, MajorityRule: models.MajoritySimple}),
+			"invalid voting policy: quorum_percent must be between 1 and 100")
+		assert.EqualError(t, validateVotingPolicy(CommitteeVotingPolicyRequest{QuorumPercent: 50, MajorityRule: "unanimous"}),
+			`invalid voting policy: unknown majority rule "unanimous"`)
+		assert.EqualError(t, validateVotingPolicy(CommitteeVotingPolicyRequest{QuorumPercent: 50, MajorityRule: models.MajorityTwoThirds, VotingPeriodDays: -1}),
+			"invalid voting policy: voting_period_days must be between 0 and 365")
+		assert.NoError(t, validateVotingPolicy(CommitteeVotingPolicyRequest{QuorumPercent: 100, MajorityRule: models.MajorityTwoThirds}))
+	})
+}
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ApprovalActor identifies who takes an approval workflow action
+type ApprovalActor struct {
+	UserID       uint     // numeric user ID, recorded in the status history when InstructorID is 0
+	InstructorID uint     // instructors.id when the actor is an instructor
+	Roles        []string // role names held by the actor
+}
+
+// historyID returns the ID recorded as ChangedBy in the status history
+func (a ApprovalActor) historyID() uint {
+	if a.InstructorID != 0 {
+		return a.InstructorID
+	}
+	return a.UserID
+}
+
+// hasRole reports whether the actor holds a role; admins hold every role
+func (a ApprovalActor) hasRole(role string) bool {
+	for _, held := range a.Roles {
+		if held == role || held == models.RoleNameAdmin || held == string(models.AdminRoleSuperAdmin) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovalWorkflowRequest represents the request for creating or replacing an approval workflow.
+// A workflow applies to a course, to a program, or as the default when IsDefault is set.
+type ApprovalWorkflowRequest struct {
+	Name          string                          `json:"name"`
+	Description   string                          `json:"description"`
+	CourseID      *uint                           `json:"course_id"`
+	ProgramID     *uint                           `json:"program_id"`
+	IsDefault     bool                            `json:"is_default"`
+	InitialStatus models.InternshipApprovalStatus `json:"initial_status"`
+	States        []models.WorkflowState          `json:"states"`
+	Transitions   []models.WorkflowTransition     `json:"transitions"`
+}
+
+// EnrollmentWorkflow represents the workflow an approval record follows and the actions available
+// from its current status
+type EnrollmentWorkflow struct {
+	StudentEnrollID      uint                            `json:"student_enroll_id"`
+	CurrentStatus        models.InternshipApprovalStatus `json:"current_status"`
+	Workflow             *models.ApprovalWorkflow        `json:"workflow"`
+	AvailableTransitions []models.WorkflowTransition     `json:"available_transitions"`
+}
+
+// knownWorkflowGuards lists the guard conditions a workflow transition may use
+var knownWorkflowGuards = []string{
+	models.GuardAssignedAdvisor,
+	models.GuardCommitteeVote,
+	models.GuardRemarksRequired,
+	models.GuardHasTraining,
+}
+
+// ListWorkflows lists approval workflows, active ones only unless inactive workflows are requested
+func (s *ApprovalService) ListWorkflows(includeInactive bool) ([]models.ApprovalWorkflow, error) {
+	query := s.db.Order("name ASC")
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var workflows []models.ApprovalWorkflow
+	if err := query.Find(&workflows).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch approval workflows: %w", err)
+	}
+	return workflows, nil
+}
+
+// GetWorkflow retrieves an approval workflow by ID
+func (s *ApprovalService) GetWorkflow(id uint) (*models.ApprovalWorkflow, error) {
+	var workflow models.ApprovalWorkflow
+	if err := s.db.First(&workflow, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval workflow not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &workflow, nil
+}
+
+// CreateWorkflow creates an approval workflow, retiring the active workflow it replaces for the same
+// course, program or default. Existing approval records keep the workflow they were created with.
+func (s *ApprovalService) CreateWorkflow(req ApprovalWorkflowRequest, userID uint) (*models.ApprovalWorkflow, error) {
+	if err := validateApprovalWorkflow(req); err != nil {
+		return nil, err
+	}
+	if err := s.checkWorkflowScope(req); err != nil {
+		return nil, err
+	}
+
+	workflow := models.ApprovalWorkflow{IsActive: true, CreatedBy: &userID}
+	if err := applyWorkflowRequest(&workflow, req); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := retireWorkflows(tx, req, 0); err != nil {
+			return err
+		}
+		return tx.Create(&workflow).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create approval workflow: %w", err)
+	}
+	return &workflow, nil
+}
+
+// UpdateWorkflow replaces an approval workflow's definition. Statuses that approval records on the
+// workflow are currently in must stay in the definition.
+func (s *ApprovalService) UpdateWorkflow(id uint, req ApprovalWorkflowRequest) (*models.ApprovalWorkflow, error) {
+	workflow, err := s.GetWorkflow(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateApprovalWorkflow(req); err != nil {
+		return nil, err
+	}
+	if err := s.checkWorkflowScope(req); err != nil {
+		return nil, err
+	}
+
+	var inUse []models.InternshipApprovalStatus
+	err = s.db.Model(&models.InternshipApproval{}).
+		Where("workflow_id = ?", id).
+		Distinct().
+		Pluck("status", &inUse).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	for _, status := range inUse {
+		if !workflowHasState(req.States, status) {
+			return nil, fmt.Errorf("invalid approval workflow: status %q is in use by approval records", status)
+		}
+	}
+
+	if err := applyWorkflowRequest(workflow, req); err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if workflow.IsActive {
+			if err := retireWorkflows(tx, req, workflow.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(workflow).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update approval workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// DeactivateWorkflow stops a workflow from applying to new approval records. Records already on it
+// keep following it.
+func (s *ApprovalService) DeactivateWorkflow(id uint) error {
+	workflow, err := s.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(workflow).Update("is_active", false).Error
+}
+
+// GetEnrollmentWorkflow returns the workflow an enrollment's approval record follows and the
+// transitions available from its current status
+func (s *ApprovalService) GetEnrollmentWorkflow(studentEnrollID uint) (*EnrollmentWorkflow, error) {
+	approval, err := models.GetApprovalByStudentEnrollID(s.db, studentEnrollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval record not found")
+		}
+		return nil, err
+	}
+
+	workflow := approval.ApprovalWorkflow()
+	available, err := workflow.AvailableTransitions(approval.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnrollmentWorkflow{
+		StudentEnrollID:      studentEnrollID,
+		CurrentStatus:        approval.Status,
+		Workflow:             workflow,
+		AvailableTransitions: available,
+	}, nil
+}
+
+// TransitionApproval takes a workflow action on an enrollment's approval record, checking the role
+// and guard conditions of the transition. Committee decisions can only be made by voting.
+func (s *ApprovalService) TransitionApproval(studentEnrollID uint, action string, actor ApprovalActor, reason string) (*models.InternshipApproval, error) {
+	approval, err := models.GetApprovalByStudentEnrollID(s.db, studentEnrollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval record not found")
+		}
+		return nil, err
+	}
+
+	transition, err := approval.ApprovalWorkflow().FindTransition(approval.Status, action)
+	if err != nil {
+		return nil, err
+	}
+	if transition == nil {
+		return nil, fmt.Errorf("action %q is not available from status %q", action, approval.Status)
+	}
+	if err := s.authorizeTransition(approval, transition, actor, reason); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if action == models.ActionAdvisorApprove {
+		approval.AdvisorApprovedAt = &now
+	}
+	if reason != "" {
+		approval.Remarks = reason
+	}
+	if err := s.enterStatus(approval, transition.To, actor.historyID(), reason, now); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(approval).Error; err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// resolveWorkflow picks the active workflow for a new approval record: the course's, then the
+// student's program's, then the configured default. It returns nil when the built-in default applies.
+func (s *ApprovalService) resolveWorkflow(studentEnrollID uint) (*models.ApprovalWorkflow, error) {
+	var enroll models.StudentEnroll
+	if err := s.db.Preload("CourseSection").Preload("Student").First(&enroll, studentEnrollID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student enrollment not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	candidates := []func(*gorm.DB) *gorm.DB{
+		func(tx *gorm.DB) *gorm.DB { return tx.Where("course_id = ?", enroll.CourseSection.CourseID) },
+	}
+	if enroll.Student.ProgramID != nil {
+		candidates = append(candidates, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("course_id IS NULL AND program_id = ?", *enroll.Student.ProgramID)
+		})
+	}
+	candidates = append(candidates, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("course_id IS NULL AND program_id IS NULL AND is_default = ?", true)
+	})
+
+	for _, candidate := range candidates {
+		var workflow models.ApprovalWorkflow
+		err := s.db.Scopes(candidate).Where("is_active = ?", true).Order("id DESC").First(&workflow).Error
+		if err == nil {
+			return &workflow, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+	}
+	return nil, nil
+}
+
+// authorizeTransition checks that an actor may take a transition on an approval record
+func (s *ApprovalService) authorizeTransition(approval *models.InternshipApproval, transition *models.WorkflowTransition, actor ApprovalActor, remarks string) error {
+	if transition.Role != "" && !actor.hasRole(transition.Role) {
+		return errors.New("insufficient role for this transition")
+	}
+
+	for _, guard := range transition.Guards {
+		switch guard {
+		case models.GuardAssignedAdvisor:
+			if approval.AdvisorID == nil || *approval.AdvisorID != actor.InstructorID {
+				return errors.New("user is not the assigned advisor for this enrollment")
+			}
+		case models.GuardCommitteeVote:
+			return errors.New("this transition is decided by committee voting")
+		case models.GuardRemarksRequired:
+			if strings.TrimSpace(remarks) == "" {
+				return errors.New("remarks are required for this transition")
+			}
+		case models.GuardHasTraining:
+			var count int64
+			if err := s.db.Model(&models.StudentTraining{}).Where("student_enroll_id = ?", approval.StudentEnrollID).Count(&count).Error; err != nil {
+				return fmt.Errorf("database error: %w", err)
+			}
+			if count == 0 {
+				return errors.New("student has no training placement")
+			}
+		}
+	}
+	return nil
+}
+
+// enterStatus moves an approval record to a status and records the transition. Entering a status with
+// a committee decision opens a new round of committee voting; leaving it clears the voting deadline.
+func (s *ApprovalService) enterStatus(approval *models.InternshipApproval, status models.InternshipApprovalStatus, changedBy uint, reason string, now time.Time) error {
+	oldStatus := approval.Status
+	approval.Status = status
+
+	inCommittee, err := committeeStage(approval)
+	if err != nil {
+		return err
+	}
+	if inCommittee {
+		if err := s.openCommitteeVoting(approval, now); err != nil {
+			return err
+		}
+	} else {
+		approval.VotingDeadline = nil
+	}
+
+	return approval.AddStatusTransition(oldStatus, status, changedBy, reason)
+}
+
+// checkWorkflowScope checks that the course or program a workflow applies to exists
+func (s *ApprovalService) checkWorkflowScope(req ApprovalWorkflowRequest) error {
+	if req.CourseID != nil {
+		var course models.Course
+		if err := s.db.First(&course, *req.CourseID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("course not found")
+			}
+			return fmt.Errorf("database error: %w", err)
+		}
+	}
+	if req.ProgramID != nil {
+		var program models.Program
+		if err := s.db.First(&program, *req.ProgramID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("program not found")
+			}
+			return fmt.Errorf("database error: %w", err)
+		}
+	}
+	return nil
+}
+
+// committeeStage reports whether an approval record's status awaits a committee decision
+func committeeStage(approval *models.InternshipApproval) (bool, error) {
+	transition, err := approval.ApprovalWorkflow().FindTransition(approval.Status, models.ActionCommitteeApprove)
+	return transition != nil, err
+}
+
+// retireWorkflows deactivates the active workflows with the same course, program or default scope,
+// other than the workflow being saved
+func retireWorkflows(tx *gorm.DB, req ApprovalWorkflowRequest, keepID uint) error {
+	query := tx.Model(&models.ApprovalWorkflow{}).Where("is_active = ? AND id <> ?", true, keepID)
+	switch {
+	case req.CourseID != nil:
+		query = query.Where("course_id = ?", *req.CourseID)
+	case req.ProgramID != nil:
+		query = query.Where("course_id IS NULL AND program_id = ?", *req.ProgramID)
+	default:
+		query = query.Where("course_id IS NULL AND program_id IS NULL AND is_default = ?", true)
+	}
+	return query.Update("is_active", false).Error
+}
+
+// applyWorkflowRequest copies a workflow request onto a workflow
+func applyWorkflowRequest(workflow *models.ApprovalWorkflow, req ApprovalWorkflowRequest) error {
+	workflow.Name = strings.TrimSpace(req.Name)
+	workflow.Description = req.Description
+	workflow.CourseID = req.CourseID
+	workflow.ProgramID = req.ProgramID
+	workflow.IsDefault = req.IsDefault
+	workflow.InitialStatus = req.InitialStatus
+	if err := workflow.SetStates(req.States); err != nil {
+		return fmt.Errorf("failed to encode workflow states: %w", err)
+	}
+	if err := workflow.SetTransitions(req.Transitions); err != nil {
+		return fmt.Errorf("failed to encode workflow transitions: %w", err)
+	}
+	return nil
+}
+
+// validateApprovalWorkflow checks that a workflow definition is consistent: transitions connect
+// declared states, final states have no way out, and committee decisions come in pairs taken only
+// by committee voting
+func validateApprovalWorkflow(req ApprovalWorkflowRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("invalid approval workflow: name is required")
+	}
+	if req.CourseID != nil && req.ProgramID != nil {
+		return errors.New("invalid approval workflow: a workflow applies to a course or a program, not both")
+	}
+	if req.IsDefault && (req.CourseID != nil || req.ProgramID != nil) {
+		return errors.New("invalid approval workflow: the default workflow cannot be limited to a course or program")
+	}
+	if !req.IsDefault && req.CourseID == nil && req.ProgramID == nil {
+		return errors.New("invalid approval workflow: a workflow needs a course, a program or to be the default")
+	}
+	if len(req.States) == 0 {
+		return errors.New("invalid approval workflow: at least one state is required")
+	}
+
+	final := make(map[models.InternshipApprovalStatus]bool, len(req.States))
+	for _, state := range req.States {
+		if strings.TrimSpace(string(state.Status)) == "" || len(state.Status) > 50 {
+			return errors.New("invalid approval workflow: state statuses must be 1 to 50 characters")
+		}
+		if _, duplicate := final[state.Status]; duplicate {
+			return fmt.Errorf("invalid approval workflow: state %q appears more than once", state.Status)
+		}
+		final[state.Status] = state.Final
+	}
+
+	isFinal, exists := final[req.InitialStatus]
+	if !exists {
+		return fmt.Errorf("invalid approval workflow: initial status %q is not a state", req.InitialStatus)
+	}
+	if isFinal {
+		return errors.New("invalid approval workflow: the initial status cannot be final")
+	}
+
+	type fromAction struct {
+		from   models.InternshipApprovalStatus
+		action string
+	}
+	seen := make(map[fromAction]bool, len(req.Transitions))
+	for _, transition := range req.Transitions {
+		if strings.TrimSpace(transition.Action) == "" {
+			return errors.New("invalid approval workflow: every transition needs an action")
+		}
+		fromFinal, fromExists := final[transition.From]
+		if !fromExists {
+			return fmt.Errorf("invalid approval workflow: transition %q leaves unknown state %q", transition.Action, transition.From)
+		}
+		if _, toExists := final[transition.To]; !toExists {
+			return fmt.Errorf("invalid approval workflow: transition %q enters unknown state %q", transition.Action, transition.To)
+		}
+		if fromFinal {
+			return fmt.Errorf("invalid approval workflow: final state %q cannot have transitions", transition.From)
+		}
+		if transition.From == transition.To {
+			return fmt.Errorf("invalid approval workflow: transition %q does not change state", transition.Action)
+		}
+
+		key := fromAction{transition.From, transition.Action}
+		if seen[key] {
+			return fmt.Errorf("invalid approval workflow: action %q is defined twice from state %q", transition.Action, transition.From)
+		}
+		seen[key] = true
+
+		committeeAction := transition.Action == models.ActionCommitteeApprove || transition.Action == models.ActionCommitteeReject
+		byVote := false
+		for _, guard := range transition.Guards {
+			if !containsString(knownWorkflowGuards, guard) {
+				return fmt.Errorf("invalid approval workflow: unknown guard %q", guard)
+			}
+			if guard == models.GuardCommitteeVote {
+				byVote = true
+			}
+		}
+		if committeeAction != byVote {
+			return fmt.Errorf("invalid approval workflow: transition %q must use the %q guard exactly when it is a committee decision", transition.Action, models.GuardCommitteeVote)
+		}
+	}
+
+	for key := range seen {
+		if key.action == models.ActionCommitteeApprove && !seen[fromAction{key.from, models.ActionCommitteeReject}] {
+			return fmt.Errorf("invalid approval workflow: state %q needs both committee decisions", key.from)
+		}
+		if key.action == models.ActionCommitteeReject && !seen[fromAction{key.from, models.ActionCommitteeApprove}] {
+			return fmt.Errorf("invalid approval workflow: state %q needs both committee decisions", key.from)
+		}
+	}
+	return nil
+}
+
+// workflowHasState reports whether a status is one of a workflow's states
+func workflowHasState(states []models.WorkflowState, status models.InternshipApprovalStatus) bool {
+	for _, state := range states {
+		if state.Status == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalWorkflows(t *testing.T) {
+	t.Run("the default workflow keeps the original transitions", func(t *testing.T) {
+		allowed := map[models.InternshipApprovalStatus][]models.InternshipApprovalStatus{
+			models.StatusRegistered:  {models.StatusTApproved, models.StatusDenied},
+			models.StatusTApproved:   {models.StatusCApproved, models.StatusDenied},
+			models.StatusCApproved:   {models.StatusDocApproved, models.StatusDocCancel},
+			models.StatusDocApproved: {models.StatusApprove, models.StatusDocCancel},
+			models.StatusDocCancel:   {},
+			models.StatusApprove:     {},
+			models.StatusDenied:      {models.StatusRegistered},
+			models.StatusPending:     {models.StatusTApproved, models.StatusDenied},
+		}
+
+		for from, targets := range allowed {
+			for to := range allowed {
+				approval := &models.InternshipApproval{Status: from}
+				assert.Equal(t, containsStatus(targets, to), approval.CanTransitionTo(to), "%s -> %s", from, to)
+			}
+		}
+	})
+
+	t.Run("records follow their own workflow", func(t *testing.T) {
+		workflow := &models.ApprovalWorkflow{InitialStatus: models.StatusRegistered}
+		require.NoError(t, workflow.SetTransitions([]models.WorkflowTransition{
+			{Action: "department_approve", From: models.StatusRegistered, To: "dept.approved", Role: models.RoleNameInstructor},
+		}))
+		approval := &models.InternshipApproval{Status: models.StatusRegistered, Workflow: workflow}
+
+		assert.True(t, approval.CanTransitionTo("dept.approved"))
+		assert.False(t, approval.CanTransitionTo(models.StatusTApproved))
+
+		transition, err := approval.ApprovalWorkflow().FindTransition(models.StatusRegistered, "department_approve")
+		require.NoError(t, err)
+		require.NotNil(t, transition)
+		assert.Equal(t, models.InternshipApprovalStatus("dept.approved"), transition.To)
+
+		transition, err = approval.ApprovalWorkflow().FindTransition(models.StatusRegistered, models.ActionAdvisorApprove)
+		require.NoError(t, err)
+		assert.Nil(t, transition)
+	})
+
+	t.Run("committee stages are states with committee decisions", func(t *testing.T) {
+		inCommittee, err := committeeStage(&models.InternshipApproval{Status: models.StatusTApproved})
+		require.NoError(t, err)
+		assert.True(t, inCommittee)
+
+		inCommittee, err = committeeStage(&models.InternshipApproval{Status: models.StatusRegistered})
+		require.NoError(t, err)
+		assert.False(t, inCommittee)
+	})
+
+	t.Run("admins hold every role", func(t *testing.T) {
+		instructor := ApprovalActor{InstructorID: 3, Roles: []string{models.RoleNameInstructor}}
+		assert.True(t, instructor.hasRole(models.RoleNameInstructor))
+		assert.False(t, instructor.hasRole(models.RoleNameAdmin))
+		assert.Equal(t, uint(3), instructor.historyID())
+
+		admin := ApprovalActor{UserID: 7, Roles: []string{models.RoleNameAdmin}}
+		assert.True(t, admin.hasRole(models.RoleNameInstructor))
+		assert.Equal(t, uint(7), admin.historyID())
+	})
+
+	t.Run("definitions are validated", func(t *testing.T) {
+		valid := func() ApprovalWorkflowRequest {
+			return ApprovalWorkflowRequest{
+				Name:          "Department review",
+				IsDefault:     true,
+				InitialStatus: models.StatusRegistered,
+				States: []models.WorkflowState{
+					{Status: models.StatusRegistered},
+					{Status: models.StatusTApproved},
+					{Status: models.StatusCApproved, Final: true},
+					{Status: models.StatusDenied, Final: true},
+				},
+				Transitions: []models.WorkflowTransition{
+					{Action: models.ActionAdvisorApprove, From: models.StatusRegistered, To: models.StatusTApproved, Guards: []string{models.GuardAssignedAdvisor}},
+					{Action: models.ActionCommitteeApprove, From: models.StatusTApproved, To: models.StatusCApproved, Guards: []string{models.GuardCommitteeVote}},
+					{Action: models.ActionCommitteeReject, From: models.StatusTApproved, To: models.StatusDenied, Guards: []string{models.GuardCommitteeVote}},
+				},
+			}
+		}
+		assert.NoError(t, validateApprovalWorkflow(valid()))
+
+		courseID := uint(1)
+		cases := map[string]func(*ApprovalWorkflowRequest){
+			"scoped default":          func(r *ApprovalWorkflowRequest) { r.CourseID = &courseID },
+			"unscoped":                func(r *ApprovalWorkflowRequest) { r.IsDefault = false },
+			"unknown initial status":  func(r *ApprovalWorkflowRequest) { r.InitialStatus = models.StatusPending },
+			"final initial status":    func(r *ApprovalWorkflowRequest) { r.InitialStatus = models.StatusDenied },
+			"unknown target state":    func(r *ApprovalWorkflowRequest) { r.Transitions[0].To = models.StatusApprove },
+			"transition out of final": func(r *ApprovalWorkflowRequest) { r.Transitions[0].From = models.StatusDenied },
+			"unknown guard":           func(r *ApprovalWorkflowRequest) { r.Transitions[0].Guards = []string{"full_moon"} },
+			"unpaired committee decision": func(r *ApprovalWorkflowRequest) {
+				r.Transitions = r.Transitions[:2]
+			},
+			"committee decision without voting": func(r *ApprovalWorkflowRequest) {
+				r.Transitions[1].Guards = nil
+			},
+			"voting on another action": func(r *ApprovalWorkflowRequest) {
+				r.Transitions[0].Guards = []string{models.GuardCommitteeVote}
+			},
+			"duplicate action": func(r *ApprovalWorkflowRequest) {
+				r.Transitions = append(r.Transitions, models.WorkflowTransition{Action: models.ActionAdvisorApprove, From: models.StatusRegistered, To: models.StatusDenied})
+			},
+		}
+		for name, mutate := range cases {
+			req := valid()
+			mutate(&req)
+			assert.Error(t, validateApprovalWorkflow(req), name)
+		}
+	})
+}
+
+// containsStatus reports whether a status is in a list
+func containsStatus(statuses []models.InternshipApprovalStatus, status models.InternshipApprovalStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
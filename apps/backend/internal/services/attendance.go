@@ -0,0 +1,820 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StandardWorkdayHours is the number of hours credited for a worked day recorded without times or hours
+const StandardWorkdayHours = 8.0
+
+// attendanceDateFormat is the format of work dates and time sheet weeks in requests
+const attendanceDateFormat = "2006-01-02"
+
+// attendanceClockFormat is the format of check-in and check-out times
+const attendanceClockFormat = "15:04"
+
+// AttendanceService handles internship attendance, weekly time sheets and required-hours tracking
+type AttendanceService struct {
+	db       *gorm.DB
+	settings AttendanceSettings
+}
+
+// AttendanceSettings configures how submitted time sheets reach the company supervisor
+type AttendanceSettings struct {
+	Mailer    Mailer // emails the review link; time sheets cannot be submitted without it
+	ReviewURL string // base URL of the review link, the review token is appended
+}
+
+// NewAttendanceService creates a new attendance service instance
+func NewAttendanceService(db *gorm.DB, settings AttendanceSettings) *AttendanceService {
+	if settings.ReviewURL == "" {
+		settings.ReviewURL = "http://localhost:8080/api/v1/timesheets/review"
+	}
+	return &AttendanceService{db: db, settings: settings}
+}
+
+// RecordAttendanceRequest represents the request for recording one day of attendance.
+// Worked hours come from CheckIn and CheckOut (HH:MM) less BreakMinutes, or from Hours.
+type RecordAttendanceRequest struct {
+	WorkDate     string                     `json:"work_date" validate:"required"`
+	EntryType    models.AttendanceEntryType `json:"entry_type" validate:"omitempty,oneof=work leave absent"`
+	LeaveType    *models.LeaveType          `json:"leave_type" validate:"omitempty,oneof=sick personal vacation holiday other"`
+	CheckIn      *string                    `json:"check_in"`
+	CheckOut     *string                    `json:"check_out"`
+	BreakMinutes int                        `json:"break_minutes" validate:"min=0"`
+	Hours        *float64                   `json:"hours" validate:"omitempty,gt=0,lte=24"`
+	Notes        string                     `json:"notes"`
+	RecordedBy   *uint                      `json:"-"`
+	RecorderType string                     `json:"-"`
+}
+
+// ReviewTimesheetRequest represents a supervisor's decision on a submitted time sheet
+type ReviewTimesheetRequest struct {
+	Approve      *bool  `json:"approve" validate:"required"`
+	ReviewerName string `json:"reviewer_name"`
+	Comment      string `json:"comment"`
+}
+
+// AttendanceRequirementRequest represents the request for setting a course's attendance requirement
+type AttendanceRequirementRequest struct {
+	RequiredHours      float64 `json:"required_hours" validate:"required,gt=0"`
+	MinimumWeeks       int     `json:"minimum_weeks" validate:"min=0"`
+	MinimumWeeklyHours float64 `json:"minimum_weekly_hours" validate:"min=0"`
+	MaxDailyHours      float64 `json:"max_daily_hours" validate:"omitempty,gt=0,lte=24"`
+}
+
+// TimesheetView is a time sheet with its hour totals
+type TimesheetView struct {
+	models.Timesheet
+	TotalHours float64 `json:"total_hours"`
+	WorkDays   int     `json:"work_days"`
+	LeaveDays  int     `json:"leave_days"`
+	AbsentDays int     `json:"absent_days"`
+}
+
+// TimesheetReview is what a supervisor sees when opening a review link
+type TimesheetReview struct {
+	TimesheetView
+	StudentName string `json:"student_name"`
+	CompanyName string `json:"company_name"`
+	Department  string `json:"department"`
+	Position    string `json:"position"`
+	Supervisor  string `json:"supervisor"`
+}
+
+// AttendanceSummary totals a student training's attendance against its course requirement.
+// Only hours on approved time sheets count toward the requirement.
+type AttendanceSummary struct {
+	StudentTrainingID uint                          `json:"student_training_id"`
+	StudentEnrollID   uint                          `json:"student_enroll_id"`
+	StudentID         uint                          `json:"student_id"`
+	StudentName       string                        `json:"student_name"`
+	CompanyID         *uint                         `json:"company_id"`
+	CompanyName       string                        `json:"company_name"`
+	StartDate         time.Time                     `json:"start_date"`
+	EndDate           time.Time                     `json:"end_date"`
+	ApprovedHours     float64                       `json:"approved_hours"`
+	PendingHours      float64                       `json:"pending_hours"`
+	WorkDays          int                           `json:"work_days"`
+	LeaveDays         map[models.LeaveType]int      `json:"leave_days"`
+	AbsentDays        int                           `json:"absent_days"`
+	QualifyingWeeks   int                           `json:"qualifying_weeks"`
+	Requirement       *models.AttendanceRequirement `json:"requirement"`
+	HoursRemaining    float64                       `json:"hours_remaining"`
+	WeeksRemaining    int                           `json:"weeks_remaining"`
+	MeetsRequirement  bool                          `json:"meets_requirement"`
+}
+
+// StudentAttendanceReport lists the attendance of every training of a student
+type StudentAttendanceReport struct {
+	StudentID uint                `json:"student_id"`
+	Trainings []AttendanceSummary `json:"trainings"`
+}
+
+// CompanyAttendanceReport lists the attendance of every student training at a company
+type CompanyAttendanceReport struct {
+	CompanyID                  uint                `json:"company_id"`
+	CompanyName                string              `json:"company_name"`
+	TotalApprovedHours         float64             `json:"total_approved_hours"`
+	StudentsMeetingRequirement int                 `json:"students_meeting_requirement"`
+	Trainings                  []AttendanceSummary `json:"trainings"`
+}
+
+// RecordEntry records or replaces the attendance of one day. The day's weekly time sheet is
+// created on demand and must not be submitted or approved.
+func (s *AttendanceService) RecordEntry(trainingID uint, req RecordAttendanceRequest) (*models.AttendanceEntry, error) {
+	training, err := s.getTraining(trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	requirement, err := s.requirementForTraining(training.StudentEnrollID)
+	if err != nil {
+		return nil, err
+	}
+	maxDailyHours := 24.0
+	if requirement != nil && requirement.MaxDailyHours > 0 {
+		maxDailyHours = requirement.MaxDailyHours
+	}
+
+	entry, err := buildAttendanceEntry(training, req, maxDailyHours)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		timesheet, err := findOrCreateTimesheet(tx, trainingID, attendanceWeekStart(entry.WorkDate))
+		if err != nil {
+			return err
+		}
+		if timesheet.IsLocked() {
+			return errors.New("timesheet is locked")
+		}
+		entry.TimesheetID = timesheet.ID
+
+		var existing models.AttendanceEntry
+		err = tx.Where("student_training_id = ? AND work_date = ?", trainingID, entry.WorkDate).First(&existing).Error
+		if err == nil {
+			entry.ID = existing.ID
+			entry.CreatedAt = existing.CreatedAt
+			return tx.Save(entry).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		if err.Error() == "timesheet is locked" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record attendance: %w", err)
+	}
+
+	return entry, nil
+}
+
+// DeleteEntry removes one day of attendance from an unlocked time sheet
+func (s *AttendanceService) DeleteEntry(trainingID, entryID uint) error {
+	var entry models.AttendanceEntry
+	err := s.db.Where("id = ? AND student_training_id = ?", entryID, trainingID).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("attendance entry not found")
+		}
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	var timesheet models.Timesheet
+	if err := s.db.First(&timesheet, entry.TimesheetID).Error; err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if timesheet.IsLocked() {
+		return errors.New("timesheet is locked")
+	}
+
+	if err := s.db.Delete(&entry).Error; err != nil {
+		return fmt.Errorf("failed to delete attendance entry: %w", err)
+	}
+	return nil
+}
+
+// GetTimesheets retrieves the weekly time sheets of a student training, oldest first
+func (s *AttendanceService) GetTimesheets(trainingID uint) ([]TimesheetView, error) {
+	if _, err := s.getTraining(trainingID); err != nil {
+		return nil, err
+	}
+
+	var timesheets []models.Timesheet
+	err := s.db.Where("student_training_id = ?", trainingID).
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("work_date ASC") }).
+		Order("week_start ASC").
+		Find(&timesheets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch timesheets: %w", err)
+	}
+
+	views := make([]TimesheetView, 0, len(timesheets))
+	for _, timesheet := range timesheets {
+		views = append(views, newTimesheetView(timesheet))
+	}
+	return views, nil
+}
+
+// SubmitTimesheet sends a draft or rejected time sheet for supervisor review. The review link is
+// emailed to the training's supervisor only; the token is never returned to the submitter,
+// since anyone holding it can approve the time sheet.
+func (s *AttendanceService) SubmitTimesheet(trainingID, timesheetID uint) (*TimesheetView, error) {
+	timesheet, err := s.getTimesheet(trainingID, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+	if timesheet.IsLocked() {
+		return nil, errors.New("timesheet has already been submitted")
+	}
+	if len(timesheet.Entries) == 0 {
+		return nil, errors.New("timesheet has no entries")
+	}
+	if s.settings.Mailer == nil {
+		return nil, errors.New("supervisor email is not configured")
+	}
+
+	var training models.StudentTraining
+	err = s.db.Preload("StudentEnroll.Student").First(&training, trainingID).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if training.SupervisorEmail == "" {
+		return nil, errors.New("student training has no supervisor email")
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate review token: %w", err)
+	}
+
+	now := time.Now()
+	view := newTimesheetView(*timesheet)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(timesheet).Updates(map[string]interface{}{
+			"status":         models.TimesheetStatusSubmitted,
+			"review_token":   token,
+			"submitted_at":   now,
+			"reviewed_at":    nil,
+			"reviewer_name":  "",
+			"review_comment": "",
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to submit timesheet: %w", err)
+		}
+
+		// Sent inside the transaction so a time sheet is never left submitted without a link
+		subject, body := timesheetReviewEmail(&training, &view, s.reviewLink(token))
+		return s.settings.Mailer.Send(training.SupervisorEmail, subject, body)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet.Status = models.TimesheetStatusSubmitted
+	timesheet.ReviewToken = nil
+	timesheet.SubmittedAt = &now
+	timesheet.ReviewedAt = nil
+	timesheet.ReviewerName = ""
+	timesheet.ReviewComment = ""
+	view = newTimesheetView(*timesheet)
+	return &view, nil
+}
+
+// reviewLink builds the supervisor review URL of a review token
+func (s *AttendanceService) reviewLink(token string) string {
+	return strings.TrimRight(s.settings.ReviewURL, "/") + "/" + token
+}
+
+// timesheetReviewEmail writes the email asking the supervisor to review a submitted time sheet
+func timesheetReviewEmail(training *models.StudentTraining, timesheet *TimesheetView, link string) (string, string) {
+	student := training.StudentEnroll.Student.GetFullName()
+	week := timesheet.WeekStart.Format(attendanceDateFormat)
+
+	subject := fmt.Sprintf("Internship time sheet for review: %s, week of %s", student, week)
+	body := fmt.Sprintf("Dear %s,\n\n"+
+		"%s has submitted the internship time sheet for the week of %s (%.2f hours over %d work days).\n\n"+
+		"Please approve or reject it at:\n%s\n\n"+
+		"The link can only be used once. Do not forward this email.\n",
+		training.Supervisor, student, week, timesheet.TotalHours, timesheet.WorkDays, link)
+	return subject, body
+}
+
+// GetTimesheetForReview retrieves a submitted time sheet by its review token
+func (s *AttendanceService) GetTimesheetForReview(token string) (*TimesheetReview, error) {
+	timesheet, err := s.getTimesheetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var training models.StudentTraining
+	err = s.db.Preload("StudentEnroll.Student").Preload("Company").First(&training, timesheet.StudentTrainingID).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	review := &TimesheetReview{
+		TimesheetView: newTimesheetView(*timesheet),
+		StudentName:   training.StudentEnroll.Student.GetFullName(),
+		Department:    training.Department,
+		Position:      training.Position,
+		Supervisor:    training.Supervisor,
+	}
+	if training.Company != nil {
+		review.CompanyName = training.Company.CompanyNameEn
+	}
+	return review, nil
+}
+
+// ReviewTimesheetByToken records the supervisor's decision made through a review link.
+// The token can only be used once.
+func (s *AttendanceService) ReviewTimesheetByToken(token string, req ReviewTimesheetRequest) (*TimesheetView, error) {
+	timesheet, err := s.getTimesheetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.reviewTimesheet(timesheet, req)
+}
+
+// ReviewTimesheet records a supervisor's decision on their behalf, for example from a signed paper time sheet
+func (s *AttendanceService) ReviewTimesheet(trainingID, timesheetID uint, req ReviewTimesheetRequest) (*TimesheetView, error) {
+	timesheet, err := s.getTimesheet(trainingID, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reviewTimesheet(timesheet, req)
+}
+
+// SetRequirement creates or replaces the attendance requirement of a course
+func (s *AttendanceService) SetRequirement(courseID uint, req AttendanceRequirementRequest) (*models.AttendanceRequirement, error) {
+	if req.MinimumWeeklyHours > 0 && req.MinimumWeeks == 0 {
+		return nil, errors.New("invalid attendance requirement: minimum_weekly_hours requires minimum_weeks")
+	}
+
+	var course models.Course
+	if err := s.db.First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	var requirement models.AttendanceRequirement
+	err := s.db.Where("course_id = ?", courseID).First(&requirement).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	requirement.CourseID = courseID
+	requirement.RequiredHours = req.RequiredHours
+	requirement.MinimumWeeks = req.MinimumWeeks
+	requirement.MinimumWeeklyHours = req.MinimumWeeklyHours
+	requirement.MaxDailyHours = req.MaxDailyHours
+	if requirement.MaxDailyHours == 0 {
+		requirement.MaxDailyHours = 12
+	}
+
+	if err := s.db.Save(&requirement).Error; err != nil {
+		return nil, fmt.Errorf("failed to save attendance requirement: %w", err)
+	}
+	return &requirement, nil
+}
+
+// GetRequirement retrieves the attendance requirement of a course
+func (s *AttendanceService) GetRequirement(courseID uint) (*models.AttendanceRequirement, error) {
+	var requirement models.AttendanceRequirement
+	err := s.db.Where("course_id = ?", courseID).First(&requirement).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attendance requirement not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &requirement, nil
+}
+
+// GetTrainingSummary totals a student training's attendance against its course requirement
+func (s *AttendanceService) GetTrainingSummary(trainingID uint) (*AttendanceSummary, error) {
+	summaries, err := s.summarize(s.db.Where("student_trainings.id = ?", trainingID))
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return nil, errors.New("student training not found")
+	}
+	return &summaries[0], nil
+}
+
+// GetStudentReport totals the attendance of every training of a student
+func (s *AttendanceService) GetStudentReport(studentID uint) (*StudentAttendanceReport, error) {
+	var student models.Student
+	if err := s.db.First(&student, studentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	summaries, err := s.summarize(s.db.
+		Joins("JOIN student_enrolls ON student_enrolls.id = student_trainings.student_enroll_id").
+		Where("student_enrolls.student_id = ?", studentID))
+	if err != nil {
+		return nil, err
+	}
+	return &StudentAttendanceReport{StudentID: studentID, Trainings: summaries}, nil
+}
+
+// GetCompanyReport totals the attendance of every student training at a company
+func (s *AttendanceService) GetCompanyReport(companyID uint) (*CompanyAttendanceReport, error) {
+	var company models.Company
+	if err := s.db.First(&company, companyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("company not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	summaries, err := s.summarize(s.db.Where("student_trainings.company_id = ?", companyID))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CompanyAttendanceReport{CompanyID: companyID, CompanyName: company.CompanyNameEn, Trainings: summaries}
+	for _, summary := range summaries {
+		report.TotalApprovedHours += summary.ApprovedHours
+		if summary.MeetsRequirement {
+			report.StudentsMeetingRequirement++
+		}
+	}
+	return report, nil
+}
+
+// summarize builds attendance summaries for the student trainings selected by query
+func (s *AttendanceService) summarize(query *gorm.DB) ([]AttendanceSummary, error) {
+	var trainings []models.StudentTraining
+	err := query.Model(&models.StudentTraining{}).
+		Preload("StudentEnroll.Student").
+		Preload("Company").
+		Order("student_trainings.start_date ASC").
+		Find(&trainings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch student trainings: %w", err)
+	}
+	if len(trainings) == 0 {
+		return []AttendanceSummary{}, nil
+	}
+
+	trainingIDs := make([]uint, len(trainings))
+	enrollIDs := make([]uint, len(trainings))
+	for i, training := range trainings {
+		trainingIDs[i] = training.ID
+		enrollIDs[i] = training.StudentEnrollID
+	}
+
+	var timesheets []models.Timesheet
+	err = s.db.Where("student_training_id IN ?", trainingIDs).Preload("Entries").Find(&timesheets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch timesheets: %w", err)
+	}
+	timesheetsByTraining := make(map[uint][]models.Timesheet)
+	for _, timesheet := range timesheets {
+		timesheetsByTraining[timesheet.StudentTrainingID] = append(timesheetsByTraining[timesheet.StudentTrainingID], timesheet)
+	}
+
+	requirements, err := s.requirementsByEnroll(enrollIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]AttendanceSummary, 0, len(trainings))
+	for _, training := range trainings {
+		summary := summarizeAttendance(timesheetsByTraining[training.ID], requirements[training.StudentEnrollID])
+		summary.StudentTrainingID = training.ID
+		summary.StudentEnrollID = training.StudentEnrollID
+		summary.StudentID = training.StudentEnroll.StudentID
+		summary.StudentName = training.StudentEnroll.Student.GetFullName()
+		summary.CompanyID = training.CompanyID
+		if training.Company != nil {
+			summary.CompanyName = training.Company.CompanyNameEn
+		}
+		summary.StartDate = training.StartDate
+		summary.EndDate = training.EndDate
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// requirementForTraining returns the attendance requirement of the enrollment's course, or nil
+func (s *AttendanceService) requirementForTraining(enrollID uint) (*models.AttendanceRequirement, error) {
+	requirements, err := s.requirementsByEnroll([]uint{enrollID})
+	if err != nil {
+		return nil, err
+	}
+	return requirements[enrollID], nil
+}
+
+// requirementsByEnroll maps student enrollments to the attendance requirements of their courses
+func (s *AttendanceService) requirementsByEnroll(enrollIDs []uint) (map[uint]*models.AttendanceRequirement, error) {
+	type row struct {
+		EnrollID uint
+		models.AttendanceRequirement
+	}
+	var rows []row
+	err := s.db.Table("attendance_requirements").
+		Select("student_enrolls.id AS enroll_id, attendance_requirements.*").
+		Joins("JOIN course_sections ON course_sections.course_id = attendance_requirements.course_id").
+		Joins("JOIN student_enrolls ON student_enrolls.course_section_id = course_sections.id").
+		Where("student_enrolls.id IN ?", enrollIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attendance requirements: %w", err)
+	}
+
+	requirements := make(map[uint]*models.AttendanceRequirement, len(rows))
+	for i := range rows {
+		requirements[rows[i].EnrollID] = &rows[i].AttendanceRequirement
+	}
+	return requirements, nil
+}
+
+func (s *AttendanceService) getTraining(trainingID uint) (*models.StudentTraining, error) {
+	var training models.StudentTraining
+	if err := s.db.First(&training, trainingID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student training not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &training, nil
+}
+
+func (s *AttendanceService) getTimesheet(trainingID, timesheetID uint) (*models.Timesheet, error) {
+	var timesheet models.Timesheet
+	err := s.db.Where("id = ? AND student_training_id = ?", timesheetID, trainingID).
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("work_date ASC") }).
+		First(&timesheet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("timesheet not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &timesheet, nil
+}
+
+func (s *AttendanceService) getTimesheetByToken(token string) (*models.Timesheet, error) {
+	if token == "" {
+		return nil, errors.New("timesheet not found")
+	}
+
+	var timesheet models.Timesheet
+	err := s.db.Where("review_token = ?", token).
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("work_date ASC") }).
+		First(&timesheet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("timesheet not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &timesheet, nil
+}
+
+// reviewTimesheet approves or rejects a submitted time sheet. A rejected time sheet unlocks its
+// entries so the student can correct and resubmit them.
+func (s *AttendanceService) reviewTimesheet(timesheet *models.Timesheet, req ReviewTimesheetRequest) (*TimesheetView, error) {
+	if timesheet.Status != models.TimesheetStatusSubmitted {
+		return nil, errors.New("timesheet is not awaiting review")
+	}
+
+	reviewerName := req.ReviewerName
+	if reviewerName == "" {
+		training, err := s.getTraining(timesheet.StudentTrainingID)
+		if err != nil {
+			return nil, err
+		}
+		reviewerName = training.Supervisor
+	}
+
+	status := models.TimesheetStatusRejected
+	if *req.Approve {
+		status = models.TimesheetStatusApproved
+	}
+
+	now := time.Now()
+	err := s.db.Model(timesheet).Updates(map[string]interface{}{
+		"status":         status,
+		"review_token":   nil,
+		"reviewed_at":    now,
+		"reviewer_name":  reviewerName,
+		"review_comment": req.Comment,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to review timesheet: %w", err)
+	}
+
+	timesheet.Status = status
+	timesheet.ReviewToken = nil
+	timesheet.ReviewedAt = &now
+	timesheet.ReviewerName = reviewerName
+	timesheet.ReviewComment = req.Comment
+	view := newTimesheetView(*timesheet)
+	return &view, nil
+}
+
+// findOrCreateTimesheet returns the draft, submitted or reviewed time sheet for a training week
+func findOrCreateTimesheet(tx *gorm.DB, trainingID uint, weekStart time.Time) (*models.Timesheet, error) {
+	var timesheet models.Timesheet
+	err := tx.Where("student_training_id = ? AND week_start = ?", trainingID, weekStart).First(&timesheet).Error
+	if err == nil {
+		return &timesheet, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	timesheet = models.Timesheet{
+		StudentTrainingID: trainingID,
+		WeekStart:         weekStart,
+		Status:            models.TimesheetStatusDraft,
+	}
+	if err := tx.Create(&timesheet).Error; err != nil {
+		return nil, err
+	}
+	return &timesheet, nil
+}
+
+// buildAttendanceEntry validates a request against the training period and daily hour limit
+func buildAttendanceEntry(training *models.StudentTraining, req RecordAttendanceRequest, maxDailyHours float64) (*models.AttendanceEntry, error) {
+	workDate, err := time.Parse(attendanceDateFormat, req.WorkDate)
+	if err != nil {
+		return nil, errors.New("invalid attendance entry: work_date must be YYYY-MM-DD")
+	}
+	if workDate.Before(truncateToDate(training.StartDate)) || workDate.After(truncateToDate(training.EndDate)) {
+		return nil, errors.New("invalid attendance entry: work_date is outside the training period")
+	}
+
+	entry := &models.AttendanceEntry{
+		StudentTrainingID: training.ID,
+		WorkDate:          workDate,
+		EntryType:         req.EntryType,
+		Notes:             req.Notes,
+		RecordedBy:        req.RecordedBy,
+		RecorderType:      req.RecorderType,
+	}
+	if entry.EntryType == "" {
+		entry.EntryType = models.AttendanceEntryWork
+	}
+
+	switch entry.EntryType {
+	case models.AttendanceEntryWork:
+		if req.LeaveType != nil {
+			return nil, errors.New("invalid attendance entry: leave_type is only allowed on leave days")
+		}
+		hours, err := workedHours(req)
+		if err != nil {
+			return nil, err
+		}
+		if hours > maxDailyHours {
+			return nil, fmt.Errorf("invalid attendance entry: at most %g hours can be recorded per day", maxDailyHours)
+		}
+		entry.CheckIn = req.CheckIn
+		entry.CheckOut = req.CheckOut
+		entry.BreakMinutes = req.BreakMinutes
+		entry.Hours = hours
+	case models.AttendanceEntryLeave:
+		if req.LeaveType == nil {
+			return nil, errors.New("invalid attendance entry: leave_type is required for leave days")
+		}
+		entry.LeaveType = req.LeaveType
+	case models.AttendanceEntryAbsent:
+		if req.LeaveType != nil {
+			return nil, errors.New("invalid attendance entry: leave_type is only allowed on leave days")
+		}
+	default:
+		return nil, fmt.Errorf("invalid attendance entry: unsupported entry type %q", entry.EntryType)
+	}
+
+	return entry, nil
+}
+
+// workedHours computes a work day's hours from its check-in and check-out times, or takes them
+// from the request. Days without either count as a standard workday.
+func workedHours(req RecordAttendanceRequest) (float64, error) {
+	if req.CheckIn == nil && req.CheckOut == nil {
+		if req.BreakMinutes > 0 {
+			return 0, errors.New("invalid attendance entry: break_minutes requires check_in and check_out")
+		}
+		if req.Hours != nil {
+			return *req.Hours, nil
+		}
+		return StandardWorkdayHours, nil
+	}
+	if req.CheckIn == nil || req.CheckOut == nil {
+		return 0, errors.New("invalid attendance entry: check_in and check_out must be given together")
+	}
+	if req.Hours != nil {
+		return 0, errors.New("invalid attendance entry: give either hours or check_in and check_out")
+	}
+
+	checkIn, err := time.Parse(attendanceClockFormat, *req.CheckIn)
+	if err != nil {
+		return 0, errors.New("invalid attendance entry: check_in must be HH:MM")
+	}
+	checkOut, err := time.Parse(attendanceClockFormat, *req.CheckOut)
+	if err != nil {
+		return 0, errors.New("invalid attendance entry: check_out must be HH:MM")
+	}
+	if !checkOut.After(checkIn) {
+		return 0, errors.New("invalid attendance entry: check_out must be after check_in")
+	}
+
+	worked := checkOut.Sub(checkIn) - time.Duration(req.BreakMinutes)*time.Minute
+	if worked <= 0 {
+		return 0, errors.New("invalid attendance entry: break_minutes exceeds the time worked")
+	}
+	return math.Round(worked.Hours()*100) / 100, nil
+}
+
+// summarizeAttendance totals time sheets against a requirement, which may be nil
+func summarizeAttendance(timesheets []models.Timesheet, requirement *models.AttendanceRequirement) AttendanceSummary {
+	summary := AttendanceSummary{
+		LeaveDays:   make(map[models.LeaveType]int),
+		Requirement: requirement,
+	}
+
+	sort.Slice(timesheets, func(i, j int) bool { return timesheets[i].WeekStart.Before(timesheets[j].WeekStart) })
+	for _, timesheet := range timesheets {
+		view := newTimesheetView(timesheet)
+		if timesheet.Status != models.TimesheetStatusApproved {
+			summary.PendingHours += view.TotalHours
+			continue
+		}
+
+		summary.ApprovedHours += view.TotalHours
+		summary.WorkDays += view.WorkDays
+		summary.AbsentDays += view.AbsentDays
+		for _, entry := range timesheet.Entries {
+			if entry.EntryType == models.AttendanceEntryLeave && entry.LeaveType != nil {
+				summary.LeaveDays[*entry.LeaveType]++
+			}
+		}
+		if requirement != nil && requirement.MinimumWeeks > 0 && view.TotalHours > 0 && view.TotalHours >= requirement.MinimumWeeklyHours {
+			summary.QualifyingWeeks++
+		}
+	}
+
+	if requirement == nil {
+		return summary
+	}
+
+	summary.HoursRemaining = math.Max(0, requirement.RequiredHours-summary.ApprovedHours)
+	if requirement.MinimumWeeks > summary.QualifyingWeeks {
+		summary.WeeksRemaining = requirement.MinimumWeeks - summary.QualifyingWeeks
+	}
+	summary.MeetsRequirement = summary.HoursRemaining == 0 && summary.WeeksRemaining == 0
+	return summary
+}
+
+// newTimesheetView totals the hours and day types of a time sheet
+func newTimesheetView(timesheet models.Timesheet) TimesheetView {
+	view := TimesheetView{Timesheet: timesheet}
+	for _, entry := range timesheet.Entries {
+		view.TotalHours += entry.Hours
+		switch entry.EntryType {
+		case models.AttendanceEntryWork:
+			view.WorkDays++
+		case models.AttendanceEntryLeave:
+			view.LeaveDays++
+		case models.AttendanceEntryAbsent:
+			view.AbsentDays++
+		}
+	}
+	return view
+}
+
+// attendanceWeekStart returns the Monday of the week containing date
+func attendanceWeekStart(date time.Time) time.Time {
+	date = truncateToDate(date)
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// truncateToDate drops the time of day, keeping the calendar date
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
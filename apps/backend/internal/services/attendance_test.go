@@ -0,0 +1,144 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendance(t *testing.T) {
+	training := &models.StudentTraining{
+		ID:        5,
+		StartDate: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 8, 28, 17, 0, 0, 0, time.UTC),
+	}
+	clock := func(s string) *string { return &s }
+	hours := func(h float64) *float64 { return &h }
+
+	t.Run("worked hours come from check-in and check-out less breaks", func(t *testing.T) {
+		entry, err := buildAttendanceEntry(training, RecordAttendanceRequest{
+			WorkDate: "2026-06-03", CheckIn: clock("08:30"), CheckOut: clock("17:15"), BreakMinutes: 60,
+		}, 12)
+		require.NoError(t, err)
+		assert.Equal(t, models.AttendanceEntryWork, entry.EntryType)
+		assert.Equal(t, 7.75, entry.Hours)
+		assert.Equal(t, time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC), entry.WorkDate)
+
+		entry, err = buildAttendanceEntry(training, RecordAttendanceRequest{WorkDate: "2026-06-04"}, 12)
+		require.NoError(t, err)
+		assert.Equal(t, StandardWorkdayHours, entry.Hours)
+
+		entry, err = buildAttendanceEntry(training, RecordAttendanceRequest{WorkDate: "2026-06-05", Hours: hours(6.5)}, 12)
+		require.NoError(t, err)
+		assert.Equal(t, 6.5, entry.Hours)
+	})
+
+	t.Run("entries are validated", func(t *testing.T) {
+		sick := models.LeaveTypeSick
+		cases := []struct {
+			req  RecordAttendanceRequest
+			want string
+		}{
+			{RecordAttendanceRequest{WorkDate: "03/06/2026"}, "invalid attendance entry: work_date must be YYYY-MM-DD"},
+			{RecordAttendanceRequest{WorkDate: "2026-05-29"}, "invalid attendance entry: work_date is outside the training period"},
+			{RecordAttendanceRequest{WorkDate: "2026-06-03", CheckIn: clock("09:00")}, "invalid attendance entry: check_in and check_out must be given together"},
+			{RecordAttendanceRequest{WorkDate: "2026-06-03", CheckIn: clock("17:00"), CheckOut: clock("09:00")}, "invalid attendance entry: check_out must be after check_in"},
+			{RecordAttendanceRequest{WorkDate: "2026-06-03", CheckIn: clock("9am"), CheckOut: clock("17:00")}, "invalid attendance entry: check_in must be HH:MM"},
+			{RecordAttendanceRequest{WorkDate: "2026-06-03", CheckIn: clock("06:00"), CheckOut: clock("20:00")}, "invalid attendance entry: at most 12 hours can be recorded per day"},
+			{RecordAttendanceRequest{WorkDate: "2026-06-03", EntryType: models.AttendanceEntryLeave}, "invalid attendance entry: leave_type is required for leave days"},
+			{RecordAttendanceRequest{WorkDate: "2026-06-03", LeaveType: &sick}, "invalid attendance entry: leave_type is only allowed on leave days"},
+		}
+		for _, tc := range cases {
+			_, err := buildAttendanceEntry(training, tc.req, 12)
+			assert.EqualError(t, err, tc.want)
+		}
+
+		entry, err := buildAttendanceEntry(training, RecordAttendanceRequest{WorkDate: "2026-06-03", EntryType: models.AttendanceEntryLeave, LeaveType: &sick}, 12)
+		require.NoError(t, err)
+		assert.Zero(t, entry.Hours)
+	})
+
+	t.Run("time sheets start on Monday", func(t *testing.T) {
+		monday := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, monday, attendanceWeekStart(time.Date(2026, 6, 1, 15, 0, 0, 0, time.UTC)))
+		assert.Equal(t, monday, attendanceWeekStart(time.Date(2026, 6, 7, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, monday.AddDate(0, 0, 7), attendanceWeekStart(time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("only approved weeks count toward the requirement", func(t *testing.T) {
+		personal := models.LeaveTypePersonal
+		week := func(status models.TimesheetStatus, dailyHours ...float64) models.Timesheet {
+			timesheet := models.Timesheet{Status: status}
+			for _, h := range dailyHours {
+				timesheet.Entries = append(timesheet.Entries, models.AttendanceEntry{EntryType: models.AttendanceEntryWork, Hours: h})
+			}
+			return timesheet
+		}
+		leaveWeek := week(models.TimesheetStatusApproved, 8, 8, 8)
+		leaveWeek.Entries = append(leaveWeek.Entries, models.AttendanceEntry{EntryType: models.AttendanceEntryLeave, LeaveType: &personal})
+
+		requirement := &models.AttendanceRequirement{RequiredHours: 80, MinimumWeeks: 3, MinimumWeeklyHours: 30}
+		summary := summarizeAttendance([]models.Timesheet{
+			week(models.TimesheetStatusApproved, 8, 8, 8, 8, 8),
+			leaveWeek,
+			week(models.TimesheetStatusSubmitted, 8, 8, 8, 8, 8),
+		}, requirement)
+
+		assert.Equal(t, 64.0, summary.ApprovedHours)
+		assert.Equal(t, 40.0, summary.PendingHours)
+		assert.Equal(t, 8, summary.WorkDays)
+		assert.Equal(t, 1, summary.LeaveDays[models.LeaveTypePersonal])
+		assert.Equal(t, 1, summary.QualifyingWeeks)
+		assert.Equal(t, 16.0, summary.HoursRemaining)
+		assert.Equal(t, 2, summary.WeeksRemaining)
+		assert.False(t, summary.MeetsRequirement)
+
+		summary = summarizeAttendance([]models.Timesheet{week(models.TimesheetStatusApproved, 10, 10, 10, 10, 10, 10, 10, 10)},
+			&models.AttendanceRequirement{RequiredHours: 80})
+		assert.True(t, summary.MeetsRequirement)
+
+		summary = summarizeAttendance(nil, nil)
+		assert.Nil(t, summary.Requirement)
+		assert.False(t, summary.MeetsRequirement)
+	})
+
+	t.Run("review tokens only reach the supervisor", func(t *testing.T) {
+		token := "secret-review-token"
+		view := newTimesheetView(models.Timesheet{
+			ID:          3,
+			WeekStart:   time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			ReviewToken: &token,
+			Entries:     []models.AttendanceEntry{{EntryType: models.AttendanceEntryWork, Hours: 8}},
+		})
+		content, err := json.Marshal(view)
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), token)
+
+		service := NewAttendanceService(nil, AttendanceSettings{ReviewURL: "https://intern.example.com/api/v1/timesheets/review/"})
+		link := service.reviewLink(token)
+		assert.Equal(t, "https://intern.example.com/api/v1/timesheets/review/"+token, link)
+
+		reviewTraining := &models.StudentTraining{Supervisor: "Somchai"}
+		reviewTraining.StudentEnroll.Student = models.Student{Name: "Suda", Surname: "Jaidee"}
+		subject, body := timesheetReviewEmail(reviewTraining, &view, link)
+		assert.Contains(t, subject, "Suda Jaidee")
+		assert.Contains(t, subject, "2026-06-01")
+		assert.Contains(t, body, "Dear Somchai")
+		assert.Contains(t, body, link)
+	})
+
+	t.Run("mail headers cannot be injected", func(t *testing.T) {
+		mailer := NewSMTPMailer(SMTPSettings{Host: "localhost", From: "noreply@example.com"})
+		assert.EqualError(t, mailer.Send("supervisor@example.com\r\nBcc: other@example.com", "Review", "body"), "invalid email header")
+		assert.EqualError(t, mailer.Send("", "Review", "body"), "email recipient is required")
+
+		message := string(buildMailMessage("noreply@example.com", "supervisor@example.com", "ตรวจสอบ", "line one\nline two"))
+		assert.Contains(t, message, "Subject: =?utf-8?q?")
+		assert.Contains(t, message, "\r\n\r\nline one\r\nline two")
+	})
+}
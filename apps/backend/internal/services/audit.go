@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditService browses the audit trail recorded in activity_logs
+type AuditService struct {
+	db *gorm.DB
+}
+
+// NewAuditService creates a new audit service instance
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// auditEntityTypes lists the entity types whose history can be browsed
+var auditEntityTypes = map[models.EntityType]bool{
+	models.EntityTypeStudent:      true,
+	models.EntityTypeCompany:      true,
+	models.EntityTypeInstructor:   true,
+	models.EntityTypeApproval:     true,
+	models.EntityTypeEvaluation:   true,
+	models.EntityTypeTraining:     true,
+	models.EntityTypeNotification: true,
+	models.EntityTypeDocument:     true,
+	models.EntityTypeUser:         true,
+}
+
+// AuditLogListRequest represents the filters for listing audit log entries
+type AuditLogListRequest struct {
+	Page       int                   `json:"page"`
+	Limit      int                   `json:"limit"`
+	EntityType models.EntityType     `json:"entity_type"`
+	EntityID   uint                  `json:"entity_id"`
+	Action     models.ActivityAction `json:"action"`
+	ActorID    string                `json:"actor_id"`
+	UserType   string                `json:"user_type"`
+	From       *time.Time            `json:"from"`
+	To         *time.Time            `json:"to"`
+}
+
+// AuditLogListResponse represents the response for listing audit log entries
+type AuditLogListResponse struct {
+	Data       []models.ActivityLog `json:"data"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// GetAuditLogs retrieves audit log entries matching the filters, newest first
+func (s *AuditService) GetAuditLogs(req AuditLogListRequest) (*AuditLogListResponse, error) {
+	if req.EntityType != "" && !auditEntityTypes[req.EntityType] {
+		return nil, errors.New("invalid entity type")
+	}
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	query := s.db.Model(&models.ActivityLog{})
+	if req.EntityType != "" {
+		query = query.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID != 0 {
+		query = query.Where("entity_id = ?", req.EntityID)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+	if req.ActorID != "" {
+		query = query.Where("actor_id = ?", req.ActorID)
+	}
+	if req.UserType != "" {
+		query = query.Where("user_type = ?", req.UserType)
+	}
+	if req.From != nil {
+		query = query.Where("created_at >= ?", *req.From)
+	}
+	if req.To != nil {
+		query = query.Where("created_at <= ?", *req.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	var logs []models.ActivityLog
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(req.Limit).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch audit logs: %w", err)
+	}
+
+	return &AuditLogListResponse{
+		Data:       logs,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+	}, nil
+}
+
+// GetEntityHistory retrieves the audit log entries of one entity, newest first
+func (s *AuditService) GetEntityHistory(entityType models.EntityType, entityID uint, page, limit int) (*AuditLogListResponse, error) {
+	if !auditEntityTypes[entityType] {
+		return nil, errors.New("invalid entity type")
+	}
+	return s.GetAuditLogs(AuditLogListRequest{
+		Page:       page,
+		Limit:      limit,
+		EntityType: entityType,
+		EntityID:   entityID,
+	})
+}
+
+// GetAuditLog retrieves one audit log entry
+func (s *AuditService) GetAuditLog(id uint) (*models.ActivityLog, error) {
+	var log models.ActivityLog
+	if err := s.db.First(&log, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("audit log not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &log, nil
+}
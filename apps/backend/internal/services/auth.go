@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"backend-go/internal/models"
 	"golang.org/x/crypto/bcrypt"
@@ -36,6 +37,27 @@ type LoginResponse struct {
 	TokenType    string      `json:"token_type"`
 	ExpiresIn    int         `json:"expires_in"`
 	User         models.User `json:"user"`
+
+	// Set instead of tokens when the account has 2FA enabled; exchange via /login/2fa
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+// AdminLoginRequest represents the super admin login request payload
+type AdminLoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// AdminLoginResponse represents the super admin login response
+type AdminLoginResponse struct {
+	AccessToken       string             `json:"access_token,omitempty"`
+	RefreshToken      string             `json:"refresh_token,omitempty"`
+	TokenType         string             `json:"token_type"`
+	ExpiresIn         int                `json:"expires_in"`
+	Admin             *models.SuperAdmin `json:"admin,omitempty"`
+	TwoFactorRequired bool               `json:"two_factor_required,omitempty"`
+	ChallengeToken    string             `json:"challenge_token,omitempty"`
 }
 
 // RegisterRequest represents the user registration request
@@ -99,6 +121,11 @@ func (a *AuthService) Login(req LoginRequest) (*LoginResponse, error) {
 		return nil, errors.New("invalid credentials")
 	}
 
+	// Accounts with 2FA get a challenge instead of tokens
+	if challenge, err := a.twoFactorChallenge(UserTypeStudent, user.StudentID, user.Email); err != nil || challenge != nil {
+		return challenge, err
+	}
+
 	// Generate tokens
 	accessToken, err := a.jwtService.GenerateTokenForUser(&user, TokenTypeAccess, []string{}, false, "")
 	if err != nil {
@@ -341,6 +368,11 @@ func (a *AuthService) StudentLogin(req StudentLoginRequest) (*LoginResponse, err
 		return nil, errors.New("invalid credentials")
 	}
 
+	// Accounts with 2FA get a challenge instead of tokens
+	if challenge, err := a.twoFactorChallenge(UserTypeStudent, user.StudentID, user.Email); err != nil || challenge != nil {
+		return challenge, err
+	}
+
 	// Update last login timestamp
 	err = user.UpdateLastLogin(a.db)
 	if err != nil {
@@ -371,6 +403,89 @@ func (a *AuthService) StudentLogin(req StudentLoginRequest) (*LoginResponse, err
 	}, nil
 }
 
+// AdminLogin authenticates a super admin. Admins with 2FA enabled receive a challenge token
+// that must be exchanged for access tokens with a TOTP or backup code.
+func (a *AuthService) AdminLogin(req AdminLoginRequest) (*AdminLoginResponse, error) {
+	var admin models.SuperAdmin
+
+	err := a.db.Where("email = ?", req.Email).First(&admin).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid credentials")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	// Check password
+	if !admin.CheckPassword(req.Password) {
+		return nil, errors.New("invalid credentials")
+	}
+
+	if admin.IsTwoFactorEnabled() {
+		challenge, err := a.jwtService.GenerateTwoFactorChallengeToken(admin.GetIdentifier(), UserTypeSuperAdmin, admin.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+		}
+		return &AdminLoginResponse{
+			TokenType:         "Bearer",
+			ExpiresIn:         int(time.Until(challenge.ExpiresAt).Seconds()),
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge.Token,
+		}, nil
+	}
+
+	// Update last login timestamp
+	if err := admin.UpdateLastLogin(a.db); err != nil {
+		// Log error but don't fail the login
+		fmt.Printf("Failed to update last login for admin %d: %v\n", admin.ID, err)
+	}
+
+	// Generate tokens
+	accessToken, err := a.jwtService.GenerateTokenForSuperAdmin(&admin, TokenTypeAccess, []string{}, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := a.jwtService.GenerateTokenForSuperAdmin(&admin, TokenTypeRefresh, []string{}, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return &AdminLoginResponse{
+		AccessToken:  accessToken.Token,
+		RefreshToken: refreshToken.Token,
+		TokenType:    "Bearer",
+		ExpiresIn:    24 * 60 * 60, // 24 hours in seconds
+		Admin:        &admin,
+	}, nil
+}
+
+// twoFactorChallenge returns a challenge response when the user has confirmed 2FA enrollment,
+// or nil when the login can proceed with tokens
+func (a *AuthService) twoFactorChallenge(userType UserType, userID, email string) (*LoginResponse, error) {
+	var count int64
+	if err := a.db.Model(&models.TwoFactorCredential{}).
+		Where("user_type = ? AND user_id = ? AND enabled_at IS NOT NULL", string(userType), userID).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	challenge, err := a.jwtService.GenerateTwoFactorChallengeToken(userID, userType, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+	}
+
+	return &LoginResponse{
+		TokenType:         "Bearer",
+		ExpiresIn:         int(time.Until(challenge.ExpiresAt).Seconds()),
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge.Token,
+	}, nil
+}
+
 // StudentRegister creates a new student account
 func (a *AuthService) StudentRegister(req StudentRegisterRequest) (*models.User, error) {
 	// Validate password confirmation
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// defaultAuthorizationCacheTTL is how long resolved roles and permissions are reused
+const defaultAuthorizationCacheTTL = 5 * time.Minute
+
+// UserAccess holds the roles and permissions resolved for an authenticated principal
+type UserAccess struct {
+	UserType    UserType `json:"user_type"`
+	UserID      string   `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+
+	// Linked record IDs, used to scope data access (0 when not linked)
+	StudentID    uint `json:"student_id,omitempty"`
+	InstructorID uint `json:"instructor_id,omitempty"`
+}
+
+// HasRole reports whether the principal holds any of the given roles
+func (a *UserAccess) HasRole(roles ...string) bool {
+	for _, held := range a.Roles {
+		for _, role := range roles {
+			if held == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PrimaryRole returns the most privileged built-in role held, or the first assigned role
+func (a *UserAccess) PrimaryRole() string {
+	for _, role := range []string{string(models.AdminRoleSuperAdmin), models.RoleNameAdmin, models.RoleNameInstructor, models.RoleNameStudent} {
+		if a.HasRole(role) {
+			return role
+		}
+	}
+	if len(a.Roles) > 0 {
+		return a.Roles[0]
+	}
+	return ""
+}
+
+// PermissionSet returns the permissions as a lookup map
+func (a *UserAccess) PermissionSet() map[string]bool {
+	set := make(map[string]bool, len(a.Permissions))
+	for _, permission := range a.Permissions {
+		set[permission] = true
+	}
+	return set
+}
+
+type cachedUserAccess struct {
+	access    *UserAccess
+	expiresAt time.Time
+}
+
+// AuthorizationService resolves roles and permissions for users and super admins.
+// Results are cached per principal for a short TTL; call Invalidate or InvalidateAll
+// after changing role assignments or role permissions.
+type AuthorizationService struct {
+	db         *gorm.DB
+	jwtService *JWTService
+	ttl        time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedUserAccess
+}
+
+// NewAuthorizationService creates a new authorization service instance
+func NewAuthorizationService(db *gorm.DB, jwtService *JWTService) *AuthorizationService {
+	return &AuthorizationService{
+		db:         db,
+		jwtService: jwtService,
+		ttl:        defaultAuthorizationCacheTTL,
+		cache:      make(map[string]cachedUserAccess),
+	}
+}
+
+// SetCacheTTL changes how long resolved access is cached (0 disables caching)
+func (s *AuthorizationService) SetCacheTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttl = ttl
+	s.cache = make(map[string]cachedUserAccess)
+}
+
+// ResolveAccess returns the roles and permissions granted to the given principal
+func (s *AuthorizationService) ResolveAccess(userType UserType, userID string) (*UserAccess, error) {
+	key := string(userType) + ":" + userID
+
+	s.mu.RLock()
+	cached, ok := s.cache[key]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.access, nil
+	}
+
+	access, err := s.loadAccess(userType, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.ttl > 0 {
+		s.cache[key] = cachedUserAccess{access: access, expiresAt: time.Now().Add(s.ttl)}
+	}
+	s.mu.Unlock()
+
+	return access, nil
+}
+
+// Invalidate drops the cached access of a single principal
+func (s *AuthorizationService) Invalidate(userType UserType, userID string) {
+	s.mu.Lock()
+	delete(s.cache, string(userType)+":"+userID)
+	s.mu.Unlock()
+}
+
+// InvalidateAll drops every cached access entry
+func (s *AuthorizationService) InvalidateAll() {
+	s.mu.Lock()
+	s.cache = make(map[string]cachedUserAccess)
+	s.mu.Unlock()
+}
+
+// HasPermission reports whether the access grants the permission. Wildcards such as
+// "*" and "students:*" are honoured the same way as token abilities.
+func (s *AuthorizationService) HasPermission(access *UserAccess, permission string) bool {
+	return s.jwtService.HasAbility(access.Permissions, permission)
+}
+
+// AssignRole grants a role to a principal
+func (s *AuthorizationService) AssignRole(userType UserType, userID string, roleName string) error {
+	var role models.Role
+	if err := s.db.Where("name = ?", roleName).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("role not found")
+		}
+		return err
+	}
+
+	assignment := models.UserRole{UserType: string(userType), UserID: userID, RoleID: role.ID}
+	if err := s.db.Where(assignment).FirstOrCreate(&assignment).Error; err != nil {
+		return err
+	}
+
+	s.Invalidate(userType, userID)
+	return nil
+}
+
+// RevokeRole removes a role from a principal
+func (s *AuthorizationService) RevokeRole(userType UserType, userID string, roleName string) error {
+	result := s.db.
+		Where("user_type = ? AND user_id = ? AND role_id IN (?)", string(userType), userID,
+			s.db.Model(&models.Role{}).Select("id").Where("name = ?", roleName)).
+		Delete(&models.UserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("role assignment not found")
+	}
+
+	s.Invalidate(userType, userID)
+	return nil
+}
+
+// loadAccess resolves the implicit roles of the account type plus assigned roles,
+// then collects the permissions of every active role
+func (s *AuthorizationService) loadAccess(userType UserType, userID string) (*UserAccess, error) {
+	roles := make(map[string]bool)
+	permissions := make(map[string]bool)
+	access := &UserAccess{UserType: userType, UserID: userID}
+
+	switch userType {
+	case UserTypeSuperAdmin:
+		var admin models.SuperAdmin
+		if err := s.db.Where("id = ?", userID).First(&admin).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("user not found")
+			}
+			return nil, err
+		}
+		roles[models.RoleNameAdmin] = true
+		roles[string(admin.Role)] = true
+		// Built-in admin permissions use dot notation (users.read); normalize to resource:action
+		for _, permission := range admin.GetPermissions() {
+			permissions[strings.Replace(permission, ".", ":", 1)] = true
+		}
+	case UserTypeStudent:
+		account, linked, err := ResolveAccount(s.db, userType, userID)
+		if err != nil {
+			return nil, err
+		}
+		if linked {
+			switch account.Type {
+			case AccountTypeStudent:
+				access.StudentID = account.ID
+				roles[models.RoleNameStudent] = true
+			case AccountTypeInstructor:
+				access.InstructorID = account.ID
+				roles[models.RoleNameInstructor] = true
+			}
+		}
+	default:
+		return nil, errors.New("unsupported user type")
+	}
+
+	var assigned []string
+	if err := s.db.Model(&models.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_type = ? AND user_roles.user_id = ?", string(userType), userID).
+		Where("roles.is_active = ?", true).
+		Pluck("roles.name", &assigned).Error; err != nil {
+		return nil, err
+	}
+	for _, role := range assigned {
+		roles[role] = true
+	}
+
+	roleNames := make([]string, 0, len(roles))
+	for role := range roles {
+		roleNames = append(roleNames, role)
+	}
+	sort.Strings(roleNames)
+
+	var granted []models.Permission
+	if err := s.db.Model(&models.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id AND roles.deleted_at IS NULL").
+		Where("roles.name IN ? AND roles.is_active = ?", roleNames, true).
+		Where("permissions.is_active = ?", true).
+		Find(&granted).Error; err != nil {
+		return nil, err
+	}
+	for _, permission := range granted {
+		permissions[permission.Name] = true
+		if permission.Resource != "" && permission.Action != "" {
+			permissions[permission.GetFullPermissionName()] = true
+		}
+	}
+
+	permissionNames := make([]string, 0, len(permissions))
+	for permission := range permissions {
+		permissionNames = append(permissionNames, permission)
+	}
+	sort.Strings(permissionNames)
+
+	access.Roles = roleNames
+	access.Permissions = permissionNames
+	return access, nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAccess(t *testing.T) {
+	t.Run("PrimaryRole prefers built-in roles", func(t *testing.T) {
+		access := &UserAccess{Roles: []string{"committee", "instructor"}}
+		assert.Equal(t, "instructor", access.PrimaryRole())
+		assert.True(t, access.HasRole("student", "committee"))
+		assert.False(t, access.HasRole("admin"))
+
+		assert.Equal(t, "committee", (&UserAccess{Roles: []string{"committee"}}).PrimaryRole())
+		assert.Equal(t, "", (&UserAccess{}).PrimaryRole())
+	})
+
+	t.Run("HasPermission honours wildcards", func(t *testing.T) {
+		authService := NewAuthorizationService(nil, &JWTService{})
+
+		access := &UserAccess{Permissions: []string{"students:*", "approvals:committee_vote"}}
+		assert.True(t, authService.HasPermission(access, "students:bulk_delete"))
+		assert.True(t, authService.HasPermission(access, "approvals:committee_vote"))
+		assert.False(t, authService.HasPermission(access, "approvals:update_status"))
+
+		assert.True(t, authService.HasPermission(&UserAccess{Permissions: []string{"*"}}, "approvals:update_status"))
+	})
+
+	t.Run("super admins resolve to their own account type", func(t *testing.T) {
+		account, linked, err := ResolveAccount(nil, UserTypeSuperAdmin, "7")
+		assert.NoError(t, err)
+		assert.True(t, linked)
+		assert.Equal(t, Account{Type: AccountTypeSuperAdmin, ID: 7}, account)
+
+		_, _, err = ResolveAccount(nil, UserType("Guest"), "7")
+		assert.EqualError(t, err, "unsupported user type")
+	})
+}
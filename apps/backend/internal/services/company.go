@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"backend-go/internal/database"
 	"backend-go/internal/models"
 	"gorm.io/gorm"
 )
@@ -20,6 +22,13 @@ func NewCompanyService(db *gorm.DB) *CompanyService {
 	}
 }
 
+// WithContext returns a copy of the service bound to the request context
+func (s *CompanyService) WithContext(ctx context.Context) *CompanyService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	return &bound
+}
+
 // CompanyListRequest represents the request for listing companies
 type CompanyListRequest struct {
 	Page         int    `json:"page"`
@@ -241,7 +250,7 @@ func (s *CompanyService) UpdateCompany(id uint, req UpdateCompanyRequest) (*mode
 	return &company, nil
 }
 
-// DeleteCompany deletes a company by ID
+// DeleteCompany moves a company to the recycle bin by ID
 func (s *CompanyService) DeleteCompany(id uint) error {
 	var company models.Company
 	err := s.db.First(&company, id).Error
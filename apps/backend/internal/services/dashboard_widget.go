@@ -0,0 +1,529 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// WidgetType identifies how a dashboard widget presents its data
+type WidgetType string
+
+const (
+	WidgetTypeKPI          WidgetType = "kpi"
+	WidgetTypeTimeSeries   WidgetType = "time_series"
+	WidgetTypeBarByFaculty WidgetType = "bar_by_faculty"
+	WidgetTypeTable        WidgetType = "table"
+	WidgetTypeStatusDonut  WidgetType = "status_donut"
+)
+
+// defaultWidgetPeriodDays is the rolling window used when a widget sets no dates
+const defaultWidgetPeriodDays = 365
+
+// WidgetConfig holds the filters and options stored in a widget's Config JSON.
+// Which fields apply depends on the widget type.
+type WidgetConfig struct {
+	StartDate       string   `json:"start_date,omitempty"`  // YYYY-MM-DD
+	EndDate         string   `json:"end_date,omitempty"`    // YYYY-MM-DD
+	PeriodDays      int      `json:"period_days,omitempty"` // rolling window ending today when no dates are set
+	Metric          string   `json:"metric,omitempty"`      // kpi value or bar_by_faculty measure
+	Series          []string `json:"series,omitempty"`      // time_series lines
+	Source          string   `json:"source,omitempty"`      // table and status_donut data source
+	FacultyIDs      []uint   `json:"faculty_ids,omitempty"` // bar_by_faculty and faculty tables
+	Statuses        []string `json:"statuses,omitempty"`    // status_donut segments to keep
+	Limit           int      `json:"limit,omitempty"`       // maximum bars or table rows
+	ComparePrevious bool     `json:"compare_previous,omitempty"`
+}
+
+// KPIWidgetData is the resolved data of a KPI tile
+type KPIWidgetData struct {
+	Metric        string   `json:"metric"`
+	Label         string   `json:"label"`
+	Unit          string   `json:"unit,omitempty"`
+	Value         float64  `json:"value"`
+	PreviousValue *float64 `json:"previous_value,omitempty"`
+	ChangePercent *float64 `json:"change_percent,omitempty"`
+}
+
+// ChartWidgetData is the resolved data of a time series or bar chart
+type ChartWidgetData struct {
+	Labels []string       `json:"labels"`
+	Series []WidgetSeries `json:"series"`
+}
+
+// WidgetSeries is one named line or bar set of a chart
+type WidgetSeries struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values"`
+}
+
+// TableWidgetData is the resolved data of a table widget
+type TableWidgetData struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// DonutWidgetData is the resolved data of a status donut
+type DonutWidgetData struct {
+	Total    int64           `json:"total"`
+	Segments []WidgetSegment `json:"segments"`
+}
+
+// WidgetSegment is one slice of a donut
+type WidgetSegment struct {
+	Label      string  `json:"label"`
+	Value      int64   `json:"value"`
+	Percentage float64 `json:"percentage"`
+}
+
+// widgetPeriod is the date range a widget reports on
+type widgetPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// previous returns the period of the same length immediately before p
+func (p widgetPeriod) previous() widgetPeriod {
+	return widgetPeriod{Start: p.Start.Add(-p.End.Sub(p.Start)), End: p.Start}
+}
+
+// kpiMetric describes a value a KPI tile can show
+type kpiMetric struct {
+	label string
+	unit  string
+	value func(d *widgetDataSet, p widgetPeriod) (float64, error)
+}
+
+// kpiMetrics lists the values available to KPI tiles
+var kpiMetrics = map[string]kpiMetric{
+	"total_internships": {label: "Total Internships", value: internshipValue(func(a *AnalyticsResponse) float64 { return float64(a.TotalInternships) })},
+	"approval_rate":     {label: "Approval Rate", unit: "%", value: internshipValue(func(a *AnalyticsResponse) float64 { return a.ApprovalRate })},
+	"completion_rate":   {label: "Completion Rate", unit: "%", value: internshipValue(func(a *AnalyticsResponse) float64 { return a.CompletionRate })},
+	"total_evaluations": {label: "Total Evaluations", value: internshipValue(func(a *AnalyticsResponse) float64 {
+		return float64(a.EvaluationMetrics.TotalEvaluations)
+	})},
+	"pending_evaluations": {label: "Pending Evaluations", value: internshipValue(func(a *AnalyticsResponse) float64 {
+		return float64(a.EvaluationMetrics.PendingEvaluations)
+	})},
+	"overdue_evaluations": {label: "Overdue Evaluations", value: internshipValue(func(a *AnalyticsResponse) float64 {
+		return float64(a.EvaluationMetrics.OverdueEvaluations)
+	})},
+	"evaluation_completion_rate": {label: "Evaluation Completion Rate", unit: "%", value: internshipValue(func(a *AnalyticsResponse) float64 {
+		return a.EvaluationMetrics.CompletionRate
+	})},
+	"average_approval_time": {label: "Average Approval Time", unit: "hours", value: func(d *widgetDataSet, p widgetPeriod) (float64, error) {
+		analytics, err := d.approvalAnalytics(p)
+		if err != nil {
+			return 0, err
+		}
+		return analytics.AverageApprovalTimeHours, nil
+	}},
+}
+
+// internshipValue adapts a field of the internship analytics to a KPI value
+func internshipValue(pick func(*AnalyticsResponse) float64) func(d *widgetDataSet, p widgetPeriod) (float64, error) {
+	return func(d *widgetDataSet, p widgetPeriod) (float64, error) {
+		analytics, err := d.internshipAnalytics(p)
+		if err != nil {
+			return 0, err
+		}
+		return pick(analytics), nil
+	}
+}
+
+// timeSeriesNames lists the monthly series a time series widget can draw
+var timeSeriesNames = map[string]string{
+	"internships": "Internships",
+	"approvals":   "Approvals",
+	"completions": "Completions",
+}
+
+// facultyMeasures lists the values a bar by faculty widget can plot
+var facultyMeasures = map[string]string{
+	"student_count":    "Students",
+	"internship_count": "Internships",
+	"success_rate":     "Success Rate (%)",
+}
+
+// tableSources lists the data sources of table widgets
+var tableSources = map[string]bool{
+	"top_companies":        true,
+	"company_performance":  true,
+	"faculty_distribution": true,
+	"company_types":        true,
+}
+
+// donutSources lists the data sources of status donut widgets
+var donutSources = map[string]bool{
+	"approval_status":   true,
+	"evaluation_status": true,
+}
+
+// parseWidgetConfig decodes a widget's Config JSON
+func parseWidgetConfig(raw json.RawMessage) (WidgetConfig, error) {
+	var cfg WidgetConfig
+	if len(raw) == 0 || string(raw) == "null" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid widget config: %w", err)
+	}
+	return cfg, nil
+}
+
+// validateWidgetConfig checks that a widget's config can be resolved and returns the
+// analytics data source it reads from
+func validateWidgetConfig(widgetType WidgetType, cfg WidgetConfig) (string, error) {
+	if _, err := cfg.period(time.Now()); err != nil {
+		return "", err
+	}
+	if cfg.Limit < 0 || cfg.PeriodDays < 0 {
+		return "", fmt.Errorf("invalid widget config: limit and period_days cannot be negative")
+	}
+
+	switch widgetType {
+	case WidgetTypeKPI:
+		if _, ok := kpiMetrics[cfg.Metric]; !ok {
+			return "", fmt.Errorf("invalid widget config: unknown KPI metric %q", cfg.Metric)
+		}
+		if cfg.Metric == "average_approval_time" {
+			return "approval_analytics", nil
+		}
+		return "internship_analytics", nil
+	case WidgetTypeTimeSeries:
+		for _, name := range cfg.Series {
+			if _, ok := timeSeriesNames[name]; !ok {
+				return "", fmt.Errorf("invalid widget config: unknown series %q", name)
+			}
+		}
+		return "internship_analytics", nil
+	case WidgetTypeBarByFaculty:
+		if _, ok := facultyMeasures[cfg.Metric]; cfg.Metric != "" && !ok {
+			return "", fmt.Errorf("invalid widget config: unknown faculty measure %q", cfg.Metric)
+		}
+		return "internship_analytics", nil
+	case WidgetTypeTable:
+		if !tableSources[cfg.Source] {
+			return "", fmt.Errorf("invalid widget config: unknown table source %q", cfg.Source)
+		}
+		if cfg.Source == "company_performance" || cfg.Source == "company_types" {
+			return "company_analytics", nil
+		}
+		return "internship_analytics", nil
+	case WidgetTypeStatusDonut:
+		if cfg.Source != "" && !donutSources[cfg.Source] {
+			return "", fmt.Errorf("invalid widget config: unknown donut source %q", cfg.Source)
+		}
+		return "internship_analytics", nil
+	default:
+		return "", fmt.Errorf("unsupported widget type: %s", widgetType)
+	}
+}
+
+// period resolves the configured dates, falling back to a rolling window ending at now
+func (cfg WidgetConfig) period(now time.Time) (widgetPeriod, error) {
+	days := cfg.PeriodDays
+	if days == 0 {
+		days = defaultWidgetPeriodDays
+	}
+	period := widgetPeriod{Start: now.AddDate(0, 0, -days), End: now}
+
+	if cfg.StartDate != "" {
+		start, err := time.Parse("2006-01-02", cfg.StartDate)
+		if err != nil {
+			return period, fmt.Errorf("invalid widget config: start_date must be YYYY-MM-DD")
+		}
+		period.Start = start
+	}
+	if cfg.EndDate != "" {
+		end, err := time.Parse("2006-01-02", cfg.EndDate)
+		if err != nil {
+			return period, fmt.Errorf("invalid widget config: end_date must be YYYY-MM-DD")
+		}
+		period.End = end.AddDate(0, 0, 1).Add(-time.Nanosecond) // include the whole end day
+	}
+	if period.End.Before(period.Start) {
+		return period, fmt.Errorf("invalid widget config: end_date must be after start_date")
+	}
+	return period, nil
+}
+
+// widgetDataSet loads each analytics query at most once per period while a dashboard is resolved,
+// so widgets that share a period share the queries
+type widgetDataSet struct {
+	analyticsService *AnalyticsService
+	now              time.Time
+	internship       map[widgetPeriod]*AnalyticsResponse
+	approval         map[widgetPeriod]*ApprovalAnalytics
+	company          map[widgetPeriod]*CompanyAnalytics
+}
+
+func newWidgetDataSet(analyticsService *AnalyticsService, now time.Time) *widgetDataSet {
+	return &widgetDataSet{
+		analyticsService: analyticsService,
+		now:              now,
+		internship:       make(map[widgetPeriod]*AnalyticsResponse),
+		approval:         make(map[widgetPeriod]*ApprovalAnalytics),
+		company:          make(map[widgetPeriod]*CompanyAnalytics),
+	}
+}
+
+func (d *widgetDataSet) internshipAnalytics(p widgetPeriod) (*AnalyticsResponse, error) {
+	if analytics, ok := d.internship[p]; ok {
+		return analytics, nil
+	}
+	analytics, err := d.analyticsService.GetInternshipAnalytics(AnalyticsRequest{StartDate: p.Start, EndDate: p.End})
+	if err != nil {
+		return nil, err
+	}
+	d.internship[p] = analytics
+	return analytics, nil
+}
+
+func (d *widgetDataSet) approvalAnalytics(p widgetPeriod) (*ApprovalAnalytics, error) {
+	if analytics, ok := d.approval[p]; ok {
+		return analytics, nil
+	}
+	analytics, err := d.analyticsService.GetApprovalAnalytics(AnalyticsRequest{StartDate: p.Start, EndDate: p.End})
+	if err != nil {
+		return nil, err
+	}
+	d.approval[p] = analytics
+	return analytics, nil
+}
+
+func (d *widgetDataSet) companyAnalytics(p widgetPeriod) (*CompanyAnalytics, error) {
+	if analytics, ok := d.company[p]; ok {
+		return analytics, nil
+	}
+	analytics, err := d.analyticsService.GetCompanyAnalytics(AnalyticsRequest{StartDate: p.Start, EndDate: p.End})
+	if err != nil {
+		return nil, err
+	}
+	d.company[p] = analytics
+	return analytics, nil
+}
+
+// resolve computes the data of one widget
+func (d *widgetDataSet) resolve(widgetType WidgetType, cfg WidgetConfig) (interface{}, error) {
+	if _, err := validateWidgetConfig(widgetType, cfg); err != nil {
+		return nil, err
+	}
+	period, err := cfg.period(d.now)
+	if err != nil {
+		return nil, err
+	}
+
+	switch widgetType {
+	case WidgetTypeKPI:
+		return d.resolveKPI(cfg, period)
+	case WidgetTypeTimeSeries:
+		return d.resolveTimeSeries(cfg, period)
+	case WidgetTypeBarByFaculty:
+		return d.resolveBarByFaculty(cfg, period)
+	case WidgetTypeTable:
+		return d.resolveTable(cfg, period)
+	default:
+		return d.resolveStatusDonut(cfg, period)
+	}
+}
+
+func (d *widgetDataSet) resolveKPI(cfg WidgetConfig, period widgetPeriod) (*KPIWidgetData, error) {
+	metric := kpiMetrics[cfg.Metric]
+
+	value, err := metric.value(d, period)
+	if err != nil {
+		return nil, err
+	}
+	data := &KPIWidgetData{Metric: cfg.Metric, Label: metric.label, Unit: metric.unit, Value: value}
+
+	if cfg.ComparePrevious {
+		previous, err := metric.value(d, period.previous())
+		if err != nil {
+			return nil, err
+		}
+		data.PreviousValue = &previous
+		if previous != 0 {
+			change := (value - previous) / previous * 100
+			data.ChangePercent = &change
+		}
+	}
+	return data, nil
+}
+
+func (d *widgetDataSet) resolveTimeSeries(cfg WidgetConfig, period widgetPeriod) (*ChartWidgetData, error) {
+	analytics, err := d.internshipAnalytics(period)
+	if err != nil {
+		return nil, err
+	}
+
+	names := cfg.Series
+	if len(names) == 0 {
+		names = []string{"internships"}
+	}
+
+	data := &ChartWidgetData{Labels: make([]string, 0, len(analytics.MonthlyTrends))}
+	for _, trend := range analytics.MonthlyTrends {
+		data.Labels = append(data.Labels, trend.Month)
+	}
+
+	for _, name := range names {
+		series := WidgetSeries{Name: timeSeriesNames[name], Values: make([]float64, 0, len(analytics.MonthlyTrends))}
+		for _, trend := range analytics.MonthlyTrends {
+			switch name {
+			case "internships":
+				series.Values = append(series.Values, float64(trend.InternshipCount))
+			case "approvals":
+				series.Values = append(series.Values, float64(trend.ApprovalCount))
+			case "completions":
+				series.Values = append(series.Values, float64(trend.CompletionCount))
+			}
+		}
+		data.Series = append(data.Series, series)
+	}
+	return data, nil
+}
+
+func (d *widgetDataSet) resolveBarByFaculty(cfg WidgetConfig, period widgetPeriod) (*ChartWidgetData, error) {
+	analytics, err := d.internshipAnalytics(period)
+	if err != nil {
+		return nil, err
+	}
+
+	measure := cfg.Metric
+	if measure == "" {
+		measure = "internship_count"
+	}
+
+	series := WidgetSeries{Name: facultyMeasures[measure], Values: []float64{}}
+	data := &ChartWidgetData{Labels: []string{}}
+	for _, faculty := range filterFaculties(analytics.FacultyDistribution, cfg.FacultyIDs, cfg.Limit) {
+		data.Labels = append(data.Labels, faculty.FacultyName)
+		switch measure {
+		case "student_count":
+			series.Values = append(series.Values, float64(faculty.StudentCount))
+		case "success_rate":
+			series.Values = append(series.Values, faculty.SuccessRate)
+		default:
+			series.Values = append(series.Values, float64(faculty.InternshipCount))
+		}
+	}
+	data.Series = []WidgetSeries{series}
+	return data, nil
+}
+
+func (d *widgetDataSet) resolveTable(cfg WidgetConfig, period widgetPeriod) (*TableWidgetData, error) {
+	data := &TableWidgetData{Rows: [][]interface{}{}}
+
+	switch cfg.Source {
+	case "top_companies", "company_performance":
+		var companies []CompanyStats
+		if cfg.Source == "top_companies" {
+			analytics, err := d.internshipAnalytics(period)
+			if err != nil {
+				return nil, err
+			}
+			companies = analytics.TopCompanies
+		} else {
+			analytics, err := d.companyAnalytics(period)
+			if err != nil {
+				return nil, err
+			}
+			companies = analytics.CompanyPerformance
+		}
+		data.Columns = []string{"Company", "Students", "Success Rate (%)", "Average Rating"}
+		for _, company := range companies {
+			data.Rows = append(data.Rows, []interface{}{company.CompanyName, company.StudentCount, company.SuccessRate, company.AverageRating})
+		}
+	case "faculty_distribution":
+		analytics, err := d.internshipAnalytics(period)
+		if err != nil {
+			return nil, err
+		}
+		data.Columns = []string{"Faculty", "Students", "Internships", "Success Rate (%)"}
+		for _, faculty := range filterFaculties(analytics.FacultyDistribution, cfg.FacultyIDs, 0) {
+			data.Rows = append(data.Rows, []interface{}{faculty.FacultyName, faculty.StudentCount, faculty.InternshipCount, faculty.SuccessRate})
+		}
+	case "company_types":
+		analytics, err := d.companyAnalytics(period)
+		if err != nil {
+			return nil, err
+		}
+		data.Columns = []string{"Company Type", "Companies", "Students"}
+		for _, companyType := range analytics.TypeDistribution {
+			data.Rows = append(data.Rows, []interface{}{companyType.CompanyType, companyType.Count, companyType.StudentCount})
+		}
+	}
+
+	if cfg.Limit > 0 && len(data.Rows) > cfg.Limit {
+		data.Rows = data.Rows[:cfg.Limit]
+	}
+	return data, nil
+}
+
+func (d *widgetDataSet) resolveStatusDonut(cfg WidgetConfig, period widgetPeriod) (*DonutWidgetData, error) {
+	analytics, err := d.internshipAnalytics(period)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []WidgetSegment
+	if cfg.Source == "evaluation_status" {
+		metrics := analytics.EvaluationMetrics
+		segments = []WidgetSegment{
+			{Label: "completed", Value: metrics.CompletedEvaluations},
+			{Label: "pending", Value: metrics.PendingEvaluations},
+			{Label: "overdue", Value: metrics.OverdueEvaluations},
+		}
+	} else {
+		for _, status := range analytics.StatusDistribution {
+			segments = append(segments, WidgetSegment{Label: status.Status, Value: status.Count})
+		}
+	}
+
+	return buildDonut(segments, cfg.Statuses), nil
+}
+
+// buildDonut keeps the requested segments and computes percentages of the remaining total
+func buildDonut(segments []WidgetSegment, statuses []string) *DonutWidgetData {
+	keep := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		keep[status] = true
+	}
+
+	data := &DonutWidgetData{Segments: []WidgetSegment{}}
+	for _, segment := range segments {
+		if len(keep) > 0 && !keep[segment.Label] {
+			continue
+		}
+		data.Total += segment.Value
+		data.Segments = append(data.Segments, segment)
+	}
+
+	for i := range data.Segments {
+		if data.Total > 0 {
+			data.Segments[i].Percentage = float64(data.Segments[i].Value) / float64(data.Total) * 100
+		}
+	}
+	return data
+}
+
+// filterFaculties keeps the configured faculties, at most limit of them (0 means no limit)
+func filterFaculties(faculties []FacultyStatsData, ids []uint, limit int) []FacultyStatsData {
+	keep := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		keep[id] = true
+	}
+
+	filtered := make([]FacultyStatsData, 0, len(faculties))
+	for _, faculty := range faculties {
+		if len(keep) > 0 && !keep[faculty.FacultyID] {
+			continue
+		}
+		filtered = append(filtered, faculty)
+		if limit > 0 && len(filtered) == limit {
+			break
+		}
+	}
+	return filtered
+}
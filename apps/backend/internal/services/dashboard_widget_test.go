@@ -0,0 +1,149 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardWidgets(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	// loadedDataSet returns a data set whose analytics are already loaded for the default period,
+	// so widgets resolve without a database
+	loadedDataSet := func(t *testing.T) *widgetDataSet {
+		period, err := WidgetConfig{}.period(now)
+		require.NoError(t, err)
+
+		data := newWidgetDataSet(nil, now)
+		data.internship[period] = &AnalyticsResponse{
+			TotalInternships: 120,
+			ApprovalRate:     75,
+			MonthlyTrends: []MonthlyTrendData{
+				{Month: "2026-04", InternshipCount: 10, ApprovalCount: 8, CompletionCount: 2},
+				{Month: "2026-05", InternshipCount: 20, ApprovalCount: 15, CompletionCount: 5},
+			},
+			FacultyDistribution: []FacultyStatsData{
+				{FacultyID: 1, FacultyName: "Engineering", StudentCount: 80, InternshipCount: 60, SuccessRate: 90},
+				{FacultyID: 2, FacultyName: "Science", StudentCount: 40, InternshipCount: 30, SuccessRate: 70},
+				{FacultyID: 3, FacultyName: "Arts", StudentCount: 20, InternshipCount: 10, SuccessRate: 50},
+			},
+			StatusDistribution: []StatusData{
+				{Status: "approved", Count: 60},
+				{Status: "pending", Count: 30},
+				{Status: "rejected", Count: 10},
+			},
+			EvaluationMetrics: EvaluationMetrics{CompletedEvaluations: 6, PendingEvaluations: 3, OverdueEvaluations: 1},
+			TopCompanies: []CompanyStats{
+				{CompanyName: "Acme", StudentCount: 12, SuccessRate: 95, AverageRating: 4.5},
+				{CompanyName: "Globex", StudentCount: 8, SuccessRate: 80, AverageRating: 4.1},
+			},
+		}
+		data.internship[period.previous()] = &AnalyticsResponse{TotalInternships: 100}
+		return data
+	}
+
+	t.Run("config is validated against the widget type", func(t *testing.T) {
+		source, err := validateWidgetConfig(WidgetTypeKPI, WidgetConfig{Metric: "average_approval_time"})
+		require.NoError(t, err)
+		assert.Equal(t, "approval_analytics", source)
+
+		source, err = validateWidgetConfig(WidgetTypeTable, WidgetConfig{Source: "company_types"})
+		require.NoError(t, err)
+		assert.Equal(t, "company_analytics", source)
+
+		_, err = validateWidgetConfig(WidgetTypeKPI, WidgetConfig{Metric: "gpax"})
+		assert.EqualError(t, err, `invalid widget config: unknown KPI metric "gpax"`)
+
+		_, err = validateWidgetConfig(WidgetTypeTimeSeries, WidgetConfig{Series: []string{"rejections"}})
+		assert.EqualError(t, err, `invalid widget config: unknown series "rejections"`)
+
+		_, err = validateWidgetConfig(WidgetTypeBarByFaculty, WidgetConfig{StartDate: "2026-06-01", EndDate: "2026-05-01"})
+		assert.EqualError(t, err, "invalid widget config: end_date must be after start_date")
+
+		_, err = validateWidgetConfig("gauge", WidgetConfig{})
+		assert.EqualError(t, err, "unsupported widget type: gauge")
+
+		_, err = parseWidgetConfig(json.RawMessage(`{"limit":"ten"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("period defaults to a rolling window and includes the whole end day", func(t *testing.T) {
+		period, err := WidgetConfig{PeriodDays: 30}.period(now)
+		require.NoError(t, err)
+		assert.Equal(t, now.AddDate(0, 0, -30), period.Start)
+		assert.Equal(t, now, period.End)
+
+		period, err = WidgetConfig{StartDate: "2026-01-01", EndDate: "2026-01-31"}.period(now)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 1, 31, 23, 59, 59, 999999999, time.UTC), period.End)
+	})
+
+	t.Run("KPI compares against the previous period", func(t *testing.T) {
+		data, err := loadedDataSet(t).resolve(WidgetTypeKPI, WidgetConfig{Metric: "total_internships", ComparePrevious: true})
+		require.NoError(t, err)
+
+		kpi := data.(*KPIWidgetData)
+		assert.Equal(t, 120.0, kpi.Value)
+		require.NotNil(t, kpi.PreviousValue)
+		assert.Equal(t, 100.0, *kpi.PreviousValue)
+		require.NotNil(t, kpi.ChangePercent)
+		assert.InDelta(t, 20.0, *kpi.ChangePercent, 0.001)
+	})
+
+	t.Run("time series draws the configured monthly series", func(t *testing.T) {
+		data, err := loadedDataSet(t).resolve(WidgetTypeTimeSeries, WidgetConfig{Series: []string{"approvals", "completions"}})
+		require.NoError(t, err)
+
+		chart := data.(*ChartWidgetData)
+		assert.Equal(t, []string{"2026-04", "2026-05"}, chart.Labels)
+		assert.Equal(t, []WidgetSeries{
+			{Name: "Approvals", Values: []float64{8, 15}},
+			{Name: "Completions", Values: []float64{2, 5}},
+		}, chart.Series)
+	})
+
+	t.Run("bar by faculty filters faculties and limits bars", func(t *testing.T) {
+		data, err := loadedDataSet(t).resolve(WidgetTypeBarByFaculty, WidgetConfig{Metric: "success_rate", FacultyIDs: []uint{2, 3}, Limit: 1})
+		require.NoError(t, err)
+
+		chart := data.(*ChartWidgetData)
+		assert.Equal(t, []string{"Science"}, chart.Labels)
+		assert.Equal(t, []float64{70}, chart.Series[0].Values)
+	})
+
+	t.Run("table limits rows", func(t *testing.T) {
+		data, err := loadedDataSet(t).resolve(WidgetTypeTable, WidgetConfig{Source: "top_companies", Limit: 1})
+		require.NoError(t, err)
+
+		table := data.(*TableWidgetData)
+		assert.Equal(t, []string{"Company", "Students", "Success Rate (%)", "Average Rating"}, table.Columns)
+		assert.Equal(t, [][]interface{}{{"Acme", int64(12), 95.0, 4.5}}, table.Rows)
+	})
+
+	t.Run("status donut keeps the requested statuses", func(t *testing.T) {
+		data, err := loadedDataSet(t).resolve(WidgetTypeStatusDonut, WidgetConfig{Statuses: []string{"approved", "rejected"}})
+		require.NoError(t, err)
+
+		donut := data.(*DonutWidgetData)
+		assert.Equal(t, int64(70), donut.Total)
+		require.Len(t, donut.Segments, 2)
+		assert.InDelta(t, 85.714, donut.Segments[0].Percentage, 0.001)
+
+		data, err = loadedDataSet(t).resolve(WidgetTypeStatusDonut, WidgetConfig{Source: "evaluation_status"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), data.(*DonutWidgetData).Total)
+	})
+
+	t.Run("built-in templates only contain valid widgets", func(t *testing.T) {
+		for _, template := range dashboardTemplates {
+			for _, widget := range template.Widgets {
+				_, err := newDashboardWidget(1, widget)
+				assert.NoError(t, err, "%s: %s", template.Key, widget.Title)
+			}
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ScopeLevel describes how much student data a principal may read
+type ScopeLevel string
+
+const (
+	ScopeAll        ScopeLevel = "all"        // admins
+	ScopeInstructor ScopeLevel = "instructor" // advisees and visitees only
+	ScopeStudent    ScopeLevel = "student"    // own records only
+	ScopeNone       ScopeLevel = "none"       // no student data
+)
+
+// ScopedResource identifies a record type that can be checked against a DataScope
+type ScopedResource string
+
+const (
+	ScopedStudent                  ScopedResource = "student"
+	ScopedStudentEnroll            ScopedResource = "student_enroll"
+	ScopedStudentTraining          ScopedResource = "student_training"
+	ScopedVisitorTraining          ScopedResource = "visitor_training"
+	ScopedVisitorSchedule          ScopedResource = "visitor_schedule"
+	ScopedVisitPhoto               ScopedResource = "visit_photo"
+	ScopedVisitorStudentEvaluation ScopedResource = "visitor_student_evaluation"
+	ScopedVisitorCompanyEvaluation ScopedResource = "visitor_company_evaluation"
+	ScopedInstructor               ScopedResource = "instructor"
+	ScopedCourse                   ScopedResource = "course"
+	ScopedCommitteeEnroll          ScopedResource = "committee_enroll" // student enrolls in scope plus those the instructor votes on
+)
+
+// DataScope restricts which students' records a principal can read.
+// A nil scope is unrestricted, so internal callers can keep passing nil.
+type DataScope struct {
+	Level        ScopeLevel `json:"level"`
+	StudentID    uint       `json:"student_id,omitempty"`    // students.id for student scope
+	InstructorID uint       `json:"instructor_id,omitempty"` // instructors.id for instructor scope
+}
+
+// Unrestricted reports whether the scope allows every record
+func (s *DataScope) Unrestricted() bool {
+	return s == nil || s.Level == ScopeAll
+}
+
+// StudentEnrolls restricts a query to rows whose column references a visible student_enrolls.id
+func (s *DataScope) StudentEnrolls(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if s.Unrestricted() {
+			return tx
+		}
+		return tx.Where(column+" IN (?)", s.visibleStudentEnrolls(tx))
+	}
+}
+
+// StudentTrainings restricts a query to rows whose column references a visible student_trainings.id
+func (s *DataScope) StudentTrainings(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if s.Unrestricted() {
+			return tx
+		}
+		db := tx.Session(&gorm.Session{NewDB: true})
+		return tx.Where(column+" IN (?)", db.Model(&models.StudentTraining{}).
+			Select("id").
+			Where("student_enroll_id IN (?)", s.visibleStudentEnrolls(tx)))
+	}
+}
+
+// VisitorTrainings restricts a query to rows whose column references a visible visitor_trainings.id
+func (s *DataScope) VisitorTrainings(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if s.Unrestricted() {
+			return tx
+		}
+		db := tx.Session(&gorm.Session{NewDB: true})
+		return tx.Where(column+" IN (?)", db.Model(&models.VisitorTraining{}).
+			Select("id").
+			Where("student_enroll_id IN (?)", s.visibleStudentEnrolls(tx)))
+	}
+}
+
+// VisitorSchedules restricts a query to rows whose column references a visible visitor_schedules.id
+func (s *DataScope) VisitorSchedules(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if s.Unrestricted() {
+			return tx
+		}
+		db := tx.Session(&gorm.Session{NewDB: true})
+		return tx.Where(column+" IN (?)", db.Model(&models.VisitorSchedule{}).
+			Select("id").
+			Where("visitor_training_id IN (?)", db.Model(&models.VisitorTraining{}).
+				Select("id").
+				Where("student_enroll_id IN (?)", s.visibleStudentEnrolls(tx))))
+	}
+}
+
+// Courses restricts a query to rows whose column references a visible courses.id.
+// Students see the courses they are enrolled in; instructors also see the courses
+// they teach or sit on the committee of.
+func (s *DataScope) Courses(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if s.Unrestricted() {
+			return tx
+		}
+		db := tx.Session(&gorm.Session{NewDB: true})
+		sections := db.Model(&models.StudentEnroll{}).Select("course_section_id").Where("id IN (?)", s.visibleStudentEnrolls(tx))
+		if s.Level == ScopeInstructor {
+			sections = db.Model(&models.CourseSection{}).Select("id").
+				Where("id IN (?) OR id IN (?) OR id IN (?)", sections,
+					db.Model(&models.CourseInstructor{}).Select("course_section_id").Where("instructor_id = ?", s.InstructorID),
+					db.Model(&models.CourseCommittee{}).Select("course_section_id").Where("instructor_id = ?", s.InstructorID))
+		}
+		return tx.Where(column+" IN (?)", db.Model(&models.CourseSection{}).Select("course_id").Where("id IN (?)", sections))
+	}
+}
+
+// CommitteeEnrolls restricts a query to rows whose column references a student_enrolls.id that is
+// visible, or that belongs to a course section the instructor sits on the committee of
+func (s *DataScope) CommitteeEnrolls(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if s.Unrestricted() || s.Level != ScopeInstructor {
+			return s.StudentEnrolls(column)(tx)
+		}
+		db := tx.Session(&gorm.Session{NewDB: true})
+		return tx.Where(column+" IN (?) OR "+column+" IN (?)", s.visibleStudentEnrolls(tx),
+			db.Model(&models.StudentEnroll{}).Select("id").
+				Where("course_section_id IN (?)", db.Model(&models.CourseCommittee{}).
+					Select("course_section_id").
+					Where("instructor_id = ?", s.InstructorID)))
+	}
+}
+
+// Students restricts a query to rows whose column references a visible students.id
+func (s *DataScope) Students(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		switch {
+		case s.Unrestricted():
+			return tx
+		case s.Level == ScopeStudent:
+			return tx.Where(column+" = ?", s.StudentID)
+		}
+		db := tx.Session(&gorm.Session{NewDB: true})
+		return tx.Where(column+" IN (?)", db.Model(&models.StudentEnroll{}).
+			Select("student_id").
+			Where("id IN (?)", s.visibleStudentEnrolls(tx)))
+	}
+}
+
+// Instructors restricts a query to rows whose column references a visible instructors.id.
+// Instructors only see themselves; students see no instructor records.
+func (s *DataScope) Instructors(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		switch {
+		case s.Unrestricted():
+			return tx
+		case s.Level == ScopeInstructor:
+			return tx.Where(column+" = ?", s.InstructorID)
+		}
+		return tx.Where("1 = 0")
+	}
+}
+
+// visibleStudentEnrolls builds a subquery selecting the student_enrolls.id values in scope
+func (s *DataScope) visibleStudentEnrolls(tx *gorm.DB) *gorm.DB {
+	db := tx.Session(&gorm.Session{NewDB: true})
+	query := db.Model(&models.StudentEnroll{}).Select("id")
+
+	switch s.Level {
+	case ScopeStudent:
+		return query.Where("student_id = ?", s.StudentID)
+	case ScopeInstructor:
+		return query.Where("id IN (?) OR id IN (?)",
+			db.Model(&models.InternshipApproval{}).Select("student_enroll_id").Where("advisor_id = ?", s.InstructorID),
+			db.Model(&models.VisitorTraining{}).Select("student_enroll_id").Where("visitor_instructor_id = ?", s.InstructorID))
+	default:
+		return query.Where("1 = 0")
+	}
+}
+
+// ResolveDataScope derives the data scope of a principal from its resolved roles
+func (s *AuthorizationService) ResolveDataScope(userType UserType, userID string) (*DataScope, error) {
+	access, err := s.ResolveAccess(userType, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case access.HasRole(models.RoleNameAdmin) || s.HasPermission(access, "*"):
+		return &DataScope{Level: ScopeAll}, nil
+	case access.HasRole(models.RoleNameInstructor) && access.InstructorID != 0:
+		return &DataScope{Level: ScopeInstructor, InstructorID: access.InstructorID}, nil
+	case access.StudentID != 0:
+		return &DataScope{Level: ScopeStudent, StudentID: access.StudentID}, nil
+	default:
+		return &DataScope{Level: ScopeNone}, nil
+	}
+}
+
+// CanAccess reports whether a single record is visible in the scope.
+// Records that do not exist are reported as not accessible.
+func (s *AuthorizationService) CanAccess(scope *DataScope, resource ScopedResource, id uint) (bool, error) {
+	if scope.Unrestricted() {
+		return true, nil
+	}
+
+	var query *gorm.DB
+	switch resource {
+	case ScopedStudent:
+		query = s.db.Model(&models.Student{}).Scopes(scope.Students("students.id"))
+	case ScopedStudentEnroll:
+		query = s.db.Model(&models.StudentEnroll{}).Scopes(scope.StudentEnrolls("student_enrolls.id"))
+	case ScopedStudentTraining:
+		query = s.db.Model(&models.StudentTraining{}).Scopes(scope.StudentTrainings("student_trainings.id"))
+	case ScopedVisitorTraining:
+		query = s.db.Model(&models.VisitorTraining{}).Scopes(scope.VisitorTrainings("visitor_trainings.id"))
+	case ScopedVisitorSchedule:
+		query = s.db.Model(&models.VisitorSchedule{}).Scopes(scope.VisitorSchedules("visitor_schedules.id"))
+	case ScopedVisitPhoto:
+		query = s.db.Model(&models.VisitsPicture{}).Scopes(scope.VisitorSchedules("visits_pictures.visitor_schedule_id"))
+	case ScopedVisitorStudentEvaluation:
+		query = s.db.Model(&models.VisitorEvaluateStudent{}).Scopes(scope.VisitorTrainings("visitor_evaluate_students.visitor_training_id"))
+	case ScopedVisitorCompanyEvaluation:
+		query = s.db.Model(&models.VisitorEvaluateCompany{}).Scopes(scope.VisitorTrainings("visitor_evaluate_companies.visitor_training_id"))
+	case ScopedInstructor:
+		query = s.db.Model(&models.Instructor{}).Scopes(scope.Instructors("instructors.id"))
+	case ScopedCourse:
+		query = s.db.Model(&models.Course{}).Scopes(scope.Courses("courses.id"))
+	case ScopedCommitteeEnroll:
+		query = s.db.Model(&models.StudentEnroll{}).Scopes(scope.CommitteeEnrolls("student_enrolls.id"))
+	default:
+		return false, nil
+	}
+
+	var count int64
+	if err := query.Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestDataScope(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	trainingsSQL := func(scope *DataScope) string {
+		return db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var trainings []models.StudentTraining
+			return tx.Model(&models.StudentTraining{}).
+				Scopes(scope.StudentEnrolls("student_trainings.student_enroll_id")).
+				Find(&trainings)
+		})
+	}
+
+	t.Run("Nil and admin scopes are unrestricted", func(t *testing.T) {
+		assert.True(t, (*DataScope)(nil).Unrestricted())
+		assert.NotContains(t, trainingsSQL(nil), "student_enroll_id IN")
+		assert.NotContains(t, trainingsSQL(&DataScope{Level: ScopeAll}), "student_enroll_id IN")
+	})
+
+	t.Run("Student scope limits to own enrollments", func(t *testing.T) {
+		sql := trainingsSQL(&DataScope{Level: ScopeStudent, StudentID: 42})
+		assert.Contains(t, sql, `student_trainings.student_enroll_id IN (SELECT "id" FROM "student_enrolls" WHERE student_id = 42 AND "student_enrolls"."deleted_at" IS NULL)`)
+	})
+
+	t.Run("Instructor scope covers advisees and visitees", func(t *testing.T) {
+		sql := trainingsSQL(&DataScope{Level: ScopeInstructor, InstructorID: 7})
+		assert.Contains(t, sql, `id IN (SELECT "student_enroll_id" FROM "internship_approvals" WHERE advisor_id = 7)`)
+		assert.Contains(t, sql, `OR id IN (SELECT "student_enroll_id" FROM "visitor_trainings" WHERE visitor_instructor_id = 7)`)
+	})
+
+	t.Run("Students and instructors scopes", func(t *testing.T) {
+		studentScope := &DataScope{Level: ScopeStudent, StudentID: 42}
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var instructors []models.Instructor
+			return tx.Model(&models.Instructor{}).Scopes(studentScope.Instructors("instructors.id")).Find(&instructors)
+		})
+		assert.Contains(t, sql, "1 = 0")
+
+		sql = db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var students []models.Student
+			return tx.Model(&models.Student{}).Scopes(studentScope.Students("students.id")).Find(&students)
+		})
+		assert.Contains(t, sql, "students.id = 42")
+	})
+
+	t.Run("Committee members see the enrollments they vote on", func(t *testing.T) {
+		scope := &DataScope{Level: ScopeInstructor, InstructorID: 7}
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var enrolls []models.StudentEnroll
+			return tx.Model(&models.StudentEnroll{}).Scopes(scope.CommitteeEnrolls("student_enrolls.id")).Where("id = ?", 3).Find(&enrolls)
+		})
+		assert.Contains(t, sql, `SELECT "course_section_id" FROM "course_committees" WHERE instructor_id = 7`)
+		assert.Contains(t, sql, `WHERE id = 3 AND (student_enrolls.id IN`)
+	})
+
+	t.Run("Courses cover taught and committee sections for instructors", func(t *testing.T) {
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var courses []models.Course
+			return tx.Model(&models.Course{}).Scopes((&DataScope{Level: ScopeInstructor, InstructorID: 7}).Courses("courses.id")).Find(&courses)
+		})
+		assert.Contains(t, sql, `SELECT "course_section_id" FROM "course_instructors" WHERE instructor_id = 7`)
+		assert.Contains(t, sql, `SELECT "course_section_id" FROM "course_committees" WHERE instructor_id = 7`)
+
+		sql = db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var courses []models.Course
+			return tx.Model(&models.Course{}).Scopes((&DataScope{Level: ScopeStudent, StudentID: 42}).Courses("courses.id")).Find(&courses)
+		})
+		assert.NotContains(t, sql, "course_instructors")
+		assert.Contains(t, sql, `courses.id IN (SELECT "course_id" FROM "course_sections" WHERE id IN (SELECT "course_section_id" FROM "student_enrolls" WHERE id IN`)
+	})
+
+	t.Run("Visit photos follow their visitor schedule", func(t *testing.T) {
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var pictures []models.VisitsPicture
+			return tx.Model(&models.VisitsPicture{}).Scopes((&DataScope{Level: ScopeStudent, StudentID: 42}).VisitorSchedules("visits_pictures.visitor_schedule_id")).Find(&pictures)
+		})
+		assert.Contains(t, sql, `visits_pictures.visitor_schedule_id IN (SELECT "id" FROM "visitor_schedules" WHERE visitor_training_id IN (SELECT "id" FROM "visitor_trainings" WHERE student_enroll_id IN`)
+	})
+
+	t.Run("Visitor evaluations follow their visitor training", func(t *testing.T) {
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var evaluations []models.VisitorEvaluateStudent
+			return tx.Model(&models.VisitorEvaluateStudent{}).Scopes((&DataScope{Level: ScopeInstructor, InstructorID: 7}).VisitorTrainings("visitor_evaluate_students.visitor_training_id")).Find(&evaluations)
+		})
+		assert.Contains(t, sql, `visitor_evaluate_students.visitor_training_id IN (SELECT "id" FROM "visitor_trainings" WHERE student_enroll_id IN`)
+		assert.Contains(t, sql, "visitor_instructor_id = 7")
+	})
+}
@@ -0,0 +1,635 @@
+package services
+
+import (
+	"backend-go/internal/database"
+	"backend-go/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DocumentService handles document management business logic (Yellow Flow)
+type DocumentService struct {
+	db        *gorm.DB
+	uploadDir string
+}
+
+// NewDocumentService creates a new document service
+func NewDocumentService(db *gorm.DB) *DocumentService {
+	return &DocumentService{
+		db:        db,
+		uploadDir: "uploads/documents",
+	}
+}
+
+// WithContext returns a copy of the service bound to the request context
+func (s *DocumentService) WithContext(ctx context.Context) *DocumentService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	return &bound
+}
+
+// DocumentActor identifies the caller reading or changing documents
+type DocumentActor struct {
+	UserID      uint
+	AccountType string
+	Scope       *DataScope // admins are unrestricted; others also see documents on trainings in their scope
+}
+
+// owns reports whether the actor may change the document: its uploader, matched on account type
+// and ID, or an admin
+func (a DocumentActor) owns(document *models.Document) bool {
+	return a.Scope.Unrestricted() || (document.UploadedByID == a.UserID && document.UploadedByType == a.AccountType)
+}
+
+// visible restricts a document query to what the actor uploaded or may read through its data scope
+func (a DocumentActor) visible() func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if a.Scope.Unrestricted() {
+			return tx
+		}
+		db := tx.Session(&gorm.Session{NewDB: true})
+		return tx.Where(db.Where("documents.uploaded_by_id = ? AND documents.uploaded_by_type = ?", a.UserID, a.AccountType).
+			Or(a.Scope.StudentTrainings("documents.student_training_id")(db)))
+	}
+}
+
+// DocumentListRequest represents the request for listing documents
+type DocumentListRequest struct {
+	Page              int    `json:"page"`
+	Limit             int    `json:"limit"`
+	Search            string `json:"search"`
+	DocumentType      string `json:"document_type"`
+	Status            string `json:"status"`
+	StudentTrainingID *uint  `json:"student_training_id"`
+	UploadedByID      *uint  `json:"uploaded_by_id"`
+	Overdue           bool   `json:"overdue"`
+	LatestOnly        bool   `json:"latest_only"`
+	SortBy            string `json:"sort_by"`
+	SortDesc          bool   `json:"sort_desc"`
+}
+
+// UploadDocumentRequest represents the request for uploading a document
+type UploadDocumentRequest struct {
+	Title             string     `json:"title" validate:"required,max=255"`
+	Description       string     `json:"description"`
+	DocumentType      string     `json:"document_type" validate:"required,oneof=application contract evaluation report certificate recommendation insurance other"`
+	StudentTrainingID *uint      `json:"student_training_id"`
+	DueDate           *time.Time `json:"due_date"`
+	SaveAsDraft       bool       `json:"save_as_draft"`
+}
+
+// UpdateDocumentRequest represents the request for updating a document
+type UpdateDocumentRequest struct {
+	Title        *string                `json:"title" validate:"omitempty,max=255"`
+	Description  *string                `json:"description"`
+	DocumentType *string                `json:"document_type" validate:"omitempty,oneof=application contract evaluation report certificate recommendation insurance other"`
+	Status       *models.DocumentStatus `json:"status" validate:"omitempty,oneof=draft pending approved rejected revision archived"`
+	DueDate      *time.Time             `json:"due_date"`
+	ClearDueDate bool                   `json:"clear_due_date"`
+}
+
+// ApproveDocumentRequest represents the request for reviewing a document
+type ApproveDocumentRequest struct {
+	Status   models.DocumentStatus `json:"status" validate:"omitempty,oneof=approved rejected revision"`
+	Comments string                `json:"comments"`
+}
+
+// AddCommentRequest represents the request for commenting on a document
+type AddCommentRequest struct {
+	DocumentID uint   `json:"document_id"`
+	Comment    string `json:"comment" validate:"required"`
+}
+
+// DocumentListResponse represents the response for listing documents
+type DocumentListResponse struct {
+	Data       []models.Document `json:"data"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// DocumentStatsResponse represents document statistics
+type DocumentStatsResponse struct {
+	TotalDocuments   int64            `json:"total_documents"`
+	PendingDocuments int64            `json:"pending_documents"`
+	OverdueDocuments int64            `json:"overdue_documents"`
+	DueSoonDocuments int64            `json:"due_soon_documents"`
+	ByStatus         map[string]int64 `json:"by_status"`
+	ByType           map[string]int64 `json:"by_type"`
+}
+
+// documentSortColumns lists the columns documents may be sorted by
+var documentSortColumns = map[string]bool{
+	"title":         true,
+	"document_type": true,
+	"status":        true,
+	"version":       true,
+	"due_date":      true,
+	"submitted_at":  true,
+	"created_at":    true,
+	"updated_at":    true,
+}
+
+// GetDocuments retrieves the documents visible to the actor with pagination and filtering
+func (s *DocumentService) GetDocuments(req DocumentListRequest, actor DocumentActor) (*DocumentListResponse, error) {
+	var documents []models.Document
+	var total int64
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+
+	query := s.db.Model(&models.Document{}).
+		Scopes(actor.visible()).
+		Preload("StudentTraining")
+
+	// Apply filters
+	if req.DocumentType != "" {
+		query = query.Where("document_type = ?", req.DocumentType)
+	}
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if req.StudentTrainingID != nil {
+		query = query.Where("student_training_id = ?", *req.StudentTrainingID)
+	}
+
+	if req.UploadedByID != nil {
+		query = query.Where("uploaded_by_id = ?", *req.UploadedByID)
+	}
+
+	if req.Overdue {
+		query = query.Where("due_date IS NOT NULL AND due_date < ? AND status IN ?", time.Now(), openDocumentStatuses())
+	}
+
+	if req.LatestOnly {
+		query = query.Where("status <> ?", models.DocStatusArchived)
+	}
+
+	if req.Search != "" {
+		query = query.Where("title ILIKE ? OR description ILIKE ? OR file_name ILIKE ?",
+			"%"+req.Search+"%", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply sorting
+	orderBy := "created_at DESC"
+	if documentSortColumns[req.SortBy] {
+		direction := "ASC"
+		if req.SortDesc {
+			direction = "DESC"
+		}
+		orderBy = req.SortBy + " " + direction
+	}
+	query = query.Order(orderBy)
+
+	// Apply pagination
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Offset(offset).Limit(req.Limit).Find(&documents).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &DocumentListResponse{
+		Data:       documents,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetDocumentByID retrieves a document visible to the actor by ID with its approvals and comments
+func (s *DocumentService) GetDocumentByID(id uint, actor DocumentActor) (*models.Document, error) {
+	var document models.Document
+	err := s.db.Scopes(actor.visible()).
+		Preload("StudentTraining").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&document, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("document not found")
+		}
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// GetDocumentVersions retrieves every version of the document series the given document belongs to
+func (s *DocumentService) GetDocumentVersions(id uint, actor DocumentActor) ([]models.Document, error) {
+	document, err := s.GetDocumentByID(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []models.Document
+	if document.StudentTrainingID == nil {
+		return []models.Document{*document}, nil
+	}
+
+	if err := s.db.Scopes(actor.visible()).
+		Where("student_training_id = ? AND document_type = ?", *document.StudentTrainingID, document.DocumentType).
+		Order("version DESC").
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// UploadDocument stores the uploaded file and creates a new document version uploaded by the actor
+func (s *DocumentService) UploadDocument(req UploadDocumentRequest, file *multipart.FileHeader, actor DocumentActor) (*models.Document, error) {
+	if file == nil {
+		return nil, errors.New("file is required")
+	}
+
+	// Documents may only be attached to a training in the actor's data scope
+	if req.StudentTrainingID != nil {
+		var training models.StudentTraining
+		if err := s.db.Scopes(actor.Scope.StudentTrainings("student_trainings.id")).First(&training, *req.StudentTrainingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("student training not found")
+			}
+			return nil, err
+		}
+	}
+
+	filePath, err := s.saveFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	now := time.Now()
+	document := &models.Document{
+		Title:             req.Title,
+		Description:       req.Description,
+		DocumentType:      models.DocumentType(req.DocumentType),
+		Status:            models.DocStatusPending,
+		FilePath:          filePath,
+		FileName:          file.Filename,
+		FileSize:          file.Size,
+		MimeType:          file.Header.Get("Content-Type"),
+		Version:           1,
+		StudentTrainingID: req.StudentTrainingID,
+		UploadedByID:      actor.UserID,
+		UploadedByType:    actor.AccountType,
+		DueDate:           req.DueDate,
+		SubmittedAt:       &now,
+	}
+
+	if req.SaveAsDraft {
+		document.Status = models.DocStatusDraft
+		document.SubmittedAt = nil
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Documents attached to a training form a version series per document type
+		if req.StudentTrainingID != nil {
+			var previous models.Document
+			err := tx.Where("student_training_id = ? AND document_type = ?", *req.StudentTrainingID, req.DocumentType).
+				Order("version DESC").
+				First(&previous).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if err == nil {
+				document.Version = previous.Version + 1
+				// Carry the due date forward so resubmissions are tracked against the same deadline
+				if document.DueDate == nil {
+					document.DueDate = previous.DueDate
+				}
+
+				// Older versions are superseded by the new upload
+				if err := tx.Model(&models.Document{}).
+					Where("student_training_id = ? AND document_type = ? AND status <> ?", *req.StudentTrainingID, req.DocumentType, models.DocStatusArchived).
+					Update("status", models.DocStatusArchived).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Create(document).Error
+	})
+	if err != nil {
+		os.Remove(filePath)
+		return nil, err
+	}
+
+	return s.GetDocumentByID(document.ID, actor)
+}
+
+// UpdateDocument updates document metadata and status. Only the uploader or an admin may change a document.
+func (s *DocumentService) UpdateDocument(id uint, req UpdateDocumentRequest, actor DocumentActor) (*models.Document, error) {
+	document, err := s.getOwnedDocument(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields
+	if req.Title != nil {
+		document.Title = *req.Title
+	}
+	if req.Description != nil {
+		document.Description = *req.Description
+	}
+	if req.DocumentType != nil {
+		document.DocumentType = models.DocumentType(*req.DocumentType)
+	}
+	if req.DueDate != nil {
+		document.DueDate = req.DueDate
+	}
+	if req.ClearDueDate {
+		document.DueDate = nil
+	}
+
+	if req.Status != nil && *req.Status != document.Status {
+		// Approval decisions are recorded through ApproveDocument so they keep an audit trail
+		if *req.Status == models.DocStatusApproved || *req.Status == models.DocStatusRejected {
+			return nil, fmt.Errorf("invalid status transition from %s to %s: use the approve endpoint", document.Status, *req.Status)
+		}
+		if !document.CanTransitionTo(*req.Status) {
+			return nil, fmt.Errorf("invalid status transition from %s to %s", document.Status, *req.Status)
+		}
+
+		if *req.Status == models.DocStatusPending {
+			now := time.Now()
+			document.SubmittedAt = &now
+		}
+		document.Status = *req.Status
+	}
+
+	if err := s.db.Save(document).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetDocumentByID(document.ID, actor)
+}
+
+// DeleteDocument deletes a document and its stored file. Only the uploader or an admin may delete a document.
+func (s *DocumentService) DeleteDocument(id uint, actor DocumentActor) error {
+	document, err := s.getOwnedDocument(id, actor)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(document).Error; err != nil {
+		return err
+	}
+
+	// The record is gone; a missing file on disk is not an error
+	if err := os.Remove(document.FilePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("document deleted but failed to remove file: %w", err)
+	}
+
+	return nil
+}
+
+// ApproveDocument records the actor's review decision (approve, reject or request revision) on a
+// pending document visible to the actor
+func (s *DocumentService) ApproveDocument(id uint, req ApproveDocumentRequest, actor DocumentActor) error {
+	decision := req.Status
+	if decision == "" {
+		decision = models.DocStatusApproved
+	}
+
+	if decision != models.DocStatusApproved && decision != models.DocStatusRejected && decision != models.DocStatusRevision {
+		return fmt.Errorf("invalid review decision: %s", decision)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var document models.Document
+		if err := tx.Scopes(actor.visible()).First(&document, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("document not found")
+			}
+			return err
+		}
+
+		if !document.CanTransitionTo(decision) {
+			return fmt.Errorf("invalid status transition from %s to %s", document.Status, decision)
+		}
+
+		approval := &models.DocumentApproval{
+			DocumentID:   document.ID,
+			ApproverID:   actor.UserID,
+			ApproverType: actor.AccountType,
+			Status:       decision,
+			Comments:     req.Comments,
+		}
+		if err := tx.Create(approval).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status": decision,
+		}
+		if decision == models.DocStatusApproved {
+			now := time.Now()
+			updates["approved_by_id"] = actor.UserID
+			updates["approved_by_type"] = actor.AccountType
+			updates["approved_at"] = now
+		}
+
+		return tx.Model(&document).Updates(updates).Error
+	})
+}
+
+// AddComment adds the actor's comment to a document visible to the actor
+func (s *DocumentService) AddComment(req AddCommentRequest, actor DocumentActor) (*models.DocumentComment, error) {
+	if strings.TrimSpace(req.Comment) == "" {
+		return nil, errors.New("comment is required")
+	}
+
+	var document models.Document
+	if err := s.db.Scopes(actor.visible()).First(&document, req.DocumentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("document not found")
+		}
+		return nil, err
+	}
+
+	comment := &models.DocumentComment{
+		DocumentID: req.DocumentID,
+		UserID:     actor.UserID,
+		UserType:   actor.AccountType,
+		Comment:    req.Comment,
+	}
+
+	if err := s.db.Create(comment).Error; err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetDocumentFile returns the stored file path and original file name of a document visible to the actor
+func (s *DocumentService) GetDocumentFile(id uint, actor DocumentActor) (string, string, error) {
+	var document models.Document
+	if err := s.db.Scopes(actor.visible()).Select("id", "file_path", "file_name").First(&document, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", errors.New("document not found")
+		}
+		return "", "", err
+	}
+
+	if _, err := os.Stat(document.FilePath); err != nil {
+		return "", "", fmt.Errorf("document file unavailable: %w", err)
+	}
+
+	return document.FilePath, document.FileName, nil
+}
+
+// GetDocumentStats retrieves statistics including due-date tracking over the documents visible to the actor
+func (s *DocumentService) GetDocumentStats(actor DocumentActor) (*DocumentStatsResponse, error) {
+	stats := &DocumentStatsResponse{
+		ByStatus: make(map[string]int64),
+		ByType:   make(map[string]int64),
+	}
+	visible := actor.visible()
+
+	if err := s.db.Model(&models.Document{}).Scopes(visible).Count(&stats.TotalDocuments).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&models.Document{}).Scopes(visible).
+		Where("status = ?", models.DocStatusPending).
+		Count(&stats.PendingDocuments).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&models.Document{}).Scopes(visible).
+		Where("due_date IS NOT NULL AND due_date < ? AND status IN ?", now, openDocumentStatuses()).
+		Count(&stats.OverdueDocuments).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&models.Document{}).Scopes(visible).
+		Where("due_date IS NOT NULL AND due_date BETWEEN ? AND ? AND status IN ?", now, now.AddDate(0, 0, 7), openDocumentStatuses()).
+		Count(&stats.DueSoonDocuments).Error; err != nil {
+		return nil, err
+	}
+
+	var statusCounts []struct {
+		Status string
+		Count  int64
+	}
+	if err := s.db.Model(&models.Document{}).Scopes(visible).
+		Select("status, COUNT(*) as count").
+		Group("status").
+		Scan(&statusCounts).Error; err != nil {
+		return nil, err
+	}
+	for _, sc := range statusCounts {
+		stats.ByStatus[sc.Status] = sc.Count
+	}
+
+	var typeCounts []struct {
+		DocumentType string
+		Count        int64
+	}
+	if err := s.db.Model(&models.Document{}).Scopes(visible).
+		Select("document_type, COUNT(*) as count").
+		Group("document_type").
+		Scan(&typeCounts).Error; err != nil {
+		return nil, err
+	}
+	for _, tc := range typeCounts {
+		stats.ByType[tc.DocumentType] = tc.Count
+	}
+
+	return stats, nil
+}
+
+// getOwnedDocument loads a document the actor may change. Documents the actor cannot see are
+// reported as not found; visible documents uploaded by someone else are denied.
+func (s *DocumentService) getOwnedDocument(id uint, actor DocumentActor) (*models.Document, error) {
+	var document models.Document
+	if err := s.db.Scopes(actor.visible()).First(&document, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("document not found")
+		}
+		return nil, err
+	}
+
+	if !actor.owns(&document) {
+		return nil, errors.New("document access denied")
+	}
+
+	return &document, nil
+}
+
+// saveFile writes an uploaded file to the upload directory and returns its path
+func (s *DocumentService) saveFile(file *multipart.FileHeader) (string, error) {
+	if err := os.MkdirAll(s.uploadDir, 0755); err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(file.Filename)
+	name := strings.TrimSuffix(filepath.Base(file.Filename), ext)
+	for _, char := range []string{" ", "/", "\\", ":", "*", "?", "\"", "<", ">", "|"} {
+		name = strings.ReplaceAll(name, char, "_")
+	}
+	if len(name) > 50 {
+		name = name[:50]
+	}
+
+	filePath := filepath.Join(s.uploadDir, fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), name, ext))
+
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+
+	return filePath, nil
+}
+
+// openDocumentStatuses returns the statuses that still count against a due date
+func openDocumentStatuses() []models.DocumentStatus {
+	return []models.DocumentStatus{
+		models.DocStatusDraft,
+		models.DocStatusPending,
+		models.DocStatusRevision,
+		models.DocStatusRejected,
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestDocumentAccess(t *testing.T) {
+	t.Run("Only the uploader of the same account type or an admin owns a document", func(t *testing.T) {
+		document := &models.Document{UploadedByID: 5, UploadedByType: string(AccountTypeStudent)}
+		studentScope := &DataScope{Level: ScopeStudent, StudentID: 5}
+
+		assert.True(t, DocumentActor{UserID: 5, AccountType: string(AccountTypeStudent), Scope: studentScope}.owns(document))
+		assert.False(t, DocumentActor{UserID: 5, AccountType: string(AccountTypeInstructor), Scope: &DataScope{Level: ScopeInstructor, InstructorID: 5}}.owns(document))
+		assert.False(t, DocumentActor{UserID: 6, AccountType: string(AccountTypeStudent), Scope: &DataScope{Level: ScopeStudent, StudentID: 6}}.owns(document))
+		assert.True(t, DocumentActor{UserID: 1, AccountType: string(AccountTypeSuperAdmin), Scope: &DataScope{Level: ScopeAll}}.owns(document))
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	documentsSQL := func(actor DocumentActor) string {
+		return db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var documents []models.Document
+			return tx.Model(&models.Document{}).Scopes(actor.visible()).Where("status = ?", models.DocStatusPending).Find(&documents)
+		})
+	}
+
+	t.Run("Admins see every document", func(t *testing.T) {
+		sql := documentsSQL(DocumentActor{UserID: 1, AccountType: string(AccountTypeSuperAdmin), Scope: &DataScope{Level: ScopeAll}})
+		assert.NotContains(t, sql, "uploaded_by_id")
+	})
+
+	t.Run("Others see their uploads and documents on trainings in scope", func(t *testing.T) {
+		sql := documentsSQL(DocumentActor{UserID: 5, AccountType: string(AccountTypeStudent), Scope: &DataScope{Level: ScopeStudent, StudentID: 5}})
+		assert.Contains(t, sql, `status = 'pending' AND ((documents.uploaded_by_id = 5 AND documents.uploaded_by_type = 'User') OR documents.student_training_id IN (SELECT "id" FROM "student_trainings"`)
+	})
+}
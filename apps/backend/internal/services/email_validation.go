@@ -36,6 +36,9 @@ type EmailValidationResult struct {
 	Suggestions []string `json:"suggestions"`
 }
 
+// EmailInUseError is the validation error reported for an email that already belongs to an account
+const EmailInUseError = "อีเมลนี้ถูกใช้งานแล้ว"
+
 // EmailValidationService handles email validation and management
 type EmailValidationService struct {
 	db *gorm.DB
@@ -358,7 +361,7 @@ func (e *EmailValidationService) ValidateEmail(email string, userType string) (E
 
 	if !isUnique {
 		result.IsValid = false
-		result.Errors = append(result.Errors, "อีเมลนี้ถูกใช้งานแล้ว")
+		result.Errors = append(result.Errors, EmailInUseError)
 	}
 
 	// Add warnings for educational domains
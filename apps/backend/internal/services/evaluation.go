@@ -158,9 +158,10 @@ func (s *EvaluationService) MarkEvaluationCompleted(studentTrainingID uint, eval
 	return evaluation.MarkAsCompleted(s.db)
 }
 
-// GetEvaluationsByType gets evaluations by type and optional status
-func (s *EvaluationService) GetEvaluationsByType(evalType models.EvaluationType, status *models.EvaluationStatus, limit, offset int) ([]models.EvaluationStatusTracker, error) {
-	query := s.db.Where("evaluation_type = ?", evalType)
+// GetEvaluationsByType gets evaluations by type and optional status within the caller's data scope
+func (s *EvaluationService) GetEvaluationsByType(scope *DataScope, evalType models.EvaluationType, status *models.EvaluationStatus, limit, offset int) ([]models.EvaluationStatusTracker, error) {
+	query := s.db.Scopes(scope.StudentTrainings("evaluation_status_trackers.student_training_id")).
+		Where("evaluation_type = ?", evalType)
 	
 	if status != nil {
 		query = query.Where("status = ?", *status)
@@ -179,9 +180,9 @@ func (s *EvaluationService) GetEvaluationsByType(evalType models.EvaluationType,
 	return evaluations, err
 }
 
-// GetOverdueEvaluations gets all overdue evaluations
-func (s *EvaluationService) GetOverdueEvaluations() ([]models.EvaluationStatusTracker, error) {
-	return models.GetOverdueEvaluations(s.db)
+// GetOverdueEvaluations gets all overdue evaluations within the caller's data scope
+func (s *EvaluationService) GetOverdueEvaluations(scope *DataScope) ([]models.EvaluationStatusTracker, error) {
+	return models.GetOverdueEvaluations(s.db.Scopes(scope.StudentTrainings("evaluation_status_trackers.student_training_id")))
 }
 
 // GetEvaluationStats gets evaluation statistics
@@ -0,0 +1,789 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-go/internal/database"
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Default answer range of rating questions that do not set one
+const (
+	DefaultRatingMin = 1
+	DefaultRatingMax = 5
+)
+
+// EvaluationFormService handles evaluation form authoring and evaluation submissions
+type EvaluationFormService struct {
+	db *gorm.DB
+}
+
+// NewEvaluationFormService creates a new evaluation form service instance
+func NewEvaluationFormService(db *gorm.DB) *EvaluationFormService {
+	return &EvaluationFormService{db: db}
+}
+
+// WithContext returns a copy of the service bound to the request context
+func (s *EvaluationFormService) WithContext(ctx context.Context) *EvaluationFormService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	return &bound
+}
+
+// EvaluationFormRequest represents the request for creating an evaluation form.
+// A form without a faculty applies to every faculty that has no form of its own.
+type EvaluationFormRequest struct {
+	Name        string                      `json:"name" validate:"required"`
+	Description string                      `json:"description"`
+	FormType    models.EvaluationFormType   `json:"form_type" validate:"required,oneof=student_self student_company company_student visitor_student visitor_company instructor_student"`
+	FacultyID   *uint                       `json:"faculty_id"`
+	Questions   []models.EvaluationQuestion `json:"questions" validate:"required,min=1"`
+}
+
+// UpdateEvaluationFormRequest represents the request for editing an evaluation form's content
+type UpdateEvaluationFormRequest struct {
+	Name        string                      `json:"name" validate:"required"`
+	Description string                      `json:"description"`
+	Questions   []models.EvaluationQuestion `json:"questions" validate:"required,min=1"`
+}
+
+// EvaluationFormFilter represents the filters for listing evaluation forms
+type EvaluationFormFilter struct {
+	FormType        models.EvaluationFormType
+	FacultyID       *uint
+	IncludeInactive bool
+}
+
+// SaveEvaluationRequest represents an evaluator's answers to a form. Drafts may be incomplete;
+// Submit validates that every required question is answered.
+type SaveEvaluationRequest struct {
+	FormID   uint                      `json:"form_id" validate:"required"`
+	Answers  []models.EvaluationAnswer `json:"answers"`
+	Comments string                    `json:"comments"`
+	Submit   bool                      `json:"submit"`
+}
+
+// QuestionAnalyticsFilter represents the filters for question-by-question evaluation analytics.
+// FormID selects a form together with all of its versions.
+type QuestionAnalyticsFilter struct {
+	FormType models.EvaluationFormType
+	FormID   *uint
+}
+
+// QuestionStat summarizes the answers given to one question across submitted evaluations
+type QuestionStat struct {
+	FormType     models.EvaluationFormType `json:"form_type"`
+	QuestionID   string                    `json:"question_id"`
+	Question     string                    `json:"question"`
+	Type         models.QuestionType       `json:"type"`
+	Category     string                    `json:"category"`
+	Responses    int                       `json:"responses"`
+	Average      *float64                  `json:"average,omitempty"`
+	Min          *float64                  `json:"min,omitempty"`
+	Max          *float64                  `json:"max,omitempty"`
+	Distribution map[string]int            `json:"distribution,omitempty"`
+}
+
+// ListForms lists evaluation forms, current versions only unless inactive forms are requested
+func (s *EvaluationFormService) ListForms(filter EvaluationFormFilter) ([]models.EvaluationForm, error) {
+	query := s.db.Model(&models.EvaluationForm{})
+	if filter.FormType != "" {
+		query = query.Where("form_type = ?", filter.FormType)
+	}
+	if filter.FacultyID != nil {
+		query = query.Where("faculty_id = ?", *filter.FacultyID)
+	}
+	if !filter.IncludeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var forms []models.EvaluationForm
+	if err := query.Preload("Faculty").Order("form_type ASC, faculty_id ASC, version DESC").Find(&forms).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch evaluation forms: %w", err)
+	}
+	return forms, nil
+}
+
+// GetForm retrieves an evaluation form by ID
+func (s *EvaluationFormService) GetForm(id uint) (*models.EvaluationForm, error) {
+	var form models.EvaluationForm
+	if err := s.db.Preload("Faculty").First(&form, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("evaluation form not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &form, nil
+}
+
+// GetFormVersions lists every version of an evaluation form, oldest first
+func (s *EvaluationFormService) GetFormVersions(id uint) ([]models.EvaluationForm, error) {
+	form, err := s.GetForm(id)
+	if err != nil {
+		return nil, err
+	}
+
+	root := form.RootFormID()
+	var versions []models.EvaluationForm
+	err = s.db.Where("id = ? OR parent_form_id = ?", root, root).Order("version ASC").Find(&versions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch evaluation form versions: %w", err)
+	}
+	return versions, nil
+}
+
+// CreateForm creates an evaluation form. It becomes the faculty's questionnaire for the form type,
+// retiring the form it replaces.
+func (s *EvaluationFormService) CreateForm(req EvaluationFormRequest, userID uint) (*models.EvaluationForm, error) {
+	questions, err := validateEvaluationQuestions(req.Questions)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FacultyID != nil {
+		var count int64
+		if err := s.db.Model(&models.Faculty{}).Where("id = ?", *req.FacultyID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		if count == 0 {
+			return nil, errors.New("faculty not found")
+		}
+	}
+
+	form := models.EvaluationForm{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		FormType:    req.FormType,
+		FacultyID:   req.FacultyID,
+		IsActive:    true,
+		Version:     1,
+		CreatedBy:   userID,
+	}
+	if err := form.SetQuestions(questions); err != nil {
+		return nil, fmt.Errorf("failed to encode questions: %w", err)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		retired := tx.Model(&models.EvaluationForm{}).Where("form_type = ? AND is_active = ?", req.FormType, true)
+		if req.FacultyID != nil {
+			retired = retired.Where("faculty_id = ?", *req.FacultyID)
+		} else {
+			retired = retired.Where("faculty_id IS NULL")
+		}
+		if err := retired.Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return tx.Create(&form).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evaluation form: %w", err)
+	}
+	return &form, nil
+}
+
+// UpdateForm edits an evaluation form. Forms that already have submissions are not changed;
+// a new version is created instead so existing submissions keep the questions they answered.
+// The returned flag reports whether a new version was created.
+func (s *EvaluationFormService) UpdateForm(id uint, req UpdateEvaluationFormRequest, userID uint) (*models.EvaluationForm, bool, error) {
+	questions, err := validateEvaluationQuestions(req.Questions)
+	if err != nil {
+		return nil, false, err
+	}
+
+	form, err := s.GetForm(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if !form.IsActive {
+		return nil, false, errors.New("evaluation form is inactive")
+	}
+
+	var submissions int64
+	if err := s.db.Model(&models.EvaluationSubmission{}).Where("form_id = ?", form.ID).Count(&submissions).Error; err != nil {
+		return nil, false, fmt.Errorf("database error: %w", err)
+	}
+
+	if submissions == 0 {
+		form.Name = strings.TrimSpace(req.Name)
+		form.Description = req.Description
+		if err := form.SetQuestions(questions); err != nil {
+			return nil, false, fmt.Errorf("failed to encode questions: %w", err)
+		}
+		if err := s.db.Omit("Faculty", "Creator", "Submissions").Save(form).Error; err != nil {
+			return nil, false, fmt.Errorf("failed to update evaluation form: %w", err)
+		}
+		return form, false, nil
+	}
+
+	root := form.RootFormID()
+	next := models.EvaluationForm{
+		Name:         strings.TrimSpace(req.Name),
+		Description:  req.Description,
+		FormType:     form.FormType,
+		FacultyID:    form.FacultyID,
+		ParentFormID: &root,
+		IsActive:     true,
+		Version:      form.Version + 1,
+		CreatedBy:    userID,
+	}
+	if err := next.SetQuestions(questions); err != nil {
+		return nil, false, fmt.Errorf("failed to encode questions: %w", err)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EvaluationForm{}).Where("id = ?", form.ID).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return tx.Create(&next).Error
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create evaluation form version: %w", err)
+	}
+	return &next, true, nil
+}
+
+// DeactivateForm retires an evaluation form so no new evaluations are started with it
+func (s *EvaluationFormService) DeactivateForm(id uint) error {
+	form, err := s.GetForm(id)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Model(form).Update("is_active", false).Error; err != nil {
+		return fmt.Errorf("failed to deactivate evaluation form: %w", err)
+	}
+	return nil
+}
+
+// ResolveForm returns the active form of a type that applies to a student training: the
+// student's faculty's form, or the form shared by all faculties when it has none
+func (s *EvaluationFormService) ResolveForm(formType models.EvaluationFormType, studentTrainingID uint) (*models.EvaluationForm, error) {
+	var training models.StudentTraining
+	if err := s.db.Preload("StudentEnroll.Student").First(&training, studentTrainingID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student training not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	query := s.db.Where("form_type = ? AND is_active = ?", formType, true)
+	if facultyID := training.StudentEnroll.Student.FacultyID; facultyID != nil {
+		query = query.Where("faculty_id = ? OR faculty_id IS NULL", *facultyID)
+	} else {
+		query = query.Where("faculty_id IS NULL")
+	}
+
+	// Faculty forms sort before the shared form
+	var form models.EvaluationForm
+	if err := query.Order("faculty_id IS NULL ASC, version DESC").First(&form).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no active evaluation form")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &form, nil
+}
+
+// GetSubmissions lists the evaluation submissions of a student training
+func (s *EvaluationFormService) GetSubmissions(studentTrainingID uint) ([]models.EvaluationSubmission, error) {
+	var submissions []models.EvaluationSubmission
+	err := s.db.Where("student_training_id = ?", studentTrainingID).
+		Preload("Form").
+		Order("created_at ASC").
+		Find(&submissions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch evaluation submissions: %w", err)
+	}
+	return submissions, nil
+}
+
+// GetSubmission retrieves an evaluation submission within the caller's data scope
+func (s *EvaluationFormService) GetSubmission(scope *DataScope, id uint) (*models.EvaluationSubmission, error) {
+	var submission models.EvaluationSubmission
+	err := s.db.Scopes(scope.StudentTrainings("student_training_id")).Preload("Form").First(&submission, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("evaluation submission not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &submission, nil
+}
+
+// GetQuestionAnalytics aggregates submitted and reviewed evaluations within the caller's data scope
+// question by question, including submissions mirrored from legacy evaluation records
+func (s *EvaluationFormService) GetQuestionAnalytics(scope *DataScope, filter QuestionAnalyticsFilter) ([]QuestionStat, error) {
+	query := s.db.Scopes(scope.StudentTrainings("evaluation_submissions.student_training_id")).
+		Joins("JOIN evaluation_forms ON evaluation_forms.id = evaluation_submissions.form_id").
+		Where("evaluation_submissions.status IN ?", []models.EvaluationSubmissionStatus{models.SubmissionStatusSubmitted, models.SubmissionStatusReviewed})
+
+	if filter.FormType != "" {
+		query = query.Where("evaluation_forms.form_type = ?", filter.FormType)
+	}
+	if filter.FormID != nil {
+		form, err := s.GetForm(*filter.FormID)
+		if err != nil {
+			return nil, err
+		}
+		root := form.RootFormID()
+		query = query.Where("evaluation_forms.id = ? OR evaluation_forms.parent_form_id = ?", root, root)
+	}
+
+	var submissions []models.EvaluationSubmission
+	if err := query.Preload("Form").Order("evaluation_submissions.id ASC").Find(&submissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch evaluation submissions: %w", err)
+	}
+	return aggregateQuestionStats(submissions)
+}
+
+// evaluatorFormTypes lists the form types each account type may fill in. Staff and super admins
+// are not restricted, they enter forms on behalf of evaluators, such as company evaluations
+// received on paper.
+var evaluatorFormTypes = map[AccountType][]models.EvaluationFormType{
+	AccountTypeStudent:    {models.FormTypeStudentSelf, models.FormTypeStudentCompany},
+	AccountTypeInstructor: {models.FormTypeInstructorStudent, models.FormTypeVisitorStudent, models.FormTypeVisitorCompany},
+}
+
+// checkEvaluatorFormType rejects form types the evaluator's account type may not fill in
+func checkEvaluatorFormType(formType models.EvaluationFormType, evaluatorType AccountType) error {
+	if evaluatorType == AccountTypeStaff || evaluatorType == AccountTypeSuperAdmin {
+		return nil
+	}
+	for _, allowed := range evaluatorFormTypes[evaluatorType] {
+		if allowed == formType {
+			return nil
+		}
+	}
+	return errors.New("evaluation form type not allowed for evaluator")
+}
+
+// checkEvaluator rejects evaluators who may not fill in the form for the training. Visitor forms
+// are limited to the instructor assigned to visit the student.
+func (s *EvaluationFormService) checkEvaluator(form *models.EvaluationForm, training *models.StudentTraining, evaluatorID uint, evaluatorType AccountType) error {
+	if err := checkEvaluatorFormType(form.FormType, evaluatorType); err != nil {
+		return err
+	}
+	if evaluatorType != AccountTypeInstructor ||
+		(form.FormType != models.FormTypeVisitorStudent && form.FormType != models.FormTypeVisitorCompany) {
+		return nil
+	}
+
+	var count int64
+	err := s.db.Model(&models.VisitorTraining{}).
+		Where("student_enroll_id = ? AND visitor_instructor_id = ?", training.StudentEnrollID, evaluatorID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if count == 0 {
+		return errors.New("evaluation form type not allowed for evaluator")
+	}
+	return nil
+}
+
+// SaveSubmission saves an evaluator's answers for a student training, continuing their draft on the
+// same form when there is one. Answers are validated and scored on every save.
+func (s *EvaluationFormService) SaveSubmission(studentTrainingID uint, req SaveEvaluationRequest, evaluatorID uint, evaluatorType AccountType) (*models.EvaluationSubmission, error) {
+	var training models.StudentTraining
+	if err := s.db.First(&training, studentTrainingID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student training not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	form, err := s.GetForm(req.FormID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEvaluator(form, &training, evaluatorID, evaluatorType); err != nil {
+		return nil, err
+	}
+
+	var submission models.EvaluationSubmission
+	err = s.db.Where("form_id = ? AND student_training_id = ? AND evaluator_id = ?", form.ID, studentTrainingID, evaluatorID).
+		First(&submission).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if !form.IsActive {
+			return nil, errors.New("evaluation form is inactive")
+		}
+		submission = models.EvaluationSubmission{
+			FormID:            form.ID,
+			StudentTrainingID: studentTrainingID,
+			EvaluatorID:       evaluatorID,
+			Status:            models.SubmissionStatusDraft,
+		}
+	case err != nil:
+		return nil, fmt.Errorf("database error: %w", err)
+	case submission.Status != models.SubmissionStatusDraft:
+		return nil, errors.New("evaluation has already been submitted")
+	}
+
+	submission.Comments = req.Comments
+	if err := scoreSubmission(&submission, form, req.Answers, req.Submit); err != nil {
+		return nil, err
+	}
+	if req.Submit {
+		now := time.Now()
+		submission.Status = models.SubmissionStatusSubmitted
+		submission.SubmittedAt = &now
+	}
+
+	if err := s.db.Omit("Form", "StudentTraining", "Evaluator", "Reviewer").Save(&submission).Error; err != nil {
+		return nil, fmt.Errorf("failed to save evaluation submission: %w", err)
+	}
+	if req.Submit {
+		s.completeTracker(form, studentTrainingID)
+	}
+
+	submission.Form = *form
+	return &submission, nil
+}
+
+// SubmitSubmission submits an evaluator's draft once every required question is answered
+func (s *EvaluationFormService) SubmitSubmission(scope *DataScope, id, evaluatorID uint, evaluatorType AccountType) (*models.EvaluationSubmission, error) {
+	submission, err := s.GetSubmission(scope, id)
+	if err != nil {
+		return nil, err
+	}
+	// Evaluator IDs of different account types overlap, the form type tells them apart
+	if submission.EvaluatorID != evaluatorID || checkEvaluatorFormType(submission.Form.FormType, evaluatorType) != nil {
+		return nil, errors.New("evaluation submission access denied")
+	}
+	if submission.Status != models.SubmissionStatusDraft {
+		return nil, errors.New("evaluation has already been submitted")
+	}
+
+	answers, err := submission.GetAnswers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse answers: %w", err)
+	}
+	if err := scoreSubmission(submission, &submission.Form, answers, true); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	submission.Status = models.SubmissionStatusSubmitted
+	submission.SubmittedAt = &now
+	if err := s.db.Omit("Form", "StudentTraining", "Evaluator", "Reviewer").Save(submission).Error; err != nil {
+		return nil, fmt.Errorf("failed to submit evaluation: %w", err)
+	}
+	s.completeTracker(&submission.Form, submission.StudentTrainingID)
+
+	return submission, nil
+}
+
+// ReviewSubmission marks a submitted evaluation as reviewed
+func (s *EvaluationFormService) ReviewSubmission(scope *DataScope, id, reviewerID uint) (*models.EvaluationSubmission, error) {
+	submission, err := s.GetSubmission(scope, id)
+	if err != nil {
+		return nil, err
+	}
+	if submission.Status != models.SubmissionStatusSubmitted {
+		return nil, errors.New("evaluation submission is not awaiting review")
+	}
+
+	now := time.Now()
+	submission.Status = models.SubmissionStatusReviewed
+	submission.ReviewedAt = &now
+	submission.ReviewedBy = &reviewerID
+	if err := s.db.Omit("Form", "StudentTraining", "Evaluator", "Reviewer").Save(submission).Error; err != nil {
+		return nil, fmt.Errorf("failed to review evaluation: %w", err)
+	}
+	return submission, nil
+}
+
+// completeTracker marks the training's status tracker for the form type as completed, when the
+// form type is tracked and a tracker exists
+func (s *EvaluationFormService) completeTracker(form *models.EvaluationForm, studentTrainingID uint) {
+	switch evalType := models.EvaluationType(form.FormType); evalType {
+	case models.EvalTypeStudentCompany, models.EvalTypeVisitorStudent, models.EvalTypeVisitorCompany:
+		_ = NewEvaluationService(s.db).MarkEvaluationCompleted(studentTrainingID, evalType)
+	}
+}
+
+// scoreSubmission validates answers against the form and stores them with the weighted score
+func scoreSubmission(submission *models.EvaluationSubmission, form *models.EvaluationForm, answers []models.EvaluationAnswer, complete bool) error {
+	questions, err := form.GetQuestions()
+	if err != nil {
+		return fmt.Errorf("failed to parse questions: %w", err)
+	}
+
+	scored, err := scoreEvaluationAnswers(questions, answers, complete)
+	if err != nil {
+		return err
+	}
+	if err := submission.SetAnswers(scored); err != nil {
+		return fmt.Errorf("failed to encode answers: %w", err)
+	}
+	if err := submission.CalculateScore(form); err != nil {
+		return fmt.Errorf("failed to calculate score: %w", err)
+	}
+	submission.Percentage = math.Round(submission.Percentage*100) / 100
+	return nil
+}
+
+// validateEvaluationQuestions checks authored questions and fills in the default rating range
+func validateEvaluationQuestions(questions []models.EvaluationQuestion) ([]models.EvaluationQuestion, error) {
+	if len(questions) == 0 {
+		return nil, errors.New("invalid evaluation form: at least one question is required")
+	}
+
+	ids := make(map[string]bool)
+	normalized := make([]models.EvaluationQuestion, len(questions))
+	for i, question := range questions {
+		question.ID = strings.TrimSpace(question.ID)
+		question.Question = strings.TrimSpace(question.Question)
+		if question.ID == "" {
+			return nil, fmt.Errorf("invalid evaluation form: question %d needs an id", i+1)
+		}
+		if ids[question.ID] {
+			return nil, fmt.Errorf("invalid evaluation form: question id %q is used more than once", question.ID)
+		}
+		ids[question.ID] = true
+		if question.Question == "" {
+			return nil, fmt.Errorf("invalid evaluation form: question %q needs text", question.ID)
+		}
+		if question.Weight < 0 {
+			return nil, fmt.Errorf("invalid evaluation form: weight of question %q cannot be negative", question.ID)
+		}
+
+		switch question.Type {
+		case models.QuestionTypeRating:
+			if question.MinValue == nil {
+				min := DefaultRatingMin
+				question.MinValue = &min
+			}
+			if question.MaxValue == nil {
+				max := DefaultRatingMax
+				question.MaxValue = &max
+			}
+		case models.QuestionTypeScale:
+			if question.MinValue == nil || question.MaxValue == nil {
+				return nil, fmt.Errorf("invalid evaluation form: scale question %q needs min_value and max_value", question.ID)
+			}
+		case models.QuestionTypeChoice, models.QuestionTypeCheckbox:
+			if len(question.Options) < 2 {
+				return nil, fmt.Errorf("invalid evaluation form: question %q needs at least two options", question.ID)
+			}
+			options := make(map[string]bool)
+			for _, option := range question.Options {
+				if options[option] {
+					return nil, fmt.Errorf("invalid evaluation form: question %q lists option %q more than once", question.ID, option)
+				}
+				options[option] = true
+			}
+		case models.QuestionTypeText:
+		default:
+			return nil, fmt.Errorf("invalid evaluation form: question %q has unknown type %q", question.ID, question.Type)
+		}
+
+		if question.IsScored() {
+			if *question.MinValue >= *question.MaxValue {
+				return nil, fmt.Errorf("invalid evaluation form: min_value of question %q must be below max_value", question.ID)
+			}
+			if question.Weight == 0 {
+				return nil, fmt.Errorf("invalid evaluation form: rating and scale question %q needs a weight", question.ID)
+			}
+		}
+		normalized[i] = question
+	}
+	return normalized, nil
+}
+
+// scoreEvaluationAnswers validates answers by question type and returns them in question order with
+// scores for rating and scale questions. Scores sent by the client are ignored. Unless complete is
+// set, required questions may be left unanswered.
+func scoreEvaluationAnswers(questions []models.EvaluationQuestion, answers []models.EvaluationAnswer, complete bool) ([]models.EvaluationAnswer, error) {
+	byQuestion := make(map[string]models.EvaluationAnswer)
+	for _, answer := range answers {
+		if _, exists := byQuestion[answer.QuestionID]; exists {
+			return nil, fmt.Errorf("invalid evaluation answer: question %q is answered more than once", answer.QuestionID)
+		}
+		byQuestion[answer.QuestionID] = answer
+	}
+
+	scored := make([]models.EvaluationAnswer, 0, len(answers))
+	for _, question := range questions {
+		answer, exists := byQuestion[question.ID]
+		delete(byQuestion, question.ID)
+		if !exists || isBlankAnswer(answer.Answer) {
+			if complete && question.Required {
+				return nil, fmt.Errorf("invalid evaluation answer: question %q is required", question.ID)
+			}
+			continue
+		}
+
+		answer.Score = nil
+		switch question.Type {
+		case models.QuestionTypeRating, models.QuestionTypeScale:
+			value, ok := answer.Answer.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid evaluation answer: question %q needs a number", question.ID)
+			}
+			if question.Type == models.QuestionTypeRating && value != math.Trunc(value) {
+				return nil, fmt.Errorf("invalid evaluation answer: question %q needs a whole number", question.ID)
+			}
+			if value < float64(*question.MinValue) || value > float64(*question.MaxValue) {
+				return nil, fmt.Errorf("invalid evaluation answer: question %q must be between %d and %d", question.ID, *question.MinValue, *question.MaxValue)
+			}
+			answer.Score = &value
+
+		case models.QuestionTypeText:
+			if _, ok := answer.Answer.(string); !ok {
+				return nil, fmt.Errorf("invalid evaluation answer: question %q needs text", question.ID)
+			}
+
+		case models.QuestionTypeChoice:
+			choice, ok := answer.Answer.(string)
+			if !ok || !containsString(question.Options, choice) {
+				return nil, fmt.Errorf("invalid evaluation answer: question %q needs one of its options", question.ID)
+			}
+
+		case models.QuestionTypeCheckbox:
+			items, ok := answer.Answer.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid evaluation answer: question %q needs a list of options", question.ID)
+			}
+			checked := make(map[string]bool)
+			for _, item := range items {
+				option, ok := item.(string)
+				if !ok || !containsString(question.Options, option) || checked[option] {
+					return nil, fmt.Errorf("invalid evaluation answer: question %q needs distinct options from its list", question.ID)
+				}
+				checked[option] = true
+			}
+		}
+		scored = append(scored, answer)
+	}
+
+	for questionID := range byQuestion {
+		return nil, fmt.Errorf("invalid evaluation answer: question %q is not on the form", questionID)
+	}
+	return scored, nil
+}
+
+// aggregateQuestionStats groups answers by form type and question ID so that every version of a form
+// contributes to the same question. Question text and type come from the newest form answering it.
+func aggregateQuestionStats(submissions []models.EvaluationSubmission) ([]QuestionStat, error) {
+	type key struct {
+		formType   models.EvaluationFormType
+		questionID string
+	}
+	stats := make(map[key]*QuestionStat)
+	sums := make(map[key]float64)
+	numeric := make(map[key]int)
+	formIDs := make(map[key]uint)
+	var order []key
+
+	for _, submission := range submissions {
+		form := &submission.Form
+		if form.ID == 0 {
+			continue
+		}
+		questions, err := form.GetQuestions()
+		if err != nil {
+			return nil, err
+		}
+		answers, err := submission.GetAnswers()
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]models.EvaluationQuestion, len(questions))
+		for _, question := range questions {
+			byID[question.ID] = question
+		}
+
+		for _, answer := range answers {
+			question, ok := byID[answer.QuestionID]
+			if !ok || isBlankAnswer(answer.Answer) {
+				continue
+			}
+			k := key{form.FormType, answer.QuestionID}
+			stat, ok := stats[k]
+			if !ok {
+				stat = &QuestionStat{FormType: k.formType, QuestionID: k.questionID, Distribution: make(map[string]int)}
+				stats[k] = stat
+				order = append(order, k)
+			}
+			if form.ID >= formIDs[k] {
+				formIDs[k] = form.ID
+				stat.Question = question.Question
+				stat.Type = question.Type
+				stat.Category = question.Category
+			}
+			stat.Responses++
+
+			switch value := answer.Answer.(type) {
+			case float64:
+				sums[k] += value
+				numeric[k]++
+				if stat.Min == nil || value < *stat.Min {
+					stat.Min = &value
+				}
+				if stat.Max == nil || value > *stat.Max {
+					stat.Max = &value
+				}
+				stat.Distribution[strconv.FormatFloat(value, 'f', -1, 64)]++
+			case []interface{}:
+				for _, option := range value {
+					stat.Distribution[fmt.Sprint(option)]++
+				}
+			case string:
+				if question.Type == models.QuestionTypeChoice {
+					stat.Distribution[value]++
+				}
+			default:
+				stat.Distribution[fmt.Sprint(value)]++
+			}
+		}
+	}
+
+	result := make([]QuestionStat, 0, len(order))
+	for _, k := range order {
+		stat := stats[k]
+		if numeric[k] > 0 {
+			average := math.Round(sums[k]/float64(numeric[k])*100) / 100
+			stat.Average = &average
+		}
+		if len(stat.Distribution) == 0 {
+			stat.Distribution = nil
+		}
+		result = append(result, *stat)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].FormType < result[j].FormType
+	})
+	return result, nil
+}
+
+// isBlankAnswer reports whether an answer leaves its question unanswered
+func isBlankAnswer(answer interface{}) bool {
+	switch value := answer.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(value) == ""
+	case []interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
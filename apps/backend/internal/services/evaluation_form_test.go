@@ -0,0 +1,118 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluationForms(t *testing.T) {
+	bound := func(v int) *int { return &v }
+	questions, err := validateEvaluationQuestions([]models.EvaluationQuestion{
+		{ID: "skills", Question: "Technical skills", Type: models.QuestionTypeRating, Required: true, Weight: 2},
+		{ID: "punctuality", Question: "Punctuality", Type: models.QuestionTypeScale, MinValue: bound(0), MaxValue: bound(10), Weight: 1},
+		{ID: "strengths", Question: "Strengths", Type: models.QuestionTypeCheckbox, Options: []string{"teamwork", "initiative", "communication"}},
+		{ID: "rehire", Question: "Would you hire this student?", Type: models.QuestionTypeChoice, Options: []string{"yes", "no"}, Required: true},
+		{ID: "notes", Question: "Notes", Type: models.QuestionTypeText},
+	})
+	require.NoError(t, err)
+
+	t.Run("rating questions default to one to five", func(t *testing.T) {
+		assert.Equal(t, DefaultRatingMin, *questions[0].MinValue)
+		assert.Equal(t, DefaultRatingMax, *questions[0].MaxValue)
+	})
+
+	t.Run("authored questions are validated", func(t *testing.T) {
+		cases := []struct {
+			question models.EvaluationQuestion
+			want     string
+		}{
+			{models.EvaluationQuestion{Question: "No id", Type: models.QuestionTypeText}, "invalid evaluation form: question 1 needs an id"},
+			{models.EvaluationQuestion{ID: "q", Question: "Pick", Type: models.QuestionTypeChoice, Options: []string{"only"}}, `invalid evaluation form: question "q" needs at least two options`},
+			{models.EvaluationQuestion{ID: "q", Question: "Scale", Type: models.QuestionTypeScale, Weight: 1}, `invalid evaluation form: scale question "q" needs min_value and max_value`},
+			{models.EvaluationQuestion{ID: "q", Question: "Rate", Type: models.QuestionTypeRating}, `invalid evaluation form: rating and scale question "q" needs a weight`},
+			{models.EvaluationQuestion{ID: "q", Question: "Upload", Type: "file"}, `invalid evaluation form: question "q" has unknown type "file"`},
+		}
+		for _, tc := range cases {
+			_, err := validateEvaluationQuestions([]models.EvaluationQuestion{tc.question})
+			assert.EqualError(t, err, tc.want)
+		}
+	})
+
+	t.Run("answers are validated by question type", func(t *testing.T) {
+		cases := []struct {
+			answer models.EvaluationAnswer
+			want   string
+		}{
+			{models.EvaluationAnswer{QuestionID: "skills", Answer: 6.0}, `invalid evaluation answer: question "skills" must be between 1 and 5`},
+			{models.EvaluationAnswer{QuestionID: "skills", Answer: 3.5}, `invalid evaluation answer: question "skills" needs a whole number`},
+			{models.EvaluationAnswer{QuestionID: "skills", Answer: "good"}, `invalid evaluation answer: question "skills" needs a number`},
+			{models.EvaluationAnswer{QuestionID: "rehire", Answer: "maybe"}, `invalid evaluation answer: question "rehire" needs one of its options`},
+			{models.EvaluationAnswer{QuestionID: "strengths", Answer: []interface{}{"teamwork", "teamwork"}}, `invalid evaluation answer: question "strengths" needs distinct options from its list`},
+			{models.EvaluationAnswer{QuestionID: "hobbies", Answer: "chess"}, `invalid evaluation answer: question "hobbies" is not on the form`},
+		}
+		for _, tc := range cases {
+			_, err := scoreEvaluationAnswers(questions, []models.EvaluationAnswer{tc.answer}, false)
+			assert.EqualError(t, err, tc.want)
+		}
+	})
+
+	t.Run("required questions only block submitting", func(t *testing.T) {
+		partial := []models.EvaluationAnswer{{QuestionID: "skills", Answer: 4.0}}
+		_, err := scoreEvaluationAnswers(questions, partial, false)
+		assert.NoError(t, err)
+
+		_, err = scoreEvaluationAnswers(questions, partial, true)
+		assert.EqualError(t, err, `invalid evaluation answer: question "rehire" is required`)
+	})
+
+	t.Run("scores are weighted over rating and scale questions", func(t *testing.T) {
+		form := &models.EvaluationForm{}
+		require.NoError(t, form.SetQuestions(questions))
+
+		submission := &models.EvaluationSubmission{}
+		err := scoreSubmission(submission, form, []models.EvaluationAnswer{
+			{QuestionID: "notes", Answer: "Reliable"},
+			{QuestionID: "skills", Answer: 4.0, Score: func() *float64 { v := 100.0; return &v }()},
+			{QuestionID: "punctuality", Answer: 9.0},
+			{QuestionID: "rehire", Answer: "yes"},
+			{QuestionID: "strengths", Answer: []interface{}{"initiative"}},
+		}, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, 17.0, submission.TotalScore)
+		assert.Equal(t, 20.0, submission.MaxScore)
+		assert.Equal(t, 85.0, submission.Percentage)
+
+		answers, err := submission.GetAnswers()
+		require.NoError(t, err)
+		require.Len(t, answers, 5)
+		assert.Equal(t, "skills", answers[0].QuestionID)
+		assert.Equal(t, 4.0, *answers[0].Score)
+		assert.Nil(t, answers[4].Score)
+	})
+
+	t.Run("evaluators may only fill in their own form types", func(t *testing.T) {
+		assert.EqualError(t, checkEvaluatorFormType(models.FormTypeInstructorStudent, AccountTypeStudent), "evaluation form type not allowed for evaluator")
+		assert.EqualError(t, checkEvaluatorFormType(models.FormTypeCompanyStudent, AccountTypeStudent), "evaluation form type not allowed for evaluator")
+		assert.NoError(t, checkEvaluatorFormType(models.FormTypeStudentSelf, AccountTypeStudent))
+		assert.NoError(t, checkEvaluatorFormType(models.FormTypeStudentCompany, AccountTypeStudent))
+
+		assert.EqualError(t, checkEvaluatorFormType(models.FormTypeStudentSelf, AccountTypeInstructor), "evaluation form type not allowed for evaluator")
+		assert.NoError(t, checkEvaluatorFormType(models.FormTypeInstructorStudent, AccountTypeInstructor))
+		assert.NoError(t, checkEvaluatorFormType(models.FormTypeVisitorStudent, AccountTypeInstructor))
+
+		assert.NoError(t, checkEvaluatorFormType(models.FormTypeCompanyStudent, AccountTypeStaff))
+		assert.EqualError(t, checkEvaluatorFormType(models.FormTypeStudentSelf, ""), "evaluation form type not allowed for evaluator")
+	})
+
+	t.Run("a student cannot save an instructor evaluation", func(t *testing.T) {
+		service := NewEvaluationFormService(nil)
+		form := &models.EvaluationForm{ID: 1, FormType: models.FormTypeInstructorStudent}
+		err := service.checkEvaluator(form, &models.StudentTraining{ID: 5, StudentEnrollID: 7}, 42, AccountTypeStudent)
+		assert.EqualError(t, err, "evaluation form type not allowed for evaluator")
+	})
+}
@@ -0,0 +1,643 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// legacyOverallQuestionID is the question holding a legacy record's single score
+const legacyOverallQuestionID = "overall_score"
+
+// legacyFormNames names the inactive forms that hold imported legacy evaluations, one per form type
+var legacyFormNames = map[models.EvaluationFormType]string{
+	models.FormTypeVisitorStudent: "Visitor evaluation of student (legacy)",
+	models.FormTypeVisitorCompany: "Visitor evaluation of company (legacy)",
+	models.FormTypeStudentCompany: "Student evaluation of company (legacy)",
+}
+
+// errLegacyUnlinked is returned for legacy records that cannot be tied to a student training
+var errLegacyUnlinked = errors.New("legacy evaluation has no student training")
+
+// LegacyEvaluationService mirrors the score-and-questions evaluation tables (visitor_evaluate_students,
+// visitor_evaluate_companies and student_evaluate_companies) as evaluation form submissions, so every
+// evaluation can be analyzed question by question
+type LegacyEvaluationService struct {
+	db *gorm.DB
+}
+
+// NewLegacyEvaluationService creates a new legacy evaluation service instance
+func NewLegacyEvaluationService(db *gorm.DB) *LegacyEvaluationService {
+	return &LegacyEvaluationService{db: db}
+}
+
+// LegacyMigrationReport summarizes the import of one legacy evaluation table
+type LegacyMigrationReport struct {
+	Source   string   `json:"source"`
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Errors   []string `json:"errors"`
+}
+
+// record counts the outcome of importing one legacy record
+func (r *LegacyMigrationReport) record(id uint, err error) {
+	switch {
+	case err == nil:
+		r.Imported++
+	case errors.Is(err, errLegacyUnlinked):
+		r.Skipped++
+	default:
+		r.Errors = append(r.Errors, fmt.Sprintf("%s %d: %v", r.Source, id, err))
+	}
+}
+
+// legacyRecord is a legacy evaluation reduced to what its submission needs
+type legacyRecord struct {
+	source            string
+	id                uint
+	formType          models.EvaluationFormType
+	studentTrainingID uint
+	evaluatorID       uint
+	score             int
+	questions         string
+	comment           string
+	recordedAt        time.Time
+}
+
+// legacyAnswer is one question parsed from a legacy questions payload
+type legacyAnswer struct {
+	Key   string
+	Text  string
+	Value interface{}
+}
+
+// MigrateAll imports every legacy evaluation record. Records that were imported before are updated,
+// so the migration can be run again safely.
+func (s *LegacyEvaluationService) MigrateAll() ([]LegacyMigrationReport, error) {
+	reports := make([]LegacyMigrationReport, 0, 3)
+
+	report := LegacyMigrationReport{Source: models.LegacySourceVisitorEvaluateStudent, Errors: []string{}}
+	var visitorStudents []models.VisitorEvaluateStudent
+	err := s.db.FindInBatches(&visitorStudents, 200, func(tx *gorm.DB, batch int) error {
+		for i := range visitorStudents {
+			report.record(visitorStudents[i].ID, s.SyncVisitorEvaluateStudent(&visitorStudents[i], nil))
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to import visitor evaluate students: %w", err)
+	}
+	reports = append(reports, report)
+
+	report = LegacyMigrationReport{Source: models.LegacySourceVisitorEvaluateCompany, Errors: []string{}}
+	var visitorCompanies []models.VisitorEvaluateCompany
+	err = s.db.FindInBatches(&visitorCompanies, 200, func(tx *gorm.DB, batch int) error {
+		for i := range visitorCompanies {
+			report.record(visitorCompanies[i].ID, s.SyncVisitorEvaluateCompany(&visitorCompanies[i], nil))
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to import visitor evaluate companies: %w", err)
+	}
+	reports = append(reports, report)
+
+	report = LegacyMigrationReport{Source: models.LegacySourceStudentEvaluateCompany, Errors: []string{}}
+	var studentCompanies []models.StudentEvaluateCompany
+	err = s.db.FindInBatches(&studentCompanies, 200, func(tx *gorm.DB, batch int) error {
+		for i := range studentCompanies {
+			report.record(studentCompanies[i].ID, s.SyncStudentEvaluateCompany(&studentCompanies[i]))
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to import student evaluate companies: %w", err)
+	}
+	reports = append(reports, report)
+
+	return reports, nil
+}
+
+// SyncVisitorEvaluateStudent mirrors a visitor's evaluation of a student as a submission. When answers
+// are given they are validated against the form that applies to the student, and the record's score and
+// questions are rewritten from them.
+func (s *LegacyEvaluationService) SyncVisitorEvaluateStudent(evaluation *models.VisitorEvaluateStudent, answers []models.EvaluationAnswer) error {
+	var training models.VisitorTraining
+	if err := s.db.Preload("Visitor").First(&training, evaluation.VisitorTrainingID).Error; err != nil {
+		return fmt.Errorf("failed to fetch visitor training: %w", err)
+	}
+	studentTrainingID, err := s.studentTrainingForEnroll(training.StudentEnrollID)
+	if err != nil {
+		return err
+	}
+
+	record := legacyRecord{
+		source:            models.LegacySourceVisitorEvaluateStudent,
+		id:                evaluation.ID,
+		formType:          models.FormTypeVisitorStudent,
+		studentTrainingID: studentTrainingID,
+		evaluatorID:       training.Visitor.UserID,
+		score:             evaluation.Score,
+		questions:         evaluation.Questions,
+		comment:           evaluation.Comment,
+		recordedAt:        evaluation.CreatedAt,
+	}
+	return s.sync(record, answers, func(tx *gorm.DB, score int, questions string) error {
+		evaluation.Score, evaluation.Questions = score, questions
+		return tx.Model(&models.VisitorEvaluateStudent{}).Where("id = ?", evaluation.ID).
+			Updates(map[string]interface{}{"score": score, "questions": questions}).Error
+	})
+}
+
+// SyncVisitorEvaluateCompany mirrors a visitor's evaluation of a company as a submission. When answers
+// are given they are validated against the form that applies to the student, and the record's score and
+// questions are rewritten from them.
+func (s *LegacyEvaluationService) SyncVisitorEvaluateCompany(evaluation *models.VisitorEvaluateCompany, answers []models.EvaluationAnswer) error {
+	var training models.VisitorTraining
+	if err := s.db.Preload("Visitor").First(&training, evaluation.VisitorTrainingID).Error; err != nil {
+		return fmt.Errorf("failed to fetch visitor training: %w", err)
+	}
+
+	var studentTrainingID uint
+	if evaluation.StudentTrainingID != nil {
+		studentTrainingID = *evaluation.StudentTrainingID
+	} else {
+		var err error
+		if studentTrainingID, err = s.studentTrainingForEnroll(training.StudentEnrollID); err != nil {
+			return err
+		}
+	}
+
+	record := legacyRecord{
+		source:            models.LegacySourceVisitorEvaluateCompany,
+		id:                evaluation.ID,
+		formType:          models.FormTypeVisitorCompany,
+		studentTrainingID: studentTrainingID,
+		evaluatorID:       training.Visitor.UserID,
+		score:             evaluation.Score,
+		questions:         evaluation.Questions,
+		comment:           evaluation.Comment,
+		recordedAt:        evaluation.CreatedAt,
+	}
+	return s.sync(record, answers, func(tx *gorm.DB, score int, questions string) error {
+		evaluation.Score, evaluation.Questions = score, questions
+		return tx.Model(&models.VisitorEvaluateCompany{}).Where("id = ?", evaluation.ID).
+			Updates(map[string]interface{}{"score": score, "questions": questions}).Error
+	})
+}
+
+// SyncStudentEvaluateCompany mirrors a student's evaluation of their company as a submission
+func (s *LegacyEvaluationService) SyncStudentEvaluateCompany(evaluation *models.StudentEvaluateCompany) error {
+	var training models.StudentTraining
+	if err := s.db.Preload("StudentEnroll").First(&training, evaluation.StudentTrainingID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errLegacyUnlinked
+		}
+		return fmt.Errorf("failed to fetch student training: %w", err)
+	}
+
+	record := legacyRecord{
+		source:            models.LegacySourceStudentEvaluateCompany,
+		id:                evaluation.ID,
+		formType:          models.FormTypeStudentCompany,
+		studentTrainingID: training.ID,
+		evaluatorID:       training.StudentEnroll.StudentID,
+		score:             evaluation.Score,
+		questions:         evaluation.Questions,
+		comment:           evaluation.Comment,
+		recordedAt:        evaluation.CreatedAt,
+	}
+	return s.sync(record, nil, nil)
+}
+
+// RemoveMirror deletes the submission mirroring a legacy record
+func (s *LegacyEvaluationService) RemoveMirror(source string, id uint) error {
+	err := s.db.Where("legacy_source = ? AND legacy_id = ?", source, id).Delete(&models.EvaluationSubmission{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete mirrored evaluation submission: %w", err)
+	}
+	return nil
+}
+
+// studentTrainingForEnroll finds the student training of an enrollment, which visitor records do not
+// reference directly
+func (s *LegacyEvaluationService) studentTrainingForEnroll(enrollID uint) (uint, error) {
+	var training models.StudentTraining
+	err := s.db.Where("student_enroll_id = ?", enrollID).Order("id ASC").First(&training).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errLegacyUnlinked
+		}
+		return 0, fmt.Errorf("database error: %w", err)
+	}
+	return training.ID, nil
+}
+
+// sync creates or updates the submission mirroring a legacy record. Without structured answers the
+// record's questions payload is parsed onto the legacy form of its type, and its score is kept as the
+// submission's percentage; mirrors that were answered on a structured form keep their answers. With structured answers the submission is scored on the applicable form and
+// rewrite stores the resulting score and answers on the legacy record.
+func (s *LegacyEvaluationService) sync(record legacyRecord, answers []models.EvaluationAnswer, rewrite func(tx *gorm.DB, score int, questions string) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var submission models.EvaluationSubmission
+		err := tx.Where("legacy_source = ? AND legacy_id = ?", record.source, record.id).First(&submission).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			source, id := record.source, record.id
+			submission = models.EvaluationSubmission{LegacySource: &source, LegacyID: &id}
+		}
+
+		if len(answers) > 0 {
+			form, err := NewEvaluationFormService(tx).ResolveForm(record.formType, record.studentTrainingID)
+			if err != nil {
+				return err
+			}
+			if err := scoreSubmission(&submission, form, answers, true); err != nil {
+				return err
+			}
+			submission.FormID = form.ID
+			if rewrite != nil {
+				if err := rewrite(tx, int(math.Round(submission.Percentage)), string(submission.Answers)); err != nil {
+					return err
+				}
+			}
+		} else {
+			form, err := legacyForm(tx, record.formType)
+			if err != nil {
+				return err
+			}
+			if submission.ID != 0 && submission.FormID != form.ID {
+				// Answered on a structured form: those answers stay the source of truth
+				submission.Comments = record.comment
+				return tx.Omit("Form", "StudentTraining", "Evaluator", "Reviewer").Save(&submission).Error
+			}
+			questions, err := form.GetQuestions()
+			if err != nil {
+				return fmt.Errorf("failed to parse legacy form questions: %w", err)
+			}
+			questions, parsed, changed := mergeLegacyAnswers(questions, parseLegacyQuestions(record.questions), record.score)
+			if changed {
+				if err := form.SetQuestions(questions); err != nil {
+					return err
+				}
+				if err := tx.Model(form).Update("questions", form.Questions).Error; err != nil {
+					return err
+				}
+			}
+			if err := submission.SetAnswers(parsed); err != nil {
+				return err
+			}
+			submission.FormID = form.ID
+			submission.TotalScore = float64(record.score)
+			submission.MaxScore = 100
+			submission.Percentage = float64(record.score)
+		}
+
+		submission.StudentTrainingID = record.studentTrainingID
+		submission.EvaluatorID = record.evaluatorID
+		submission.Comments = record.comment
+		if submission.Status != models.SubmissionStatusReviewed {
+			submission.Status = models.SubmissionStatusSubmitted
+		}
+		if submission.SubmittedAt == nil {
+			submittedAt := record.recordedAt
+			if submittedAt.IsZero() {
+				submittedAt = time.Now()
+			}
+			submission.SubmittedAt = &submittedAt
+		}
+
+		return tx.Omit("Form", "StudentTraining", "Evaluator", "Reviewer").Save(&submission).Error
+	})
+}
+
+// legacyForm finds or creates the inactive form holding imported legacy evaluations of a type
+func legacyForm(tx *gorm.DB, formType models.EvaluationFormType) (*models.EvaluationForm, error) {
+	name := legacyFormNames[formType]
+
+	var form models.EvaluationForm
+	err := tx.Where("form_type = ? AND name = ? AND faculty_id IS NULL", formType, name).Order("id ASC").First(&form).Error
+	if err == nil {
+		return &form, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	form = models.EvaluationForm{
+		Name:        name,
+		Description: "Evaluations recorded before structured forms, imported from " + string(formType) + " records",
+		FormType:    formType,
+		IsActive:    false,
+		Version:     1,
+	}
+	questions, _, _ := mergeLegacyAnswers(nil, nil, 0)
+	if err := form.SetQuestions(questions); err != nil {
+		return nil, err
+	}
+	// IsActive false is the zero value and is replaced by the column default on create
+	if err := tx.Create(&form).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&form).Update("is_active", false).Error; err != nil {
+		return nil, err
+	}
+	return &form, nil
+}
+
+// mergeLegacyAnswers maps parsed legacy answers onto the legacy form's questions, adding questions
+// for keys not seen before and widening ranges and option lists as needed. Existing questions are
+// only ever extended, so answers imported earlier stay valid. The record's score is answered as the
+// overall score question.
+func mergeLegacyAnswers(questions []models.EvaluationQuestion, parsed []legacyAnswer, score int) ([]models.EvaluationQuestion, []models.EvaluationAnswer, bool) {
+	changed := false
+	index := make(map[string]int)
+	for i, question := range questions {
+		index[question.ID] = i
+	}
+	if _, exists := index[legacyOverallQuestionID]; !exists {
+		min, max := 0, 100
+		questions = append([]models.EvaluationQuestion{{
+			ID:       legacyOverallQuestionID,
+			Question: "Overall score",
+			Type:     models.QuestionTypeScale,
+			MinValue: &min,
+			MaxValue: &max,
+			Weight:   1,
+			Category: "overall",
+		}}, questions...)
+		for id := range index {
+			index[id]++
+		}
+		index[legacyOverallQuestionID] = 0
+		changed = true
+	}
+
+	overall := float64(score)
+	answers := []models.EvaluationAnswer{{QuestionID: legacyOverallQuestionID, Answer: overall, Score: &overall}}
+	answered := map[string]bool{legacyOverallQuestionID: true}
+
+	for _, item := range parsed {
+		id := legacyQuestionID(item.Key)
+		if answered[id] {
+			continue
+		}
+		answered[id] = true
+
+		pos, exists := index[id]
+		if !exists {
+			text := item.Text
+			if text == "" {
+				text = item.Key
+			}
+			questions = append(questions, models.EvaluationQuestion{ID: id, Question: text, Type: legacyQuestionType(item.Value), Category: "legacy"})
+			pos = len(questions) - 1
+			index[id] = pos
+			changed = true
+		}
+		question := &questions[pos]
+
+		answer := models.EvaluationAnswer{QuestionID: id, Answer: item.Value}
+		switch value := item.Value.(type) {
+		case float64:
+			if question.Type == models.QuestionTypeScale {
+				if question.MinValue == nil || question.MaxValue == nil || value > float64(*question.MaxValue) || value < float64(*question.MinValue) {
+					min, max := legacyScaleRange(question, value)
+					question.MinValue, question.MaxValue = &min, &max
+					question.Weight = 1
+					changed = true
+				}
+				answer.Score = &value
+			}
+		case string:
+			if question.Type == models.QuestionTypeChoice && !containsString(question.Options, value) {
+				question.Options = append(question.Options, value)
+				changed = true
+			}
+		case []interface{}:
+			if question.Type == models.QuestionTypeCheckbox {
+				for _, option := range value {
+					if text, ok := option.(string); ok && !containsString(question.Options, text) {
+						question.Options = append(question.Options, text)
+						changed = true
+					}
+				}
+			}
+		}
+		answers = append(answers, answer)
+	}
+
+	return questions, answers, changed
+}
+
+// legacyQuestionType picks the question type for a legacy answer value
+func legacyQuestionType(value interface{}) models.QuestionType {
+	switch value.(type) {
+	case float64:
+		return models.QuestionTypeScale
+	case []interface{}:
+		return models.QuestionTypeCheckbox
+	}
+	return models.QuestionTypeText
+}
+
+// legacyScaleRange widens a legacy scale question so it includes value. Ranges grow through 0-5,
+// 0-10 and 0-100 so they stay comparable between records.
+func legacyScaleRange(question *models.EvaluationQuestion, value float64) (int, int) {
+	min := 0
+	if question.MinValue != nil && *question.MinValue < min {
+		min = *question.MinValue
+	}
+	if value < float64(min) {
+		min = int(math.Floor(value))
+	}
+
+	max := 5
+	if question.MaxValue != nil && *question.MaxValue > max {
+		max = *question.MaxValue
+	}
+	for _, bound := range []int{5, 10, 100} {
+		if bound >= max && value <= float64(bound) {
+			return min, bound
+		}
+	}
+	return min, int(math.Ceil(value))
+}
+
+var legacyKeyPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// legacyQuestionID turns a legacy question key or text into a question ID
+func legacyQuestionID(key string) string {
+	id := strings.Trim(legacyKeyPattern.ReplaceAllString(strings.ToLower(key), "_"), "_")
+	if id == "" {
+		return "notes"
+	}
+	if id[0] >= '0' && id[0] <= '9' {
+		id = "q" + id
+	}
+	if len(id) > 64 {
+		id = id[:64]
+	}
+	return id
+}
+
+// parseLegacyQuestions parses a legacy questions payload. Payloads were free-form, so several shapes
+// are recognized: a JSON array of answers or values, a JSON object keyed by question, or "key: value"
+// pairs separated by lines, semicolons or commas. Anything else is kept as a single notes answer.
+func parseLegacyQuestions(raw string) []legacyAnswer {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err == nil {
+		switch value := decoded.(type) {
+		case []interface{}:
+			return legacyAnswersFromArray(value)
+		case map[string]interface{}:
+			for _, key := range []string{"answers", "questions"} {
+				if items, ok := value[key].([]interface{}); ok {
+					return legacyAnswersFromArray(items)
+				}
+			}
+			return legacyAnswersFromObject(value)
+		case string:
+			raw = strings.TrimSpace(value)
+		}
+	}
+
+	if answers := legacyAnswersFromPairs(raw); answers != nil {
+		return answers
+	}
+	return []legacyAnswer{{Key: "notes", Text: "Notes", Value: raw}}
+}
+
+// legacyAnswersFromArray reads answers from a JSON array of answer objects or bare values
+func legacyAnswersFromArray(items []interface{}) []legacyAnswer {
+	answers := make([]legacyAnswer, 0, len(items))
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			answers = append(answers, legacyAnswer{Key: "q" + strconv.Itoa(i+1), Value: legacyValue(item)})
+			continue
+		}
+
+		answer := legacyAnswer{
+			Key:   firstString(object, "question_id", "id", "key", "no"),
+			Text:  firstString(object, "question", "title", "text", "label"),
+			Value: legacyValue(firstValue(object, "answer", "score", "value", "rating")),
+		}
+		if answer.Key == "" {
+			answer.Key = answer.Text
+		}
+		if answer.Key == "" {
+			answer.Key = "q" + strconv.Itoa(i+1)
+		}
+		answers = append(answers, answer)
+	}
+	return answers
+}
+
+// legacyAnswersFromObject reads answers from a JSON object keyed by question, in key order
+func legacyAnswersFromObject(object map[string]interface{}) []legacyAnswer {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	answers := make([]legacyAnswer, 0, len(keys))
+	for _, key := range keys {
+		answer := legacyAnswer{Key: key, Value: legacyValue(object[key])}
+		if nested, ok := object[key].(map[string]interface{}); ok {
+			answer.Text = firstString(nested, "question", "title", "text", "label")
+			answer.Value = legacyValue(firstValue(nested, "answer", "score", "value", "rating"))
+		}
+		answers = append(answers, answer)
+	}
+	return answers
+}
+
+// legacyAnswersFromPairs reads "key: value" or "key=value" pairs, returning nil unless every part is a pair
+func legacyAnswersFromPairs(raw string) []legacyAnswer {
+	parts := strings.FieldsFunc(raw, func(r rune) bool { return r == '\n' || r == ';' || r == ',' })
+	answers := make([]legacyAnswer, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		sep := strings.IndexAny(part, ":=")
+		if sep <= 0 {
+			return nil
+		}
+		key := strings.TrimSpace(part[:sep])
+		text := strings.TrimSpace(part[sep+1:])
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			answers = append(answers, legacyAnswer{Key: key, Value: number})
+		} else {
+			answers = append(answers, legacyAnswer{Key: key, Value: text})
+		}
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+	return answers
+}
+
+// legacyValue normalizes a decoded legacy value to a number, string or list of strings
+func legacyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64, string:
+		if text, ok := v.(string); ok {
+			if number, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+				return number
+			}
+		}
+		return v
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func firstString(object map[string]interface{}, keys ...string) string {
+	if value := firstValue(object, keys...); value != nil {
+		return strings.TrimSpace(fmt.Sprint(value))
+	}
+	return ""
+}
+
+func firstValue(object map[string]interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		if value, ok := object[key]; ok && value != nil {
+			return value
+		}
+	}
+	return nil
+}
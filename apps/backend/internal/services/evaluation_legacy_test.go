@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyEvaluations(t *testing.T) {
+	t.Run("legacy payload shapes are parsed", func(t *testing.T) {
+		cases := []struct {
+			raw  string
+			want []legacyAnswer
+		}{
+			{"", nil},
+			{`[{"id": "skills", "question": "Skills", "score": "4"}, {"question": "Attitude", "answer": true}]`, []legacyAnswer{
+				{Key: "skills", Text: "Skills", Value: 4.0},
+				{Key: "Attitude", Text: "Attitude", Value: "yes"},
+			}},
+			{`[5, "good"]`, []legacyAnswer{{Key: "q1", Value: 5.0}, {Key: "q2", Value: "good"}}},
+			{`{"teamwork": 3, "strengths": ["initiative", 2]}`, []legacyAnswer{
+				{Key: "strengths", Value: []interface{}{"initiative", "2"}},
+				{Key: "teamwork", Value: 3.0},
+			}},
+			{`{"answers": [{"key": "k", "value": 1}]}`, []legacyAnswer{{Key: "k", Value: 1.0}}},
+			{"Skills: 4\nAttitude = excellent; Punctuality: 9", []legacyAnswer{
+				{Key: "Skills", Value: 4.0},
+				{Key: "Attitude", Value: "excellent"},
+				{Key: "Punctuality", Value: 9.0},
+			}},
+			{"Reliable student, would hire again", []legacyAnswer{{Key: "notes", Text: "Notes", Value: "Reliable student, would hire again"}}},
+		}
+		for _, tc := range cases {
+			assert.Equal(t, tc.want, parseLegacyQuestions(tc.raw), tc.raw)
+		}
+	})
+
+	t.Run("question ids are slugs", func(t *testing.T) {
+		assert.Equal(t, "technical_skills", legacyQuestionID("  Technical Skills! "))
+		assert.Equal(t, "q1_attitude", legacyQuestionID("1. Attitude"))
+		assert.Equal(t, "notes", legacyQuestionID("???"))
+	})
+
+	t.Run("merging extends the legacy form", func(t *testing.T) {
+		questions, answers, changed := mergeLegacyAnswers(nil, []legacyAnswer{
+			{Key: "Skills", Value: 4.0},
+			{Key: "notes", Text: "Notes", Value: "Reliable"},
+		}, 80)
+		assert.True(t, changed)
+		require.Len(t, questions, 3)
+		assert.Equal(t, legacyOverallQuestionID, questions[0].ID)
+		assert.Equal(t, models.QuestionTypeScale, questions[1].Type)
+		assert.Equal(t, 5, *questions[1].MaxValue)
+		assert.Equal(t, models.QuestionTypeText, questions[2].Type)
+		require.Len(t, answers, 3)
+		assert.Equal(t, 80.0, answers[0].Answer)
+
+		questions, _, changed = mergeLegacyAnswers(questions, []legacyAnswer{{Key: "skills", Value: 4.0}}, 70)
+		assert.False(t, changed)
+
+		questions, _, changed = mergeLegacyAnswers(questions, []legacyAnswer{{Key: "skills", Value: 8.0}}, 70)
+		assert.True(t, changed)
+		assert.Equal(t, 10, *questions[1].MaxValue)
+
+		_, err := scoreEvaluationAnswers(questions, answers, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("answers are aggregated per question across form versions", func(t *testing.T) {
+		bound := func(v int) *int { return &v }
+		first := models.EvaluationForm{ID: 1, FormType: models.FormTypeVisitorStudent}
+		require.NoError(t, first.SetQuestions([]models.EvaluationQuestion{
+			{ID: "skills", Question: "Skills", Type: models.QuestionTypeScale, MinValue: bound(0), MaxValue: bound(10), Weight: 1},
+			{ID: "rehire", Question: "Rehire?", Type: models.QuestionTypeChoice, Options: []string{"yes", "no"}},
+		}))
+		second := models.EvaluationForm{ID: 2, FormType: models.FormTypeVisitorStudent}
+		require.NoError(t, second.SetQuestions([]models.EvaluationQuestion{
+			{ID: "skills", Question: "Technical skills", Type: models.QuestionTypeScale, MinValue: bound(0), MaxValue: bound(10), Weight: 1},
+		}))
+
+		submission := func(form models.EvaluationForm, answers ...models.EvaluationAnswer) models.EvaluationSubmission {
+			sub := models.EvaluationSubmission{Form: form}
+			require.NoError(t, sub.SetAnswers(answers))
+			return sub
+		}
+		stats, err := aggregateQuestionStats([]models.EvaluationSubmission{
+			submission(first, models.EvaluationAnswer{QuestionID: "skills", Answer: 6.0}, models.EvaluationAnswer{QuestionID: "rehire", Answer: "yes"}),
+			submission(second, models.EvaluationAnswer{QuestionID: "skills", Answer: 9.0}),
+			submission(first, models.EvaluationAnswer{QuestionID: "rehire", Answer: ""}),
+		})
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+
+		assert.Equal(t, "Technical skills", stats[0].Question)
+		assert.Equal(t, 2, stats[0].Responses)
+		assert.Equal(t, 7.5, *stats[0].Average)
+		assert.Equal(t, 6.0, *stats[0].Min)
+		assert.Equal(t, 9.0, *stats[0].Max)
+		assert.Equal(t, map[string]int{"6": 1, "9": 1}, stats[0].Distribution)
+
+		assert.Equal(t, 1, stats[1].Responses)
+		assert.Nil(t, stats[1].Average)
+		assert.Equal(t, map[string]int{"yes": 1}, stats[1].Distribution)
+	})
+}
@@ -0,0 +1,819 @@
+package services
+
+import (
+	"backend-go/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExportJobType identifies the dataset an export job writes
+type ExportJobType string
+
+const (
+	ExportJobStudents          ExportJobType = "students"
+	ExportJobTrainings         ExportJobType = "trainings"
+	ExportJobEvaluations       ExportJobType = "evaluations"
+	ExportJobApprovalHistories ExportJobType = "approval_histories"
+)
+
+const (
+	// exportProgressEvery is how many source records are written between progress updates
+	exportProgressEvery = 500
+
+	// exportMaintenanceInterval is the time between expired file cleanups and stale job checks
+	exportMaintenanceInterval = time.Minute
+)
+
+// ExportService queues export jobs and runs the worker pool that writes them to disk.
+// Jobs are claimed from the export_jobs table, so any number of replicas can run workers.
+type ExportService struct {
+	db       *gorm.DB
+	settings ExportSettings
+	logger   *Logger
+}
+
+// ExportSettings holds configuration for export jobs and the worker pool
+type ExportSettings struct {
+	Directory    string        // where finished export files are written
+	Workers      int           // concurrent jobs per instance
+	PollInterval time.Duration // time between checks for queued jobs
+	Retention    time.Duration // how long a finished file can be downloaded
+	StaleAfter   time.Duration // running jobs without progress for this long are failed
+}
+
+// CreateExportJobRequest represents the request for queuing an export job
+type CreateExportJobRequest struct {
+	JobType   ExportJobType `json:"job_type" validate:"required,oneof=students trainings evaluations approval_histories"`
+	Format    ReportFormat  `json:"format" validate:"required,oneof=csv excel"`
+	FacultyID *uint         `json:"faculty_id"`
+	MajorID   *uint         `json:"major_id"`
+	Status    string        `json:"status"`
+	StartDate *time.Time    `json:"start_date"`
+	EndDate   *time.Time    `json:"end_date"`
+}
+
+// ExportParameters are the stored parameters of an export job. The requester's data scope
+// is captured when the job is queued so the worker applies the same visibility rules.
+type ExportParameters struct {
+	Format    ReportFormat `json:"format"`
+	FacultyID *uint        `json:"faculty_id,omitempty"`
+	MajorID   *uint        `json:"major_id,omitempty"`
+	Status    string       `json:"status,omitempty"`
+	StartDate *time.Time   `json:"start_date,omitempty"`
+	EndDate   *time.Time   `json:"end_date,omitempty"`
+	Scope     *DataScope   `json:"scope,omitempty"`
+}
+
+// ExportJobListRequest represents the request for listing the caller's export jobs
+type ExportJobListRequest struct {
+	Page    int    `json:"page"`
+	Limit   int    `json:"limit"`
+	Status  string `json:"status"`
+	JobType string `json:"job_type"`
+}
+
+// ExportJobListResponse represents the response for listing export jobs
+type ExportJobListResponse struct {
+	Data       []models.ExportJob `json:"data"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// exportSource describes how one job type is queried and turned into rows
+type exportSource struct {
+	title   string
+	headers []string
+	query   func(db *gorm.DB, params *ExportParameters) *gorm.DB
+	rows    func(db *gorm.DB, rows *sql.Rows, params *ExportParameters) ([][]interface{}, error)
+}
+
+// NewExportService creates a new export service instance
+func NewExportService(db *gorm.DB, settings ExportSettings) *ExportService {
+	if settings.Directory == "" {
+		settings.Directory = "uploads/exports"
+	}
+	if settings.Workers < 1 {
+		settings.Workers = 2
+	}
+	if settings.PollInterval <= 0 {
+		settings.PollInterval = 5 * time.Second
+	}
+	if settings.Retention <= 0 {
+		settings.Retention = 72 * time.Hour
+	}
+	if settings.StaleAfter <= 0 {
+		settings.StaleAfter = 30 * time.Minute
+	}
+
+	return &ExportService{
+		db:       db,
+		settings: settings,
+		logger:   GetGlobalLogger(),
+	}
+}
+
+// CreateJob queues an export job for the worker pool
+func (s *ExportService) CreateJob(req CreateExportJobRequest, requestedBy uint, requesterType string, scope *DataScope) (*models.ExportJob, error) {
+	if _, ok := exportSources[req.JobType]; !ok {
+		return nil, fmt.Errorf("unsupported export type: %s", req.JobType)
+	}
+	if req.Format != ReportFormatCSV && req.Format != ReportFormatExcel {
+		return nil, fmt.Errorf("unsupported export format: %s", req.Format)
+	}
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	parameters, err := json.Marshal(ExportParameters{
+		Format:    req.Format,
+		FacultyID: req.FacultyID,
+		MajorID:   req.MajorID,
+		Status:    req.Status,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Scope:     scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.ExportJob{
+		JobType:       string(req.JobType),
+		Status:        models.ReportStatusPending,
+		Parameters:    parameters,
+		RequestedBy:   requestedBy,
+		RequesterType: requesterType,
+	}
+	if err := s.db.Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// GetJobs retrieves the caller's export jobs, newest first
+func (s *ExportService) GetJobs(req ExportJobListRequest, requestedBy uint, requesterType string) (*ExportJobListResponse, error) {
+	var jobs []models.ExportJob
+	var total int64
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	query := s.db.Model(&models.ExportJob{}).
+		Where("requested_by = ? AND requester_type = ?", requestedBy, requesterType)
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.JobType != "" {
+		query = query.Where("job_type = ?", req.JobType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.Limit).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &ExportJobListResponse{
+		Data:       jobs,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetJob retrieves one of the caller's export jobs
+func (s *ExportService) GetJob(id uint, requestedBy uint, requesterType string) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := s.db.Where("requested_by = ? AND requester_type = ?", requestedBy, requesterType).First(&job, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("export job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetExportFile returns the path and download name of a finished export
+func (s *ExportService) GetExportFile(id uint, requestedBy uint, requesterType string) (string, string, error) {
+	job, err := s.GetJob(id, requestedBy, requesterType)
+	if err != nil {
+		return "", "", err
+	}
+
+	if job.Status != models.ReportStatusCompleted {
+		return "", "", errors.New("export not ready")
+	}
+	if job.IsExpired() || job.FilePath == "" {
+		return "", "", errors.New("export expired")
+	}
+	if _, err := os.Stat(job.FilePath); err != nil {
+		return "", "", fmt.Errorf("export file unavailable: %w", err)
+	}
+
+	return job.FilePath, filepath.Base(job.FilePath), nil
+}
+
+// StartWorkers runs the worker pool and the expiry cleanup in the background until ctx is cancelled
+func (s *ExportService) StartWorkers(ctx context.Context) {
+	for i := 0; i < s.settings.Workers; i++ {
+		go s.worker(ctx)
+	}
+	go s.maintain(ctx)
+
+	s.logger.Info("Export workers started", map[string]interface{}{
+		"workers":       s.settings.Workers,
+		"poll_interval": s.settings.PollInterval.String(),
+		"directory":     s.settings.Directory,
+	})
+}
+
+// worker drains the queue, then waits for the next poll
+func (s *ExportService) worker(ctx context.Context) {
+	ticker := time.NewTicker(s.settings.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			processed, err := s.ProcessNext(ctx)
+			if err != nil {
+				s.logger.Error("Failed to claim export job", map[string]interface{}{
+					"error": err.Error(),
+				})
+				break
+			}
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// maintain periodically deletes expired files and fails jobs whose worker died
+func (s *ExportService) maintain(ctx context.Context) {
+	ticker := time.NewTicker(exportMaintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if removed, err := s.CleanupExpired(now); err != nil {
+			s.logger.Error("Export cleanup failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else if removed > 0 {
+			s.logger.Info("Expired exports removed", map[string]interface{}{
+				"count": removed,
+			})
+		}
+		if err := s.FailStaleJobs(now); err != nil {
+			s.logger.Error("Failed to mark stale export jobs", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNext claims the oldest queued job and runs it. It reports false when the queue is empty.
+func (s *ExportService) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := s.claimNextJob()
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	s.processJob(ctx, job)
+	return true, nil
+}
+
+// claimNextJob moves the oldest pending job to generating. The conditional update makes
+// the claim safe when several workers or replicas race for the same row.
+func (s *ExportService) claimNextJob() (*models.ExportJob, error) {
+	for {
+		var job models.ExportJob
+		err := s.db.Where("status = ?", models.ReportStatusPending).Order("created_at, id").First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		result := s.db.Model(&models.ExportJob{}).
+			Where("id = ? AND status = ?", job.ID, models.ReportStatusPending).
+			Updates(map[string]interface{}{
+				"status":        models.ReportStatusGenerating,
+				"progress":      0,
+				"error_message": "",
+				"started_at":    now,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			job.Status = models.ReportStatusGenerating
+			job.StartedAt = &now
+			return &job, nil
+		}
+		// Another worker claimed it first, try the next one
+	}
+}
+
+// processJob writes the export and records the outcome on the job
+func (s *ExportService) processJob(ctx context.Context, job *models.ExportJob) {
+	started := time.Now()
+
+	path, size, err := s.writeExport(ctx, job)
+	if err != nil {
+		os.Remove(s.partialPath(job.ID))
+
+		if ctx.Err() != nil {
+			// Shutting down: hand the job back so the next worker starts it over
+			s.requeue(job.ID)
+			return
+		}
+
+		s.logger.Warn("Export job failed", map[string]interface{}{
+			"job_id":   job.ID,
+			"job_type": job.JobType,
+			"error":    err.Error(),
+		})
+		s.markFailed(job.ID, err.Error())
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.settings.Retention)
+	if err := s.db.Model(&models.ExportJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":       models.ReportStatusCompleted,
+		"progress":     100,
+		"file_path":    path,
+		"file_size":    size,
+		"completed_at": now,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		s.logger.Error("Failed to record finished export", map[string]interface{}{
+			"job_id": job.ID,
+			"error":  err.Error(),
+		})
+		os.Remove(path)
+		return
+	}
+
+	s.logger.Info("Export job completed", map[string]interface{}{
+		"job_id":    job.ID,
+		"job_type":  job.JobType,
+		"file_size": size,
+		"duration":  time.Since(started).String(),
+	})
+}
+
+// writeExport streams the job's rows to a partial file and renames it once complete
+func (s *ExportService) writeExport(ctx context.Context, job *models.ExportJob) (string, int64, error) {
+	source, ok := exportSources[ExportJobType(job.JobType)]
+	if !ok {
+		return "", 0, fmt.Errorf("unsupported export type: %s", job.JobType)
+	}
+
+	var params ExportParameters
+	if len(job.Parameters) > 0 {
+		if err := json.Unmarshal(job.Parameters, &params); err != nil {
+			return "", 0, fmt.Errorf("invalid export parameters: %w", err)
+		}
+	}
+
+	extension, err := reportExtension(params.Format)
+	if err != nil || params.Format == ReportFormatPDF {
+		return "", 0, fmt.Errorf("unsupported export format: %s", params.Format)
+	}
+
+	if err := os.MkdirAll(s.settings.Directory, 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	db := s.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Table("(?) AS export_rows", source.query(db, &params)).Count(&total).Error; err != nil {
+		return "", 0, fmt.Errorf("failed to count export rows: %w", err)
+	}
+
+	partial := s.partialPath(job.ID)
+	writer, err := newExportWriter(params.Format, partial, source.title, source.headers)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := s.streamRows(ctx, db, job.ID, source, &params, total, writer); err != nil {
+		writer.Close()
+		return "", 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return "", 0, err
+	}
+
+	filename := fmt.Sprintf("%s_export_%d_%s.%s", job.JobType, job.ID, time.Now().Format("20060102_150405"), extension)
+	path := filepath.Join(s.settings.Directory, filename)
+	if err := os.Rename(partial, path); err != nil {
+		return "", 0, fmt.Errorf("failed to save export file: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+// streamRows reads source records one at a time and reports progress as they are written
+func (s *ExportService) streamRows(ctx context.Context, db *gorm.DB, jobID uint, source exportSource, params *ExportParameters, total int64, writer exportWriter) error {
+	rows, err := source.query(db, params).Rows()
+	if err != nil {
+		return fmt.Errorf("failed to query export rows: %w", err)
+	}
+	defer rows.Close()
+
+	var processed int64
+	for rows.Next() {
+		values, err := source.rows(db, rows, params)
+		if err != nil {
+			return fmt.Errorf("failed to read export row: %w", err)
+		}
+		for _, row := range values {
+			if err := writer.WriteRow(row); err != nil {
+				return fmt.Errorf("failed to write export row: %w", err)
+			}
+		}
+
+		processed++
+		if processed%exportProgressEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			s.updateProgress(jobID, exportProgress(processed, total))
+		}
+	}
+	return rows.Err()
+}
+
+// exportProgress converts processed records to a percentage. It stays below 100
+// until the file has been saved.
+func exportProgress(processed, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	progress := int(processed * 100 / total)
+	if progress > 99 {
+		progress = 99
+	}
+	return progress
+}
+
+// updateProgress records progress, which also refreshes updated_at for the stale job check
+func (s *ExportService) updateProgress(jobID uint, progress int) {
+	if err := s.db.Model(&models.ExportJob{}).Where("id = ?", jobID).Update("progress", progress).Error; err != nil {
+		s.logger.Warn("Failed to update export progress", map[string]interface{}{
+			"job_id": jobID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// markFailed records why a job failed
+func (s *ExportService) markFailed(jobID uint, message string) {
+	if err := s.db.Model(&models.ExportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":        models.ReportStatusFailed,
+		"error_message": message,
+		"completed_at":  time.Now(),
+	}).Error; err != nil {
+		s.logger.Error("Failed to record export failure", map[string]interface{}{
+			"job_id": jobID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// requeue returns an interrupted job to the queue
+func (s *ExportService) requeue(jobID uint) {
+	if err := s.db.Model(&models.ExportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":     models.ReportStatusPending,
+		"progress":   0,
+		"started_at": nil,
+	}).Error; err != nil {
+		s.logger.Error("Failed to requeue export job", map[string]interface{}{
+			"job_id": jobID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// partialPath is where a job writes until its file is complete
+func (s *ExportService) partialPath(jobID uint) string {
+	return filepath.Join(s.settings.Directory, fmt.Sprintf("export_%d.part", jobID))
+}
+
+// CleanupExpired deletes files of exports past their retention period and returns how many were removed.
+// The job rows are kept so the history stays visible; downloads report the export as expired.
+func (s *ExportService) CleanupExpired(now time.Time) (int, error) {
+	var jobs []models.ExportJob
+	if err := s.db.Select("id", "file_path").
+		Where("status = ? AND expires_at < ? AND file_path <> ''", models.ReportStatusCompleted, now).
+		Find(&jobs).Error; err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, job := range jobs {
+		if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("Failed to delete expired export", map[string]interface{}{
+				"job_id": job.ID,
+				"error":  err.Error(),
+			})
+			continue
+		}
+		if err := s.db.Model(&models.ExportJob{}).Where("id = ?", job.ID).Update("file_path", "").Error; err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// FailStaleJobs fails running jobs that have not reported progress within StaleAfter,
+// which happens when the instance running them crashed
+func (s *ExportService) FailStaleJobs(now time.Time) error {
+	var jobs []models.ExportJob
+	if err := s.db.Select("id").
+		Where("status = ? AND updated_at < ?", models.ReportStatusGenerating, now.Add(-s.settings.StaleAfter)).
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		result := s.db.Model(&models.ExportJob{}).
+			Where("id = ? AND status = ? AND updated_at < ?", job.ID, models.ReportStatusGenerating, now.Add(-s.settings.StaleAfter)).
+			Updates(map[string]interface{}{
+				"status":        models.ReportStatusFailed,
+				"error_message": "export worker stopped before the job finished",
+				"completed_at":  now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			os.Remove(s.partialPath(job.ID))
+		}
+	}
+	return nil
+}
+
+// exportSources maps each job type to its query and row layout
+var exportSources = map[ExportJobType]exportSource{
+	ExportJobStudents: {
+		title:   "Students",
+		headers: []string{"Student ID", "Name", "Email", "Faculty", "Major", "GPAX", "Company", "Approval Status"},
+		query: func(db *gorm.DB, params *ExportParameters) *gorm.DB {
+			query := db.Table("students s").
+				Select(`s.student_id,
+					CONCAT(s.name, ' ', s.surname) AS name,
+					s.email,
+					COALESCE(f.name, '') AS faculty,
+					COALESCE(m.major_name_th, '') AS major,
+					s.gpax,
+					COALESCE(c.company_name_en, '') AS company_name,
+					COALESCE(ia.status, 'not_applied') AS status`).
+				Joins("LEFT JOIN faculties f ON s.faculty_id = f.id").
+				Joins("LEFT JOIN majors m ON s.major_id = m.id").
+				Joins("LEFT JOIN student_enrolls se ON s.id = se.student_id").
+				Joins("LEFT JOIN student_trainings st ON se.id = st.student_enroll_id").
+				Joins("LEFT JOIN companies c ON st.company_id = c.id").
+				Joins("LEFT JOIN internship_approvals ia ON se.id = ia.student_enroll_id").
+				Scopes(params.Scope.Students("s.id"), params.studentFilters("s"))
+			if params.Status != "" {
+				query = query.Where("COALESCE(ia.status, 'not_applied') = ?", params.Status)
+			}
+			return query.Scopes(params.dateRange("s.created_at")).Order("s.student_id")
+		},
+		rows: func(db *gorm.DB, rows *sql.Rows, params *ExportParameters) ([][]interface{}, error) {
+			var record struct {
+				StudentID   string
+				Name        string
+				Email       string
+				Faculty     string
+				Major       string
+				GPAX        float64 `gorm:"column:gpax"`
+				CompanyName string
+				Status      string
+			}
+			if err := db.ScanRows(rows, &record); err != nil {
+				return nil, err
+			}
+			return [][]interface{}{{record.StudentID, record.Name, record.Email, record.Faculty, record.Major, record.GPAX, record.CompanyName, record.Status}}, nil
+		},
+	},
+	ExportJobTrainings: {
+		title:   "Trainings",
+		headers: []string{"Training ID", "Student ID", "Name", "Faculty", "Company", "Department", "Position", "Supervisor", "Coordinator", "Start Date", "End Date"},
+		query: func(db *gorm.DB, params *ExportParameters) *gorm.DB {
+			return db.Table("student_trainings st").
+				Select(`st.id,
+					s.student_id,
+					CONCAT(s.name, ' ', s.surname) AS name,
+					COALESCE(f.name, '') AS faculty,
+					COALESCE(c.company_name_en, '') AS company_name,
+					st.department,
+					st.position,
+					st.supervisor,
+					st.coordinator,
+					st.start_date,
+					st.end_date`).
+				Joins("JOIN student_enrolls se ON st.student_enroll_id = se.id").
+				Joins("JOIN students s ON se.student_id = s.id").
+				Joins("LEFT JOIN faculties f ON s.faculty_id = f.id").
+				Joins("LEFT JOIN companies c ON st.company_id = c.id").
+				Scopes(params.Scope.StudentTrainings("st.id"), params.studentFilters("s"), params.dateRange("st.start_date")).
+				Order("st.start_date, st.id")
+		},
+		rows: func(db *gorm.DB, rows *sql.Rows, params *ExportParameters) ([][]interface{}, error) {
+			var record struct {
+				ID          uint
+				StudentID   string
+				Name        string
+				Faculty     string
+				CompanyName string
+				Department  string
+				Position    string
+				Supervisor  string
+				Coordinator string
+				StartDate   time.Time
+				EndDate     time.Time
+			}
+			if err := db.ScanRows(rows, &record); err != nil {
+				return nil, err
+			}
+			return [][]interface{}{{record.ID, record.StudentID, record.Name, record.Faculty, record.CompanyName, record.Department, record.Position, record.Supervisor, record.Coordinator, record.StartDate, record.EndDate}}, nil
+		},
+	},
+	ExportJobEvaluations: {
+		title:   "Evaluations",
+		headers: []string{"Student ID", "Name", "Company", "Evaluation Type", "Status", "Due Date", "Completed At"},
+		query: func(db *gorm.DB, params *ExportParameters) *gorm.DB {
+			query := db.Table("evaluation_status_trackers est").
+				Select(`s.student_id,
+					CONCAT(s.name, ' ', s.surname) AS name,
+					COALESCE(c.company_name_en, '') AS company_name,
+					est.evaluation_type,
+					est.status,
+					est.due_date,
+					est.completed_at`).
+				Joins("JOIN student_trainings st ON est.student_training_id = st.id").
+				Joins("JOIN student_enrolls se ON st.student_enroll_id = se.id").
+				Joins("JOIN students s ON se.student_id = s.id").
+				Joins("LEFT JOIN companies c ON st.company_id = c.id").
+				Scopes(params.Scope.StudentTrainings("est.student_training_id"), params.studentFilters("s"))
+			if params.Status != "" {
+				query = query.Where("est.status = ?", params.Status)
+			}
+			return query.Scopes(params.dateRange("est.created_at")).Order("est.due_date, s.student_id")
+		},
+		rows: func(db *gorm.DB, rows *sql.Rows, params *ExportParameters) ([][]interface{}, error) {
+			var record struct {
+				StudentID      string
+				Name           string
+				CompanyName    string
+				EvaluationType string
+				Status         string
+				DueDate        *time.Time
+				CompletedAt    *time.Time
+			}
+			if err := db.ScanRows(rows, &record); err != nil {
+				return nil, err
+			}
+			return [][]interface{}{{record.StudentID, record.Name, record.CompanyName, record.EvaluationType, record.Status, record.DueDate, record.CompletedAt}}, nil
+		},
+	},
+	ExportJobApprovalHistories: {
+		title:   "Approval Histories",
+		headers: []string{"Approval ID", "Student ID", "Name", "Current Status", "From Status", "To Status", "Changed By", "Changed At", "Reason"},
+		query: func(db *gorm.DB, params *ExportParameters) *gorm.DB {
+			query := db.Table("internship_approvals ia").
+				Select(`ia.id,
+					s.student_id,
+					CONCAT(s.name, ' ', s.surname) AS name,
+					ia.status,
+					ia.status_history`).
+				Joins("JOIN student_enrolls se ON ia.student_enroll_id = se.id").
+				Joins("JOIN students s ON se.student_id = s.id").
+				Scopes(params.Scope.StudentEnrolls("ia.student_enroll_id"), params.studentFilters("s"))
+			if params.Status != "" {
+				query = query.Where("ia.status = ?", params.Status)
+			}
+			return query.Order("ia.id")
+		},
+		// Each approval expands into one row per status transition within the date range
+		rows: func(db *gorm.DB, rows *sql.Rows, params *ExportParameters) ([][]interface{}, error) {
+			var record struct {
+				ID            uint
+				StudentID     string
+				Name          string
+				Status        string
+				StatusHistory json.RawMessage
+			}
+			if err := db.ScanRows(rows, &record); err != nil {
+				return nil, err
+			}
+
+			approval := models.InternshipApproval{StatusHistory: record.StatusHistory}
+			history, err := approval.GetStatusHistory()
+			if err != nil {
+				return nil, fmt.Errorf("invalid status history for approval %d: %w", record.ID, err)
+			}
+
+			values := make([][]interface{}, 0, len(history))
+			for _, transition := range history {
+				if !params.inDateRange(transition.ChangedAt) {
+					continue
+				}
+				values = append(values, []interface{}{
+					record.ID, record.StudentID, record.Name, record.Status,
+					string(transition.FromStatus), string(transition.ToStatus), transition.ChangedBy,
+					transition.ChangedAt.Format("2006-01-02 15:04:05"), transition.Reason,
+				})
+			}
+			return values, nil
+		},
+	},
+}
+
+// studentFilters applies the faculty and major filters to the students table alias
+func (p *ExportParameters) studentFilters(alias string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if p.FacultyID != nil {
+			tx = tx.Where(alias+".faculty_id = ?", *p.FacultyID)
+		}
+		if p.MajorID != nil {
+			tx = tx.Where(alias+".major_id = ?", *p.MajorID)
+		}
+		return tx
+	}
+}
+
+// dateRange restricts column to the requested period; either bound may be open
+func (p *ExportParameters) dateRange(column string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if p.StartDate != nil {
+			tx = tx.Where(column+" >= ?", *p.StartDate)
+		}
+		if p.EndDate != nil {
+			tx = tx.Where(column+" <= ?", *p.EndDate)
+		}
+		return tx
+	}
+}
+
+// inDateRange reports whether t falls within the requested period
+func (p *ExportParameters) inDateRange(t time.Time) bool {
+	if p.StartDate != nil && t.Before(*p.StartDate) {
+		return false
+	}
+	if p.EndDate != nil && t.After(*p.EndDate) {
+		return false
+	}
+	return true
+}
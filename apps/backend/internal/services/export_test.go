@@ -0,0 +1,91 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportService(t *testing.T) {
+	t.Run("CSV writer streams rows with BOM and CRLF", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "students.csv")
+
+		writer, err := newExportWriter(ReportFormatCSV, path, "Students", []string{"Student ID", "Name", "GPAX"})
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteRow([]interface{}{"6401", "สมชาย ใจดี", 3.5}))
+		require.NoError(t, writer.WriteRow([]interface{}{"6402", "Smith, John", 2.75}))
+		require.NoError(t, writer.Close())
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), utf8BOM))
+		assert.Equal(t, utf8BOM+"Student ID,Name,GPAX\r\n6401,สมชาย ใจดี,3.50\r\n6402,\"Smith, John\",2.75\r\n", string(content))
+	})
+
+	t.Run("XLSX writer keeps numbers numeric", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "trainings.xlsx")
+
+		writer, err := newExportWriter(ReportFormatExcel, path, "Trainings", []string{"Training ID", "Start Date"})
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteRow([]interface{}{uint(7), time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}))
+		require.NoError(t, writer.Close())
+
+		f, err := excelize.OpenFile(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		rows, err := f.GetRows("Trainings")
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"Training ID", "Start Date"}, {"7", "2026-06-01"}}, rows)
+
+		cellType, err := f.GetCellType("Trainings", "A2")
+		require.NoError(t, err)
+		assert.NotEqual(t, excelize.CellTypeSharedString, cellType)
+	})
+
+	t.Run("PDF is not an export format", func(t *testing.T) {
+		_, err := newExportWriter(ReportFormatPDF, filepath.Join(t.TempDir(), "x.pdf"), "X", nil)
+		assert.EqualError(t, err, "unsupported export format: pdf")
+	})
+
+	t.Run("progress stays below 100 until the file is saved", func(t *testing.T) {
+		assert.Equal(t, 0, exportProgress(0, 0))
+		assert.Equal(t, 50, exportProgress(500, 1000))
+		assert.Equal(t, 99, exportProgress(1000, 1000))
+		assert.Equal(t, 99, exportProgress(1200, 1000)) // rows added after counting
+	})
+
+	t.Run("CreateJob rejects invalid requests before queuing", func(t *testing.T) {
+		exportService := NewExportService(nil, ExportSettings{})
+
+		_, err := exportService.CreateJob(CreateExportJobRequest{JobType: "grades", Format: ReportFormatCSV}, 1, "User", nil)
+		assert.EqualError(t, err, "unsupported export type: grades")
+
+		_, err = exportService.CreateJob(CreateExportJobRequest{JobType: ExportJobStudents, Format: ReportFormatPDF}, 1, "User", nil)
+		assert.EqualError(t, err, "unsupported export format: pdf")
+
+		start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+		end := start.AddDate(0, -1, 0)
+		_, err = exportService.CreateJob(CreateExportJobRequest{JobType: ExportJobStudents, Format: ReportFormatCSV, StartDate: &start, EndDate: &end}, 1, "User", nil)
+		assert.EqualError(t, err, "end date must be after start date")
+	})
+
+	t.Run("date range bounds are inclusive and optional", func(t *testing.T) {
+		start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+
+		params := ExportParameters{StartDate: &start, EndDate: &end}
+		assert.True(t, params.inDateRange(start))
+		assert.True(t, params.inDateRange(end))
+		assert.False(t, params.inDateRange(start.Add(-time.Second)))
+		assert.False(t, params.inDateRange(end.Add(time.Second)))
+
+		assert.True(t, (&ExportParameters{}).inDateRange(start))
+	})
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// exportWriter streams rows of an export to a file so large exports never sit in memory
+type exportWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// newExportWriter creates the file at path and writes the header row
+func newExportWriter(format ReportFormat, path, title string, headers []string) (exportWriter, error) {
+	switch format {
+	case ReportFormatCSV:
+		return newCSVExportWriter(path, headers)
+	case ReportFormatExcel:
+		return newXLSXExportWriter(path, title, headers)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// csvExportWriter writes RFC 4180 CSV with a UTF-8 BOM, like CSV analytics reports
+type csvExportWriter struct {
+	file   *os.File
+	buf    *bufio.Writer
+	writer *csv.Writer
+}
+
+func newCSVExportWriter(path string, headers []string) (*csvExportWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &csvExportWriter{file: file, buf: bufio.NewWriter(file)}
+	w.writer = csv.NewWriter(w.buf)
+	w.writer.UseCRLF = true
+
+	if _, err := w.buf.WriteString(utf8BOM); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := w.writer.Write(headers); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteRow writes one record
+func (w *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatReportValue(value)
+	}
+	return w.writer.Write(record)
+}
+
+// Close flushes buffered records and closes the file
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to write CSV export: %w", err)
+	}
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to write CSV export: %w", err)
+	}
+	return w.file.Close()
+}
+
+// xlsxExportWriter writes a single sheet through excelize's stream writer, which
+// spills rows to temporary files instead of keeping the whole sheet in memory
+type xlsxExportWriter struct {
+	path   string
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXExportWriter(path, title string, headers []string) (*xlsxExportWriter, error) {
+	f := excelize.NewFile()
+
+	sheet := xlsxSheetName(title, map[string]bool{})
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9E1F2"}},
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	stream, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := stream.SetColWidth(1, len(headers), 20); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	cells := make([]interface{}, len(headers))
+	for i, header := range headers {
+		cells[i] = excelize.Cell{StyleID: headerStyle, Value: header}
+	}
+	if err := stream.SetRow("A1", cells); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &xlsxExportWriter{path: path, file: f, stream: stream, row: 1}, nil
+}
+
+// WriteRow appends one row below the previous one
+func (w *xlsxExportWriter) WriteRow(values []interface{}) error {
+	w.row++
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = xlsxCellValue(value)
+	}
+	return w.stream.SetRow(fmt.Sprintf("A%d", w.row), cells)
+}
+
+// Close finishes the sheet and saves the workbook to disk
+func (w *xlsxExportWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return fmt.Errorf("failed to write XLSX export: %w", err)
+	}
+	if err := w.file.SaveAs(w.path); err != nil {
+		return fmt.Errorf("failed to write XLSX export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,792 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultGradeScale is the grade scale used when a grading scheme does not define one
+var DefaultGradeScale = []models.GradeBand{
+	{Grade: "A", MinPercent: 80, GradePoints: 4.0},
+	{Grade: "B+", MinPercent: 75, GradePoints: 3.5},
+	{Grade: "B", MinPercent: 70, GradePoints: 3.0},
+	{Grade: "C+", MinPercent: 65, GradePoints: 2.5},
+	{Grade: "C", MinPercent: 60, GradePoints: 2.0},
+	{Grade: "D+", MinPercent: 55, GradePoints: 1.5},
+	{Grade: "D", MinPercent: 50, GradePoints: 1.0},
+	{Grade: "F", MinPercent: 0, GradePoints: 0},
+}
+
+// gradeSourceFormTypes maps evaluation form sources to the form type whose submissions they average
+var gradeSourceFormTypes = map[models.GradeSource]models.EvaluationFormType{
+	models.GradeSourceCompanyEvaluation:    models.FormTypeCompanyStudent,
+	models.GradeSourceInstructorEvaluation: models.FormTypeInstructorStudent,
+	models.GradeSourceSelfEvaluation:       models.FormTypeStudentSelf,
+}
+
+// GradingService computes final internship grades from evaluation scores with per-course schemes,
+// and manages overrides, locking and publishing per course section
+type GradingService struct {
+	db *gorm.DB
+}
+
+// NewGradingService creates a new grading service instance
+func NewGradingService(db *gorm.DB) *GradingService {
+	return &GradingService{db: db}
+}
+
+// GradingSchemeRequest represents the request for setting a course's grading scheme.
+// The default grade scale is used when GradeScale is empty.
+type GradingSchemeRequest struct {
+	Components []models.GradingComponent `json:"components" validate:"required,min=1"`
+	GradeScale []models.GradeBand        `json:"grade_scale"`
+}
+
+// GradeOverrideRequest represents an instructor's override of a computed grade
+type GradeOverrideRequest struct {
+	Grade  string `json:"grade" validate:"required"`
+	Reason string `json:"reason" validate:"required"`
+}
+
+// ComponentScore is the contribution of one scheme component to a computed grade
+type ComponentScore struct {
+	Source       models.GradeSource `json:"source"`
+	Label        string             `json:"label,omitempty"`
+	Weight       float64            `json:"weight"`
+	Score        *float64           `json:"score"` // percentage, nil when the source has no data yet
+	Contribution float64            `json:"contribution"`
+}
+
+// GradeComputation is the result of applying a grading scheme to a student's scores
+type GradeComputation struct {
+	Percent     *float64             `json:"percent"`
+	Grade       *string              `json:"grade"`
+	GradePoints *float64             `json:"grade_points"`
+	Breakdown   []ComponentScore     `json:"breakdown"`
+	Missing     []models.GradeSource `json:"missing"`
+}
+
+// GradeRow is one student's line on a section grade sheet
+type GradeRow struct {
+	StudentEnrollID  uint             `json:"student_enroll_id"`
+	StudentID        uint             `json:"student_id"`
+	StudentCode      string           `json:"student_code"`
+	StudentName      string           `json:"student_name"`
+	Computed         GradeComputation `json:"computed"`
+	OverrideGrade    *string          `json:"override_grade"`
+	OverrideReason   string           `json:"override_reason,omitempty"`
+	OverriddenBy     *uint            `json:"overridden_by,omitempty"`
+	OverriddenAt     *time.Time       `json:"overridden_at,omitempty"`
+	FinalGrade       *string          `json:"final_grade"`
+	FinalGradePoints *float64         `json:"final_grade_points"`
+	Incomplete       bool             `json:"incomplete"`
+}
+
+// GradeSheetView is the grade sheet of a course section. Draft sheets are computed from the current
+// evaluation scores; locked and published sheets show the snapshot taken when they were locked.
+type GradeSheetView struct {
+	CourseSectionID uint                    `json:"course_section_id"`
+	CourseID        uint                    `json:"course_id"`
+	Status          models.GradeSheetStatus `json:"status"`
+	LockedBy        *uint                   `json:"locked_by"`
+	LockedAt        *time.Time              `json:"locked_at"`
+	PublishedBy     *uint                   `json:"published_by"`
+	PublishedAt     *time.Time              `json:"published_at"`
+	Scheme          *models.GradingScheme   `json:"scheme"`
+	Rows            []GradeRow              `json:"rows"`
+}
+
+// GetScheme retrieves the grading scheme of a course
+func (s *GradingService) GetScheme(courseID uint) (*models.GradingScheme, error) {
+	var scheme models.GradingScheme
+	if err := s.db.Where("course_id = ?", courseID).First(&scheme).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("grading scheme not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &scheme, nil
+}
+
+// SetScheme creates or replaces the grading scheme of a course. Sections whose grades are already
+// locked keep the grades computed with the previous scheme.
+func (s *GradingService) SetScheme(courseID uint, req GradingSchemeRequest, userID uint) (*models.GradingScheme, error) {
+	scale := req.GradeScale
+	if len(scale) == 0 {
+		scale = DefaultGradeScale
+	}
+	scale, err := validateGradingScheme(req.Components, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	var course models.Course
+	if err := s.db.First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	components, err := json.Marshal(req.Components)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode grading components: %w", err)
+	}
+	gradeScale, err := json.Marshal(scale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode grade scale: %w", err)
+	}
+
+	var scheme models.GradingScheme
+	err = s.db.Where("course_id = ?", courseID).First(&scheme).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	scheme.CourseID = courseID
+	scheme.Components = components
+	scheme.GradeScale = gradeScale
+	scheme.UpdatedBy = &userID
+	if err := s.db.Save(&scheme).Error; err != nil {
+		return nil, fmt.Errorf("failed to save grading scheme: %w", err)
+	}
+	return &scheme, nil
+}
+
+// GetSectionGrades returns the grade sheet of a course section
+func (s *GradingService) GetSectionGrades(scope *DataScope, sectionID uint) (*GradeSheetView, error) {
+	section, err := s.getSection(scope, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildSheet(section)
+}
+
+// OverrideGrade replaces the computed grade of one enrollment while the section's grades are a draft
+func (s *GradingService) OverrideGrade(scope *DataScope, sectionID, enrollID uint, req GradeOverrideRequest, userID uint) (*GradeSheetView, error) {
+	section, err := s.getSection(scope, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	_, scale, err := s.schemeForSection(section)
+	if err != nil {
+		return nil, err
+	}
+
+	band, ok := gradeBand(scale, req.Grade)
+	if !ok {
+		return nil, fmt.Errorf("invalid grade override: %q is not on the course's grade scale", req.Grade)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		grade, err := s.editableGrade(tx, section.ID, enrollID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		grade.OverrideGrade = &band.Grade
+		grade.OverrideGradePoints = &band.GradePoints
+		grade.OverrideReason = strings.TrimSpace(req.Reason)
+		grade.OverriddenBy = &userID
+		grade.OverriddenAt = &now
+		return tx.Save(grade).Error
+	})
+	if err != nil {
+		return nil, gradingError(err, "failed to override grade")
+	}
+
+	return s.buildSheet(section)
+}
+
+// ClearOverride restores the computed grade of one enrollment while the section's grades are a draft
+func (s *GradingService) ClearOverride(scope *DataScope, sectionID, enrollID uint) (*GradeSheetView, error) {
+	section, err := s.getSection(scope, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		grade, err := s.editableGrade(tx, section.ID, enrollID)
+		if err != nil {
+			return err
+		}
+		return tx.Model(grade).Updates(map[string]interface{}{
+			"override_grade":        nil,
+			"override_grade_points": nil,
+			"override_reason":       "",
+			"overridden_by":         nil,
+			"overridden_at":         nil,
+		}).Error
+	})
+	if err != nil {
+		return nil, gradingError(err, "failed to clear grade override")
+	}
+
+	return s.buildSheet(section)
+}
+
+// LockSection snapshots the section's computed grades. Every student needs either a complete
+// computation or an override.
+func (s *GradingService) LockSection(scope *DataScope, sectionID, userID uint) (*GradeSheetView, error) {
+	section, err := s.getSection(scope, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.buildSheet(section)
+	if err != nil {
+		return nil, err
+	}
+	if view.Status != models.GradeSheetStatusDraft {
+		return nil, errors.New("grades are locked")
+	}
+
+	incomplete := 0
+	for _, row := range view.Rows {
+		if row.FinalGrade == nil {
+			incomplete++
+		}
+	}
+	if incomplete > 0 {
+		return nil, fmt.Errorf("grades are incomplete: %d student(s) have missing evaluation scores and no override", incomplete)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		sheet, err := findOrCreateGradeSheet(tx, section.ID)
+		if err != nil {
+			return err
+		}
+		if sheet.IsLocked() {
+			return errors.New("grades are locked")
+		}
+
+		for _, row := range view.Rows {
+			breakdown, err := json.Marshal(row.Computed.Breakdown)
+			if err != nil {
+				return err
+			}
+
+			grade, err := findOrCreateStudentGrade(tx, sheet.ID, row.StudentEnrollID)
+			if err != nil {
+				return err
+			}
+			grade.ComputedPercent = row.Computed.Percent
+			grade.ComputedGrade = row.Computed.Grade
+			grade.ComputedGradePoints = row.Computed.GradePoints
+			grade.Breakdown = breakdown
+			if err := tx.Save(grade).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(sheet).Updates(map[string]interface{}{
+			"status":    models.GradeSheetStatusLocked,
+			"locked_by": userID,
+			"locked_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, gradingError(err, "failed to lock grades")
+	}
+
+	return s.buildSheet(section)
+}
+
+// UnlockSection returns locked grades to a draft so they can be recomputed and overridden again
+func (s *GradingService) UnlockSection(scope *DataScope, sectionID uint) (*GradeSheetView, error) {
+	section, err := s.getSection(scope, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := s.findSheet(section.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case sheet == nil || sheet.Status == models.GradeSheetStatusDraft:
+		return nil, errors.New("grades are not locked")
+	case sheet.Status == models.GradeSheetStatusPublished:
+		return nil, errors.New("grades are already published")
+	}
+
+	err = s.db.Model(sheet).Updates(map[string]interface{}{
+		"status":    models.GradeSheetStatusDraft,
+		"locked_by": nil,
+		"locked_at": nil,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock grades: %w", err)
+	}
+
+	return s.buildSheet(section)
+}
+
+// PublishSection writes the locked final grades to the students' enrollments
+func (s *GradingService) PublishSection(scope *DataScope, sectionID, userID uint) (*GradeSheetView, error) {
+	section, err := s.getSection(scope, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var sheet models.GradeSheet
+		err := tx.Where("course_section_id = ?", section.ID).Preload("Grades").First(&sheet).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound) || sheet.Status == models.GradeSheetStatusDraft:
+			return errors.New("grades must be locked before publishing")
+		case sheet.Status == models.GradeSheetStatusPublished:
+			return errors.New("grades are already published")
+		}
+
+		for _, grade := range sheet.Grades {
+			finalGrade, finalPoints := finalStudentGrade(&grade)
+			err := tx.Model(&models.StudentEnroll{}).Where("id = ?", grade.StudentEnrollID).Updates(map[string]interface{}{
+				"grade":        finalGrade,
+				"grade_points": finalPoints,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(&sheet).Updates(map[string]interface{}{
+			"status":       models.GradeSheetStatusPublished,
+			"published_by": userID,
+			"published_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, gradingError(err, "failed to publish grades")
+	}
+
+	return s.buildSheet(section)
+}
+
+// buildSheet assembles the grade sheet of a section, computing draft grades from current scores
+func (s *GradingService) buildSheet(section *models.CourseSection) (*GradeSheetView, error) {
+	scheme, scale, err := s.schemeForSection(section)
+	if err != nil {
+		return nil, err
+	}
+	components, err := scheme.GetComponents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse grading components: %w", err)
+	}
+
+	var enrollments []models.StudentEnroll
+	err = s.db.Where("course_section_id = ?", section.ID).Preload("Student").Order("id ASC").Find(&enrollments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch enrollments: %w", err)
+	}
+
+	sheet, err := s.findSheet(section.ID)
+	if err != nil {
+		return nil, err
+	}
+	view := &GradeSheetView{
+		CourseSectionID: section.ID,
+		CourseID:        section.CourseID,
+		Status:          models.GradeSheetStatusDraft,
+		Scheme:          scheme,
+		Rows:            make([]GradeRow, 0, len(enrollments)),
+	}
+	stored := make(map[uint]*models.StudentGrade)
+	if sheet != nil {
+		view.Status = sheet.Status
+		view.LockedBy = sheet.LockedBy
+		view.LockedAt = sheet.LockedAt
+		view.PublishedBy = sheet.PublishedBy
+		view.PublishedAt = sheet.PublishedAt
+		for i := range sheet.Grades {
+			stored[sheet.Grades[i].StudentEnrollID] = &sheet.Grades[i]
+		}
+	}
+
+	var scores map[uint]map[models.GradeSource]float64
+	if view.Status == models.GradeSheetStatusDraft {
+		enrollIDs := make([]uint, len(enrollments))
+		for i, enrollment := range enrollments {
+			enrollIDs[i] = enrollment.ID
+		}
+		scores, err = s.loadScores(enrollIDs, components)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, enrollment := range enrollments {
+		row := GradeRow{
+			StudentEnrollID: enrollment.ID,
+			StudentID:       enrollment.StudentID,
+			StudentCode:     enrollment.Student.StudentID,
+			StudentName:     enrollment.Student.GetFullName(),
+		}
+
+		grade := stored[enrollment.ID]
+		if view.Status == models.GradeSheetStatusDraft || grade == nil {
+			row.Computed = computeGrade(components, scale, scores[enrollment.ID])
+		} else {
+			row.Computed = GradeComputation{Percent: grade.ComputedPercent, Grade: grade.ComputedGrade, GradePoints: grade.ComputedGradePoints}
+			if len(grade.Breakdown) > 0 {
+				if err := json.Unmarshal(grade.Breakdown, &row.Computed.Breakdown); err != nil {
+					return nil, fmt.Errorf("failed to parse grade breakdown: %w", err)
+				}
+			}
+		}
+
+		row.FinalGrade, row.FinalGradePoints = row.Computed.Grade, row.Computed.GradePoints
+		if grade != nil && grade.OverrideGrade != nil {
+			row.OverrideGrade = grade.OverrideGrade
+			row.OverrideReason = grade.OverrideReason
+			row.OverriddenBy = grade.OverriddenBy
+			row.OverriddenAt = grade.OverriddenAt
+			row.FinalGrade, row.FinalGradePoints = grade.OverrideGrade, grade.OverrideGradePoints
+		}
+		row.Incomplete = row.Computed.Percent == nil
+		view.Rows = append(view.Rows, row)
+	}
+
+	return view, nil
+}
+
+// loadScores returns the percentage score of each enrollment for each component source that has data
+func (s *GradingService) loadScores(enrollIDs []uint, components []models.GradingComponent) (map[uint]map[models.GradeSource]float64, error) {
+	scores := make(map[uint]map[models.GradeSource]float64)
+	if len(enrollIDs) == 0 {
+		return scores, nil
+	}
+	set := func(enrollID uint, source models.GradeSource, score float64) {
+		if scores[enrollID] == nil {
+			scores[enrollID] = make(map[models.GradeSource]float64)
+		}
+		scores[enrollID][source] = math.Min(100, math.Max(0, score))
+	}
+
+	type scoreRow struct {
+		StudentEnrollID uint
+		Score           float64
+	}
+
+	for _, component := range components {
+		switch component.Source {
+		case models.GradeSourceVisitorEvaluation:
+			var rows []scoreRow
+			err := s.db.Table("visitor_evaluate_students").
+				Select("visitor_trainings.student_enroll_id, AVG(visitor_evaluate_students.score) AS score").
+				Joins("JOIN visitor_trainings ON visitor_trainings.id = visitor_evaluate_students.visitor_training_id").
+				Where("visitor_trainings.student_enroll_id IN ?", enrollIDs).
+				Group("visitor_trainings.student_enroll_id").
+				Scan(&rows).Error
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch visitor evaluation scores: %w", err)
+			}
+			for _, row := range rows {
+				set(row.StudentEnrollID, component.Source, row.Score)
+			}
+
+		case models.GradeSourceCompanyEvaluation, models.GradeSourceInstructorEvaluation, models.GradeSourceSelfEvaluation:
+			var rows []scoreRow
+			err := s.db.Table("evaluation_submissions").
+				Select("student_trainings.student_enroll_id, AVG(evaluation_submissions.percentage) AS score").
+				Joins("JOIN evaluation_forms ON evaluation_forms.id = evaluation_submissions.form_id").
+				Joins("JOIN student_trainings ON student_trainings.id = evaluation_submissions.student_training_id").
+				Where("student_trainings.student_enroll_id IN ?", enrollIDs).
+				Where("evaluation_forms.form_type = ?", gradeSourceFormTypes[component.Source]).
+				Where("evaluation_submissions.status IN ?", []models.EvaluationSubmissionStatus{models.SubmissionStatusSubmitted, models.SubmissionStatusReviewed}).
+				Group("student_trainings.student_enroll_id").
+				Scan(&rows).Error
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch evaluation submission scores: %w", err)
+			}
+			for _, row := range rows {
+				set(row.StudentEnrollID, component.Source, row.Score)
+			}
+
+		case models.GradeSourceAttendance:
+			attendance := NewAttendanceService(s.db, AttendanceSettings{})
+			summaries, err := attendance.summarize(s.db.Where("student_trainings.student_enroll_id IN ?", enrollIDs))
+			if err != nil {
+				return nil, err
+			}
+			approved := make(map[uint]float64)
+			required := make(map[uint]float64)
+			for _, summary := range summaries {
+				if summary.Requirement == nil || summary.Requirement.RequiredHours <= 0 {
+					continue
+				}
+				approved[summary.StudentEnrollID] += summary.ApprovedHours
+				required[summary.StudentEnrollID] = summary.Requirement.RequiredHours
+			}
+			for enrollID, hours := range required {
+				set(enrollID, component.Source, approved[enrollID]/hours*100)
+			}
+		}
+	}
+
+	return scores, nil
+}
+
+// getSection loads a course section the caller may grade. Instructors may only grade sections
+// they are assigned to.
+func (s *GradingService) getSection(scope *DataScope, sectionID uint) (*models.CourseSection, error) {
+	var section models.CourseSection
+	if err := s.db.First(&section, sectionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course section not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if scope.Unrestricted() {
+		return &section, nil
+	}
+	if scope.Level != ScopeInstructor {
+		return nil, errors.New("course section access denied")
+	}
+
+	var count int64
+	err := s.db.Model(&models.CourseInstructor{}).
+		Where("course_section_id = ? AND instructor_id = ?", sectionID, scope.InstructorID).
+		Count(&count).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if count == 0 {
+		return nil, errors.New("course section access denied")
+	}
+	return &section, nil
+}
+
+// schemeForSection loads the grading scheme and grade scale of the section's course
+func (s *GradingService) schemeForSection(section *models.CourseSection) (*models.GradingScheme, []models.GradeBand, error) {
+	scheme, err := s.GetScheme(section.CourseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	scale, err := scheme.GetGradeScale()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse grade scale: %w", err)
+	}
+	if len(scale) == 0 {
+		scale = DefaultGradeScale
+	}
+	return scheme, scale, nil
+}
+
+// findSheet returns the grade sheet of a section with its stored grades, or nil when there is none yet
+func (s *GradingService) findSheet(sectionID uint) (*models.GradeSheet, error) {
+	var sheet models.GradeSheet
+	err := s.db.Where("course_section_id = ?", sectionID).Preload("Grades").First(&sheet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &sheet, nil
+}
+
+// editableGrade returns the stored grade of an enrollment in a draft section, creating it when needed
+func (s *GradingService) editableGrade(tx *gorm.DB, sectionID, enrollID uint) (*models.StudentGrade, error) {
+	var enrollment models.StudentEnroll
+	err := tx.Where("id = ? AND course_section_id = ?", enrollID, sectionID).First(&enrollment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student enrollment not found")
+		}
+		return nil, err
+	}
+
+	sheet, err := findOrCreateGradeSheet(tx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if sheet.IsLocked() {
+		return nil, errors.New("grades are locked")
+	}
+	return findOrCreateStudentGrade(tx, sheet.ID, enrollID)
+}
+
+func findOrCreateGradeSheet(tx *gorm.DB, sectionID uint) (*models.GradeSheet, error) {
+	var sheet models.GradeSheet
+	err := tx.Where("course_section_id = ?", sectionID).First(&sheet).Error
+	if err == nil {
+		return &sheet, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	sheet = models.GradeSheet{CourseSectionID: sectionID, Status: models.GradeSheetStatusDraft}
+	if err := tx.Create(&sheet).Error; err != nil {
+		return nil, err
+	}
+	return &sheet, nil
+}
+
+func findOrCreateStudentGrade(tx *gorm.DB, sheetID, enrollID uint) (*models.StudentGrade, error) {
+	var grade models.StudentGrade
+	err := tx.Where("student_enroll_id = ?", enrollID).First(&grade).Error
+	if err == nil {
+		return &grade, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	grade = models.StudentGrade{GradeSheetID: sheetID, StudentEnrollID: enrollID}
+	if err := tx.Create(&grade).Error; err != nil {
+		return nil, err
+	}
+	return &grade, nil
+}
+
+// gradingErrors lists the grading errors that are returned to callers unwrapped
+var gradingErrors = map[string]bool{
+	"student enrollment not found":            true,
+	"grades are locked":                       true,
+	"grades must be locked before publishing": true,
+	"grades are already published":            true,
+}
+
+// gradingError passes known grading errors through and wraps database errors
+func gradingError(err error, action string) error {
+	if gradingErrors[err.Error()] {
+		return err
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// finalStudentGrade returns the override grade when there is one, otherwise the computed grade
+func finalStudentGrade(grade *models.StudentGrade) (*string, *float64) {
+	if grade.OverrideGrade != nil {
+		return grade.OverrideGrade, grade.OverrideGradePoints
+	}
+	return grade.ComputedGrade, grade.ComputedGradePoints
+}
+
+// validateGradingScheme checks that component weights add up to 100 and returns the grade scale
+// sorted from the highest band down
+func validateGradingScheme(components []models.GradingComponent, scale []models.GradeBand) ([]models.GradeBand, error) {
+	if len(components) == 0 {
+		return nil, errors.New("invalid grading scheme: at least one component is required")
+	}
+
+	seen := make(map[models.GradeSource]bool)
+	total := 0.0
+	for _, component := range components {
+		switch component.Source {
+		case models.GradeSourceVisitorEvaluation, models.GradeSourceCompanyEvaluation,
+			models.GradeSourceInstructorEvaluation, models.GradeSourceSelfEvaluation, models.GradeSourceAttendance:
+		default:
+			return nil, fmt.Errorf("invalid grading scheme: unknown source %q", component.Source)
+		}
+		if seen[component.Source] {
+			return nil, fmt.Errorf("invalid grading scheme: source %q is used more than once", component.Source)
+		}
+		seen[component.Source] = true
+		if component.Weight <= 0 {
+			return nil, fmt.Errorf("invalid grading scheme: weight of %q must be positive", component.Source)
+		}
+		total += component.Weight
+	}
+	if math.Abs(total-100) > 0.001 {
+		return nil, fmt.Errorf("invalid grading scheme: component weights add up to %g, not 100", total)
+	}
+
+	sorted := make([]models.GradeBand, len(scale))
+	copy(sorted, scale)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MinPercent > sorted[j].MinPercent })
+
+	grades := make(map[string]bool)
+	for i, band := range sorted {
+		band.Grade = strings.TrimSpace(band.Grade)
+		if band.Grade == "" {
+			return nil, errors.New("invalid grading scheme: every grade band needs a grade")
+		}
+		if grades[band.Grade] {
+			return nil, fmt.Errorf("invalid grading scheme: grade %q appears more than once", band.Grade)
+		}
+		grades[band.Grade] = true
+		if band.MinPercent < 0 || band.MinPercent > 100 {
+			return nil, fmt.Errorf("invalid grading scheme: min_percent of %q must be between 0 and 100", band.Grade)
+		}
+		if i > 0 && band.MinPercent == sorted[i-1].MinPercent {
+			return nil, fmt.Errorf("invalid grading scheme: grades %q and %q have the same min_percent", sorted[i-1].Grade, band.Grade)
+		}
+		if band.GradePoints < 0 {
+			return nil, fmt.Errorf("invalid grading scheme: grade_points of %q cannot be negative", band.Grade)
+		}
+		sorted[i] = band
+	}
+	if sorted[len(sorted)-1].MinPercent != 0 {
+		return nil, errors.New("invalid grading scheme: the lowest grade band must start at 0")
+	}
+
+	return sorted, nil
+}
+
+// computeGrade applies scheme components to a student's percentage scores. The grade is only
+// assigned when every component has a score.
+func computeGrade(components []models.GradingComponent, scale []models.GradeBand, scores map[models.GradeSource]float64) GradeComputation {
+	result := GradeComputation{
+		Breakdown: make([]ComponentScore, 0, len(components)),
+		Missing:   []models.GradeSource{},
+	}
+
+	total := 0.0
+	for _, component := range components {
+		line := ComponentScore{Source: component.Source, Label: component.Label, Weight: component.Weight}
+		if score, ok := scores[component.Source]; ok {
+			score = math.Round(score*100) / 100
+			line.Score = &score
+			line.Contribution = math.Round(score*component.Weight) / 100
+			total += line.Contribution
+		} else {
+			result.Missing = append(result.Missing, component.Source)
+		}
+		result.Breakdown = append(result.Breakdown, line)
+	}
+	if len(result.Missing) > 0 {
+		return result
+	}
+
+	percent := math.Round(total*100) / 100
+	result.Percent = &percent
+	for _, band := range scale {
+		if percent >= band.MinPercent {
+			grade, points := band.Grade, band.GradePoints
+			result.Grade = &grade
+			result.GradePoints = &points
+			break
+		}
+	}
+	return result
+}
+
+// gradeBand looks up a letter grade on a scale
+func gradeBand(scale []models.GradeBand, grade string) (models.GradeBand, bool) {
+	grade = strings.TrimSpace(grade)
+	for _, band := range scale {
+		if strings.EqualFold(band.Grade, grade) {
+			return band, true
+		}
+	}
+	return models.GradeBand{}, false
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrading(t *testing.T) {
+	components := []models.GradingComponent{
+		{Source: models.GradeSourceVisitorEvaluation, Weight: 30},
+		{Source: models.GradeSourceCompanyEvaluation, Weight: 50},
+		{Source: models.GradeSourceAttendance, Weight: 20},
+	}
+
+	t.Run("schemes are validated", func(t *testing.T) {
+		cases := []struct {
+			components []models.GradingComponent
+			scale      []models.GradeBand
+			want       string
+		}{
+			{nil, DefaultGradeScale, "invalid grading scheme: at least one component is required"},
+			{[]models.GradingComponent{{Source: "quiz", Weight: 100}}, DefaultGradeScale, `invalid grading scheme: unknown source "quiz"`},
+			{[]models.GradingComponent{{Source: models.GradeSourceAttendance, Weight: 50}, {Source: models.GradeSourceAttendance, Weight: 50}}, DefaultGradeScale,
+				`invalid grading scheme: source "attendance" is used more than once`},
+			{[]models.GradingComponent{{Source: models.GradeSourceAttendance, Weight: 90}}, DefaultGradeScale, "invalid grading scheme: component weights add up to 90, not 100"},
+			{components, []models.GradeBand{{Grade: "P", MinPercent: 50}, {Grade: "P", MinPercent: 0}}, `invalid grading scheme: grade "P" appears more than once`},
+			{components, []models.GradeBand{{Grade: "P", MinPercent: 50}}, "invalid grading scheme: the lowest grade band must start at 0"},
+		}
+		for _, tc := range cases {
+			_, err := validateGradingScheme(tc.components, tc.scale)
+			assert.EqualError(t, err, tc.want)
+		}
+
+		scale, err := validateGradingScheme(components, []models.GradeBand{{Grade: "F", MinPercent: 0}, {Grade: "S", MinPercent: 60, GradePoints: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, "S", scale[0].Grade)
+	})
+
+	t.Run("weighted scores map to the grade scale", func(t *testing.T) {
+		result := computeGrade(components, DefaultGradeScale, map[models.GradeSource]float64{
+			models.GradeSourceVisitorEvaluation: 80,
+			models.GradeSourceCompanyEvaluation: 70,
+			models.GradeSourceAttendance:        100,
+		})
+		require.NotNil(t, result.Percent)
+		assert.Equal(t, 79.0, *result.Percent)
+		assert.Equal(t, "B+", *result.Grade)
+		assert.Equal(t, 3.5, *result.GradePoints)
+		assert.Equal(t, 35.0, result.Breakdown[1].Contribution)
+		assert.Empty(t, result.Missing)
+	})
+
+	t.Run("missing sources leave the grade unassigned", func(t *testing.T) {
+		result := computeGrade(components, DefaultGradeScale, map[models.GradeSource]float64{
+			models.GradeSourceCompanyEvaluation: 90,
+		})
+		assert.Nil(t, result.Percent)
+		assert.Nil(t, result.Grade)
+		assert.Equal(t, []models.GradeSource{models.GradeSourceVisitorEvaluation, models.GradeSourceAttendance}, result.Missing)
+		assert.Nil(t, result.Breakdown[0].Score)
+	})
+
+	t.Run("overrides must be on the scale", func(t *testing.T) {
+		band, ok := gradeBand(DefaultGradeScale, "b+")
+		assert.True(t, ok)
+		assert.Equal(t, 3.5, band.GradePoints)
+
+		_, ok = gradeBand(DefaultGradeScale, "E")
+		assert.False(t, ok)
+	})
+}
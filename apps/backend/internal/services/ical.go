@@ -0,0 +1,1019 @@
+package services
+
+import (
+	"backend-go/internal/models"
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	icalProdID    = "-//Internship Management System//Calendar 1.0//EN"
+	icalUIDDomain = "internship-management"
+
+	icalDateFormat     = "20060102"
+	icalDateTimeFormat = "20060102T150405"
+	icalUTCFormat      = "20060102T150405Z"
+
+	// icalLineLimit is the maximum line length in octets before folding (RFC 5545 section 3.1)
+	icalLineLimit = 75
+
+	// defaultVisitDuration is used for supervision visits, which only record a start time
+	defaultVisitDuration = 2 * time.Hour
+
+	// defaultImportedEventDuration is used for imported events without DTEND or DURATION
+	defaultImportedEventDuration = time.Hour
+
+	// subscriptionFeedWindow limits how far back the personal feed reaches for one-off events
+	subscriptionFeedWindow = 180 * 24 * time.Hour
+)
+
+// ICalService serializes calendars, appointments and supervision visits as iCalendar (RFC 5545)
+// and imports .ics files into calendars
+type ICalService struct {
+	db              *gorm.DB
+	scheduleService *ScheduleService
+}
+
+// NewICalService creates a new iCalendar service
+func NewICalService(db *gorm.DB) *ICalService {
+	return &ICalService{
+		db:              db,
+		scheduleService: NewScheduleService(db),
+	}
+}
+
+// ICalImportResult summarizes an .ics import
+type ICalImportResult struct {
+	Imported int      `json:"imported"`
+	Updated  int      `json:"updated"`
+	Skipped  int      `json:"skipped"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// CalendarFeed serializes a calendar and its schedules for its owner, or for anyone when the calendar is public
+func (s *ICalService) CalendarFeed(calendarID uint, actor ScheduleActor) ([]byte, error) {
+	calendar, err := s.scheduleService.GetCalendarByID(calendarID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleIDs := make([]uint, 0, len(calendar.Schedules))
+	for _, schedule := range calendar.Schedules {
+		scheduleIDs = append(scheduleIDs, schedule.ID)
+	}
+
+	var schedules []models.Schedule
+	if len(scheduleIDs) > 0 {
+		if err := s.db.Preload("Exceptions").Where("id IN ?", scheduleIDs).Order("start_time ASC").Find(&schedules).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	writer := newICalWriter(calendar.Name, calendar.Description)
+	for i := range schedules {
+		writer.writeSchedule(&schedules[i])
+	}
+
+	return writer.bytes(), nil
+}
+
+// GetSubscription returns the account's feed subscription, creating one on first use
+func (s *ICalService) GetSubscription(account Account) (*models.CalendarSubscription, error) {
+	var subscription models.CalendarSubscription
+	err := s.db.Where("user_type = ? AND user_id = ?", string(account.Type), account.ID).First(&subscription).Error
+	if err == nil {
+		return &subscription, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription = models.CalendarSubscription{
+		UserType: string(account.Type),
+		UserID:   account.ID,
+		Token:    token,
+	}
+	if err := s.db.Create(&subscription).Error; err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// RotateSubscription issues a new feed token so previously shared URLs stop working
+func (s *ICalService) RotateSubscription(account Account) (*models.CalendarSubscription, error) {
+	subscription, err := s.GetSubscription(account)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Token = token
+	subscription.LastAccessedAt = nil
+	if err := s.db.Save(subscription).Error; err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// SubscriptionFeed serializes everything on the token owner's agenda: schedules they organize or attend,
+// their appointments and the supervision visits they are assigned to
+func (s *ICalService) SubscriptionFeed(token string) ([]byte, error) {
+	var subscription models.CalendarSubscription
+	if err := s.db.Where("token = ?", token).First(&subscription).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("calendar subscription not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	since := now.Add(-subscriptionFeedWindow)
+	userID, userType := subscription.UserID, subscription.UserType
+
+	var schedules []models.Schedule
+	err := s.db.Preload("Exceptions").
+		Where("(created_by = ? AND creator_type = ?) OR id IN (?)", userID, userType,
+			s.db.Model(&models.ScheduleParticipant{}).Select("schedule_id").
+				Where("user_id = ? AND user_type = ? AND status <> ?", userID, userType, models.ParticipantStatusDeclined)).
+		Where("end_time >= ? OR (recurrence_type <> ? AND (recurrence_end IS NULL OR recurrence_end >= ?))",
+			since, models.RecurrenceNone, since).
+		Order("start_time ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var appointments []models.Appointment
+	err = s.db.Where("(requested_by = ? AND requester_type = ?) OR (approved_by = ? AND approver_type = ?)",
+		userID, userType, userID, userType).
+		Where("appointment_date >= ?", since).
+		Order("appointment_date ASC").
+		Find(&appointments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Only instructor accounts are assigned supervision visits
+	var visits []models.VisitorSchedule
+	if userType == string(AccountTypeInstructor) {
+		err = s.db.Preload("Training.StudentEnroll.Student").
+			Joins("JOIN visitor_trainings ON visitor_trainings.id = visitor_schedules.visitor_training_id").
+			Where("visitor_trainings.visitor_instructor_id = ? AND visitor_schedules.visit_at IS NOT NULL AND visitor_schedules.visit_at >= ?", userID, since).
+			Order("visitor_schedules.visit_at ASC").
+			Find(&visits).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer := newICalWriter("Internship Schedule", "")
+	for i := range schedules {
+		writer.writeSchedule(&schedules[i])
+	}
+	for i := range appointments {
+		writer.writeAppointment(&appointments[i])
+	}
+	for i := range visits {
+		writer.writeVisit(&visits[i])
+	}
+
+	s.db.Model(&subscription).Update("last_accessed_at", now)
+
+	return writer.bytes(), nil
+}
+
+// ImportICS imports the VEVENTs of an .ics file into a calendar owned by the actor.
+// Events are matched on UID so re-importing the same file updates instead of duplicating.
+func (s *ICalService) ImportICS(calendarID uint, actor ScheduleActor, r io.Reader) (*ICalImportResult, error) {
+	if _, err := s.scheduleService.getOwnedCalendar(calendarID, actor); err != nil {
+		return nil, err
+	}
+
+	events, err := parseICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Series must exist before their overridden occurrences are applied
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].RecurrenceID == nil && events[j].RecurrenceID != nil
+	})
+
+	result := &ICalImportResult{}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		scheduleService := NewScheduleService(tx)
+		for _, event := range events {
+			if err := s.importEvent(tx, scheduleService, calendarID, actor, event, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// importEvent creates or updates the schedule for a single VEVENT
+func (s *ICalService) importEvent(tx *gorm.DB, scheduleService *ScheduleService, calendarID uint, actor ScheduleActor, event icalEvent, result *ICalImportResult) error {
+	result.Warnings = append(result.Warnings, event.Warnings...)
+
+	if event.UID == "" {
+		event.UID = fmt.Sprintf("%s-%s@import", event.Start.UTC().Format(icalUTCFormat), event.Summary)
+	}
+
+	var existing models.Schedule
+	err := tx.Where("ical_uid = ? AND created_by = ? AND creator_type = ?", event.UID, actor.UserID, actor.AccountType).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	found := err == nil
+
+	// Overridden occurrences become exceptions on the imported series
+	if event.RecurrenceID != nil {
+		if !found {
+			result.Skipped++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: occurrence override without a matching series", event.UID))
+			return nil
+		}
+		req := ScheduleExceptionRequest{
+			OccurrenceStart: *event.RecurrenceID,
+			Cancel:          event.Status == "CANCELLED",
+			CreatedBy:       actor.UserID,
+			CreatorType:     actor.AccountType,
+		}
+		if !req.Cancel {
+			req.StartTime = &event.Start
+			req.EndTime = &event.End
+			req.Location = &event.Location
+		}
+		if _, err := scheduleService.SetOccurrenceException(existing.ID, actor, req); err != nil {
+			result.Skipped++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", event.UID, err.Error()))
+			return nil
+		}
+		result.Updated++
+		return nil
+	}
+
+	if event.Status == "CANCELLED" && !found {
+		result.Skipped++
+		return nil
+	}
+
+	title := event.Summary
+	if title == "" {
+		title = "(untitled event)"
+	}
+	// Truncate by runes so multi-byte (Thai) characters are not split
+	if runes := []rune(title); len(runes) > 255 {
+		title = string(runes[:255])
+	}
+
+	var schedule *models.Schedule
+	if found {
+		status := existing.Status
+		if event.Status == "CANCELLED" {
+			status = models.ScheduleStatusCancelled
+		}
+		schedule, err = scheduleService.UpdateSchedule(existing.ID, actor, UpdateScheduleRequest{
+			Title:          &title,
+			Description:    &event.Description,
+			Status:         &status,
+			StartTime:      &event.Start,
+			EndTime:        &event.End,
+			Location:       &event.Location,
+			IsAllDay:       &event.AllDay,
+			RecurrenceType: &event.RecurrenceType,
+			RecurrenceEnd:  event.RecurrenceEnd,
+		})
+		if err == nil {
+			err = addScheduleToCalendar(tx, calendarID, schedule.ID)
+		}
+	} else {
+		schedule, err = scheduleService.CreateSchedule(CreateScheduleRequest{
+			Title:          title,
+			Description:    event.Description,
+			ScheduleType:   models.ScheduleTypeMeeting,
+			StartTime:      event.Start,
+			EndTime:        event.End,
+			Location:       event.Location,
+			IsAllDay:       event.AllDay,
+			RecurrenceType: event.RecurrenceType,
+			RecurrenceEnd:  event.RecurrenceEnd,
+			CreatedBy:      actor.UserID,
+			CreatorType:    actor.AccountType,
+			CalendarIDs:    []uint{calendarID},
+		})
+		if err == nil {
+			err = tx.Model(schedule).Update("ical_uid", event.UID).Error
+		}
+	}
+	if err != nil {
+		if _, known := scheduleValidationErrors[err.Error()]; known {
+			result.Skipped++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", event.UID, err.Error()))
+			return nil
+		}
+		return err
+	}
+
+	for _, exdate := range event.ExDates {
+		req := ScheduleExceptionRequest{OccurrenceStart: exdate, Cancel: true, CreatedBy: actor.UserID, CreatorType: actor.AccountType}
+		if _, err := scheduleService.SetOccurrenceException(schedule.ID, actor, req); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: EXDATE %s: %s", event.UID, exdate.Format(icalUTCFormat), err.Error()))
+		}
+	}
+
+	if found {
+		result.Updated++
+	} else {
+		result.Imported++
+	}
+	return nil
+}
+
+// scheduleValidationErrors lists schedule errors caused by the imported data rather than the database
+var scheduleValidationErrors = map[string]bool{
+	"end time must be after start time":       true,
+	"recurrence end must be after start time": true,
+}
+
+// generateFeedToken generates a random token for a calendar subscription URL
+func generateFeedToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// icalWriter builds an iCalendar document with CRLF line endings and folded content lines
+type icalWriter struct {
+	buf   bytes.Buffer
+	stamp time.Time
+}
+
+// newICalWriter starts a VCALENDAR with the given display name
+func newICalWriter(name, description string) *icalWriter {
+	w := &icalWriter{stamp: time.Now()}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", icalProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if name != "" {
+		w.line("X-WR-CALNAME", escapeICalText(name))
+	}
+	if description != "" {
+		w.line("X-WR-CALDESC", escapeICalText(description))
+	}
+	return w
+}
+
+// bytes closes the VCALENDAR and returns the document
+func (w *icalWriter) bytes() []byte {
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// line writes a content line, folding it at 75 octets without splitting UTF-8 sequences
+func (w *icalWriter) line(name, value string) {
+	content := name + ":" + value
+	limit := icalLineLimit
+	width := 0
+	for _, r := range content {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			w.buf.WriteString("\r\n ")
+			// The leading space of a continuation line counts towards its length
+			limit = icalLineLimit - 1
+			width = 0
+		}
+		w.buf.WriteRune(r)
+		width += size
+	}
+	w.buf.WriteString("\r\n")
+}
+
+// text writes a TEXT property, skipping empty values
+func (w *icalWriter) text(name, value string) {
+	if value != "" {
+		w.line(name, escapeICalText(value))
+	}
+}
+
+// times writes DTSTART and DTEND as dates for all-day events or as UTC date-times otherwise
+func (w *icalWriter) times(start, end time.Time, allDay bool) {
+	if allDay {
+		endDate := end
+		if !endDate.After(start) || formatICalDate(endDate) == formatICalDate(start) {
+			endDate = start.AddDate(0, 0, 1)
+		}
+		w.line("DTSTART;VALUE=DATE", formatICalDate(start))
+		w.line("DTEND;VALUE=DATE", formatICalDate(endDate))
+		return
+	}
+	w.line("DTSTART", formatICalUTC(start))
+	w.line("DTEND", formatICalUTC(end))
+}
+
+// writeSchedule writes a schedule as a VEVENT, with an RRULE, EXDATEs for cancelled occurrences
+// and overriding VEVENTs for moved or relocated occurrences
+func (w *icalWriter) writeSchedule(schedule *models.Schedule) {
+	uid := fmt.Sprintf("schedule-%d@%s", schedule.ID, icalUIDDomain)
+	if schedule.ICalUID != nil && *schedule.ICalUID != "" {
+		uid = *schedule.ICalUID
+	}
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", uid)
+	w.line("DTSTAMP", formatICalUTC(w.stamp))
+	w.line("LAST-MODIFIED", formatICalUTC(schedule.UpdatedAt))
+	w.times(schedule.StartTime, schedule.EndTime, schedule.IsAllDay)
+	w.text("SUMMARY", schedule.Title)
+	w.text("DESCRIPTION", schedule.Description)
+	w.text("LOCATION", schedule.Location)
+	w.line("STATUS", icalScheduleStatus(schedule.Status))
+	w.line("CATEGORIES", strings.ToUpper(string(schedule.ScheduleType)))
+
+	if rrule := icalRRule(schedule); rrule != "" {
+		w.line("RRULE", rrule)
+		for _, exception := range schedule.Exceptions {
+			if exception.IsCancelled {
+				w.recurrenceDate("EXDATE", exception.OccurrenceStart, schedule.IsAllDay)
+			}
+		}
+	}
+	w.line("END", "VEVENT")
+
+	if !schedule.IsRecurring() {
+		return
+	}
+
+	for _, exception := range schedule.Exceptions {
+		if exception.IsCancelled {
+			continue
+		}
+
+		start, end := exception.OccurrenceStart, exception.OccurrenceStart.Add(schedule.Duration())
+		if exception.IsMoved() {
+			start, end = *exception.NewStartTime, *exception.NewEndTime
+		}
+		location := schedule.Location
+		if exception.Location != nil {
+			location = *exception.Location
+		}
+
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", uid)
+		w.line("DTSTAMP", formatICalUTC(w.stamp))
+		w.recurrenceDate("RECURRENCE-ID", exception.OccurrenceStart, schedule.IsAllDay)
+		w.times(start, end, schedule.IsAllDay)
+		w.text("SUMMARY", schedule.Title)
+		w.text("DESCRIPTION", schedule.Description)
+		w.text("LOCATION", location)
+		w.line("STATUS", icalScheduleStatus(schedule.Status))
+		w.line("END", "VEVENT")
+	}
+}
+
+// writeAppointment writes an appointment as a VEVENT; unapproved appointments are tentative
+func (w *icalWriter) writeAppointment(appointment *models.Appointment) {
+	status := "TENTATIVE"
+	switch {
+	case appointment.Status == models.ScheduleStatusCancelled:
+		status = "CANCELLED"
+	case appointment.IsApproved():
+		status = "CONFIRMED"
+	}
+
+	description := appointment.Description
+	if appointment.Notes != "" {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += appointment.Notes
+	}
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", fmt.Sprintf("appointment-%d@%s", appointment.ID, icalUIDDomain))
+	w.line("DTSTAMP", formatICalUTC(w.stamp))
+	w.line("LAST-MODIFIED", formatICalUTC(appointment.UpdatedAt))
+	w.times(appointment.AppointmentDate, appointment.GetEndTime(), false)
+	w.text("SUMMARY", appointment.Title)
+	w.text("DESCRIPTION", description)
+	w.text("LOCATION", appointment.Location)
+	w.line("STATUS", status)
+	w.line("CATEGORIES", "APPOINTMENT")
+	w.line("END", "VEVENT")
+}
+
+// writeVisit writes a supervision visit as a VEVENT; online visits carry the meeting link
+func (w *icalWriter) writeVisit(visit *models.VisitorSchedule) {
+	if visit.VisitAt == nil {
+		return
+	}
+
+	summary := fmt.Sprintf("Supervision visit #%d", visit.VisitNo)
+	if student := visit.Training.StudentEnroll.Student; student.ID != 0 {
+		summary += " - " + student.GetFullName()
+	}
+
+	var description []string
+	if visit.Comment != nil && *visit.Comment != "" {
+		description = append(description, *visit.Comment)
+	}
+
+	mode := visit.VisitMode
+	if mode == "" {
+		mode = models.VisitModeOnsite
+	}
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", fmt.Sprintf("visit-%d@%s", visit.ID, icalUIDDomain))
+	w.line("DTSTAMP", formatICalUTC(w.stamp))
+	w.line("LAST-MODIFIED", formatICalUTC(visit.UpdatedAt))
+	w.times(*visit.VisitAt, visit.VisitAt.Add(defaultVisitDuration), false)
+	w.text("SUMMARY", summary)
+	if visit.IsOnline() {
+		w.text("LOCATION", "Online")
+		if visit.MeetingURL != nil && *visit.MeetingURL != "" {
+			w.line("URL", *visit.MeetingURL)
+			description = append(description, "Meeting link: "+*visit.MeetingURL)
+		}
+	}
+	w.text("DESCRIPTION", strings.Join(description, "\n\n"))
+	w.line("STATUS", "CONFIRMED")
+	w.line("CATEGORIES", "VISIT,"+strings.ToUpper(string(mode)))
+	w.line("X-VISIT-MODE", string(mode))
+	w.line("END", "VEVENT")
+}
+
+// recurrenceDate writes a date-valued recurrence property such as EXDATE or RECURRENCE-ID
+func (w *icalWriter) recurrenceDate(name string, t time.Time, allDay bool) {
+	if allDay {
+		w.line(name+";VALUE=DATE", formatICalDate(t))
+		return
+	}
+	w.line(name, formatICalUTC(t))
+}
+
+// icalRRule derives an RRULE from the schedule's recurrence settings
+func icalRRule(schedule *models.Schedule) string {
+	var freq string
+	switch schedule.RecurrenceType {
+	case models.RecurrenceDaily:
+		freq = "DAILY"
+	case models.RecurrenceWeekly:
+		freq = "WEEKLY"
+	case models.RecurrenceMonthly:
+		freq = "MONTHLY"
+	case models.RecurrenceYearly:
+		freq = "YEARLY"
+	default:
+		return ""
+	}
+
+	rrule := "FREQ=" + freq
+	if schedule.RecurrenceEnd != nil {
+		if schedule.IsAllDay {
+			rrule += ";UNTIL=" + formatICalDate(*schedule.RecurrenceEnd)
+		} else {
+			rrule += ";UNTIL=" + formatICalUTC(*schedule.RecurrenceEnd)
+		}
+	}
+	return rrule
+}
+
+// icalScheduleStatus maps a schedule status to a VEVENT STATUS value
+func icalScheduleStatus(status models.ScheduleStatus) string {
+	switch status {
+	case models.ScheduleStatusCancelled:
+		return "CANCELLED"
+	case models.ScheduleStatusConfirmed, models.ScheduleStatusCompleted:
+		return "CONFIRMED"
+	default:
+		return "TENTATIVE"
+	}
+}
+
+// formatICalUTC formats a time as a UTC DATE-TIME value
+func formatICalUTC(t time.Time) string {
+	return t.UTC().Format(icalUTCFormat)
+}
+
+// formatICalDate formats a time as a DATE value
+func formatICalDate(t time.Time) string {
+	return t.Format(icalDateFormat)
+}
+
+// escapeICalText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return replacer.Replace(value)
+}
+
+// unescapeICalText reverses escapeICalText
+func unescapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return replacer.Replace(value)
+}
+
+// icalEvent is a VEVENT parsed from an imported .ics file
+type icalEvent struct {
+	UID            string
+	Summary        string
+	Description    string
+	Location       string
+	Status         string
+	Start          time.Time
+	End            time.Time
+	AllDay         bool
+	RecurrenceType models.RecurrenceType
+	RecurrenceEnd  *time.Time
+	RecurrenceID   *time.Time
+	ExDates        []time.Time
+	Warnings       []string
+}
+
+// icalProperty is a single unfolded content line
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseICal parses the VEVENTs of an iCalendar document
+func parseICal(r io.Reader) ([]icalEvent, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, errors.New("invalid iCalendar file")
+	}
+
+	var events []icalEvent
+	var properties []icalProperty
+	inEvent := false
+	depth := 0 // nesting inside the VEVENT, e.g. VALARM
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		property, ok := parseICalProperty(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case property.Name == "BEGIN" && strings.EqualFold(property.Value, "VEVENT") && !inEvent:
+			inEvent = true
+			properties = nil
+		case property.Name == "END" && strings.EqualFold(property.Value, "VEVENT") && inEvent && depth == 0:
+			inEvent = false
+			event, err := buildICalEvent(properties)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		case inEvent && property.Name == "BEGIN":
+			depth++
+		case inEvent && property.Name == "END":
+			depth--
+		case inEvent && depth == 0:
+			properties = append(properties, property)
+		}
+	}
+
+	if inEvent {
+		return nil, errors.New("invalid iCalendar file")
+	}
+
+	return events, nil
+}
+
+// unfoldICalLines reads content lines, joining folded continuation lines
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("invalid iCalendar file")
+	}
+
+	return lines, nil
+}
+
+// parseICalProperty splits a content line into name, parameters and value
+func parseICalProperty(line string) (icalProperty, bool) {
+	// The value starts at the first colon outside a quoted parameter value
+	inQuotes := false
+	split := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			split = i
+			break
+		}
+	}
+	if split < 0 {
+		return icalProperty{}, false
+	}
+
+	parts := strings.Split(line[:split], ";")
+	property := icalProperty{
+		Name:   strings.ToUpper(parts[0]),
+		Params: make(map[string]string),
+		Value:  line[split+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return property, true
+}
+
+// buildICalEvent converts the properties of a VEVENT into an icalEvent
+func buildICalEvent(properties []icalProperty) (icalEvent, error) {
+	var event icalEvent
+	var hasStart, hasEnd bool
+	var duration time.Duration
+	var rrule string
+
+	for _, property := range properties {
+		switch property.Name {
+		case "UID":
+			event.UID = strings.TrimSpace(property.Value)
+		case "SUMMARY":
+			event.Summary = unescapeICalText(property.Value)
+		case "DESCRIPTION":
+			event.Description = unescapeICalText(property.Value)
+		case "LOCATION":
+			event.Location = unescapeICalText(property.Value)
+		case "STATUS":
+			event.Status = strings.ToUpper(strings.TrimSpace(property.Value))
+		case "DTSTART":
+			start, allDay, err := parseICalTime(property)
+			if err != nil {
+				return event, err
+			}
+			event.Start, event.AllDay, hasStart = start, allDay, true
+		case "DTEND":
+			end, _, err := parseICalTime(property)
+			if err != nil {
+				return event, err
+			}
+			event.End, hasEnd = end, true
+		case "DURATION":
+			parsed, err := parseICalDuration(property.Value)
+			if err != nil {
+				return event, err
+			}
+			duration = parsed
+		case "RRULE":
+			rrule = property.Value
+		case "RECURRENCE-ID":
+			recurrenceID, _, err := parseICalTime(property)
+			if err != nil {
+				return event, err
+			}
+			event.RecurrenceID = &recurrenceID
+		case "EXDATE":
+			for _, value := range strings.Split(property.Value, ",") {
+				exdate, _, err := parseICalTime(icalProperty{Name: property.Name, Params: property.Params, Value: value})
+				if err != nil {
+					return event, err
+				}
+				event.ExDates = append(event.ExDates, exdate)
+			}
+		}
+	}
+
+	if !hasStart {
+		return event, errors.New("invalid iCalendar file: event without DTSTART")
+	}
+
+	if !hasEnd {
+		switch {
+		case duration > 0:
+			event.End = event.Start.Add(duration)
+		case event.AllDay:
+			event.End = event.Start.AddDate(0, 0, 1)
+		default:
+			event.End = event.Start.Add(defaultImportedEventDuration)
+		}
+	}
+
+	event.RecurrenceType = models.RecurrenceNone
+	if rrule != "" {
+		applyICalRRule(&event, rrule)
+	}
+
+	return event, nil
+}
+
+// applyICalRRule maps an RRULE onto the supported recurrence types.
+// Rules that cannot be represented import only their first occurrence.
+func applyICalRRule(event *icalEvent, rrule string) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rrule, ";") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			parts[strings.ToUpper(key)] = strings.ToUpper(value)
+		}
+	}
+
+	unsupported := func(reason string) {
+		event.Warnings = append(event.Warnings, fmt.Sprintf("%s: %s, only the first occurrence was imported", event.UID, reason))
+	}
+
+	switch parts["FREQ"] {
+	case "DAILY":
+		event.RecurrenceType = models.RecurrenceDaily
+	case "WEEKLY":
+		event.RecurrenceType = models.RecurrenceWeekly
+	case "MONTHLY":
+		event.RecurrenceType = models.RecurrenceMonthly
+	case "YEARLY":
+		event.RecurrenceType = models.RecurrenceYearly
+	default:
+		unsupported("unsupported recurrence frequency " + parts["FREQ"])
+		return
+	}
+
+	if interval, ok := parts["INTERVAL"]; ok && interval != "1" {
+		event.RecurrenceType = models.RecurrenceNone
+		unsupported("recurrence intervals are not supported")
+		return
+	}
+
+	// BY* rules are only accepted when they restate the start date
+	for key, value := range parts {
+		if !strings.HasPrefix(key, "BY") {
+			continue
+		}
+		redundant := (key == "BYDAY" && value == icalWeekday(event.Start)) ||
+			(key == "BYMONTHDAY" && value == strconv.Itoa(event.Start.Day())) ||
+			(key == "BYMONTH" && value == strconv.Itoa(int(event.Start.Month())))
+		if !redundant {
+			event.RecurrenceType = models.RecurrenceNone
+			unsupported("recurrence rule " + key + " is not supported")
+			return
+		}
+	}
+
+	if until, ok := parts["UNTIL"]; ok {
+		parsed, _, err := parseICalTime(icalProperty{Name: "UNTIL", Params: map[string]string{}, Value: until})
+		if err != nil {
+			event.RecurrenceType = models.RecurrenceNone
+			unsupported("invalid recurrence end")
+			return
+		}
+		event.RecurrenceEnd = &parsed
+	} else if count, ok := parts["COUNT"]; ok {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			event.RecurrenceType = models.RecurrenceNone
+			unsupported("invalid recurrence count")
+			return
+		}
+		series := models.Schedule{StartTime: event.Start, EndTime: event.End, RecurrenceType: event.RecurrenceType}
+		occurrences := series.Occurrences(event.Start, event.Start.AddDate(4*n, 0, 0))
+		if len(occurrences) > n {
+			occurrences = occurrences[:n]
+		}
+		if len(occurrences) > 0 {
+			last := occurrences[len(occurrences)-1]
+			event.RecurrenceEnd = &last
+		}
+	}
+}
+
+// icalWeekday returns the two-letter RRULE weekday of t
+func icalWeekday(t time.Time) string {
+	return [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[t.Weekday()]
+}
+
+// parseICalTime parses a DATE or DATE-TIME value, honouring TZID and VALUE=DATE
+func parseICalTime(property icalProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(property.Value)
+
+	if property.Params["VALUE"] == "DATE" || len(value) == len(icalDateFormat) {
+		t, err := time.ParseInLocation(icalDateFormat, value, time.UTC)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid iCalendar file: invalid %s value %q", property.Name, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalUTCFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid iCalendar file: invalid %s value %q", property.Name, value)
+		}
+		return t, false, nil
+	}
+
+	// Floating times and unknown time zones are read as UTC
+	location := time.UTC
+	if tzid := property.Params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation(icalDateTimeFormat, value, location)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid iCalendar file: invalid %s value %q", property.Name, value)
+	}
+	return t, false, nil
+}
+
+// parseICalDuration parses a DURATION value such as PT1H30M, P1D or P2W
+func parseICalDuration(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid iCalendar file: invalid DURATION value %q", value)
+
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "+")
+	if strings.HasPrefix(value, "-") || !strings.HasPrefix(value, "P") {
+		return 0, invalid
+	}
+	value = value[1:]
+
+	var duration time.Duration
+	inTime := false
+	number := ""
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+		case r == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, invalid
+			}
+			number = ""
+			switch {
+			case r == 'W' && !inTime:
+				duration += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D' && !inTime:
+				duration += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				duration += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				duration += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				duration += time.Duration(n) * time.Second
+			default:
+				return 0, invalid
+			}
+		}
+	}
+	if number != "" {
+		return 0, invalid
+	}
+
+	return duration, nil
+}
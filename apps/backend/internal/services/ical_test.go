@@ -0,0 +1,194 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICalWriter(t *testing.T) {
+	t.Run("Folds long lines without splitting UTF-8", func(t *testing.T) {
+		writer := newICalWriter("", "")
+		writer.text("SUMMARY", strings.Repeat("นิเทศนักศึกษา ", 10))
+		output := string(writer.bytes())
+
+		for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), icalLineLimit)
+			assert.True(t, strings.ToValidUTF8(line, "?") == line)
+		}
+	})
+
+	t.Run("Writes recurring schedule with exceptions", func(t *testing.T) {
+		start := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+		until := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+		moved := time.Date(2024, 6, 18, 13, 0, 0, 0, time.UTC)
+		movedEnd := moved.Add(time.Hour)
+		schedule := &models.Schedule{
+			ID:             7,
+			Title:          "Weekly check-in; project, review",
+			ScheduleType:   models.ScheduleTypeMeeting,
+			Status:         models.ScheduleStatusConfirmed,
+			StartTime:      start,
+			EndTime:        start.Add(time.Hour),
+			RecurrenceType: models.RecurrenceWeekly,
+			RecurrenceEnd:  &until,
+			Exceptions: []models.ScheduleException{
+				{OccurrenceStart: start.AddDate(0, 0, 7), IsCancelled: true},
+				{OccurrenceStart: start.AddDate(0, 0, 14), NewStartTime: &moved, NewEndTime: &movedEnd},
+			},
+		}
+
+		writer := newICalWriter("Test", "")
+		writer.writeSchedule(schedule)
+		output := string(writer.bytes())
+
+		assert.Contains(t, output, "UID:schedule-7@"+icalUIDDomain+"\r\n")
+		assert.Contains(t, output, "SUMMARY:Weekly check-in\\; project\\, review\r\n")
+		assert.Contains(t, output, "RRULE:FREQ=WEEKLY;UNTIL=20240701T090000Z\r\n")
+		assert.Contains(t, output, "EXDATE:20240610T090000Z\r\n")
+		assert.Contains(t, output, "RECURRENCE-ID:20240617T090000Z\r\nDTSTART:20240618T130000Z\r\n")
+		assert.Equal(t, 2, strings.Count(output, "BEGIN:VEVENT"))
+	})
+
+	t.Run("Writes online visit with meeting link", func(t *testing.T) {
+		visitAt := time.Date(2024, 6, 5, 2, 0, 0, 0, time.UTC)
+		url := "https://meet.example.com/abc"
+		visit := &models.VisitorSchedule{ID: 3, VisitNo: 2, VisitAt: &visitAt, VisitMode: models.VisitModeOnline, MeetingURL: &url}
+
+		writer := newICalWriter("", "")
+		writer.writeVisit(visit)
+		output := string(writer.bytes())
+
+		assert.Contains(t, output, "DTEND:20240605T040000Z\r\n")
+		assert.Contains(t, output, "LOCATION:Online\r\n")
+		assert.Contains(t, output, "URL:"+url+"\r\n")
+		assert.Contains(t, output, "X-VISIT-MODE:online\r\n")
+	})
+}
+
+func TestParseICal(t *testing.T) {
+	t.Run("Round trips exported schedules", func(t *testing.T) {
+		start := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+		schedule := &models.Schedule{
+			ID:             1,
+			Title:          "Monthly report\nsubmission",
+			StartTime:      start,
+			EndTime:        start.Add(30 * time.Minute),
+			RecurrenceType: models.RecurrenceMonthly,
+		}
+		writer := newICalWriter("", "")
+		writer.writeSchedule(schedule)
+
+		events, err := parseICal(strings.NewReader(string(writer.bytes())))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "Monthly report\nsubmission", events[0].Summary)
+		assert.True(t, events[0].Start.Equal(start))
+		assert.True(t, events[0].End.Equal(start.Add(30*time.Minute)))
+		assert.Equal(t, models.RecurrenceMonthly, events[0].RecurrenceType)
+		assert.Nil(t, events[0].RecurrenceEnd)
+	})
+
+	t.Run("Parses folded lines, TZID, DURATION and COUNT", func(t *testing.T) {
+		ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:abc@example.com\r\n" +
+			"SUMMARY:Company\r\n  orientation\r\n" +
+			"DTSTART;TZID=Asia/Bangkok:20240603T090000\r\nDURATION:PT1H30M\r\n" +
+			"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3\r\n" +
+			"BEGIN:VALARM\r\nTRIGGER:-PT15M\r\nEND:VALARM\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+		events, err := parseICal(strings.NewReader(ics))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		event := events[0]
+		assert.Equal(t, "Company orientation", event.Summary)
+		assert.True(t, event.Start.Equal(time.Date(2024, 6, 3, 2, 0, 0, 0, time.UTC)))
+		assert.Equal(t, 90*time.Minute, event.End.Sub(event.Start))
+		assert.Equal(t, models.RecurrenceWeekly, event.RecurrenceType)
+		require.NotNil(t, event.RecurrenceEnd)
+		assert.True(t, event.RecurrenceEnd.Equal(event.Start.AddDate(0, 0, 14)))
+		assert.Empty(t, event.Warnings)
+	})
+
+	t.Run("Falls back to a single occurrence for unsupported rules", func(t *testing.T) {
+		ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x\r\nDTSTART:20240603T090000Z\r\n" +
+			"RRULE:FREQ=WEEKLY;INTERVAL=2\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+		events, err := parseICal(strings.NewReader(ics))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.RecurrenceNone, events[0].RecurrenceType)
+		assert.Len(t, events[0].Warnings, 1)
+	})
+
+	t.Run("Rejects invalid files", func(t *testing.T) {
+		_, err := parseICal(strings.NewReader("not a calendar"))
+		assert.Error(t, err)
+
+		_, err = parseICal(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:x\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestICalSubscriptionFeed(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.Schedule{},
+		&models.ScheduleParticipant{},
+		&models.ScheduleNotification{},
+		&models.ScheduleException{},
+		&models.Appointment{},
+		&models.VisitorTraining{},
+		&models.VisitorSchedule{},
+		&models.CalendarSubscription{},
+	))
+
+	icalService := NewICalService(db)
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+
+	// A student and an instructor sharing a numeric ID each organize a schedule
+	for _, organizer := range []ScheduleAccount{
+		{UserID: 9101, UserType: string(AccountTypeStudent)},
+		{UserID: 9101, UserType: string(AccountTypeInstructor)},
+	} {
+		schedule, err := icalService.scheduleService.CreateSchedule(CreateScheduleRequest{
+			Title:        organizer.UserType + " meeting",
+			ScheduleType: models.ScheduleTypeMeeting,
+			StartTime:    start,
+			EndTime:      start.Add(time.Hour),
+			CreatedBy:    organizer.UserID,
+			CreatorType:  organizer.UserType,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			db.Where("schedule_id = ?", schedule.ID).Delete(&models.ScheduleParticipant{})
+			db.Unscoped().Delete(&models.Schedule{}, schedule.ID)
+		})
+	}
+
+	appointment, err := icalService.scheduleService.CreateAppointment(CreateAppointmentRequest{
+		Title:           "Student appointment",
+		AppointmentDate: start,
+		Duration:        30,
+		RequestedBy:     9101,
+		RequesterType:   string(AccountTypeStudent),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Unscoped().Delete(&models.Appointment{}, appointment.ID) })
+
+	subscription, err := icalService.GetSubscription(Account{Type: AccountTypeInstructor, ID: 9101})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Delete(&models.CalendarSubscription{}, subscription.ID) })
+
+	feed, err := icalService.SubscriptionFeed(subscription.Token)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(feed), "Instructor meeting")
+	assert.NotContains(t, string(feed), "User meeting")
+	assert.NotContains(t, string(feed), "Student appointment")
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"backend-go/internal/models"
 	"gorm.io/gorm"
@@ -106,6 +107,7 @@ type TrainingAttendanceRequest struct {
 	StudentTrainingID uint   `json:"student_training_id" validate:"required"`
 	AttendanceStatus string `json:"attendance_status" validate:"required,oneof=present absent excused late"`
 	AttendanceDate   string `json:"attendance_date" validate:"required"`
+	Hours            *float64 `json:"hours" validate:"omitempty,gt=0,lte=24"` // worked hours for present and late days, a standard workday when omitted
 	Notes            *string `json:"notes"`
 }
 
@@ -545,21 +547,25 @@ func (s *InstructorService) ManageInstructorGrade(req InstructorGradeRequest) (*
 		return nil, fmt.Errorf("database error: %w", err)
 	}
 
-	// For this implementation, we'll work with the existing StudentEnrollStatus structure
-	// In a real implementation, you might need to modify the model or create a separate grading table
-	// This is a simplified implementation that just updates the instructor assignment
+	// Only instructors assigned to the enrollment's course section may grade it
+	var assigned int64
+	err = s.db.Model(&models.CourseInstructor{}).
+		Where("course_section_id = ? AND instructor_id = ?", enrollment.CourseSectionID, req.InstructorID).
+		Count(&assigned).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if assigned == 0 {
+		return nil, errors.New("course section access denied")
+	}
+
+	// Letter grades are computed and published through the grading service's grade sheets,
+	// only the student's status under this instructor is tracked here
 	var enrollStatus models.StudentEnrollStatus
-	err = s.db.Where("instructor_id = ?", req.InstructorID).First(&enrollStatus).Error
+	err = s.db.Where("student_id = ? AND instructor_id = ?", enrollment.StudentID, req.InstructorID).First(&enrollStatus).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		// Create a basic status record - we need to get the student ID from enrollment
-		var student models.Student
-		err = s.db.Where("id = (SELECT student_id FROM student_enrolls WHERE id = ?)", req.StudentEnrollID).First(&student).Error
-		if err != nil {
-			return nil, fmt.Errorf("failed to find student: %w", err)
-		}
-		
 		enrollStatus = models.StudentEnrollStatus{
-			StudentID:    student.ID,
+			StudentID:    enrollment.StudentID,
 			Semester:     "1", // Default values
 			Year:         2024,
 			Status:       req.Status,
@@ -588,7 +594,8 @@ func (s *InstructorService) ManageInstructorGrade(req InstructorGradeRequest) (*
 	return &enrollStatus, nil
 }
 
-// RecordTrainingAttendance records attendance for instructor training
+// RecordTrainingAttendance records a student's attendance for a day of their training on the
+// instructor's behalf, as an entry on the training's weekly time sheet
 func (s *InstructorService) RecordTrainingAttendance(req TrainingAttendanceRequest) error {
 	// Check if instructor exists
 	var instructor models.Instructor
@@ -610,22 +617,32 @@ func (s *InstructorService) RecordTrainingAttendance(req TrainingAttendanceReque
 		return fmt.Errorf("database error: %w", err)
 	}
 
-	// For now, we'll store this information in a simple way
-	// In a real implementation, you might want to create a separate attendance table
-	// This is a simplified implementation that updates the training notes
-	notes := fmt.Sprintf("Instructor %d attendance: %s on %s", req.InstructorID, req.AttendanceStatus, req.AttendanceDate)
+	entryReq := RecordAttendanceRequest{
+		WorkDate:     req.AttendanceDate,
+		EntryType:    models.AttendanceEntryWork,
+		Hours:        req.Hours,
+		RecordedBy:   &instructor.UserID,
+		RecorderType: "User",
+	}
 	if req.Notes != nil {
-		notes += " - " + *req.Notes
+		entryReq.Notes = *req.Notes
 	}
 
-	// Update training with attendance information
-	err = s.db.Model(&training).Update("job_description", 
-		training.JobDescription + "\n[Attendance] " + notes).Error
-	if err != nil {
-		return fmt.Errorf("failed to record attendance: %w", err)
+	switch req.AttendanceStatus {
+	case "absent":
+		entryReq.EntryType = models.AttendanceEntryAbsent
+		entryReq.Hours = nil
+	case "excused":
+		leaveType := models.LeaveTypePersonal
+		entryReq.EntryType = models.AttendanceEntryLeave
+		entryReq.LeaveType = &leaveType
+		entryReq.Hours = nil
+	case "late":
+		entryReq.Notes = strings.TrimSpace("Late. " + entryReq.Notes)
 	}
 
-	return nil
+	_, err = NewAttendanceService(s.db, AttendanceSettings{}).RecordEntry(training.ID, entryReq)
+	return err
 }
 
 // GetInstructorStats retrieves instructor statistics
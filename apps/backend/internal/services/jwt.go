@@ -23,6 +23,7 @@ const (
 	TokenTypeRefresh       TokenType = "refresh"
 	TokenTypePasswordReset TokenType = "password_reset"
 	TokenTypeEmailVerify   TokenType = "email_verify"
+	TokenTypeTwoFactor     TokenType = "two_factor" // short-lived login challenge awaiting a TOTP code
 )
 
 // UserType represents the type of user for polymorphic relationships
@@ -49,6 +50,7 @@ type JWTClaims struct {
 type TokenVerificationResult struct {
 	IsValid   bool                    `json:"is_valid"`
 	User      interface{}             `json:"user,omitempty"`      // *models.User or *models.SuperAdmin
+	Account   *Account                `json:"account,omitempty"`   // linked record of the user, nil when there is none
 	Claims    *JWTClaims             `json:"claims,omitempty"`
 	Token     *models.AccessToken    `json:"token,omitempty"`
 	Abilities []string               `json:"abilities"`
@@ -66,6 +68,7 @@ type JWTConfig struct {
 	RememberTokenTTL time.Duration
 	ResetTokenTTL   time.Duration
 	VerifyTokenTTL  time.Duration
+	TwoFactorTokenTTL time.Duration
 }
 
 // JWTService handles enhanced JWT token operations
@@ -87,6 +90,7 @@ func NewJWTService(config *JWTConfig, db *gorm.DB) *JWTService {
 			RememberTokenTTL: 30 * 24 * time.Hour,
 			ResetTokenTTL:   1 * time.Hour,
 			VerifyTokenTTL:  24 * time.Hour,
+			TwoFactorTokenTTL: 5 * time.Minute,
 		}
 	}
 
@@ -137,6 +141,13 @@ func (j *JWTService) generateToken(userID string, userType UserType, email strin
 	case TokenTypeEmailVerify:
 		expiresAt = time.Now().Add(j.config.VerifyTokenTTL)
 		tokenName = "email_verify_token"
+	case TokenTypeTwoFactor:
+		ttl := j.config.TwoFactorTokenTTL
+		if ttl <= 0 {
+			ttl = 5 * time.Minute
+		}
+		expiresAt = time.Now().Add(ttl)
+		tokenName = "two_factor_token"
 	default:
 		return nil, errors.New("invalid token type")
 	}
@@ -239,7 +250,7 @@ func (j *JWTService) ValidateToken(tokenString string) (*TokenVerificationResult
 	switch claims.UserType {
 	case UserTypeStudent:
 		var studentUser models.User
-		if err := j.db.Where("student_id = ?", claims.UserID).First(&studentUser).Error; err != nil {
+		if err := j.db.Preload("Student").Where("student_id = ?", claims.UserID).First(&studentUser).Error; err != nil {
 			result.Error = "user not found"
 			return result, errors.New("user not found")
 		}
@@ -256,6 +267,15 @@ func (j *JWTService) ValidateToken(tokenString string) (*TokenVerificationResult
 		return result, errors.New("invalid user type")
 	}
 
+	account, linked, err := ResolveAccount(j.db, claims.UserType, claims.UserID)
+	if err != nil {
+		result.Error = fmt.Sprintf("database error: %v", err)
+		return result, err
+	}
+	if linked {
+		result.Account = &account
+	}
+
 	// Parse abilities from access token
 	var abilities []string
 	if accessToken.Abilities != "" {
@@ -360,6 +380,12 @@ func (j *JWTService) GenerateEmailVerificationToken(userID string, userType User
 	return j.generateToken(userID, userType, email, TokenTypeEmailVerify, abilities, false, "")
 }
 
+// GenerateTwoFactorChallengeToken generates a token proving the password step of a 2FA login
+func (j *JWTService) GenerateTwoFactorChallengeToken(userID string, userType UserType, email string) (*models.AccessToken, error) {
+	abilities := []string{"2fa:verify"}
+	return j.generateToken(userID, userType, email, TokenTypeTwoFactor, abilities, false, "")
+}
+
 // generateTokenID generates a unique token ID for JWT ID claim
 func (j *JWTService) generateTokenID() (string, error) {
 	bytes := make([]byte, 16)
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// Mailer sends plain-text email
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPSettings holds the SMTP server outgoing email is sent through
+type SMTPSettings struct {
+	Host     string
+	Port     int
+	Username string // PLAIN authentication is used when set
+	Password string
+	From     string
+}
+
+// SMTPMailer sends email through an SMTP server
+type SMTPMailer struct {
+	settings SMTPSettings
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(settings SMTPSettings) *SMTPMailer {
+	if settings.Port == 0 {
+		settings.Port = 587
+	}
+	return &SMTPMailer{settings: settings}
+}
+
+// Send sends a UTF-8 plain-text message to a single recipient
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	if to == "" {
+		return errors.New("email recipient is required")
+	}
+	// Header values must not carry line breaks, they would inject extra headers
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return errors.New("invalid email header")
+	}
+
+	var auth smtp.Auth
+	if m.settings.Username != "" {
+		auth = smtp.PlainAuth("", m.settings.Username, m.settings.Password, m.settings.Host)
+	}
+
+	addr := net.JoinHostPort(m.settings.Host, strconv.Itoa(m.settings.Port))
+	if err := smtp.SendMail(addr, auth, m.settings.From, []string{to}, buildMailMessage(m.settings.From, to, subject, body)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// buildMailMessage formats the headers and body of a plain-text message, encoding the subject for Thai text
+func buildMailMessage(from, to, subject, body string) []byte {
+	var msg strings.Builder
+	msg.WriteString("From: " + from + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(msg.String())
+}
@@ -0,0 +1,614 @@
+package services
+
+import (
+	"backend-go/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MetricType identifies how the values of a metric are aggregated
+type MetricType string
+
+const (
+	// MetricTypeCounter values are increments; a bucket reports their sum
+	MetricTypeCounter MetricType = "counter"
+	// MetricTypeGauge values are point-in-time readings; a bucket reports their average
+	MetricTypeGauge MetricType = "gauge"
+	// MetricTypeHistogram values are individual observations; a bucket reports their distribution
+	MetricTypeHistogram MetricType = "histogram"
+)
+
+// metricQueryTimeout bounds how long a scheduled metric query may run
+const metricQueryTimeout = 30 * time.Second
+
+// metricNamePattern restricts metric names to Prometheus-compatible identifiers
+var metricNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// metricBucketIntervals lists the downsampling intervals accepted by GetMetricValues
+var metricBucketIntervals = map[string]bool{
+	"hour":  true,
+	"day":   true,
+	"month": true,
+}
+
+// MetricService manages the business metric registry and its time series
+type MetricService struct {
+	db *gorm.DB
+}
+
+// CreateMetricRequest represents the request for defining a metric. A metric with a Query and a
+// CollectInterval is computed on a schedule; the query must be a single SELECT returning a numeric
+// "value" column, every other column becomes a label.
+type CreateMetricRequest struct {
+	Name            string          `json:"name" validate:"required,max=100"`
+	DisplayName     string          `json:"display_name" validate:"required,max=255"`
+	Description     string          `json:"description"`
+	MetricType      MetricType      `json:"metric_type" validate:"required,oneof=counter gauge histogram"`
+	Unit            string          `json:"unit" validate:"max=50"`
+	Query           string          `json:"query"`
+	CollectInterval int             `json:"collect_interval" validate:"min=0"`
+	Config          json.RawMessage `json:"config"`
+}
+
+// MetricListRequest represents the request for listing metrics
+type MetricListRequest struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	MetricType string `json:"metric_type"`
+	Search     string `json:"search"`
+	ActiveOnly bool   `json:"active_only"`
+}
+
+// MetricListResponse represents the response for listing metrics
+type MetricListResponse struct {
+	Data       []models.Metric `json:"data"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+}
+
+// RecordMetricValueRequest represents the request for recording a metric value
+type RecordMetricValueRequest struct {
+	Value     *float64          `json:"value" validate:"required"`
+	Labels    map[string]string `json:"labels"`
+	Timestamp *time.Time        `json:"timestamp"`
+}
+
+// MetricValuesRequest represents a time series query. Values are downsampled into Interval buckets,
+// restricted to values carrying all of Labels and split into one series per value of the GroupBy label.
+type MetricValuesRequest struct {
+	StartDate time.Time         `json:"start_date"`
+	EndDate   time.Time         `json:"end_date"`
+	Interval  string            `json:"interval"` // hour, day or month
+	Labels    map[string]string `json:"labels"`
+	GroupBy   string            `json:"group_by"`
+}
+
+// MetricValuesResponse is the downsampled time series of a metric
+type MetricValuesResponse struct {
+	Metric    models.Metric  `json:"metric"`
+	Interval  string         `json:"interval"`
+	StartDate time.Time      `json:"start_date"`
+	EndDate   time.Time      `json:"end_date"`
+	GroupBy   string         `json:"group_by,omitempty"`
+	Series    []MetricSeries `json:"series"`
+}
+
+// MetricSeries is one line of a metric chart
+type MetricSeries struct {
+	Group  string        `json:"group,omitempty"` // value of the group_by label
+	Points []MetricPoint `json:"points"`
+}
+
+// MetricPoint is one downsampled bucket. Value is the sum for counters and the average otherwise.
+type MetricPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Count     int64     `json:"count"`
+	Sum       float64   `json:"sum"`
+	Min       float64   `json:"min"`
+	Max       float64   `json:"max"`
+	P50       *float64  `json:"p50,omitempty"`
+	P95       *float64  `json:"p95,omitempty"`
+	P99       *float64  `json:"p99,omitempty"`
+}
+
+// metricBucketRow is one row of the downsampling query
+type metricBucketRow struct {
+	Bucket time.Time
+	Series string
+	Count  int64
+	Sum    float64
+	Avg    float64
+	Min    float64
+	Max    float64
+	P50    *float64
+	P95    *float64
+	P99    *float64
+}
+
+// NewMetricService creates a new metric service instance
+func NewMetricService(db *gorm.DB) *MetricService {
+	return &MetricService{db: db}
+}
+
+// GetMetrics retrieves the metric registry
+func (s *MetricService) GetMetrics(req MetricListRequest) (*MetricListResponse, error) {
+	var metrics []models.Metric
+	var total int64
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	query := s.db.Model(&models.Metric{})
+	if req.MetricType != "" {
+		query = query.Where("metric_type = ?", req.MetricType)
+	}
+	if req.ActiveOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if req.Search != "" {
+		query = query.Where("(name ILIKE ? OR display_name ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("name ASC").Offset(offset).Limit(req.Limit).Find(&metrics).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &MetricListResponse{
+		Data:       metrics,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetMetric retrieves a metric by name
+func (s *MetricService) GetMetric(name string) (*models.Metric, error) {
+	var metric models.Metric
+	if err := s.db.Where("name = ?", name).First(&metric).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("metric not found")
+		}
+		return nil, err
+	}
+	return &metric, nil
+}
+
+// CreateMetric defines a new metric
+func (s *MetricService) CreateMetric(req CreateMetricRequest) (*models.Metric, error) {
+	if err := validateMetricDefinition(req); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Metric{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("metric already exists")
+	}
+
+	metric := models.Metric{
+		Name:            req.Name,
+		DisplayName:     req.DisplayName,
+		Description:     req.Description,
+		MetricType:      string(req.MetricType),
+		Unit:            req.Unit,
+		Query:           strings.TrimSpace(req.Query),
+		Config:          req.Config,
+		IsActive:        true,
+		CollectInterval: req.CollectInterval,
+	}
+	if err := s.db.Create(&metric).Error; err != nil {
+		return nil, err
+	}
+
+	return &metric, nil
+}
+
+// RecordValue stores a value of an active metric
+func (s *MetricService) RecordValue(name string, req RecordMetricValueRequest) (*models.MetricValue, error) {
+	metric, err := s.GetMetric(name)
+	if err != nil {
+		return nil, err
+	}
+	if !metric.IsActive {
+		return nil, errors.New("metric is inactive")
+	}
+	if req.Value == nil {
+		return nil, errors.New("invalid metric value: value is required")
+	}
+	if MetricType(metric.MetricType) == MetricTypeCounter && *req.Value < 0 {
+		return nil, errors.New("invalid metric value: counter increments cannot be negative")
+	}
+
+	labels, err := encodeMetricLabels(req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now()
+	if req.Timestamp != nil {
+		timestamp = *req.Timestamp
+	}
+
+	value := models.MetricValue{
+		MetricID:  metric.ID,
+		Value:     *req.Value,
+		Labels:    labels,
+		Timestamp: timestamp,
+	}
+	if err := s.db.Create(&value).Error; err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+// GetValues returns the downsampled time series of a metric
+func (s *MetricService) GetValues(name string, req MetricValuesRequest) (*MetricValuesResponse, error) {
+	metric, err := s.GetMetric(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+	if !metricBucketIntervals[req.Interval] {
+		return nil, fmt.Errorf("invalid metric query: unsupported interval %q", req.Interval)
+	}
+	if req.EndDate.IsZero() {
+		req.EndDate = time.Now()
+	}
+	if req.StartDate.IsZero() {
+		req.StartDate = req.EndDate.AddDate(0, 0, -30)
+	}
+	if req.EndDate.Before(req.StartDate) {
+		return nil, errors.New("invalid metric query: end date must be after start date")
+	}
+	for key := range req.Labels {
+		if !metricNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid metric query: invalid label name %q", key)
+		}
+	}
+	if req.GroupBy != "" && !metricNamePattern.MatchString(req.GroupBy) {
+		return nil, fmt.Errorf("invalid metric query: invalid label name %q", req.GroupBy)
+	}
+
+	rows, err := s.queryBuckets(metric, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetricValuesResponse{
+		Metric:    *metric,
+		Interval:  req.Interval,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		GroupBy:   req.GroupBy,
+		Series:    buildMetricSeries(MetricType(metric.MetricType), rows),
+	}, nil
+}
+
+// queryBuckets downsamples the values of a metric in the database.
+// Gauge readings taken at the same instant are summed first, so an ungrouped gauge recorded per
+// label set (e.g. per faculty) charts the overall total rather than the average of its parts.
+func (s *MetricService) queryBuckets(metric *models.Metric, req MetricValuesRequest) ([]metricBucketRow, error) {
+	where := "metric_id = ? AND timestamp BETWEEN ? AND ?"
+	args := []interface{}{req.GroupBy, metric.ID, req.StartDate, req.EndDate}
+	if len(req.Labels) > 0 {
+		filter, err := json.Marshal(req.Labels)
+		if err != nil {
+			return nil, err
+		}
+		where += " AND labels::jsonb @> ?::jsonb"
+		args = append(args, string(filter))
+	}
+
+	samples := "SELECT timestamp, COALESCE(labels::jsonb ->> ?, '') AS series, value FROM metric_values WHERE " + where
+	if MetricType(metric.MetricType) == MetricTypeGauge {
+		samples = "SELECT timestamp, series, SUM(value) AS value FROM (" + samples + ") AS readings GROUP BY timestamp, series"
+	}
+
+	percentiles := "NULL AS p50, NULL AS p95, NULL AS p99"
+	if MetricType(metric.MetricType) == MetricTypeHistogram {
+		percentiles = "percentile_cont(0.5) WITHIN GROUP (ORDER BY value) AS p50, " +
+			"percentile_cont(0.95) WITHIN GROUP (ORDER BY value) AS p95, " +
+			"percentile_cont(0.99) WITHIN GROUP (ORDER BY value) AS p99"
+	}
+
+	var rows []metricBucketRow
+	err := s.db.Raw(
+		"SELECT date_trunc(?, timestamp) AS bucket, series, COUNT(*) AS count, SUM(value) AS sum, AVG(value) AS avg, "+
+			"MIN(value) AS min, MAX(value) AS max, "+percentiles+
+			" FROM ("+samples+") AS samples GROUP BY bucket, series ORDER BY series, bucket",
+		append([]interface{}{req.Interval}, args...)...,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CollectDue runs the queries of scheduled metrics whose interval has elapsed and returns how many
+// were collected. A failing query does not stop the others.
+func (s *MetricService) CollectDue(ctx context.Context, now time.Time) (int, error) {
+	var metrics []models.Metric
+	if err := s.db.Where("is_active = ? AND collect_interval > 0 AND query <> ''", true).Find(&metrics).Error; err != nil {
+		return 0, err
+	}
+
+	collected := 0
+	var failures []string
+	for i := range metrics {
+		metric := &metrics[i]
+		if !metricCollectionDue(metric, now) {
+			continue
+		}
+
+		claimed, err := s.claimCollection(metric, now)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", metric.Name, err))
+			continue
+		}
+		if !claimed {
+			continue // collected by another instance since it was loaded
+		}
+
+		if err := s.collect(ctx, metric, now); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", metric.Name, err))
+			continue
+		}
+		collected++
+	}
+
+	if len(failures) > 0 {
+		return collected, fmt.Errorf("failed to collect metrics: %s", strings.Join(failures, "; "))
+	}
+	return collected, nil
+}
+
+// claimCollection marks a metric as collected at now unless another run got there first
+func (s *MetricService) claimCollection(metric *models.Metric, now time.Time) (bool, error) {
+	query := s.db.Model(&models.Metric{}).Where("id = ?", metric.ID)
+	if metric.LastCollectedAt == nil {
+		query = query.Where("last_collected_at IS NULL")
+	} else {
+		query = query.Where("last_collected_at = ?", *metric.LastCollectedAt)
+	}
+
+	result := query.Update("last_collected_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// collect runs a metric's query in a read-only transaction and stores one value per returned row
+func (s *MetricService) collect(ctx context.Context, metric *models.Metric, now time.Time) error {
+	if err := validateMetricQuery(metric.Query); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, metricQueryTimeout)
+	defer cancel()
+
+	var values []models.MetricValue
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SET TRANSACTION READ ONLY").Error; err != nil {
+				return err
+			}
+		}
+
+		rows, err := tx.Raw(metric.Query).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			row := make(map[string]interface{})
+			if err := tx.ScanRows(rows, &row); err != nil {
+				return err
+			}
+
+			value, labels, err := metricSampleFromRow(row)
+			if err != nil {
+				return err
+			}
+			encoded, err := encodeMetricLabels(labels)
+			if err != nil {
+				return err
+			}
+			values = append(values, models.MetricValue{MetricID: metric.ID, Value: value, Labels: encoded, Timestamp: now})
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+	return s.db.CreateInBatches(values, 500).Error
+}
+
+// validateMetricDefinition checks a metric definition before it is stored
+func validateMetricDefinition(req CreateMetricRequest) error {
+	if !metricNamePattern.MatchString(req.Name) {
+		return errors.New("invalid metric definition: name must be lowercase letters, digits and underscores")
+	}
+
+	switch req.MetricType {
+	case MetricTypeCounter, MetricTypeGauge, MetricTypeHistogram:
+	default:
+		return fmt.Errorf("invalid metric definition: unsupported metric type %q", req.MetricType)
+	}
+
+	if len(req.Config) > 0 && !json.Valid(req.Config) {
+		return errors.New("invalid metric definition: config must be valid JSON")
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		if req.CollectInterval > 0 {
+			return errors.New("invalid metric definition: a collect interval requires a query")
+		}
+		return nil
+	}
+	if req.CollectInterval > 0 && req.CollectInterval < 60 {
+		return errors.New("invalid metric definition: collect interval must be at least 60 seconds")
+	}
+	return validateMetricQuery(req.Query)
+}
+
+// validateMetricQuery accepts a single SELECT statement. Queries also run in a read-only
+// transaction, this check only rejects obvious mistakes early.
+func validateMetricQuery(query string) error {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	lower := strings.ToLower(query)
+
+	if !strings.HasPrefix(lower, "select") && !strings.HasPrefix(lower, "with") {
+		return errors.New("invalid metric definition: query must be a SELECT statement")
+	}
+	if strings.Contains(query, ";") {
+		return errors.New("invalid metric definition: query must be a single statement")
+	}
+	return nil
+}
+
+// metricCollectionDue reports whether a scheduled metric should be collected at now
+func metricCollectionDue(metric *models.Metric, now time.Time) bool {
+	if metric.CollectInterval <= 0 {
+		return false
+	}
+	if metric.LastCollectedAt == nil {
+		return true
+	}
+	return !now.Before(metric.LastCollectedAt.Add(time.Duration(metric.CollectInterval) * time.Second))
+}
+
+// metricSampleFromRow splits a query result row into its "value" column and its labels
+func metricSampleFromRow(row map[string]interface{}) (float64, map[string]string, error) {
+	raw, ok := row["value"]
+	if !ok {
+		return 0, nil, errors.New("metric query must return a value column")
+	}
+
+	var value float64
+	switch v := raw.(type) {
+	case float64:
+		value = v
+	case float32:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case int32:
+		value = float64(v)
+	case int:
+		value = float64(v)
+	case []byte:
+		if _, err := fmt.Sscan(string(v), &value); err != nil {
+			return 0, nil, fmt.Errorf("metric query value is not numeric: %s", v)
+		}
+	case string:
+		if _, err := fmt.Sscan(v, &value); err != nil {
+			return 0, nil, fmt.Errorf("metric query value is not numeric: %s", v)
+		}
+	case nil:
+		value = 0
+	default:
+		return 0, nil, fmt.Errorf("metric query value is not numeric: %v", v)
+	}
+
+	labels := make(map[string]string, len(row)-1)
+	for column, v := range row {
+		if column == "value" || v == nil {
+			continue
+		}
+		if b, ok := v.([]byte); ok {
+			labels[column] = string(b)
+		} else {
+			labels[column] = fmt.Sprint(v)
+		}
+	}
+	return value, labels, nil
+}
+
+// encodeMetricLabels validates label names and encodes labels for storage
+func encodeMetricLabels(labels map[string]string) (json.RawMessage, error) {
+	if len(labels) == 0 {
+		return json.RawMessage(`{}`), nil
+	}
+	for key := range labels {
+		if !metricNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid metric value: invalid label name %q", key)
+		}
+	}
+	return json.Marshal(labels)
+}
+
+// buildMetricSeries groups downsampled rows into series ordered by group, each ordered by time
+func buildMetricSeries(metricType MetricType, rows []metricBucketRow) []MetricSeries {
+	series := []MetricSeries{}
+	index := make(map[string]int)
+
+	for _, row := range rows {
+		i, ok := index[row.Series]
+		if !ok {
+			i = len(series)
+			index[row.Series] = i
+			series = append(series, MetricSeries{Group: row.Series})
+		}
+
+		point := MetricPoint{
+			Timestamp: row.Bucket,
+			Value:     row.Avg,
+			Count:     row.Count,
+			Sum:       row.Sum,
+			Min:       row.Min,
+			Max:       row.Max,
+			P50:       row.P50,
+			P95:       row.P95,
+			P99:       row.P99,
+		}
+		if metricType == MetricTypeCounter {
+			point.Value = row.Sum
+		}
+		series[i].Points = append(series[i].Points, point)
+	}
+
+	sort.SliceStable(series, func(a, b int) bool { return series[a].Group < series[b].Group })
+	for i := range series {
+		points := series[i].Points
+		sort.SliceStable(points, func(a, b int) bool { return points[a].Timestamp.Before(points[b].Timestamp) })
+	}
+	return series
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricService(t *testing.T) {
+	t.Run("metric definitions are validated before they are stored", func(t *testing.T) {
+		valid := CreateMetricRequest{Name: "active_interns", DisplayName: "Active Interns", MetricType: MetricTypeGauge}
+		assert.NoError(t, validateMetricDefinition(valid))
+
+		invalid := valid
+		invalid.Name = "Active Interns"
+		assert.EqualError(t, validateMetricDefinition(invalid), "invalid metric definition: name must be lowercase letters, digits and underscores")
+
+		invalid = valid
+		invalid.MetricType = "summary"
+		assert.EqualError(t, validateMetricDefinition(invalid), `invalid metric definition: unsupported metric type "summary"`)
+
+		invalid = valid
+		invalid.CollectInterval = 3600
+		assert.EqualError(t, validateMetricDefinition(invalid), "invalid metric definition: a collect interval requires a query")
+
+		scheduled := valid
+		scheduled.Query = "SELECT faculty_name AS faculty, COUNT(*) AS value FROM student_trainings GROUP BY faculty_name;"
+		scheduled.CollectInterval = 3600
+		assert.NoError(t, validateMetricDefinition(scheduled))
+
+		scheduled.CollectInterval = 10
+		assert.EqualError(t, validateMetricDefinition(scheduled), "invalid metric definition: collect interval must be at least 60 seconds")
+	})
+
+	t.Run("only single SELECT queries are accepted", func(t *testing.T) {
+		assert.NoError(t, validateMetricQuery("WITH t AS (SELECT 1 AS value) SELECT value FROM t"))
+		assert.EqualError(t, validateMetricQuery("DELETE FROM metrics"), "invalid metric definition: query must be a SELECT statement")
+		assert.EqualError(t, validateMetricQuery("SELECT 1 AS value; DROP TABLE metrics"), "invalid metric definition: query must be a single statement")
+	})
+
+	t.Run("query rows split into value and labels", func(t *testing.T) {
+		value, labels, err := metricSampleFromRow(map[string]interface{}{
+			"value":   int64(42),
+			"faculty": "Engineering",
+			"year":    []byte("2026"),
+			"major":   nil,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 42.0, value)
+		assert.Equal(t, map[string]string{"faculty": "Engineering", "year": "2026"}, labels)
+
+		value, _, err = metricSampleFromRow(map[string]interface{}{"value": []byte("3.25")})
+		require.NoError(t, err)
+		assert.Equal(t, 3.25, value)
+
+		_, _, err = metricSampleFromRow(map[string]interface{}{"count": int64(1)})
+		assert.EqualError(t, err, "metric query must return a value column")
+	})
+
+	t.Run("label names must be identifiers", func(t *testing.T) {
+		encoded, err := encodeMetricLabels(map[string]string{"faculty": "Science"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"faculty":"Science"}`, string(encoded))
+
+		_, err = encodeMetricLabels(map[string]string{"faculty name": "Science"})
+		assert.EqualError(t, err, `invalid metric value: invalid label name "faculty name"`)
+	})
+
+	t.Run("collection is due once the interval has elapsed", func(t *testing.T) {
+		now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+		last := now.Add(-30 * time.Minute)
+
+		assert.True(t, metricCollectionDue(&models.Metric{CollectInterval: 3600}, now))
+		assert.False(t, metricCollectionDue(&models.Metric{CollectInterval: 3600, LastCollectedAt: &last}, now))
+		assert.True(t, metricCollectionDue(&models.Metric{CollectInterval: 1800, LastCollectedAt: &last}, now))
+		assert.False(t, metricCollectionDue(&models.Metric{}, now))
+	})
+
+	t.Run("buckets are grouped into series and counters report sums", func(t *testing.T) {
+		may := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+		june := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+		rows := []metricBucketRow{
+			{Bucket: june, Series: "Science", Count: 2, Sum: 10, Avg: 5},
+			{Bucket: may, Series: "Engineering", Count: 1, Sum: 7, Avg: 7},
+			{Bucket: may, Series: "Science", Count: 4, Sum: 8, Avg: 2},
+		}
+
+		series := buildMetricSeries(MetricTypeCounter, rows)
+		require.Len(t, series, 2)
+		assert.Equal(t, "Engineering", series[0].Group)
+		assert.Equal(t, "Science", series[1].Group)
+		require.Len(t, series[1].Points, 2)
+		assert.Equal(t, may, series[1].Points[0].Timestamp)
+		assert.Equal(t, 8.0, series[1].Points[0].Value)
+
+		series = buildMetricSeries(MetricTypeGauge, rows)
+		assert.Equal(t, 2.0, series[1].Points[0].Value)
+
+		assert.Empty(t, buildMetricSeries(MetricTypeGauge, nil))
+	})
+}
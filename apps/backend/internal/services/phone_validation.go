@@ -0,0 +1,25 @@
+package services
+
+import (
+	"strings"
+)
+
+// NormalizePhoneNumber removes the separators commonly typed in phone numbers
+func NormalizePhoneNumber(phone string) string {
+	return strings.NewReplacer("-", "", " ", "", "(", "", ")", "").Replace(phone)
+}
+
+// IsValidPhoneNumber reports whether phone is a Thai phone number: 10 digits starting with 0,
+// separators allowed
+func IsValidPhoneNumber(phone string) bool {
+	phone = NormalizePhoneNumber(phone)
+	if len(phone) != 10 || phone[0] != '0' {
+		return false
+	}
+	for _, char := range phone[1:] {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// httpLatencyBuckets are the upper bounds, in seconds, of the request latency histogram
+var httpLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HTTPMetrics collects request counts and latency histograms by method, route and status
+// for the Prometheus endpoint. It is safe for concurrent use.
+type HTTPMetrics struct {
+	mu     sync.Mutex
+	series map[HTTPSeriesKey]*httpSeries
+}
+
+// HTTPSeriesKey identifies one request series. Route is the registered route pattern, not the
+// request path, so IDs in paths do not create new series.
+type HTTPSeriesKey struct {
+	Method string
+	Route  string
+	Status string
+}
+
+// HTTPSeriesSnapshot is a point-in-time copy of one request series.
+// BucketCounts are cumulative and aligned with the latency buckets.
+type HTTPSeriesSnapshot struct {
+	HTTPSeriesKey
+	Count        uint64
+	LatencySum   float64
+	BucketCounts []uint64
+}
+
+type httpSeries struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+var globalHTTPMetrics = NewHTTPMetrics()
+
+// NewHTTPMetrics creates an empty request metrics collector
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{series: make(map[HTTPSeriesKey]*httpSeries)}
+}
+
+// GetHTTPMetrics returns the process-wide request metrics collector
+func GetHTTPMetrics() *HTTPMetrics {
+	return globalHTTPMetrics
+}
+
+// Observe records one finished request
+func (m *HTTPMetrics) Observe(method, route string, status int, latency time.Duration) {
+	key := HTTPSeriesKey{Method: method, Route: route, Status: strconv.Itoa(status)}
+	seconds := latency.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series, ok := m.series[key]
+	if !ok {
+		series = &httpSeries{buckets: make([]uint64, len(httpLatencyBuckets))}
+		m.series[key] = series
+	}
+
+	series.count++
+	series.sum += seconds
+	for i, bound := range httpLatencyBuckets {
+		if seconds <= bound {
+			series.buckets[i]++
+		}
+	}
+}
+
+// Snapshot returns a copy of every request series, sorted by route, method and status
+func (m *HTTPMetrics) Snapshot() []HTTPSeriesSnapshot {
+	m.mu.Lock()
+	snapshot := make([]HTTPSeriesSnapshot, 0, len(m.series))
+	for key, series := range m.series {
+		buckets := make([]uint64, len(series.buckets))
+		copy(buckets, series.buckets)
+		snapshot = append(snapshot, HTTPSeriesSnapshot{
+			HTTPSeriesKey: key,
+			Count:         series.count,
+			LatencySum:    series.sum,
+			BucketCounts:  buckets,
+		})
+	}
+	m.mu.Unlock()
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		a, b := snapshot[i], snapshot[j]
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Status < b.Status
+	})
+	return snapshot
+}
+
+// WritePrometheus writes the request counter and latency histogram families
+func (m *HTTPMetrics) WritePrometheus(w *PrometheusWriter) {
+	snapshot := m.Snapshot()
+
+	w.Family("http_requests_total", "Total HTTP requests by method, route and status.", "counter")
+	for _, series := range snapshot {
+		w.Sample("http_requests_total", float64(series.Count),
+			"method", series.Method, "route", series.Route, "status", series.Status)
+	}
+
+	w.Family("http_request_duration_seconds", "HTTP request latency by method, route and status.", "histogram")
+	for _, series := range snapshot {
+		for i, bound := range httpLatencyBuckets {
+			w.Sample("http_request_duration_seconds_bucket", float64(series.BucketCounts[i]),
+				"method", series.Method, "route", series.Route, "status", series.Status, "le", formatPrometheusValue(bound))
+		}
+		w.Sample("http_request_duration_seconds_bucket", float64(series.Count),
+			"method", series.Method, "route", series.Route, "status", series.Status, "le", "+Inf")
+		w.Sample("http_request_duration_seconds_sum", series.LatencySum,
+			"method", series.Method, "route", series.Route, "status", series.Status)
+		w.Sample("http_request_duration_seconds_count", float64(series.Count),
+			"method", series.Method, "route", series.Route, "status", series.Status)
+	}
+}
+
+// PrometheusWriter writes metric families in the Prometheus text exposition format.
+// Write errors are kept and returned by Flush.
+type PrometheusWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewPrometheusWriter creates a writer on top of w
+func NewPrometheusWriter(w io.Writer) *PrometheusWriter {
+	return &PrometheusWriter{w: bufio.NewWriter(w)}
+}
+
+// Family writes the HELP and TYPE lines that introduce a metric family
+func (p *PrometheusWriter) Family(name, help, metricType string) {
+	p.write("# HELP " + name + " " + escapePrometheusHelp(help) + "\n")
+	p.write("# TYPE " + name + " " + metricType + "\n")
+}
+
+// Sample writes one sample. Labels are given as alternating names and values.
+func (p *PrometheusWriter) Sample(name string, value float64, labels ...string) {
+	var line strings.Builder
+	line.WriteString(name)
+
+	if len(labels) > 1 {
+		line.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			line.WriteString(labels[i])
+			line.WriteString(`="`)
+			line.WriteString(escapePrometheusLabel(labels[i+1]))
+			line.WriteByte('"')
+		}
+		line.WriteByte('}')
+	}
+
+	line.WriteByte(' ')
+	line.WriteString(formatPrometheusValue(value))
+	line.WriteByte('\n')
+	p.write(line.String())
+}
+
+// Gauge writes a single unlabelled gauge family
+func (p *PrometheusWriter) Gauge(name, help string, value float64) {
+	p.Family(name, help, "gauge")
+	p.Sample(name, value)
+}
+
+// Counter writes a single unlabelled counter family
+func (p *PrometheusWriter) Counter(name, help string, value float64) {
+	p.Family(name, help, "counter")
+	p.Sample(name, value)
+}
+
+// Flush writes any buffered output and returns the first error encountered
+func (p *PrometheusWriter) Flush() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+func (p *PrometheusWriter) write(s string) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = p.w.WriteString(s)
+}
+
+// formatPrometheusValue formats a sample value, including the special float values
+func formatPrometheusValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+var prometheusHelpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapePrometheusLabel(value string) string {
+	return prometheusLabelEscaper.Replace(value)
+}
+
+func escapePrometheusHelp(help string) string {
+	return prometheusHelpEscaper.Replace(help)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheus(t *testing.T) {
+	t.Run("writer formats families, labels and special values", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewPrometheusWriter(&buf)
+
+		w.Family("internship_approvals", "Approvals by status.\nLine two", "gauge")
+		w.Sample("internship_approvals", 3, "status", `t."approved"\`)
+		w.Gauge("go_goroutines", "Goroutines.", 12)
+		w.Sample("bucket", math.Inf(1))
+		require.NoError(t, w.Flush())
+
+		assert.Equal(t, "# HELP internship_approvals Approvals by status.\\nLine two\n"+
+			"# TYPE internship_approvals gauge\n"+
+			"internship_approvals{status=\"t.\\\"approved\\\"\\\\\"} 3\n"+
+			"# HELP go_goroutines Goroutines.\n"+
+			"# TYPE go_goroutines gauge\n"+
+			"go_goroutines 12\n"+
+			"bucket +Inf\n", buf.String())
+	})
+
+	t.Run("request histogram buckets are cumulative", func(t *testing.T) {
+		metrics := NewHTTPMetrics()
+		metrics.Observe("GET", "/api/v1/students/:id", 200, 20*time.Millisecond)
+		metrics.Observe("GET", "/api/v1/students/:id", 200, 300*time.Millisecond)
+		metrics.Observe("GET", "/api/v1/students/:id", 404, time.Millisecond)
+		metrics.Observe("POST", "/api/v1/login", 401, 20*time.Second)
+
+		snapshot := metrics.Snapshot()
+		require.Len(t, snapshot, 3)
+		assert.Equal(t, HTTPSeriesKey{Method: "POST", Route: "/api/v1/login", Status: "401"}, snapshot[0].HTTPSeriesKey)
+		assert.Equal(t, uint64(0), snapshot[0].BucketCounts[len(httpLatencyBuckets)-1]) // slower than the largest bucket
+
+		ok := snapshot[1]
+		assert.Equal(t, "200", ok.Status)
+		assert.Equal(t, uint64(2), ok.Count)
+		assert.InDelta(t, 0.32, ok.LatencySum, 1e-9)
+		assert.Equal(t, []uint64{0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 2}, ok.BucketCounts)
+
+		var buf bytes.Buffer
+		w := NewPrometheusWriter(&buf)
+		metrics.WritePrometheus(w)
+		require.NoError(t, w.Flush())
+
+		output := buf.String()
+		assert.Contains(t, output, "# TYPE http_requests_total counter\n")
+		assert.Contains(t, output, `http_requests_total{method="GET",route="/api/v1/students/:id",status="200"} 2`+"\n")
+		assert.Contains(t, output, `http_request_duration_seconds_bucket{method="GET",route="/api/v1/students/:id",status="200",le="0.025"} 1`+"\n")
+		assert.Contains(t, output, `http_request_duration_seconds_bucket{method="POST",route="/api/v1/login",status="401",le="+Inf"} 1`+"\n")
+		assert.Equal(t, 1, strings.Count(output, "# TYPE http_request_duration_seconds histogram"))
+	})
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend-go/internal/database"
+	"backend-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RecycleBinSettings configures how long deleted records are kept
+type RecycleBinSettings struct {
+	Retention     time.Duration // how long a deleted record can be restored
+	PurgeInterval time.Duration // time between purge runs
+}
+
+// RecycleBinService lists, restores and permanently deletes soft-deleted students, companies and
+// student trainings
+type RecycleBinService struct {
+	db       *gorm.DB
+	settings RecycleBinSettings
+	logger   *Logger
+}
+
+// NewRecycleBinService creates a new recycle bin service instance
+func NewRecycleBinService(db *gorm.DB, settings RecycleBinSettings) *RecycleBinService {
+	if settings.Retention <= 0 {
+		settings.Retention = 30 * 24 * time.Hour
+	}
+	if settings.PurgeInterval <= 0 {
+		settings.PurgeInterval = 6 * time.Hour
+	}
+
+	return &RecycleBinService{
+		db:       db,
+		settings: settings,
+		logger:   GetGlobalLogger(),
+	}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx
+func (s *RecycleBinService) WithContext(ctx context.Context) *RecycleBinService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	return &bound
+}
+
+// RecycleBinItem represents a deleted record
+type RecycleBinItem struct {
+	EntityType models.EntityType `json:"entity_type"`
+	ID         uint              `json:"id"`
+	Label      string            `json:"label"`
+	DeletedAt  time.Time         `json:"deleted_at"`
+	PurgeAt    time.Time         `json:"purge_at"` // when the purge job permanently deletes it
+}
+
+// RecycleBinListRequest represents the request for listing deleted records of one entity type
+type RecycleBinListRequest struct {
+	EntityType models.EntityType `json:"entity_type"`
+	Search     string            `json:"search"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+}
+
+// RecycleBinListResponse represents the response for listing deleted records
+type RecycleBinListResponse struct {
+	Data       []RecycleBinItem `json:"data"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// RecycleBinRestoreResult reports a restored record and the dependents restored with it
+type RecycleBinRestoreResult struct {
+	EntityType  models.EntityType `json:"entity_type"`
+	ID          uint              `json:"id"`
+	Enrollments int64             `json:"enrollments"`
+	Trainings   int64             `json:"trainings"`
+}
+
+// RecycleBinPurgeReport counts the records a purge permanently deleted
+type RecycleBinPurgeReport struct {
+	Students    int64 `json:"students"`
+	Enrollments int64 `json:"enrollments"`
+	Trainings   int64 `json:"trainings"`
+	Companies   int64 `json:"companies"`
+}
+
+// recycleBinSearchColumns lists the columns searched when listing each entity type
+var recycleBinSearchColumns = map[models.EntityType][]string{
+	models.EntityTypeStudent:  {"student_id", "name", "surname", "email"},
+	models.EntityTypeCompany:  {"company_name_en", "company_name_th", "company_register_number"},
+	models.EntityTypeTraining: {"position", "department", "supervisor"},
+}
+
+// recycleBinModel returns the model of a recycle bin entity type
+func recycleBinModel(entityType models.EntityType) (interface{}, error) {
+	switch entityType {
+	case models.EntityTypeStudent:
+		return &models.Student{}, nil
+	case models.EntityTypeCompany:
+		return &models.Company{}, nil
+	case models.EntityTypeTraining:
+		return &models.StudentTraining{}, nil
+	}
+	return nil, errors.New("invalid entity type")
+}
+
+// ListDeleted retrieves the deleted records of one entity type, most recently deleted first
+func (s *RecycleBinService) ListDeleted(req RecycleBinListRequest) (*RecycleBinListResponse, error) {
+	model, err := recycleBinModel(req.EntityType)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	query := s.db.Unscoped().Model(model).Where("deleted_at IS NOT NULL")
+	if req.Search != "" {
+		columns := recycleBinSearchColumns[req.EntityType]
+		conditions := make([]string, len(columns))
+		args := make([]interface{}, len(columns))
+		for i, column := range columns {
+			conditions[i] = column + " ILIKE ?"
+			args[i] = "%" + req.Search + "%"
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count deleted records: %w", err)
+	}
+
+	items, err := s.deletedItems(req.EntityType, query.Order("deleted_at DESC, id DESC").Offset((req.Page-1)*req.Limit).Limit(req.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deleted records: %w", err)
+	}
+
+	return &RecycleBinListResponse{
+		Data:       items,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+	}, nil
+}
+
+// deletedItems loads a page of deleted records and describes them
+func (s *RecycleBinService) deletedItems(entityType models.EntityType, query *gorm.DB) ([]RecycleBinItem, error) {
+	items := []RecycleBinItem{}
+	add := func(id uint, label string, deletedAt gorm.DeletedAt) {
+		items = append(items, RecycleBinItem{
+			EntityType: entityType,
+			ID:         id,
+			Label:      label,
+			DeletedAt:  deletedAt.Time,
+			PurgeAt:    deletedAt.Time.Add(s.settings.Retention),
+		})
+	}
+
+	switch entityType {
+	case models.EntityTypeStudent:
+		var students []models.Student
+		if err := query.Find(&students).Error; err != nil {
+			return nil, err
+		}
+		for i := range students {
+			add(students[i].ID, students[i].StudentID+" "+students[i].GetFullName(), students[i].DeletedAt)
+		}
+	case models.EntityTypeCompany:
+		var companies []models.Company
+		if err := query.Find(&companies).Error; err != nil {
+			return nil, err
+		}
+		for _, company := range companies {
+			label := company.CompanyNameEn
+			if label == "" {
+				label = company.CompanyNameTh
+			}
+			add(company.ID, label, company.DeletedAt)
+		}
+	case models.EntityTypeTraining:
+		var trainings []models.StudentTraining
+		if err := query.Find(&trainings).Error; err != nil {
+			return nil, err
+		}
+		for _, training := range trainings {
+			add(training.ID, training.Position+", "+training.Department, training.DeletedAt)
+		}
+	}
+	return items, nil
+}
+
+// Restore brings a deleted record back. A student comes back with the enrollments and trainings
+// deleted together with it; records deleted on their own before it stay in the recycle bin.
+func (s *RecycleBinService) Restore(entityType models.EntityType, id uint) (*RecycleBinRestoreResult, error) {
+	model, err := recycleBinModel(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecycleBinRestoreResult{EntityType: entityType, ID: id}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(model, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("deleted record not found")
+			}
+			return fmt.Errorf("database error: %w", err)
+		}
+
+		switch record := model.(type) {
+		case *models.Student:
+			enrollments := tx.Unscoped().Model(&models.StudentEnroll{}).
+				Select("id").
+				Where("student_id = ? AND deleted_at = ?", record.ID, record.DeletedAt.Time)
+			trainings := tx.Unscoped().Model(&models.StudentTraining{}).
+				Where("student_enroll_id IN (?) AND deleted_at = ?", enrollments, record.DeletedAt.Time).
+				Update("deleted_at", nil)
+			if trainings.Error != nil {
+				return fmt.Errorf("failed to restore trainings: %w", trainings.Error)
+			}
+			result.Trainings = trainings.RowsAffected
+
+			restored := tx.Unscoped().Model(&models.StudentEnroll{}).
+				Where("student_id = ? AND deleted_at = ?", record.ID, record.DeletedAt.Time).
+				Update("deleted_at", nil)
+			if restored.Error != nil {
+				return fmt.Errorf("failed to restore enrollments: %w", restored.Error)
+			}
+			result.Enrollments = restored.RowsAffected
+		case *models.StudentTraining:
+			var enrollment models.StudentEnroll
+			if err := tx.Select("id").First(&enrollment, record.StudentEnrollID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("the training's student is deleted, restore the student first")
+				}
+				return fmt.Errorf("database error: %w", err)
+			}
+		}
+
+		if err := tx.Unscoped().Model(model).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Purge permanently deletes one record from the recycle bin together with everything that depends
+// on it
+func (s *RecycleBinService) Purge(entityType models.EntityType, id uint) error {
+	model, err := recycleBinModel(entityType)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Unscoped().Where("deleted_at IS NOT NULL").First(model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("deleted record not found")
+		}
+		return fmt.Errorf("database error: %w", err)
+	}
+	if err := s.db.Unscoped().Delete(model).Error; err != nil {
+		return fmt.Errorf("failed to purge record: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired permanently deletes the records deleted longer than the retention ago. Dependents
+// go first so each record is only deleted once.
+func (s *RecycleBinService) PurgeExpired(now time.Time) (*RecycleBinPurgeReport, error) {
+	cutoff := now.Add(-s.settings.Retention)
+	report := &RecycleBinPurgeReport{}
+
+	var trainings []models.StudentTraining
+	if err := s.purgeBatches(&trainings, cutoff, &report.Trainings); err != nil {
+		return report, fmt.Errorf("failed to purge trainings: %w", err)
+	}
+	var enrollments []models.StudentEnroll
+	if err := s.purgeBatches(&enrollments, cutoff, &report.Enrollments); err != nil {
+		return report, fmt.Errorf("failed to purge enrollments: %w", err)
+	}
+	var students []models.Student
+	if err := s.purgeBatches(&students, cutoff, &report.Students); err != nil {
+		return report, fmt.Errorf("failed to purge students: %w", err)
+	}
+	var companies []models.Company
+	if err := s.purgeBatches(&companies, cutoff, &report.Companies); err != nil {
+		return report, fmt.Errorf("failed to purge companies: %w", err)
+	}
+	return report, nil
+}
+
+// purgeBatches permanently deletes the records of dest's type deleted before cutoff, a batch at a
+// time so the delete hooks run for each record
+func (s *RecycleBinService) purgeBatches(dest interface{}, cutoff time.Time, count *int64) error {
+	return s.db.Unscoped().Where("deleted_at < ?", cutoff).FindInBatches(dest, 200, func(tx *gorm.DB, batch int) error {
+		result := s.db.Unscoped().Delete(dest)
+		if result.Error != nil {
+			return result.Error
+		}
+		*count += result.RowsAffected
+		return nil
+	}).Error
+}
+
+// StartPurgeJob runs PurgeExpired every purge interval until ctx is cancelled. Purging is
+// idempotent, so the job is safe to run on every replica.
+func (s *RecycleBinService) StartPurgeJob(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.settings.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			report, err := s.PurgeExpired(time.Now())
+			if err != nil {
+				s.logger.Error("Recycle bin purge failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			} else if total := report.Students + report.Enrollments + report.Trainings + report.Companies; total > 0 {
+				s.logger.Info("Expired recycle bin records purged", map[string]interface{}{
+					"students":    report.Students,
+					"enrollments": report.Enrollments,
+					"trainings":   report.Trainings,
+					"companies":   report.Companies,
+				})
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	s.logger.Info("Recycle bin purge job started", map[string]interface{}{
+		"retention":      s.settings.Retention.String(),
+		"purge_interval": s.settings.PurgeInterval.String(),
+	})
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecycleBin(t *testing.T) {
+	t.Run("entity types map to soft-deletable models", func(t *testing.T) {
+		model, err := recycleBinModel(models.EntityTypeStudent)
+		require.NoError(t, err)
+		assert.IsType(t, &models.Student{}, model)
+
+		model, err = recycleBinModel(models.EntityTypeTraining)
+		require.NoError(t, err)
+		assert.IsType(t, &models.StudentTraining{}, model)
+
+		_, err = recycleBinModel(models.EntityTypeDocument)
+		assert.EqualError(t, err, "invalid entity type")
+	})
+
+	t.Run("every entity type is searchable", func(t *testing.T) {
+		for _, entityType := range []models.EntityType{models.EntityTypeStudent, models.EntityTypeCompany, models.EntityTypeTraining} {
+			assert.NotEmpty(t, recycleBinSearchColumns[entityType], entityType)
+		}
+	})
+
+	t.Run("settings fall back to defaults", func(t *testing.T) {
+		service := NewRecycleBinService(nil, RecycleBinSettings{})
+		assert.Equal(t, 30*24*time.Hour, service.settings.Retention)
+		assert.Equal(t, 6*time.Hour, service.settings.PurgeInterval)
+	})
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"backend-go/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReminderDispatcher periodically sends due schedule reminders and evaluation due-date reminders,
+// marks overdue evaluations, closes committee voting past its deadline, collects scheduled metrics and
+// cleans up expired notifications, tokens and report files.
+// Each run is guarded by a PostgreSQL advisory lock so only one replica dispatches at a time.
+type ReminderDispatcher struct {
+	db                  *gorm.DB
+	config              *ReminderDispatcherConfig
+	notificationService *NotificationService
+	evaluationService   *EvaluationService
+	approvalService     *ApprovalService
+	reportService       *ReportService
+	metricService       *MetricService
+	jwtService          *JWTService
+	logger              *Logger
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+// ReminderDispatcherConfig holds configuration for the reminder dispatcher
+type ReminderDispatcherConfig struct {
+	Interval               time.Duration // time between runs
+	EvaluationReminderDays []int         // days before an evaluation due date to remind the evaluator
+	CleanupInterval        time.Duration // time between cleanups of expired notifications, tokens and report files
+	LeaderLockKey          int64         // PostgreSQL advisory lock key shared by all replicas
+	BatchSize              int           // maximum schedule reminders sent per run
+}
+
+// DispatchResult summarizes a single dispatcher run
+type DispatchResult struct {
+	Skipped                   bool     `json:"skipped"` // another replica holds the leader lock
+	ScheduleRemindersSent     int      `json:"schedule_reminders_sent"`
+	EvaluationRemindersSent   int      `json:"evaluation_reminders_sent"`
+	CommitteeVotesResolved    int      `json:"committee_votes_resolved"`
+	OverdueEvaluationsUpdated bool     `json:"overdue_evaluations_updated"`
+	MetricsCollected          int      `json:"metrics_collected"`
+	CleanupRan                bool     `json:"cleanup_ran"`
+	Errors                    []string `json:"errors,omitempty"`
+}
+
+// NewReminderDispatcher creates a new reminder dispatcher
+func NewReminderDispatcher(db *gorm.DB, cfg *ReminderDispatcherConfig, jwtService *JWTService) *ReminderDispatcher {
+	if cfg == nil {
+		cfg = &ReminderDispatcherConfig{
+			Interval:               time.Minute,
+			EvaluationReminderDays: []int{7, 3, 1},
+			CleanupInterval:        24 * time.Hour,
+			LeaderLockKey:          727001,
+			BatchSize:              500,
+		}
+	}
+
+	return &ReminderDispatcher{
+		db:                  db,
+		config:              cfg,
+		notificationService: NewNotificationService(db),
+		evaluationService:   NewEvaluationService(db),
+		approvalService:     NewApprovalService(db),
+		reportService:       NewReportService(db, ReportSettings{}),
+		metricService:       NewMetricService(db),
+		jwtService:          jwtService,
+		logger:              GetGlobalLogger(),
+	}
+}
+
+// Start runs the dispatcher in the background until ctx is cancelled
+func (d *ReminderDispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.config.Interval)
+		defer ticker.Stop()
+
+		d.logger.Info("Reminder dispatcher started", map[string]interface{}{
+			"interval": d.config.Interval.String(),
+		})
+
+		for {
+			d.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				d.logger.Info("Reminder dispatcher stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// run executes one dispatcher cycle and logs its outcome
+func (d *ReminderDispatcher) run(ctx context.Context) {
+	result, err := d.RunOnce(ctx)
+	if err != nil {
+		d.logger.Error("Reminder dispatcher run failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if result.Skipped {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"schedule_reminders_sent":   result.ScheduleRemindersSent,
+		"evaluation_reminders_sent": result.EvaluationRemindersSent,
+		"committee_votes_resolved":  result.CommitteeVotesResolved,
+		"metrics_collected":         result.MetricsCollected,
+		"cleanup_ran":               result.CleanupRan,
+	}
+	if len(result.Errors) > 0 {
+		fields["errors"] = result.Errors
+		d.logger.Warn("Reminder dispatcher run completed with errors", fields)
+		return
+	}
+	d.logger.Debug("Reminder dispatcher run completed", fields)
+}
+
+// RunOnce performs a single dispatch cycle if this instance can take the leader lock.
+// Failures of individual jobs are collected in the result so one failing job does not block the others.
+func (d *ReminderDispatcher) RunOnce(ctx context.Context) (*DispatchResult, error) {
+	acquired, release, err := d.acquireLeaderLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire leader lock: %w", err)
+	}
+	if !acquired {
+		return &DispatchResult{Skipped: true}, nil
+	}
+	defer release()
+
+	result := &DispatchResult{}
+	now := time.Now()
+
+	sent, err := d.sendDueScheduleReminders(now)
+	result.ScheduleRemindersSent = sent
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("schedule reminders: %v", err))
+	}
+
+	if err := d.evaluationService.UpdateOverdueEvaluations(); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("overdue evaluations: %v", err))
+	} else {
+		result.OverdueEvaluationsUpdated = true
+	}
+
+	sent, err = d.sendEvaluationReminders(now)
+	result.EvaluationRemindersSent = sent
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("evaluation reminders: %v", err))
+	}
+
+	resolved, err := d.approvalService.ResolveExpiredVoting(now)
+	result.CommitteeVotesResolved = resolved
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("committee voting: %v", err))
+	}
+
+	collected, err := d.metricService.CollectDue(ctx, now)
+	result.MetricsCollected = collected
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("metrics: %v", err))
+	}
+
+	if d.cleanupDue(now) {
+		result.CleanupRan = true
+		if err := d.notificationService.CleanupExpiredNotifications(); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("notification cleanup: %v", err))
+		}
+		if d.jwtService != nil {
+			if err := d.jwtService.CleanupExpiredTokens(); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("token cleanup: %v", err))
+			}
+		}
+		if _, err := d.reportService.CleanupExpired(now); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("report cleanup: %v", err))
+		}
+	}
+
+	return result, nil
+}
+
+// acquireLeaderLock takes a session-level advisory lock on a dedicated connection.
+// Databases without advisory locks are treated as single-instance deployments.
+func (d *ReminderDispatcher) acquireLeaderLock(ctx context.Context) (bool, func(), error) {
+	if d.db.Dialector.Name() != "postgres" {
+		return true, func() {}, nil
+	}
+
+	sqlDB, err := d.db.DB()
+	if err != nil {
+		return false, nil, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", d.config.LeaderLockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil, nil
+	}
+
+	release := func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", d.config.LeaderLockKey); err != nil {
+			d.logger.Warn("Failed to release reminder dispatcher lock", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		conn.Close()
+	}
+
+	return true, release, nil
+}
+
+// cleanupDue reports whether the cleanup jobs should run, recording the run time when they do
+func (d *ReminderDispatcher) cleanupDue(now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.lastCleanup.IsZero() && now.Sub(d.lastCleanup) < d.config.CleanupInterval {
+		return false
+	}
+	d.lastCleanup = now
+	return true
+}
+
+// sendDueScheduleReminders sends every unsent schedule notification whose NotifyAt has passed.
+// Reminders of recurring schedules are re-armed for the next occurrence instead of being marked sent.
+func (d *ReminderDispatcher) sendDueScheduleReminders(now time.Time) (int, error) {
+	var due []models.ScheduleNotification
+	err := d.db.Preload("Schedule.Exceptions").
+		Where("is_sent = ? AND notify_at <= ?", false, now).
+		Order("notify_at ASC").
+		Limit(d.config.BatchSize).
+		Find(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for i := range due {
+		reminder := &due[i]
+
+		// Claim the reminder so a concurrent run cannot send it again
+		claim := d.db.Model(&models.ScheduleNotification{}).
+			Where("id = ? AND is_sent = ?", reminder.ID, false).
+			Updates(map[string]interface{}{"is_sent": true, "sent_at": now})
+		if claim.Error != nil {
+			errs = append(errs, claim.Error)
+			continue
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		delivered, err := d.deliverScheduleReminder(reminder, now)
+		if err != nil {
+			// Release the claim so the next run retries
+			d.db.Model(&models.ScheduleNotification{}).Where("id = ?", reminder.ID).
+				Updates(map[string]interface{}{"is_sent": false, "sent_at": nil})
+			errs = append(errs, fmt.Errorf("reminder %d: %w", reminder.ID, err))
+			continue
+		}
+		if delivered {
+			sent++
+		}
+
+		if err := d.rearmRecurringReminder(reminder); err != nil {
+			errs = append(errs, fmt.Errorf("reminder %d: %w", reminder.ID, err))
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// deliverScheduleReminder sends the notification for a claimed reminder.
+// Reminders for cancelled schedules or occurrences, or for events that already ended, are dropped.
+func (d *ReminderDispatcher) deliverScheduleReminder(reminder *models.ScheduleNotification, now time.Time) (bool, error) {
+	schedule := &reminder.Schedule
+	if schedule.ID == 0 || schedule.Status == models.ScheduleStatusCancelled {
+		return false, nil
+	}
+
+	start := reminder.NotifyAt.Add(time.Duration(reminder.NotifyBefore) * time.Minute)
+	end := start.Add(schedule.Duration())
+	location := schedule.Location
+	for _, exception := range schedule.Exceptions {
+		if !exception.OccurrenceStart.Equal(start) {
+			continue
+		}
+		if exception.IsCancelled {
+			return false, nil
+		}
+		if exception.IsMoved() {
+			start, end = *exception.NewStartTime, *exception.NewEndTime
+		}
+		if exception.Location != nil {
+			location = *exception.Location
+		}
+	}
+
+	if end.Before(now) {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("%s starts at %s", schedule.Title, start.Format("2006-01-02 15:04"))
+	if location != "" {
+		message += " at " + location
+	}
+
+	priority := models.NotificationPriorityNormal
+	if reminder.NotifyBefore <= 60 {
+		priority = models.NotificationPriorityHigh
+	}
+
+	_, err := d.notificationService.SendNotification(NotificationRequest{
+		UserID:    reminder.UserID,
+		Type:      models.NotificationTypeReminder,
+		Title:     "Upcoming: " + schedule.Title,
+		Message:   message,
+		Priority:  priority,
+		ActionURL: fmt.Sprintf("/schedules/%d", schedule.ID),
+		Metadata: map[string]interface{}{
+			"schedule_id":   schedule.ID,
+			"schedule_type": schedule.ScheduleType,
+			"start_time":    start,
+			"notify_before": reminder.NotifyBefore,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// rearmRecurringReminder moves a sent reminder of a recurring schedule to the next occurrence
+func (d *ReminderDispatcher) rearmRecurringReminder(reminder *models.ScheduleNotification) error {
+	schedule := &reminder.Schedule
+	if schedule.ID == 0 || !schedule.IsRecurring() || schedule.Status == models.ScheduleStatusCancelled {
+		return nil
+	}
+
+	before := time.Duration(reminder.NotifyBefore) * time.Minute
+	current := reminder.NotifyAt.Add(before)
+
+	// Yearly series starting on 29 February can skip up to eight years
+	for _, start := range schedule.Occurrences(current, current.AddDate(9, 0, 0)) {
+		if !start.After(current) {
+			continue
+		}
+		return d.db.Model(&models.ScheduleNotification{}).
+			Where("id = ?", reminder.ID).
+			Updates(map[string]interface{}{"notify_at": start.Add(-before), "is_sent": false}).Error
+	}
+
+	return nil
+}
+
+// sendEvaluationReminders notifies evaluators ahead of evaluation due dates.
+// A tracker falls into the smallest configured reminder window that contains its due date,
+// and each (tracker, window) pair is recorded so the reminder is sent once.
+func (d *ReminderDispatcher) sendEvaluationReminders(now time.Time) (int, error) {
+	if len(d.config.EvaluationReminderDays) == 0 {
+		return 0, nil
+	}
+
+	days := append([]int(nil), d.config.EvaluationReminderDays...)
+	sort.Ints(days)
+	horizon := now.AddDate(0, 0, days[len(days)-1])
+
+	var trackers []models.EvaluationStatusTracker
+	err := d.db.Preload("StudentTraining.StudentEnroll.Student").
+		Where("due_date > ? AND due_date <= ? AND evaluator_id IS NOT NULL AND status IN ?", now, horizon,
+			[]models.EvaluationStatus{models.EvalStatusPending, models.EvalStatusInProgress}).
+		Find(&trackers).Error
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for i := range trackers {
+		tracker := &trackers[i]
+
+		window := -1
+		for _, windowDays := range days {
+			if !tracker.DueDate.After(now.AddDate(0, 0, windowDays)) {
+				window = windowDays
+				break
+			}
+		}
+		if window < 0 {
+			continue
+		}
+
+		// Claim the reminder; an existing row means it was already sent
+		reminder := models.EvaluationReminder{
+			EvaluationStatusTrackerID: tracker.ID,
+			DaysBefore:                window,
+			SentAt:                    now,
+		}
+		claim := d.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&reminder)
+		if claim.Error != nil {
+			errs = append(errs, claim.Error)
+			continue
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		studentName := tracker.StudentTraining.StudentEnroll.Student.GetFullName()
+		if err := d.notificationService.SendEvaluationReminder(*tracker.EvaluatorID, string(tracker.EvaluationType), studentName, *tracker.DueDate); err != nil {
+			d.db.Delete(&reminder)
+			errs = append(errs, fmt.Errorf("evaluation %d: %w", tracker.ID, err))
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
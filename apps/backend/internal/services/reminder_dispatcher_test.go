@@ -0,0 +1,144 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderDispatcher(t *testing.T) {
+	// Delivery is not under test; the claims recorded in the database are
+	t.Setenv("DISABLE_NOTIFICATIONS", "true")
+
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.Schedule{},
+		&models.ScheduleNotification{},
+		&models.ScheduleException{},
+		&models.EvaluationStatusTracker{},
+		&models.EvaluationReminder{},
+	))
+
+	dispatcher := NewReminderDispatcher(db, nil, nil)
+	now := time.Now().Truncate(time.Minute)
+
+	createReminder := func(recurrence models.RecurrenceType) (*models.Schedule, *models.ScheduleNotification) {
+		start := now.Add(30 * time.Minute)
+		schedule := &models.Schedule{
+			Title:          "Supervision meeting",
+			ScheduleType:   models.ScheduleTypeMeeting,
+			Status:         models.ScheduleStatusScheduled,
+			StartTime:      start,
+			EndTime:        start.Add(time.Hour),
+			RecurrenceType: recurrence,
+			CreatedBy:      9201,
+			CreatorType:    string(AccountTypeInstructor),
+		}
+		require.NoError(t, db.Create(schedule).Error)
+
+		reminder := &models.ScheduleNotification{
+			ScheduleID:   schedule.ID,
+			UserID:       9201,
+			UserType:     string(AccountTypeInstructor),
+			NotifyBefore: 60,
+			NotifyAt:     start.Add(-time.Hour),
+		}
+		require.NoError(t, db.Create(reminder).Error)
+
+		t.Cleanup(func() {
+			db.Delete(&models.ScheduleNotification{}, reminder.ID)
+			db.Unscoped().Delete(&models.Schedule{}, schedule.ID)
+		})
+		return schedule, reminder
+	}
+
+	t.Run("a due reminder is claimed and sent exactly once", func(t *testing.T) {
+		_, reminder := createReminder(models.RecurrenceNone)
+
+		sent, err := dispatcher.sendDueScheduleReminders(now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+
+		var claimed models.ScheduleNotification
+		require.NoError(t, db.First(&claimed, reminder.ID).Error)
+		assert.True(t, claimed.IsSent)
+		require.NotNil(t, claimed.SentAt)
+
+		sent, err = dispatcher.sendDueScheduleReminders(now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+
+	t.Run("reminders of recurring schedules are re-armed for the next occurrence", func(t *testing.T) {
+		schedule, reminder := createReminder(models.RecurrenceDaily)
+
+		sent, err := dispatcher.sendDueScheduleReminders(now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+
+		var rearmed models.ScheduleNotification
+		require.NoError(t, db.First(&rearmed, reminder.ID).Error)
+		assert.False(t, rearmed.IsSent)
+		assert.WithinDuration(t, schedule.StartTime.AddDate(0, 0, 1).Add(-time.Hour), rearmed.NotifyAt, time.Second)
+
+		// Nothing is due again until the next occurrence comes around
+		sent, err = dispatcher.sendDueScheduleReminders(now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+
+		sent, err = dispatcher.sendDueScheduleReminders(now.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+
+		require.NoError(t, db.First(&rearmed, reminder.ID).Error)
+		assert.WithinDuration(t, schedule.StartTime.AddDate(0, 0, 2).Add(-time.Hour), rearmed.NotifyAt, time.Second)
+	})
+
+	t.Run("evaluation reminders are sent once per tracker and window", func(t *testing.T) {
+		evaluatorID := uint(9201)
+		dueDate := now.Add(36 * time.Hour)
+		tracker := &models.EvaluationStatusTracker{
+			StudentTrainingID: 9201,
+			EvaluationType:    models.EvalTypeVisitorStudent,
+			Status:            models.EvalStatusPending,
+			EvaluatorID:       &evaluatorID,
+			DueDate:           &dueDate,
+		}
+		require.NoError(t, db.Create(tracker).Error)
+		t.Cleanup(func() {
+			db.Where("evaluation_status_tracker_id = ?", tracker.ID).Delete(&models.EvaluationReminder{})
+			db.Delete(&models.EvaluationStatusTracker{}, tracker.ID)
+		})
+
+		windows := func() []int {
+			var days []int
+			db.Model(&models.EvaluationReminder{}).
+				Where("evaluation_status_tracker_id = ?", tracker.ID).
+				Order("days_before DESC").
+				Pluck("days_before", &days)
+			return days
+		}
+
+		sendTwice := func(now time.Time) []int {
+			var sent []int
+			for run := 0; run < 2; run++ {
+				count, err := dispatcher.sendEvaluationReminders(now)
+				require.NoError(t, err)
+				sent = append(sent, count)
+			}
+			return sent
+		}
+
+		// Due in a day and a half falls into the three day window
+		assert.Equal(t, []int{1, 0}, sendTwice(now))
+		assert.Equal(t, []int{3}, windows())
+
+		// Within a day of the due date the one day window sends its own reminder
+		assert.Equal(t, []int{1, 0}, sendTwice(now.Add(13*time.Hour)))
+		assert.Equal(t, []int{3, 1}, windows())
+	})
+}
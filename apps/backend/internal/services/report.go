@@ -0,0 +1,374 @@
+package services
+
+import (
+	"backend-go/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reportGenerationTimeout is how long a report may stay generating before another
+// request may take it over, in case the instance generating it crashed
+const reportGenerationTimeout = 10 * time.Minute
+
+// ReportService manages saved report definitions and their generated files.
+// A saved report is visible to its owner and, when public, to every other staff member.
+type ReportService struct {
+	db               *gorm.DB
+	analyticsService *AnalyticsService
+	settings         ReportSettings
+}
+
+// ReportSettings holds configuration for generated report files
+type ReportSettings struct {
+	Directory string        // where generated reports are written
+	Retention time.Duration // how long a generated report can be downloaded
+}
+
+// ReportParameters are the stored inputs of a saved report, replayed on every generation
+type ReportParameters struct {
+	Type      AnalyticsReportType    `json:"type"`
+	StartDate *time.Time             `json:"start_date,omitempty"`
+	EndDate   *time.Time             `json:"end_date,omitempty"`
+	Filters   map[string]interface{} `json:"filters,omitempty"`
+}
+
+// CreateReportRequest represents the request for saving a report definition
+type CreateReportRequest struct {
+	Title       string                 `json:"title" validate:"required,max=255"`
+	Description string                 `json:"description"`
+	Type        AnalyticsReportType    `json:"type" validate:"required,oneof=internship approval company student evaluation"`
+	Format      ReportFormat           `json:"format" validate:"required,oneof=pdf excel csv"`
+	StartDate   *time.Time             `json:"start_date"`
+	EndDate     *time.Time             `json:"end_date"`
+	Filters     map[string]interface{} `json:"filters"`
+	IsPublic    bool                   `json:"is_public"`
+}
+
+// ReportListRequest represents the request for listing saved reports
+type ReportListRequest struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Status     string `json:"status"`
+	ReportType string `json:"report_type"`
+	OwnedOnly  bool   `json:"owned_only"`
+	Search     string `json:"search"`
+}
+
+// ReportListResponse represents the response for listing saved reports
+type ReportListResponse struct {
+	Data       []models.Report `json:"data"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+}
+
+// savedReportTypes maps analytics report types onto the report type stored on models.Report
+var savedReportTypes = map[AnalyticsReportType]models.ReportType{
+	AnalyticsReportTypeInternship: models.ReportTypeStatistics,
+	AnalyticsReportTypeApproval:   models.ReportTypeCustom,
+	AnalyticsReportTypeCompany:    models.ReportTypeCompanyPerformance,
+	AnalyticsReportTypeStudent:    models.ReportTypeStudentProgress,
+	AnalyticsReportTypeEvaluation: models.ReportTypeEvaluationSummary,
+}
+
+// NewReportService creates a new report service instance
+func NewReportService(db *gorm.DB, settings ReportSettings) *ReportService {
+	if settings.Directory == "" {
+		settings.Directory = "uploads/reports"
+	}
+	if settings.Retention <= 0 {
+		settings.Retention = 30 * 24 * time.Hour
+	}
+
+	return &ReportService{
+		db:               db,
+		analyticsService: NewAnalyticsService(db),
+		settings:         settings,
+	}
+}
+
+// CreateReport saves a report definition. It is generated separately so it can be rerun later.
+func (s *ReportService) CreateReport(req CreateReportRequest, generatedBy uint, generatorType string) (*models.Report, error) {
+	reportType, ok := savedReportTypes[req.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported report type: %s", req.Type)
+	}
+	if _, err := reportExtension(req.Format); err != nil {
+		return nil, err
+	}
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	parameters, err := json.Marshal(ReportParameters{
+		Type:      req.Type,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Filters:   req.Filters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.Report{
+		Title:         req.Title,
+		Description:   req.Description,
+		ReportType:    reportType,
+		Format:        models.ReportFormat(req.Format),
+		Status:        models.ReportStatusPending,
+		Parameters:    parameters,
+		GeneratedBy:   generatedBy,
+		GeneratorType: generatorType,
+		IsPublic:      req.IsPublic,
+	}
+	if err := s.db.Create(&report).Error; err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// GetReports retrieves the caller's reports and reports shared by other staff, newest first
+func (s *ReportService) GetReports(req ReportListRequest, generatedBy uint, generatorType string) (*ReportListResponse, error) {
+	var reports []models.Report
+	var total int64
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	query := s.db.Model(&models.Report{})
+	if req.OwnedOnly {
+		query = query.Scopes(ownedReports(generatedBy, generatorType))
+	} else {
+		query = query.Scopes(visibleReports(generatedBy, generatorType))
+	}
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.ReportType != "" {
+		query = query.Where("report_type = ?", req.ReportType)
+	}
+	if req.Search != "" {
+		query = query.Where("(title ILIKE ? OR description ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.Limit).Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &ReportListResponse{
+		Data:       reports,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetReport retrieves a report the caller owns or that has been shared
+func (s *ReportService) GetReport(id uint, generatedBy uint, generatorType string) (*models.Report, error) {
+	var report models.Report
+	if err := s.db.Scopes(visibleReports(generatedBy, generatorType)).First(&report, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report not found")
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GenerateReport (re)runs a saved report with its stored parameters and replaces its file.
+// Shared reports can be rerun by any staff member who can see them.
+func (s *ReportService) GenerateReport(id uint, generatedBy uint, generatorType string) (*models.Report, error) {
+	report, err := s.GetReport(id, generatedBy, generatorType)
+	if err != nil {
+		return nil, err
+	}
+
+	var params ReportParameters
+	if len(report.Parameters) > 0 {
+		if err := json.Unmarshal(report.Parameters, &params); err != nil {
+			return nil, fmt.Errorf("invalid report parameters: %w", err)
+		}
+	}
+
+	// Claim the report so two reruns do not write the same file at once
+	result := s.db.Model(&models.Report{}).
+		Where("id = ? AND (status <> ? OR updated_at < ?)", report.ID, models.ReportStatusGenerating, time.Now().Add(-reportGenerationTimeout)).
+		Update("status", models.ReportStatusGenerating)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("report is already generating")
+	}
+
+	path, size, err := s.writeReport(report, params)
+	if err != nil {
+		if updateErr := s.db.Model(&models.Report{}).Where("id = ?", report.ID).
+			Update("status", models.ReportStatusFailed).Error; updateErr != nil {
+			return nil, updateErr
+		}
+		return nil, err
+	}
+
+	previous := report.FilePath
+	now := time.Now()
+	expiresAt := now.Add(s.settings.Retention)
+	if err := s.db.Model(&models.Report{}).Where("id = ?", report.ID).Updates(map[string]interface{}{
+		"status":       models.ReportStatusCompleted,
+		"file_path":    path,
+		"file_size":    size,
+		"generated_at": now,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	if previous != "" && previous != path {
+		os.Remove(previous)
+	}
+
+	return s.GetReport(report.ID, generatedBy, generatorType)
+}
+
+// writeReport renders the report and saves it under the report directory
+func (s *ReportService) writeReport(report *models.Report, params ReportParameters) (string, int64, error) {
+	req := ReportRequest{
+		Type:    params.Type,
+		Format:  ReportFormat(report.Format),
+		Filters: params.Filters,
+		Title:   report.Title,
+	}
+	if params.StartDate != nil {
+		req.StartDate = *params.StartDate
+	}
+	if params.EndDate != nil {
+		req.EndDate = *params.EndDate
+	}
+
+	content, filename, err := s.analyticsService.GenerateReport(req)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := os.MkdirAll(s.settings.Directory, 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	path := filepath.Join(s.settings.Directory, fmt.Sprintf("%d_%s", report.ID, filename))
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", 0, fmt.Errorf("failed to save report file: %w", err)
+	}
+
+	return path, int64(len(content)), nil
+}
+
+// GetReportFile returns the path and download name of a generated report and counts the download
+func (s *ReportService) GetReportFile(id uint, generatedBy uint, generatorType string) (string, string, error) {
+	report, err := s.GetReport(id, generatedBy, generatorType)
+	if err != nil {
+		return "", "", err
+	}
+
+	if report.Status != models.ReportStatusCompleted {
+		return "", "", errors.New("report not generated")
+	}
+	if report.IsExpired() || report.FilePath == "" {
+		return "", "", errors.New("report expired")
+	}
+	if _, err := os.Stat(report.FilePath); err != nil {
+		return "", "", fmt.Errorf("report file unavailable: %w", err)
+	}
+
+	if err := s.db.Model(&models.Report{}).Where("id = ?", report.ID).
+		UpdateColumn("download_count", gorm.Expr("download_count + ?", 1)).Error; err != nil {
+		return "", "", err
+	}
+
+	// Drop the report ID prefix used to keep files unique on disk
+	name := strings.TrimPrefix(filepath.Base(report.FilePath), fmt.Sprintf("%d_", report.ID))
+
+	return report.FilePath, name, nil
+}
+
+// DeleteReport deletes a report and its file. Only the owner can delete a report, even a shared one.
+func (s *ReportService) DeleteReport(id uint, generatedBy uint, generatorType string) error {
+	var report models.Report
+	if err := s.db.Scopes(ownedReports(generatedBy, generatorType)).First(&report, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("report not found")
+		}
+		return err
+	}
+
+	if err := s.db.Delete(&report).Error; err != nil {
+		return err
+	}
+
+	if report.FilePath != "" {
+		os.Remove(report.FilePath)
+	}
+	return nil
+}
+
+// CleanupExpired deletes files of reports past their expiry and returns how many were removed.
+// The definitions are kept so they can be regenerated.
+func (s *ReportService) CleanupExpired(now time.Time) (int, error) {
+	var reports []models.Report
+	if err := s.db.Select("id", "file_path").
+		Where("expires_at < ? AND file_path <> ''", now).
+		Find(&reports).Error; err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, report := range reports {
+		if err := os.Remove(report.FilePath); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		if err := s.db.Model(&models.Report{}).Where("id = ?", report.ID).Update("file_path", "").Error; err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// ownedReports restricts a query to reports generated by the caller
+func ownedReports(generatedBy uint, generatorType string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("generated_by = ? AND generator_type = ?", generatedBy, generatorType)
+	}
+}
+
+// visibleReports restricts a query to the caller's reports and reports shared with all staff
+func visibleReports(generatedBy uint, generatorType string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("((generated_by = ? AND generator_type = ?) OR is_public = ?)", generatedBy, generatorType, true)
+	}
+}
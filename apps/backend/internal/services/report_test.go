@@ -0,0 +1,120 @@
+package services
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportService(t *testing.T) {
+	t.Run("every analytics report type can be saved", func(t *testing.T) {
+		for _, reportType := range []AnalyticsReportType{
+			AnalyticsReportTypeInternship,
+			AnalyticsReportTypeApproval,
+			AnalyticsReportTypeCompany,
+			AnalyticsReportTypeStudent,
+			AnalyticsReportTypeEvaluation,
+		} {
+			assert.Contains(t, savedReportTypes, reportType)
+		}
+	})
+
+	t.Run("CreateReport rejects invalid definitions before saving", func(t *testing.T) {
+		reportService := NewReportService(nil, ReportSettings{})
+
+		_, err := reportService.CreateReport(CreateReportRequest{Title: "Attendance", Type: "attendance", Format: ReportFormatPDF}, 1, "User")
+		assert.EqualError(t, err, "unsupported report type: attendance")
+
+		_, err = reportService.CreateReport(CreateReportRequest{Title: "Students", Type: AnalyticsReportTypeStudent, Format: "json"}, 1, "User")
+		assert.EqualError(t, err, "unsupported report format: json")
+
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := start.AddDate(0, 0, -1)
+		_, err = reportService.CreateReport(CreateReportRequest{Title: "Students", Type: AnalyticsReportTypeStudent, Format: ReportFormatCSV, StartDate: &start, EndDate: &end}, 1, "User")
+		assert.EqualError(t, err, "end date must be after start date")
+	})
+}
+
+func TestReportServiceFiles(t *testing.T) {
+	db := setupTestDB(t)
+	// student_enrolls is both a model and the students/course sections join table
+	require.NoError(t, db.SetupJoinTable(&models.Student{}, "CourseSections", &models.StudentEnroll{}))
+	require.NoError(t, db.SetupJoinTable(&models.CourseSection{}, "Students", &models.StudentEnroll{}))
+	require.NoError(t, db.AutoMigrate(
+		&models.Company{},
+		&models.Student{},
+		&models.StudentEnroll{},
+		&models.StudentTraining{},
+		&models.EvaluationStatusTracker{},
+		&models.Report{},
+	))
+
+	reportService := NewReportService(db, ReportSettings{Directory: t.TempDir(), Retention: time.Hour})
+	owner := string(AccountTypeStaff)
+
+	report, err := reportService.CreateReport(CreateReportRequest{
+		Title:  "Evaluations",
+		Type:   AnalyticsReportTypeEvaluation,
+		Format: ReportFormatCSV,
+	}, 9001, owner)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Unscoped().Delete(&models.Report{}, report.ID) })
+
+	t.Run("generation writes the file and sets the expiry", func(t *testing.T) {
+		generated, err := reportService.GenerateReport(report.ID, 9001, owner)
+		require.NoError(t, err)
+
+		assert.Equal(t, models.ReportStatusCompleted, generated.Status)
+		assert.FileExists(t, generated.FilePath)
+		require.NotNil(t, generated.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *generated.ExpiresAt, time.Minute)
+	})
+
+	t.Run("the owner can download the report", func(t *testing.T) {
+		path, name, err := reportService.GetReportFile(report.ID, 9001, owner)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(name, "evaluation_report_"), name)
+		assert.True(t, strings.HasSuffix(name, ".csv"), name)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), utf8BOM))
+
+		var saved models.Report
+		require.NoError(t, db.First(&saved, report.ID).Error)
+		assert.Equal(t, 1, saved.DownloadCount)
+	})
+
+	t.Run("other users cannot download or regenerate a private report", func(t *testing.T) {
+		_, _, err := reportService.GetReportFile(report.ID, 9002, owner)
+		assert.EqualError(t, err, "report not found")
+
+		// Same numeric ID, different account type
+		_, _, err = reportService.GetReportFile(report.ID, 9001, string(AccountTypeInstructor))
+		assert.EqualError(t, err, "report not found")
+
+		_, err = reportService.GenerateReport(report.ID, 9002, owner)
+		assert.EqualError(t, err, "report not found")
+	})
+
+	t.Run("cleanup removes expired files and keeps the definition", func(t *testing.T) {
+		saved, err := reportService.GetReport(report.ID, 9001, owner)
+		require.NoError(t, err)
+		path := saved.FilePath
+
+		removed, err := reportService.CleanupExpired(time.Now().Add(2 * time.Hour))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, removed, 1)
+		assert.NoFileExists(t, path)
+
+		saved, err = reportService.GetReport(report.ID, 9001, owner)
+		require.NoError(t, err)
+		assert.Empty(t, saved.FilePath)
+	})
+}
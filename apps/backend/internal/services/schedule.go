@@ -0,0 +1,1390 @@
+package services
+
+import (
+	"backend-go/internal/models"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleService handles schedule, appointment and calendar business logic (Green Flow)
+type ScheduleService struct {
+	db *gorm.DB
+}
+
+// NewScheduleService creates a new schedule service
+func NewScheduleService(db *gorm.DB) *ScheduleService {
+	return &ScheduleService{
+		db: db,
+	}
+}
+
+// ScheduleActor identifies the caller changing a schedule, appointment or calendar.
+// Numeric IDs overlap between account types, so ownership checks compare both.
+type ScheduleActor struct {
+	UserID      uint
+	AccountType string
+	IsAdmin     bool // admins may change schedules and appointments they do not organize
+}
+
+// is reports whether the actor is the account with the given ID and type
+func (a ScheduleActor) is(userID uint, accountType string) bool {
+	return a.UserID == userID && a.AccountType == accountType
+}
+
+// ScheduleAccount identifies a schedule participant by numeric ID and account type
+// ("User", "Instructor", "Staff" or "SuperAdmin"); an empty type means a student ("User")
+type ScheduleAccount struct {
+	UserID   uint   `json:"user_id" validate:"required"`
+	UserType string `json:"user_type" validate:"omitempty,oneof=User Instructor Staff SuperAdmin"`
+}
+
+// ScheduleListRequest represents the request for listing schedules
+type ScheduleListRequest struct {
+	Page              int        `json:"page"`
+	Limit             int        `json:"limit"`
+	Search            string     `json:"search"`
+	ScheduleType      string     `json:"schedule_type"`
+	Status            string     `json:"status"`
+	StudentTrainingID *uint      `json:"student_training_id"`
+	UserID            *uint      `json:"user_id"`
+	UserType          string     `json:"user_type"`
+	From              *time.Time `json:"from"`
+	To                *time.Time `json:"to"`
+	SortBy            string     `json:"sort_by"`
+	SortDesc          bool       `json:"sort_desc"`
+}
+
+// CreateScheduleRequest represents the request for creating a schedule
+type CreateScheduleRequest struct {
+	Title             string                `json:"title" validate:"required,max=255"`
+	Description       string                `json:"description"`
+	ScheduleType      models.ScheduleType   `json:"schedule_type" validate:"required,oneof=visit meeting presentation evaluation deadline reminder"`
+	StartTime         time.Time             `json:"start_time" validate:"required"`
+	EndTime           time.Time             `json:"end_time" validate:"required"`
+	Location          string                `json:"location"`
+	IsAllDay          bool                  `json:"is_all_day"`
+	RecurrenceType    models.RecurrenceType `json:"recurrence_type" validate:"omitempty,oneof=none daily weekly monthly yearly"`
+	RecurrenceEnd     *time.Time            `json:"recurrence_end"`
+	StudentTrainingID *uint                 `json:"student_training_id"`
+	CreatedBy         uint                  `json:"created_by"`
+	CreatorType       string                `json:"creator_type"`
+	Participants      []ScheduleAccount     `json:"participants" validate:"dive"`
+	ReminderMinutes   []int                 `json:"reminder_minutes" validate:"dive,min=0"`
+	CalendarIDs       []uint                `json:"calendar_ids"`
+}
+
+// UpdateScheduleRequest represents the request for updating a schedule
+type UpdateScheduleRequest struct {
+	Title          *string                `json:"title" validate:"omitempty,max=255"`
+	Description    *string                `json:"description"`
+	ScheduleType   *models.ScheduleType   `json:"schedule_type" validate:"omitempty,oneof=visit meeting presentation evaluation deadline reminder"`
+	Status         *models.ScheduleStatus `json:"status" validate:"omitempty,oneof=scheduled confirmed completed cancelled postponed"`
+	StartTime      *time.Time             `json:"start_time"`
+	EndTime        *time.Time             `json:"end_time"`
+	Location       *string                `json:"location"`
+	IsAllDay       *bool                  `json:"is_all_day"`
+	RecurrenceType *models.RecurrenceType `json:"recurrence_type" validate:"omitempty,oneof=none daily weekly monthly yearly"`
+	RecurrenceEnd  *time.Time             `json:"recurrence_end"`
+}
+
+// AddParticipantRequest represents the request for adding a schedule participant
+type AddParticipantRequest struct {
+	UserID          uint   `json:"user_id" validate:"required"`
+	UserType        string `json:"user_type" validate:"omitempty,oneof=User Instructor Staff SuperAdmin"`
+	Role            string `json:"role" validate:"omitempty,oneof=organizer participant optional"`
+	ReminderMinutes []int  `json:"reminder_minutes" validate:"dive,min=0"`
+}
+
+// RespondParticipantRequest represents a participant accepting or declining a schedule
+type RespondParticipantRequest struct {
+	Status string `json:"status" validate:"required,oneof=accepted declined"`
+}
+
+// ScheduleListResponse represents the response for listing schedules
+type ScheduleListResponse struct {
+	Data       []models.Schedule `json:"data"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// ScheduleOccurrence represents a single expanded instance of a schedule within a date range
+type ScheduleOccurrence struct {
+	ScheduleID        uint                  `json:"schedule_id"`
+	OccurrenceStart   time.Time             `json:"occurrence_start"` // original start, identifies the occurrence in the series
+	StartTime         time.Time             `json:"start_time"`
+	EndTime           time.Time             `json:"end_time"`
+	Title             string                `json:"title"`
+	Description       string                `json:"description"`
+	ScheduleType      models.ScheduleType   `json:"schedule_type"`
+	Status            models.ScheduleStatus `json:"status"`
+	Location          string                `json:"location"`
+	IsAllDay          bool                  `json:"is_all_day"`
+	IsRecurring       bool                  `json:"is_recurring"`
+	StudentTrainingID *uint                 `json:"student_training_id"`
+	ExceptionID       *uint                 `json:"exception_id"`
+	IsMoved           bool                  `json:"is_moved"`
+	IsCancelled       bool                  `json:"is_cancelled"`
+}
+
+// ScheduleExceptionRequest represents the request for cancelling or moving a single occurrence
+type ScheduleExceptionRequest struct {
+	OccurrenceStart time.Time  `json:"occurrence_start" validate:"required"`
+	Cancel          bool       `json:"cancel"`
+	StartTime       *time.Time `json:"start_time"`
+	EndTime         *time.Time `json:"end_time"`
+	Location        *string    `json:"location"`
+	Reason          string     `json:"reason"`
+	CreatedBy       uint       `json:"created_by"`
+	CreatorType     string     `json:"creator_type"`
+}
+
+// maxOccurrenceRange bounds the date range schedules may be expanded over
+const maxOccurrenceRange = 366 * 24 * time.Hour
+
+// AppointmentListRequest represents the request for listing appointments
+type AppointmentListRequest struct {
+	Page              int        `json:"page"`
+	Limit             int        `json:"limit"`
+	Status            string     `json:"status"`
+	StudentTrainingID *uint      `json:"student_training_id"`
+	RequestedBy       *uint      `json:"requested_by"`
+	RequesterType     string     `json:"requester_type"`
+	From              *time.Time `json:"from"`
+	To                *time.Time `json:"to"`
+}
+
+// CreateAppointmentRequest represents the request for booking an appointment
+type CreateAppointmentRequest struct {
+	Title             string    `json:"title" validate:"required,max=255"`
+	Description       string    `json:"description"`
+	AppointmentDate   time.Time `json:"appointment_date" validate:"required"`
+	Duration          int       `json:"duration" validate:"required,min=5,max=1440"`
+	Location          string    `json:"location"`
+	StudentTrainingID *uint     `json:"student_training_id"`
+	RequestedBy       uint      `json:"requested_by"`
+	RequesterType     string    `json:"requester_type"`
+	Notes             string    `json:"notes"`
+}
+
+// UpdateAppointmentRequest represents the request for updating an appointment
+type UpdateAppointmentRequest struct {
+	Title           *string                `json:"title" validate:"omitempty,max=255"`
+	Description     *string                `json:"description"`
+	AppointmentDate *time.Time             `json:"appointment_date"`
+	Duration        *int                   `json:"duration" validate:"omitempty,min=5,max=1440"`
+	Location        *string                `json:"location"`
+	Status          *models.ScheduleStatus `json:"status" validate:"omitempty,oneof=scheduled completed cancelled postponed"`
+	Notes           *string                `json:"notes"`
+}
+
+// ApproveAppointmentRequest represents an approval decision on an appointment
+type ApproveAppointmentRequest struct {
+	ApproverID   uint   `json:"approver_id"`
+	ApproverType string `json:"approver_type"`
+	Approved     *bool  `json:"approved"`
+	Notes        string `json:"notes"`
+}
+
+// AppointmentListResponse represents the response for listing appointments
+type AppointmentListResponse struct {
+	Data       []models.Appointment `json:"data"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// CalendarListRequest represents the request for listing calendars
+type CalendarListRequest struct {
+	OwnerID       uint   `json:"owner_id"`
+	OwnerType     string `json:"owner_type"`
+	IncludePublic bool   `json:"include_public"`
+}
+
+// CreateCalendarRequest represents the request for creating a calendar
+type CreateCalendarRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+	Color       string `json:"color" validate:"omitempty,hexcolor"`
+	IsPublic    bool   `json:"is_public"`
+	OwnerID     uint   `json:"owner_id"`
+	OwnerType   string `json:"owner_type"`
+}
+
+// UpdateCalendarRequest represents the request for updating a calendar
+type UpdateCalendarRequest struct {
+	Name        *string `json:"name" validate:"omitempty,max=255"`
+	Description *string `json:"description"`
+	Color       *string `json:"color" validate:"omitempty,hexcolor"`
+	IsPublic    *bool   `json:"is_public"`
+}
+
+// CalendarScheduleRequest represents the request for adding a schedule to a calendar
+type CalendarScheduleRequest struct {
+	ScheduleID uint `json:"schedule_id" validate:"required"`
+}
+
+// scheduleSortColumns lists the columns schedules may be sorted by
+var scheduleSortColumns = map[string]bool{
+	"title":         true,
+	"schedule_type": true,
+	"status":        true,
+	"start_time":    true,
+	"end_time":      true,
+	"created_at":    true,
+}
+
+// GetSchedules retrieves schedules with pagination and filtering
+func (s *ScheduleService) GetSchedules(req ScheduleListRequest) (*ScheduleListResponse, error) {
+	var schedules []models.Schedule
+	var total int64
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+
+	query := s.filterSchedules(s.db.Model(&models.Schedule{}).Preload("Participants"), req)
+
+	if req.From != nil {
+		query = query.Where("end_time >= ?", *req.From)
+	}
+
+	if req.To != nil {
+		query = query.Where("start_time <= ?", *req.To)
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply sorting
+	orderBy := "start_time ASC"
+	if scheduleSortColumns[req.SortBy] {
+		direction := "ASC"
+		if req.SortDesc {
+			direction = "DESC"
+		}
+		orderBy = req.SortBy + " " + direction
+	}
+	query = query.Order(orderBy)
+
+	// Apply pagination
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Offset(offset).Limit(req.Limit).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &ScheduleListResponse{
+		Data:       schedules,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ExpandSchedules returns every occurrence of the matching schedules between req.From and req.To,
+// expanding recurring series and applying per-occurrence exceptions
+func (s *ScheduleService) ExpandSchedules(req ScheduleListRequest, includeCancelled bool) ([]ScheduleOccurrence, error) {
+	if req.From == nil || req.To == nil {
+		return nil, errors.New("from and to are required")
+	}
+	from, to := *req.From, *req.To
+	if to.Before(from) {
+		return nil, errors.New("invalid occurrence range")
+	}
+	if to.Sub(from) > maxOccurrenceRange {
+		return nil, errors.New("occurrence range must not exceed 366 days")
+	}
+
+	movedIn := s.db.Model(&models.ScheduleException{}).Select("schedule_id").
+		Where("is_cancelled = ? AND new_start_time <= ? AND new_end_time >= ?", false, to, from)
+
+	var schedules []models.Schedule
+	err := s.filterSchedules(s.db.Model(&models.Schedule{}).Preload("Exceptions"), req).
+		Where(s.db.
+			Where("COALESCE(recurrence_type, ?) = ? AND start_time <= ? AND end_time >= ?", models.RecurrenceNone, models.RecurrenceNone, to, from).
+			Or("recurrence_type <> ? AND start_time <= ? AND (recurrence_end IS NULL OR recurrence_end + (end_time - start_time) >= ?)", models.RecurrenceNone, to, from).
+			Or("id IN (?)", movedIn)).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]ScheduleOccurrence, 0)
+	for i := range schedules {
+		occurrences = append(occurrences, expandSchedule(&schedules[i], from, to, includeCancelled)...)
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartTime.Before(occurrences[j].StartTime)
+	})
+
+	return occurrences, nil
+}
+
+// filterSchedules applies the non-temporal list filters to a schedule query
+func (s *ScheduleService) filterSchedules(query *gorm.DB, req ScheduleListRequest) *gorm.DB {
+	if req.ScheduleType != "" {
+		query = query.Where("schedule_type = ?", req.ScheduleType)
+	}
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if req.StudentTrainingID != nil {
+		query = query.Where("student_training_id = ?", *req.StudentTrainingID)
+	}
+
+	if req.UserID != nil {
+		query = query.Where("(created_by = ? AND creator_type = ?) OR id IN (?)", *req.UserID, req.UserType,
+			s.db.Model(&models.ScheduleParticipant{}).Select("schedule_id").Where("user_id = ? AND user_type = ?", *req.UserID, req.UserType))
+	}
+
+	if req.Search != "" {
+		query = query.Where("title ILIKE ? OR description ILIKE ? OR location ILIKE ?",
+			"%"+req.Search+"%", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	return query
+}
+
+// GetScheduleByID retrieves a schedule by ID with participants and reminders
+func (s *ScheduleService) GetScheduleByID(id uint) (*models.Schedule, error) {
+	var schedule models.Schedule
+	err := s.db.Preload("StudentTraining").
+		Preload("Participants").
+		Preload("Notifications").
+		Preload("Exceptions").
+		First(&schedule, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("schedule not found")
+		}
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// CreateSchedule creates a schedule with its participants, reminders and calendar memberships
+func (s *ScheduleService) CreateSchedule(req CreateScheduleRequest) (*models.Schedule, error) {
+	if !req.EndTime.After(req.StartTime) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	recurrenceType := req.RecurrenceType
+	if recurrenceType == "" {
+		recurrenceType = models.RecurrenceNone
+	}
+	if req.RecurrenceEnd != nil && recurrenceType != models.RecurrenceNone && req.RecurrenceEnd.Before(req.StartTime) {
+		return nil, errors.New("recurrence end must be after start time")
+	}
+
+	// Check if student training exists (if provided)
+	if req.StudentTrainingID != nil {
+		var training models.StudentTraining
+		if err := s.db.First(&training, *req.StudentTrainingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("student training not found")
+			}
+			return nil, err
+		}
+	}
+
+	schedule := &models.Schedule{
+		Title:             req.Title,
+		Description:       req.Description,
+		ScheduleType:      req.ScheduleType,
+		Status:            models.ScheduleStatusScheduled,
+		StartTime:         req.StartTime,
+		EndTime:           req.EndTime,
+		Location:          req.Location,
+		IsAllDay:          req.IsAllDay,
+		RecurrenceType:    recurrenceType,
+		RecurrenceEnd:     req.RecurrenceEnd,
+		StudentTrainingID: req.StudentTrainingID,
+		CreatedBy:         req.CreatedBy,
+		CreatorType:       req.CreatorType,
+	}
+	creator := ScheduleAccount{UserID: req.CreatedBy, UserType: req.CreatorType}.normalized()
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(schedule).Error; err != nil {
+			return err
+		}
+
+		// The creator organises the schedule and is accepted by default
+		organizer := models.ScheduleParticipant{
+			ScheduleID: schedule.ID,
+			UserID:     creator.UserID,
+			UserType:   creator.UserType,
+			Role:       models.ParticipantRoleOrganizer,
+			Status:     models.ParticipantStatusAccepted,
+		}
+		if err := tx.Create(&organizer).Error; err != nil {
+			return err
+		}
+
+		accounts := []ScheduleAccount{creator}
+		for _, account := range uniqueScheduleAccounts(req.Participants) {
+			if account == creator {
+				continue
+			}
+			participant := models.ScheduleParticipant{
+				ScheduleID: schedule.ID,
+				UserID:     account.UserID,
+				UserType:   account.UserType,
+				Role:       models.ParticipantRoleParticipant,
+				Status:     models.ParticipantStatusPending,
+			}
+			if err := tx.Create(&participant).Error; err != nil {
+				return err
+			}
+			accounts = append(accounts, account)
+		}
+
+		if err := createScheduleReminders(tx, schedule, accounts, req.ReminderMinutes); err != nil {
+			return err
+		}
+
+		for _, calendarID := range uniqueUintIDs(req.CalendarIDs) {
+			if err := addScheduleToCalendar(tx, calendarID, schedule.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetScheduleByID(schedule.ID)
+}
+
+// UpdateSchedule updates an existing schedule and reschedules pending reminders
+func (s *ScheduleService) UpdateSchedule(id uint, actor ScheduleActor, req UpdateScheduleRequest) (*models.Schedule, error) {
+	organized, err := s.getOrganizedSchedule(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	schedule := *organized
+
+	startChanged := req.StartTime != nil && !req.StartTime.Equal(schedule.StartTime)
+	recurrenceChanged := req.RecurrenceType != nil && *req.RecurrenceType != schedule.RecurrenceType
+
+	// Update fields
+	if req.Title != nil {
+		schedule.Title = *req.Title
+	}
+	if req.Description != nil {
+		schedule.Description = *req.Description
+	}
+	if req.ScheduleType != nil {
+		schedule.ScheduleType = *req.ScheduleType
+	}
+	if req.Status != nil {
+		schedule.Status = *req.Status
+	}
+	if req.StartTime != nil {
+		schedule.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		schedule.EndTime = *req.EndTime
+	}
+	if req.Location != nil {
+		schedule.Location = *req.Location
+	}
+	if req.IsAllDay != nil {
+		schedule.IsAllDay = *req.IsAllDay
+	}
+	if req.RecurrenceType != nil {
+		schedule.RecurrenceType = *req.RecurrenceType
+	}
+	if req.RecurrenceEnd != nil {
+		schedule.RecurrenceEnd = req.RecurrenceEnd
+	}
+
+	if !schedule.EndTime.After(schedule.StartTime) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&schedule).Error; err != nil {
+			return err
+		}
+
+		// Exceptions are keyed by original occurrence start and no longer line up with a reshaped series
+		if startChanged || recurrenceChanged {
+			if err := tx.Where("schedule_id = ?", schedule.ID).Delete(&models.ScheduleException{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Cancelled schedules no longer send reminders
+		if schedule.Status == models.ScheduleStatusCancelled {
+			return tx.Where("schedule_id = ? AND is_sent = ?", schedule.ID, false).
+				Delete(&models.ScheduleNotification{}).Error
+		}
+
+		if startChanged {
+			var reminders []models.ScheduleNotification
+			if err := tx.Where("schedule_id = ? AND is_sent = ?", schedule.ID, false).Find(&reminders).Error; err != nil {
+				return err
+			}
+			for _, reminder := range reminders {
+				reminder.NotifyAt = schedule.StartTime.Add(-time.Duration(reminder.NotifyBefore) * time.Minute)
+				if err := tx.Save(&reminder).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetScheduleByID(schedule.ID)
+}
+
+// DeleteSchedule deletes a schedule
+func (s *ScheduleService) DeleteSchedule(id uint, actor ScheduleActor) error {
+	schedule, err := s.getOrganizedSchedule(id, actor)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Delete(schedule).Error
+}
+
+// AddParticipant invites a user to a schedule
+func (s *ScheduleService) AddParticipant(scheduleID uint, actor ScheduleActor, req AddParticipantRequest) (*models.ScheduleParticipant, error) {
+	schedule, err := s.getOrganizedSchedule(scheduleID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	account := ScheduleAccount{UserID: req.UserID, UserType: req.UserType}.normalized()
+
+	var existing models.ScheduleParticipant
+	if err := s.db.Where("schedule_id = ? AND user_id = ? AND user_type = ?", scheduleID, account.UserID, account.UserType).First(&existing).Error; err == nil {
+		return nil, errors.New("user is already a participant")
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.ParticipantRoleParticipant
+	}
+
+	participant := &models.ScheduleParticipant{
+		ScheduleID: scheduleID,
+		UserID:     account.UserID,
+		UserType:   account.UserType,
+		Role:       role,
+		Status:     models.ParticipantStatusPending,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(participant).Error; err != nil {
+			return err
+		}
+		return createScheduleReminders(tx, schedule, []ScheduleAccount{account}, req.ReminderMinutes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return participant, nil
+}
+
+// RemoveParticipant removes a user from a schedule along with their pending reminders.
+// Participants may remove themselves; anyone else needs to organize the schedule.
+func (s *ScheduleService) RemoveParticipant(scheduleID uint, account ScheduleAccount, actor ScheduleActor) error {
+	account = account.normalized()
+	if !actor.is(account.UserID, account.UserType) {
+		if _, err := s.getOrganizedSchedule(scheduleID, actor); err != nil {
+			return err
+		}
+	}
+
+	var participant models.ScheduleParticipant
+	if err := s.db.Where("schedule_id = ? AND user_id = ? AND user_type = ?", scheduleID, account.UserID, account.UserType).First(&participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("participant not found")
+		}
+		return err
+	}
+
+	if participant.Role == models.ParticipantRoleOrganizer {
+		var organizers int64
+		if err := s.db.Model(&models.ScheduleParticipant{}).
+			Where("schedule_id = ? AND role = ?", scheduleID, models.ParticipantRoleOrganizer).
+			Count(&organizers).Error; err != nil {
+			return err
+		}
+		if organizers <= 1 {
+			return errors.New("cannot remove the last organizer")
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&participant).Error; err != nil {
+			return err
+		}
+		return tx.Where("schedule_id = ? AND user_id = ? AND user_type = ? AND is_sent = ?", scheduleID, account.UserID, account.UserType, false).
+			Delete(&models.ScheduleNotification{}).Error
+	})
+}
+
+// RespondToSchedule records the actor accepting or declining a schedule they participate in
+func (s *ScheduleService) RespondToSchedule(scheduleID uint, actor ScheduleActor, req RespondParticipantRequest) (*models.ScheduleParticipant, error) {
+	var participant models.ScheduleParticipant
+	if err := s.db.Where("schedule_id = ? AND user_id = ? AND user_type = ?", scheduleID, actor.UserID, actor.AccountType).First(&participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("participant not found")
+		}
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		participant.Status = req.Status
+		if err := tx.Save(&participant).Error; err != nil {
+			return err
+		}
+
+		// Declined participants should not be reminded
+		if req.Status == models.ParticipantStatusDeclined {
+			return tx.Where("schedule_id = ? AND user_id = ? AND user_type = ? AND is_sent = ?", scheduleID, actor.UserID, actor.AccountType, false).
+				Delete(&models.ScheduleNotification{}).Error
+		}
+
+		// Once every required participant has accepted the schedule is confirmed
+		var pending int64
+		if err := tx.Model(&models.ScheduleParticipant{}).
+			Where("schedule_id = ? AND role <> ? AND status <> ?", scheduleID, models.ParticipantRoleOptional, models.ParticipantStatusAccepted).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending == 0 {
+			return tx.Model(&models.Schedule{}).
+				Where("id = ? AND status = ?", scheduleID, models.ScheduleStatusScheduled).
+				Update("status", models.ScheduleStatusConfirmed).Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+// SetOccurrenceException cancels, moves or relocates a single occurrence of a recurring schedule.
+// An existing exception for the same occurrence is replaced.
+func (s *ScheduleService) SetOccurrenceException(scheduleID uint, actor ScheduleActor, req ScheduleExceptionRequest) (*models.ScheduleException, error) {
+	schedule, err := s.getOrganizedSchedule(scheduleID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if !schedule.IsRecurring() {
+		return nil, errors.New("schedule is not recurring")
+	}
+	if !schedule.IsOccurrence(req.OccurrenceStart) {
+		return nil, errors.New("occurrence not found")
+	}
+
+	if !req.Cancel {
+		if (req.StartTime == nil) != (req.EndTime == nil) {
+			return nil, errors.New("start time and end time must be provided together")
+		}
+		if req.StartTime == nil && req.Location == nil {
+			return nil, errors.New("exception must cancel, move or relocate the occurrence")
+		}
+		if req.StartTime != nil && !req.EndTime.After(*req.StartTime) {
+			return nil, errors.New("end time must be after start time")
+		}
+	}
+
+	var exception models.ScheduleException
+	err = s.db.Where("schedule_id = ? AND occurrence_start = ?", scheduleID, req.OccurrenceStart).First(&exception).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	exception.ScheduleID = scheduleID
+	exception.OccurrenceStart = req.OccurrenceStart
+	exception.IsCancelled = req.Cancel
+	exception.Reason = req.Reason
+	exception.CreatedBy = req.CreatedBy
+	exception.CreatorType = req.CreatorType
+	exception.NewStartTime = nil
+	exception.NewEndTime = nil
+	exception.Location = nil
+	if !req.Cancel {
+		exception.NewStartTime = req.StartTime
+		exception.NewEndTime = req.EndTime
+		exception.Location = req.Location
+	}
+
+	if err := s.db.Save(&exception).Error; err != nil {
+		return nil, err
+	}
+
+	return &exception, nil
+}
+
+// DeleteOccurrenceException restores an occurrence to its place in the series
+func (s *ScheduleService) DeleteOccurrenceException(scheduleID, exceptionID uint, actor ScheduleActor) error {
+	if _, err := s.getOrganizedSchedule(scheduleID, actor); err != nil {
+		return err
+	}
+
+	result := s.db.Where("id = ? AND schedule_id = ?", exceptionID, scheduleID).Delete(&models.ScheduleException{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("schedule exception not found")
+	}
+	return nil
+}
+
+// GetAppointments retrieves appointments with pagination and filtering
+func (s *ScheduleService) GetAppointments(req AppointmentListRequest) (*AppointmentListResponse, error) {
+	var appointments []models.Appointment
+	var total int64
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+
+	query := s.db.Model(&models.Appointment{}).
+		Preload("StudentTraining")
+
+	// Apply filters
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if req.StudentTrainingID != nil {
+		query = query.Where("student_training_id = ?", *req.StudentTrainingID)
+	}
+
+	if req.RequestedBy != nil {
+		query = query.Where("requested_by = ? AND requester_type = ?", *req.RequestedBy, req.RequesterType)
+	}
+
+	if req.From != nil {
+		query = query.Where("appointment_date >= ?", *req.From)
+	}
+
+	if req.To != nil {
+		query = query.Where("appointment_date <= ?", *req.To)
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("appointment_date ASC").Offset(offset).Limit(req.Limit).Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &AppointmentListResponse{
+		Data:       appointments,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetAppointmentByID retrieves an appointment by ID
+func (s *ScheduleService) GetAppointmentByID(id uint) (*models.Appointment, error) {
+	var appointment models.Appointment
+	err := s.db.Preload("StudentTraining").First(&appointment, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("appointment not found")
+		}
+		return nil, err
+	}
+
+	return &appointment, nil
+}
+
+// CreateAppointment books an appointment awaiting approval
+func (s *ScheduleService) CreateAppointment(req CreateAppointmentRequest) (*models.Appointment, error) {
+	// Check if student training exists (if provided)
+	if req.StudentTrainingID != nil {
+		var training models.StudentTraining
+		if err := s.db.First(&training, *req.StudentTrainingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("student training not found")
+			}
+			return nil, err
+		}
+	}
+
+	appointment := &models.Appointment{
+		Title:             req.Title,
+		Description:       req.Description,
+		AppointmentDate:   req.AppointmentDate,
+		Duration:          req.Duration,
+		Location:          req.Location,
+		Status:            models.ScheduleStatusScheduled,
+		StudentTrainingID: req.StudentTrainingID,
+		RequestedBy:       req.RequestedBy,
+		RequesterType:     req.RequesterType,
+		Notes:             req.Notes,
+	}
+
+	conflict, err := s.hasAppointmentConflict(appointment, 0)
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		return nil, errors.New("appointment time conflicts with an existing appointment")
+	}
+
+	if err := s.db.Create(appointment).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetAppointmentByID(appointment.ID)
+}
+
+// UpdateAppointment updates an appointment; moving an approved appointment requires re-approval
+func (s *ScheduleService) UpdateAppointment(id uint, actor ScheduleActor, req UpdateAppointmentRequest) (*models.Appointment, error) {
+	requested, err := s.getRequestedAppointment(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	appointment := *requested
+
+	rescheduled := (req.AppointmentDate != nil && !req.AppointmentDate.Equal(appointment.AppointmentDate)) ||
+		(req.Duration != nil && *req.Duration != appointment.Duration)
+
+	// Update fields
+	if req.Title != nil {
+		appointment.Title = *req.Title
+	}
+	if req.Description != nil {
+		appointment.Description = *req.Description
+	}
+	if req.AppointmentDate != nil {
+		appointment.AppointmentDate = *req.AppointmentDate
+	}
+	if req.Duration != nil {
+		appointment.Duration = *req.Duration
+	}
+	if req.Location != nil {
+		appointment.Location = *req.Location
+	}
+	if req.Status != nil {
+		appointment.Status = *req.Status
+	}
+	if req.Notes != nil {
+		appointment.Notes = *req.Notes
+	}
+
+	if rescheduled {
+		conflict, err := s.hasAppointmentConflict(&appointment, appointment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if conflict {
+			return nil, errors.New("appointment time conflicts with an existing appointment")
+		}
+
+		if appointment.IsApproved() {
+			appointment.ApprovedBy = nil
+			appointment.ApproverType = nil
+			appointment.ApprovedAt = nil
+			if appointment.Status == models.ScheduleStatusConfirmed {
+				appointment.Status = models.ScheduleStatusScheduled
+			}
+		}
+	}
+
+	if err := s.db.Save(&appointment).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetAppointmentByID(appointment.ID)
+}
+
+// ApproveAppointment approves or rejects a requested appointment
+func (s *ScheduleService) ApproveAppointment(id uint, req ApproveAppointmentRequest) (*models.Appointment, error) {
+	var appointment models.Appointment
+	if err := s.db.First(&appointment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("appointment not found")
+		}
+		return nil, err
+	}
+
+	if appointment.Status != models.ScheduleStatusScheduled && appointment.Status != models.ScheduleStatusPostponed {
+		return nil, errors.New("appointment is not awaiting approval")
+	}
+
+	if req.ApproverID == appointment.RequestedBy && req.ApproverType == appointment.RequesterType {
+		return nil, errors.New("appointment cannot be approved by its requester")
+	}
+
+	approved := req.Approved == nil || *req.Approved
+	if approved {
+		conflict, err := s.hasAppointmentConflict(&appointment, appointment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if conflict {
+			return nil, errors.New("appointment time conflicts with an existing appointment")
+		}
+
+		now := time.Now()
+		appointment.Status = models.ScheduleStatusConfirmed
+		appointment.ApprovedBy = &req.ApproverID
+		appointment.ApproverType = &req.ApproverType
+		appointment.ApprovedAt = &now
+	} else {
+		appointment.Status = models.ScheduleStatusCancelled
+	}
+
+	if req.Notes != "" {
+		if appointment.Notes != "" {
+			appointment.Notes += "\n"
+		}
+		appointment.Notes += req.Notes
+	}
+
+	if err := s.db.Save(&appointment).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetAppointmentByID(appointment.ID)
+}
+
+// DeleteAppointment deletes an appointment
+func (s *ScheduleService) DeleteAppointment(id uint, actor ScheduleActor) error {
+	appointment, err := s.getRequestedAppointment(id, actor)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Delete(appointment).Error
+}
+
+// GetCalendars retrieves the calendars owned by a user, optionally including public calendars
+func (s *ScheduleService) GetCalendars(req CalendarListRequest) ([]models.Calendar, error) {
+	var calendars []models.Calendar
+
+	query := s.db.Model(&models.Calendar{})
+	if req.IncludePublic {
+		query = query.Where("(owner_id = ? AND owner_type = ?) OR is_public = ?", req.OwnerID, req.OwnerType, true)
+	} else {
+		query = query.Where("owner_id = ? AND owner_type = ?", req.OwnerID, req.OwnerType)
+	}
+
+	if err := query.Order("name ASC").Find(&calendars).Error; err != nil {
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+// GetCalendarByID retrieves a calendar and its schedules if the actor may view it
+func (s *ScheduleService) GetCalendarByID(id uint, actor ScheduleActor) (*models.Calendar, error) {
+	var calendar models.Calendar
+	err := s.db.Preload("Schedules", func(db *gorm.DB) *gorm.DB {
+		return db.Order("start_time ASC")
+	}).First(&calendar, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("calendar not found")
+		}
+		return nil, err
+	}
+
+	if !actor.is(calendar.OwnerID, calendar.OwnerType) && !calendar.IsPublic {
+		return nil, errors.New("calendar access denied")
+	}
+
+	return &calendar, nil
+}
+
+// CreateCalendar creates a calendar owned by the requesting user
+func (s *ScheduleService) CreateCalendar(req CreateCalendarRequest) (*models.Calendar, error) {
+	calendar := &models.Calendar{
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+		IsPublic:    req.IsPublic,
+		OwnerID:     req.OwnerID,
+		OwnerType:   req.OwnerType,
+	}
+	if calendar.Color == "" {
+		calendar.Color = "#3498db"
+	}
+
+	if err := s.db.Create(calendar).Error; err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// UpdateCalendar updates a calendar owned by the actor
+func (s *ScheduleService) UpdateCalendar(id uint, actor ScheduleActor, req UpdateCalendarRequest) (*models.Calendar, error) {
+	calendar, err := s.getOwnedCalendar(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields
+	if req.Name != nil {
+		calendar.Name = *req.Name
+	}
+	if req.Description != nil {
+		calendar.Description = *req.Description
+	}
+	if req.Color != nil {
+		calendar.Color = *req.Color
+	}
+	if req.IsPublic != nil {
+		calendar.IsPublic = *req.IsPublic
+	}
+
+	if err := s.db.Save(calendar).Error; err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// DeleteCalendar deletes a calendar owned by the actor; its schedules are kept
+func (s *ScheduleService) DeleteCalendar(id uint, actor ScheduleActor) error {
+	calendar, err := s.getOwnedCalendar(id, actor)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("calendar_id = ?", calendar.ID).Delete(&models.CalendarSchedule{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(calendar).Error
+	})
+}
+
+// AddScheduleToCalendar adds an existing schedule to a calendar owned by the actor
+func (s *ScheduleService) AddScheduleToCalendar(calendarID uint, actor ScheduleActor, req CalendarScheduleRequest) error {
+	if _, err := s.getOwnedCalendar(calendarID, actor); err != nil {
+		return err
+	}
+
+	var schedule models.Schedule
+	if err := s.db.First(&schedule, req.ScheduleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("schedule not found")
+		}
+		return err
+	}
+
+	return addScheduleToCalendar(s.db, calendarID, req.ScheduleID)
+}
+
+// RemoveScheduleFromCalendar removes a schedule from a calendar owned by the actor
+func (s *ScheduleService) RemoveScheduleFromCalendar(calendarID, scheduleID uint, actor ScheduleActor) error {
+	if _, err := s.getOwnedCalendar(calendarID, actor); err != nil {
+		return err
+	}
+
+	result := s.db.Where("calendar_id = ? AND schedule_id = ?", calendarID, scheduleID).Delete(&models.CalendarSchedule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("schedule not in calendar")
+	}
+
+	return nil
+}
+
+// getOwnedCalendar loads a calendar and verifies the actor owns it
+func (s *ScheduleService) getOwnedCalendar(id uint, actor ScheduleActor) (*models.Calendar, error) {
+	var calendar models.Calendar
+	if err := s.db.First(&calendar, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("calendar not found")
+		}
+		return nil, err
+	}
+
+	if !actor.is(calendar.OwnerID, calendar.OwnerType) {
+		return nil, errors.New("calendar access denied")
+	}
+
+	return &calendar, nil
+}
+
+// getOrganizedSchedule loads a schedule and verifies the actor is one of its organizers or an admin
+func (s *ScheduleService) getOrganizedSchedule(id uint, actor ScheduleActor) (*models.Schedule, error) {
+	var schedule models.Schedule
+	if err := s.db.First(&schedule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("schedule not found")
+		}
+		return nil, err
+	}
+
+	if actor.IsAdmin {
+		return &schedule, nil
+	}
+
+	var organizers int64
+	if err := s.db.Model(&models.ScheduleParticipant{}).
+		Where("schedule_id = ? AND user_id = ? AND user_type = ? AND role = ?", id, actor.UserID, actor.AccountType, models.ParticipantRoleOrganizer).
+		Count(&organizers).Error; err != nil {
+		return nil, err
+	}
+	if organizers == 0 {
+		return nil, errors.New("schedule access denied")
+	}
+
+	return &schedule, nil
+}
+
+// getRequestedAppointment loads an appointment and verifies the actor requested it or is an admin
+func (s *ScheduleService) getRequestedAppointment(id uint, actor ScheduleActor) (*models.Appointment, error) {
+	var appointment models.Appointment
+	if err := s.db.First(&appointment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("appointment not found")
+		}
+		return nil, err
+	}
+
+	if !actor.IsAdmin && !actor.is(appointment.RequestedBy, appointment.RequesterType) {
+		return nil, errors.New("appointment access denied")
+	}
+
+	return &appointment, nil
+}
+
+// hasAppointmentConflict checks for overlapping active appointments of the same training
+func (s *ScheduleService) hasAppointmentConflict(appointment *models.Appointment, excludeID uint) (bool, error) {
+	if appointment.StudentTrainingID == nil {
+		return false, nil
+	}
+
+	var candidates []models.Appointment
+	query := s.db.Where("student_training_id = ? AND status IN ?", *appointment.StudentTrainingID,
+		[]models.ScheduleStatus{models.ScheduleStatusScheduled, models.ScheduleStatusConfirmed}).
+		Where("appointment_date < ?", appointment.GetEndTime())
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Find(&candidates).Error; err != nil {
+		return false, err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.GetEndTime().After(appointment.AppointmentDate) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// expandSchedule expands a single schedule into its occurrences overlapping [from, to]
+func expandSchedule(schedule *models.Schedule, from, to time.Time, includeCancelled bool) []ScheduleOccurrence {
+	exceptions := make(map[int64]*models.ScheduleException, len(schedule.Exceptions))
+	for i := range schedule.Exceptions {
+		exceptions[schedule.Exceptions[i].OccurrenceStart.Unix()] = &schedule.Exceptions[i]
+	}
+
+	newOccurrence := func(occurrenceStart, start, end time.Time) ScheduleOccurrence {
+		return ScheduleOccurrence{
+			ScheduleID:        schedule.ID,
+			OccurrenceStart:   occurrenceStart,
+			StartTime:         start,
+			EndTime:           end,
+			Title:             schedule.Title,
+			Description:       schedule.Description,
+			ScheduleType:      schedule.ScheduleType,
+			Status:            schedule.Status,
+			Location:          schedule.Location,
+			IsAllDay:          schedule.IsAllDay,
+			IsRecurring:       schedule.IsRecurring(),
+			StudentTrainingID: schedule.StudentTrainingID,
+		}
+	}
+
+	var occurrences []ScheduleOccurrence
+	for _, start := range schedule.Occurrences(from, to) {
+		occurrence := newOccurrence(start, start, start.Add(schedule.Duration()))
+
+		if exception, ok := exceptions[start.Unix()]; ok {
+			// Moved occurrences are emitted at their new time below
+			if exception.IsMoved() {
+				continue
+			}
+			occurrence.ExceptionID = &exception.ID
+			if exception.IsCancelled {
+				if !includeCancelled {
+					continue
+				}
+				occurrence.IsCancelled = true
+				occurrence.Status = models.ScheduleStatusCancelled
+			}
+			if exception.Location != nil {
+				occurrence.Location = *exception.Location
+			}
+		}
+
+		occurrences = append(occurrences, occurrence)
+	}
+
+	for i := range schedule.Exceptions {
+		exception := &schedule.Exceptions[i]
+		if !exception.IsMoved() || exception.NewStartTime.After(to) || !exception.NewEndTime.After(from) {
+			continue
+		}
+		if !schedule.IsOccurrence(exception.OccurrenceStart) {
+			continue
+		}
+
+		occurrence := newOccurrence(exception.OccurrenceStart, *exception.NewStartTime, *exception.NewEndTime)
+		occurrence.ExceptionID = &exception.ID
+		occurrence.IsMoved = true
+		if exception.Location != nil {
+			occurrence.Location = *exception.Location
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return occurrences
+}
+
+// addScheduleToCalendar links a schedule to a calendar, ignoring existing links
+func addScheduleToCalendar(tx *gorm.DB, calendarID, scheduleID uint) error {
+	var count int64
+	if err := tx.Model(&models.Calendar{}).Where("id = ?", calendarID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("calendar not found")
+	}
+
+	link := models.CalendarSchedule{
+		CalendarID: calendarID,
+		ScheduleID: scheduleID,
+	}
+	return tx.Where(link).FirstOrCreate(&link).Error
+}
+
+// createScheduleReminders creates one reminder per participant and lead time that is still in the future
+func createScheduleReminders(tx *gorm.DB, schedule *models.Schedule, accounts []ScheduleAccount, minutesBefore []int) error {
+	now := time.Now()
+	for _, account := range accounts {
+		for _, minutes := range uniqueInts(minutesBefore) {
+			notifyAt := schedule.StartTime.Add(-time.Duration(minutes) * time.Minute)
+			if notifyAt.Before(now) {
+				continue
+			}
+
+			reminder := models.ScheduleNotification{
+				ScheduleID:   schedule.ID,
+				UserID:       account.UserID,
+				UserType:     account.UserType,
+				NotifyBefore: minutes,
+				NotifyAt:     notifyAt,
+			}
+			if err := tx.Create(&reminder).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// uniqueUintIDs removes duplicate and zero IDs while keeping order
+func uniqueUintIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+// normalized returns the account with an empty type defaulted to a student ("User")
+func (a ScheduleAccount) normalized() ScheduleAccount {
+	if a.UserType == "" {
+		a.UserType = string(AccountTypeStudent)
+	}
+	return a
+}
+
+// uniqueScheduleAccounts normalizes accounts and removes duplicates and zero IDs while keeping order
+func uniqueScheduleAccounts(accounts []ScheduleAccount) []ScheduleAccount {
+	seen := make(map[ScheduleAccount]bool, len(accounts))
+	result := make([]ScheduleAccount, 0, len(accounts))
+	for _, account := range accounts {
+		account = account.normalized()
+		if account.UserID == 0 || seen[account] {
+			continue
+		}
+		seen[account] = true
+		result = append(result, account)
+	}
+	return result
+}
+
+// uniqueInts removes duplicate values while keeping order
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
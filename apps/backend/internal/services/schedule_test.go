@@ -0,0 +1,196 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleAccounts(t *testing.T) {
+	t.Run("duplicates collapse per account type and an empty type means a student", func(t *testing.T) {
+		accounts := uniqueScheduleAccounts([]ScheduleAccount{
+			{UserID: 5},
+			{UserID: 5, UserType: string(AccountTypeStudent)},
+			{UserID: 5, UserType: string(AccountTypeInstructor)},
+			{UserID: 0, UserType: string(AccountTypeInstructor)},
+			{UserID: 6},
+		})
+
+		assert.Equal(t, []ScheduleAccount{
+			{UserID: 5, UserType: string(AccountTypeStudent)},
+			{UserID: 5, UserType: string(AccountTypeInstructor)},
+			{UserID: 6, UserType: string(AccountTypeStudent)},
+		}, accounts)
+	})
+
+	t.Run("actors match on ID and account type", func(t *testing.T) {
+		actor := ScheduleActor{UserID: 5, AccountType: string(AccountTypeInstructor)}
+		assert.True(t, actor.is(5, string(AccountTypeInstructor)))
+		assert.False(t, actor.is(5, string(AccountTypeStudent)))
+		assert.False(t, actor.is(6, string(AccountTypeInstructor)))
+	})
+}
+
+func TestScheduleService(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.Schedule{},
+		&models.ScheduleParticipant{},
+		&models.ScheduleNotification{},
+		&models.ScheduleException{},
+		&models.Appointment{},
+		&models.Calendar{},
+	))
+
+	scheduleService := NewScheduleService(db)
+	student := ScheduleActor{UserID: 9001, AccountType: string(AccountTypeStudent)}
+	instructor := ScheduleActor{UserID: 9001, AccountType: string(AccountTypeInstructor)}
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+
+	schedule, err := scheduleService.CreateSchedule(CreateScheduleRequest{
+		Title:        "Supervision meeting",
+		ScheduleType: models.ScheduleTypeMeeting,
+		StartTime:    start,
+		EndTime:      start.Add(time.Hour),
+		CreatedBy:    student.UserID,
+		CreatorType:  student.AccountType,
+		Participants: []ScheduleAccount{
+			{UserID: student.UserID},
+			{UserID: instructor.UserID, UserType: instructor.AccountType},
+			{UserID: instructor.UserID, UserType: instructor.AccountType},
+			{UserID: 9002},
+		},
+		ReminderMinutes: []int{60},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Where("schedule_id = ?", schedule.ID).Delete(&models.ScheduleNotification{})
+		db.Where("schedule_id = ?", schedule.ID).Delete(&models.ScheduleParticipant{})
+		db.Unscoped().Delete(&models.Schedule{}, schedule.ID)
+	})
+
+	t.Run("participants are deduplicated per account", func(t *testing.T) {
+		roles := make(map[ScheduleAccount]string, len(schedule.Participants))
+		for _, participant := range schedule.Participants {
+			roles[ScheduleAccount{UserID: participant.UserID, UserType: participant.UserType}] = participant.Role
+		}
+		assert.Equal(t, map[ScheduleAccount]string{
+			{UserID: 9001, UserType: student.AccountType}:    models.ParticipantRoleOrganizer,
+			{UserID: 9001, UserType: instructor.AccountType}: models.ParticipantRoleParticipant,
+			{UserID: 9002, UserType: student.AccountType}:    models.ParticipantRoleParticipant,
+		}, roles)
+		assert.Len(t, schedule.Notifications, 3)
+
+		_, err := scheduleService.AddParticipant(schedule.ID, student, AddParticipantRequest{
+			UserID:   instructor.UserID,
+			UserType: instructor.AccountType,
+		})
+		assert.EqualError(t, err, "user is already a participant")
+	})
+
+	t.Run("an account of another type with the same ID does not organize the schedule", func(t *testing.T) {
+		title := "Renamed"
+		_, err := scheduleService.UpdateSchedule(schedule.ID, instructor, UpdateScheduleRequest{Title: &title})
+		assert.EqualError(t, err, "schedule access denied")
+
+		err = scheduleService.RemoveParticipant(schedule.ID, ScheduleAccount{UserID: 9002}, instructor)
+		assert.EqualError(t, err, "schedule access denied")
+
+		updated, err := scheduleService.UpdateSchedule(schedule.ID, student, UpdateScheduleRequest{Title: &title})
+		require.NoError(t, err)
+		assert.Equal(t, title, updated.Title)
+	})
+
+	t.Run("participants respond for their own account only", func(t *testing.T) {
+		participant, err := scheduleService.RespondToSchedule(schedule.ID, instructor, RespondParticipantRequest{Status: models.ParticipantStatusDeclined})
+		require.NoError(t, err)
+		assert.Equal(t, instructor.AccountType, participant.UserType)
+		assert.Equal(t, models.ParticipantStatusDeclined, participant.Status)
+
+		var reminders int64
+		db.Model(&models.ScheduleNotification{}).
+			Where("schedule_id = ? AND user_id = ? AND user_type = ?", schedule.ID, instructor.UserID, instructor.AccountType).
+			Count(&reminders)
+		assert.Zero(t, reminders)
+
+		var organizer models.ScheduleParticipant
+		require.NoError(t, db.Where("schedule_id = ? AND user_type = ?", schedule.ID, student.AccountType).First(&organizer).Error)
+		assert.Equal(t, models.ParticipantStatusAccepted, organizer.Status)
+	})
+
+	t.Run("calendars are owned by one account type", func(t *testing.T) {
+		calendar, err := scheduleService.CreateCalendar(CreateCalendarRequest{
+			Name:      "Advising",
+			OwnerID:   instructor.UserID,
+			OwnerType: instructor.AccountType,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Delete(&models.Calendar{}, calendar.ID) })
+
+		_, err = scheduleService.GetCalendarByID(calendar.ID, student)
+		assert.EqualError(t, err, "calendar access denied")
+		assert.EqualError(t, scheduleService.DeleteCalendar(calendar.ID, student), "calendar access denied")
+
+		_, err = scheduleService.GetCalendarByID(calendar.ID, instructor)
+		assert.NoError(t, err)
+	})
+
+	t.Run("appointment approval and rescheduling", func(t *testing.T) {
+		appointment, err := scheduleService.CreateAppointment(CreateAppointmentRequest{
+			Title:           "Progress check",
+			AppointmentDate: start,
+			Duration:        30,
+			RequestedBy:     student.UserID,
+			RequesterType:   student.AccountType,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Unscoped().Delete(&models.Appointment{}, appointment.ID) })
+		assert.Equal(t, models.ScheduleStatusScheduled, appointment.Status)
+
+		_, err = scheduleService.ApproveAppointment(appointment.ID, ApproveAppointmentRequest{
+			ApproverID:   student.UserID,
+			ApproverType: student.AccountType,
+		})
+		assert.EqualError(t, err, "appointment cannot be approved by its requester")
+
+		duration := 45
+		_, err = scheduleService.UpdateAppointment(appointment.ID, instructor, UpdateAppointmentRequest{Duration: &duration})
+		assert.EqualError(t, err, "appointment access denied")
+
+		// An instructor sharing the requester's ID is a different account and may approve
+		approved, err := scheduleService.ApproveAppointment(appointment.ID, ApproveAppointmentRequest{
+			ApproverID:   instructor.UserID,
+			ApproverType: instructor.AccountType,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.ScheduleStatusConfirmed, approved.Status)
+		require.NotNil(t, approved.ApproverType)
+		assert.Equal(t, instructor.AccountType, *approved.ApproverType)
+
+		_, err = scheduleService.ApproveAppointment(appointment.ID, ApproveAppointmentRequest{
+			ApproverID:   instructor.UserID,
+			ApproverType: instructor.AccountType,
+		})
+		assert.EqualError(t, err, "appointment is not awaiting approval")
+
+		// Moving a confirmed appointment sends it back for approval
+		rescheduled, err := scheduleService.UpdateAppointment(appointment.ID, student, UpdateAppointmentRequest{Duration: &duration})
+		require.NoError(t, err)
+		assert.Equal(t, models.ScheduleStatusScheduled, rescheduled.Status)
+		assert.Nil(t, rescheduled.ApprovedBy)
+		assert.Nil(t, rescheduled.ApproverType)
+
+		rejected := false
+		cancelled, err := scheduleService.ApproveAppointment(appointment.ID, ApproveAppointmentRequest{
+			ApproverID:   instructor.UserID,
+			ApproverType: instructor.AccountType,
+			Approved:     &rejected,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.ScheduleStatusCancelled, cancelled.Status)
+	})
+}
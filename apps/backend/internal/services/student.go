@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"backend-go/internal/database"
 	"backend-go/internal/models"
 	"gorm.io/gorm"
 )
@@ -20,6 +22,13 @@ func NewStudentService(db *gorm.DB) *StudentService {
 	}
 }
 
+// WithContext returns a copy of the service bound to the request context
+func (s *StudentService) WithContext(ctx context.Context) *StudentService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	return &bound
+}
+
 // StudentListRequest represents the request for listing students
 type StudentListRequest struct {
 	Page       int    `json:"page"`
@@ -31,6 +40,9 @@ type StudentListRequest struct {
 	CampusID   *uint  `json:"campus_id"`
 	SortBy     string `json:"sort_by"`
 	SortDesc   bool   `json:"sort_desc"`
+
+	// Scope restricts results to the caller's visible students (nil means unrestricted)
+	Scope *DataScope `json:"-"`
 }
 
 // StudentListResponse represents the response for listing students
@@ -102,7 +114,8 @@ func (s *StudentService) GetStudents(req StudentListRequest) (*StudentListRespon
 		Preload("Program").
 		Preload("Curriculum").
 		Preload("Faculty").
-		Preload("Campus")
+		Preload("Campus").
+		Scopes(req.Scope.Students("students.id"))
 
 	// Apply search filter
 	if req.Search != "" {
@@ -200,10 +213,13 @@ func (s *StudentService) GetStudentByID(id uint) (*models.Student, error) {
 
 // CreateStudent creates a new student
 func (s *StudentService) CreateStudent(req CreateStudentRequest) (*models.Student, error) {
-	// Check if student ID already exists
+	// Check if student ID already exists, including students in the recycle bin
 	var existingStudent models.Student
-	err := s.db.Where("student_id = ?", req.StudentID).First(&existingStudent).Error
+	err := s.db.Unscoped().Where("student_id = ?", req.StudentID).First(&existingStudent).Error
 	if err == nil {
+		if existingStudent.DeletedAt.Valid {
+			return nil, errors.New("student with this student ID is in the recycle bin")
+		}
 		return nil, errors.New("student with this student ID already exists")
 	}
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -316,7 +332,7 @@ func (s *StudentService) UpdateStudent(id uint, req UpdateStudentRequest) (*mode
 	// Check if student ID is being updated and if it already exists
 	if req.StudentID != nil && *req.StudentID != student.StudentID {
 		var existingStudent models.Student
-		err := s.db.Where("student_id = ? AND id != ?", *req.StudentID, id).First(&existingStudent).Error
+		err := s.db.Unscoped().Where("student_id = ? AND id != ?", *req.StudentID, id).First(&existingStudent).Error
 		if err == nil {
 			return nil, errors.New("student with this student ID already exists")
 		}
@@ -442,7 +458,7 @@ func (s *StudentService) UpdateStudent(id uint, req UpdateStudentRequest) (*mode
 	return &student, nil
 }
 
-// DeleteStudent deletes a student by ID
+// DeleteStudent moves a student to the recycle bin by ID
 func (s *StudentService) DeleteStudent(id uint) error {
 	var student models.Student
 	err := s.db.First(&student, id).Error
@@ -453,7 +469,8 @@ func (s *StudentService) DeleteStudent(id uint) error {
 		return fmt.Errorf("database error: %w", err)
 	}
 
-	err = s.db.Delete(&student).Error
+	// Soft delete; enrollments and trainings go to the recycle bin with the student
+	err = models.SoftDeleteSession(s.db).Delete(&student).Error
 	if err != nil {
 		return fmt.Errorf("failed to delete student: %w", err)
 	}
@@ -589,11 +606,6 @@ type BulkDeleteStudentsRequest struct {
 	StudentIDs []uint `json:"student_ids" validate:"required,min=1"`
 }
 
-// BulkCreateFromExcelRequest represents the request for bulk creating students from Excel
-type BulkCreateFromExcelRequest struct {
-	FilePath string `json:"file_path" validate:"required"`
-}
-
 // StudentSearchRequest represents advanced search request
 type StudentSearchRequest struct {
 	Query        string   `json:"query"`
@@ -605,13 +617,16 @@ type StudentSearchRequest struct {
 	GPAXMax      *float64 `json:"gpax_max"`
 	HasTraining  *bool    `json:"has_training"`
 	TrainingYear *int     `json:"training_year"`
+
+	// Scope restricts results to the caller's visible students (nil means unrestricted)
+	Scope *DataScope `json:"-"`
 }
 
-// BulkDeleteStudents deletes multiple students
+// BulkDeleteStudents moves multiple students to the recycle bin
 func (s *StudentService) BulkDeleteStudents(req BulkDeleteStudentsRequest) error {
 	// Check if any students have active trainings
 	var trainingCount int64
-	err := s.db.Table("student_trainings").
+	err := s.db.Model(&models.StudentTraining{}).
 		Joins("JOIN student_enrolls ON student_trainings.student_enroll_id = student_enrolls.id").
 		Where("student_enrolls.student_id IN ?", req.StudentIDs).
 		Count(&trainingCount).Error
@@ -623,8 +638,17 @@ func (s *StudentService) BulkDeleteStudents(req BulkDeleteStudentsRequest) error
 		return errors.New("cannot delete students with active trainings")
 	}
 
-	// Delete students
-	err = s.db.Where("id IN ?", req.StudentIDs).Delete(&models.Student{}).Error
+	// Load the students so the delete hook runs for each of them
+	var students []models.Student
+	if err := s.db.Where("id IN ?", req.StudentIDs).Find(&students).Error; err != nil {
+		return fmt.Errorf("failed to fetch students: %w", err)
+	}
+	if len(students) == 0 {
+		return nil
+	}
+
+	// Soft delete; enrollments and trainings go to the recycle bin with the students
+	err = models.SoftDeleteSession(s.db).Delete(&students).Error
 	if err != nil {
 		return fmt.Errorf("failed to bulk delete students: %w", err)
 	}
@@ -639,7 +663,8 @@ func (s *StudentService) AdvancedSearch(req StudentSearchRequest) ([]models.Stud
 		Preload("Major").
 		Preload("Program").
 		Preload("Faculty").
-		Preload("Campus")
+		Preload("Campus").
+		Scopes(req.Scope.Students("students.id"))
 
 	// Text search
 	if req.Query != "" {
@@ -702,13 +727,14 @@ func (s *StudentService) AdvancedSearch(req StudentSearchRequest) ([]models.Stud
 	return students, nil
 }
 
-// GetStudentAnalytics returns detailed student analytics
-func (s *StudentService) GetStudentAnalytics() (map[string]interface{}, error) {
+// GetStudentAnalytics returns detailed student analytics over the students visible in the scope
+func (s *StudentService) GetStudentAnalytics(scope *DataScope) (map[string]interface{}, error) {
 	analytics := make(map[string]interface{})
+	visible := scope.Students("students.id")
 
 	// Basic counts
 	var totalStudents int64
-	err := s.db.Model(&models.Student{}).Count(&totalStudents).Error
+	err := s.db.Model(&models.Student{}).Scopes(visible).Count(&totalStudents).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to count total students: %w", err)
 	}
@@ -720,6 +746,7 @@ func (s *StudentService) GetStudentAnalytics() (map[string]interface{}, error) {
 		Joins("JOIN student_enrolls ON students.id = student_enrolls.student_id").
 		Joins("JOIN student_trainings ON student_enrolls.id = student_trainings.student_enroll_id").
 		Where("student_trainings.end_date > NOW()").
+		Scopes(visible).
 		Count(&activeTrainings).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to count active trainings: %w", err)
@@ -731,21 +758,20 @@ func (s *StudentService) GetStudentAnalytics() (map[string]interface{}, error) {
 		Range string `json:"range"`
 		Count int64  `json:"count"`
 	}
-	err = s.db.Raw(`
-		SELECT 
-			CASE 
+	err = s.db.Table("students").
+		Select(`CASE
 				WHEN gpax >= 3.5 THEN 'Excellent (3.5-4.0)'
 				WHEN gpax >= 3.0 THEN 'Good (3.0-3.49)'
 				WHEN gpax >= 2.5 THEN 'Fair (2.5-2.99)'
 				WHEN gpax >= 2.0 THEN 'Poor (2.0-2.49)'
 				ELSE 'Very Poor (<2.0)'
 			END as range,
-			COUNT(*) as count
-		FROM students 
-		WHERE gpax > 0
-		GROUP BY range
-		ORDER BY MIN(gpax) DESC
-	`).Scan(&gpaxStats).Error
+			COUNT(*) as count`).
+		Where("gpax > 0").
+		Scopes(visible).
+		Group("range").
+		Order("MIN(gpax) DESC").
+		Scan(&gpaxStats).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get GPAX statistics: %w", err)
 	}
@@ -760,6 +786,7 @@ func (s *StudentService) GetStudentAnalytics() (map[string]interface{}, error) {
 	err = s.db.Table("students").
 		Select("students.major_id, majors.name as major_name, COUNT(*) as count").
 		Joins("LEFT JOIN majors ON students.major_id = majors.id").
+		Scopes(visible).
 		Group("students.major_id, majors.name").
 		Order("count DESC").
 		Scan(&majorStats).Error
@@ -777,6 +804,7 @@ func (s *StudentService) GetStudentAnalytics() (map[string]interface{}, error) {
 	err = s.db.Table("students").
 		Select("students.faculty_id, faculties.name as faculty_name, COUNT(*) as count").
 		Joins("LEFT JOIN faculties ON students.faculty_id = faculties.id").
+		Scopes(visible).
 		Group("students.faculty_id, faculties.name").
 		Order("count DESC").
 		Scan(&facultyStats).Error
@@ -790,15 +818,13 @@ func (s *StudentService) GetStudentAnalytics() (map[string]interface{}, error) {
 		Month string `json:"month"`
 		Count int64  `json:"count"`
 	}
-	err = s.db.Raw(`
-		SELECT 
-			DATE_FORMAT(created_at, '%Y-%m') as month,
-			COUNT(*) as count
-		FROM students 
-		WHERE created_at >= DATE_SUB(NOW(), INTERVAL 12 MONTH)
-		GROUP BY DATE_FORMAT(created_at, '%Y-%m')
-		ORDER BY month
-	`).Scan(&enrollmentTrends).Error
+	err = s.db.Table("students").
+		Select("DATE_FORMAT(created_at, '%Y-%m') as month, COUNT(*) as count").
+		Where("created_at >= DATE_SUB(NOW(), INTERVAL 12 MONTH)").
+		Scopes(visible).
+		Group("DATE_FORMAT(created_at, '%Y-%m')").
+		Order("month").
+		Scan(&enrollmentTrends).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get enrollment trends: %w", err)
 	}
@@ -808,6 +834,6 @@ func (s *StudentService) GetStudentAnalytics() (map[string]interface{}, error) {
 }
 
 // GetStudentStats returns student statistics (legacy method)
-func (s *StudentService) GetStudentStats() (map[string]interface{}, error) {
-	return s.GetStudentAnalytics()
+func (s *StudentService) GetStudentStats(scope *DataScope) (map[string]interface{}, error) {
+	return s.GetStudentAnalytics(scope)
 }
\ No newline at end of file
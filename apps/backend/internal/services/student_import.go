@@ -0,0 +1,546 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"backend-go/internal/database"
+	"backend-go/internal/models"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// studentImportMaxRows caps the data rows of one roster
+const studentImportMaxRows = 2000
+
+// Row actions of a student import
+const (
+	StudentImportCreate = "create"
+	StudentImportUpdate = "update"
+)
+
+// studentImportColumns maps the accepted (normalized) header names to import fields
+var studentImportColumns = map[string]string{
+	"student_id":   "student_id",
+	"student_code": "student_id",
+	"name":         "name",
+	"first_name":   "name",
+	"middle_name":  "middle_name",
+	"surname":      "surname",
+	"last_name":    "surname",
+	"email":        "email",
+	"phone":        "phone_number",
+	"phone_number": "phone_number",
+	"gpax":         "gpax",
+	"gpa":          "gpax",
+	"faculty":      "faculty_code",
+	"faculty_code": "faculty_code",
+	"program":      "program_code",
+	"program_code": "program_code",
+	"major":        "major_code",
+	"major_code":   "major_code",
+}
+
+// studentImportRequiredColumns must be present in the header row
+var studentImportRequiredColumns = []string{"student_id", "name", "surname", "email", "faculty_code"}
+
+// StudentImportService imports registrar rosters (CSV or XLSX) into students and their user accounts
+type StudentImportService struct {
+	db                 *gorm.DB
+	studentIdValidator *StudentIdValidationService
+	emailValidator     *EmailValidationService
+	passwordSecurity   *PasswordSecurityService
+}
+
+// NewStudentImportService creates a new student import service instance
+func NewStudentImportService(db *gorm.DB) *StudentImportService {
+	return &StudentImportService{
+		db:                 db,
+		studentIdValidator: NewStudentIdValidationService(db),
+		emailValidator:     NewEmailValidationService(db),
+		passwordSecurity:   NewPasswordSecurityService(),
+	}
+}
+
+// WithContext returns a copy of the service whose queries carry ctx
+func (s *StudentImportService) WithContext(ctx context.Context) *StudentImportService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	bound.studentIdValidator = NewStudentIdValidationService(bound.db)
+	bound.emailValidator = NewEmailValidationService(bound.db)
+	return &bound
+}
+
+// StudentImportRowResult reports the outcome of one roster row
+type StudentImportRowResult struct {
+	Row       int      `json:"row"` // spreadsheet row number, the header being row 1
+	StudentID string   `json:"student_id"`
+	Action    string   `json:"action,omitempty"` // create or update; empty when the row is invalid
+	Errors    []string `json:"errors"`
+	Warnings  []string `json:"warnings"`
+}
+
+// StudentImportResult summarizes a roster import. In a dry run Created and Updated count the rows
+// that would be created and updated.
+type StudentImportResult struct {
+	DryRun      bool                     `json:"dry_run"`
+	Committed   bool                     `json:"committed"`
+	TotalRows   int                      `json:"total_rows"`
+	ValidRows   int                      `json:"valid_rows"`
+	InvalidRows int                      `json:"invalid_rows"`
+	Created     int                      `json:"created"`
+	Updated     int                      `json:"updated"`
+	Rows        []StudentImportRowResult `json:"rows"`
+}
+
+// studentImportRow is one parsed roster row
+type studentImportRow struct {
+	Line   int
+	Fields map[string]string
+}
+
+// studentImportPlan is the validated change for one row
+type studentImportPlan struct {
+	student  models.Student
+	hasGPAX  bool
+	existing *models.Student
+	user     *models.User
+}
+
+// studentImportLookups holds the faculties, programs and majors addressable by code
+type studentImportLookups struct {
+	faculties map[string]models.Faculty
+	programs  map[string]models.Program     // keyed by faculty ID and code
+	majors    map[string]studentImportMajor // keyed by program ID and code
+}
+
+// studentImportMajor is a major with the program of its curriculum
+type studentImportMajor struct {
+	ID           uint
+	CurriculumID uint
+	ProgramID    uint
+	Code         string
+}
+
+// ImportStudents validates every row of a roster and, unless dryRun is set, creates or updates the
+// students and their user accounts in one transaction. Nothing is written when any row is invalid.
+func (s *StudentImportService) ImportStudents(filename string, r io.Reader, dryRun bool) (*StudentImportResult, error) {
+	rows, err := parseStudentImportFile(filename, r)
+	if err != nil {
+		return nil, err
+	}
+
+	lookups, err := s.loadLookups()
+	if err != nil {
+		return nil, err
+	}
+	existingStudents, existingUsers, err := s.loadExisting(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StudentImportResult{DryRun: dryRun, TotalRows: len(rows), Rows: make([]StudentImportRowResult, 0, len(rows))}
+	plans := make([]studentImportPlan, 0, len(rows))
+	seenIDs := make(map[string]int)
+	seenEmails := make(map[string]int)
+
+	for _, row := range rows {
+		rowResult, plan, err := s.validateRow(row, lookups, existingStudents, existingUsers, seenIDs, seenEmails)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, rowResult)
+
+		if len(rowResult.Errors) > 0 {
+			result.InvalidRows++
+			continue
+		}
+		result.ValidRows++
+		if rowResult.Action == StudentImportCreate {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		plans = append(plans, plan)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if result.InvalidRows > 0 {
+		return result, errors.New("import has invalid rows")
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range plans {
+			if err := s.applyPlan(tx, &plans[i]); err != nil {
+				return fmt.Errorf("failed to import student %s: %w", plans[i].student.StudentID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Committed = true
+	return result, nil
+}
+
+// validateRow checks one row and resolves its codes, returning the row result and the change to apply
+func (s *StudentImportService) validateRow(row studentImportRow, lookups *studentImportLookups, existingStudents map[string]models.Student, existingUsers map[string]models.User, seenIDs, seenEmails map[string]int) (StudentImportRowResult, studentImportPlan, error) {
+	fields := row.Fields
+	studentID := fields["student_id"]
+	rowResult := StudentImportRowResult{Row: row.Line, StudentID: studentID, Errors: []string{}, Warnings: []string{}}
+	plan := studentImportPlan{}
+
+	// Student ID
+	idValidation := s.studentIdValidator.ValidateFormat(studentID)
+	rowResult.Errors = append(rowResult.Errors, idValidation.Errors...)
+	rowResult.Warnings = append(rowResult.Warnings, idValidation.Warnings...)
+	if line, ok := seenIDs[studentID]; ok && studentID != "" {
+		rowResult.Errors = append(rowResult.Errors, fmt.Sprintf("duplicate student ID, first seen in row %d", line))
+	} else {
+		seenIDs[studentID] = row.Line
+	}
+
+	// Names
+	if fields["name"] == "" {
+		rowResult.Errors = append(rowResult.Errors, "name is required")
+	}
+	if fields["surname"] == "" {
+		rowResult.Errors = append(rowResult.Errors, "surname is required")
+	}
+
+	// Email; an address already used by this student's own account is not a conflict
+	email := s.emailValidator.NormalizeEmail(fields["email"])
+	emailValidation, err := s.emailValidator.ValidateEmail(email, "student")
+	if err != nil {
+		return rowResult, plan, fmt.Errorf("failed to validate email: %w", err)
+	}
+	user, hasUser := existingUsers[studentID]
+	for _, message := range emailValidation.Errors {
+		if message == EmailInUseError && hasUser && strings.EqualFold(user.Email, email) {
+			continue
+		}
+		rowResult.Errors = append(rowResult.Errors, message)
+	}
+	rowResult.Warnings = append(rowResult.Warnings, emailValidation.Warnings...)
+	if line, ok := seenEmails[email]; ok && email != "" {
+		rowResult.Errors = append(rowResult.Errors, fmt.Sprintf("duplicate email, first seen in row %d", line))
+	} else {
+		seenEmails[email] = row.Line
+	}
+
+	plan.student = models.Student{
+		StudentID:  studentID,
+		Name:       fields["name"],
+		MiddleName: fields["middle_name"],
+		Surname:    fields["surname"],
+		Email:      email,
+	}
+
+	// Phone number and GPAX are optional
+	if phone := fields["phone_number"]; phone != "" {
+		if IsValidPhoneNumber(phone) {
+			plan.student.PhoneNumber = NormalizePhoneNumber(phone)
+		} else {
+			rowResult.Errors = append(rowResult.Errors, "phone number must be 10 digits starting with 0")
+		}
+	}
+	if gpax := fields["gpax"]; gpax != "" {
+		value, err := strconv.ParseFloat(gpax, 64)
+		if err != nil || value < 0 || value > 4 {
+			rowResult.Errors = append(rowResult.Errors, "gpax must be a number between 0 and 4")
+		} else {
+			plan.student.GPAX = value
+			plan.hasGPAX = true
+		}
+	}
+
+	// Faculty, program and major codes
+	rowResult.Errors = append(rowResult.Errors, lookups.resolve(fields, &plan.student)...)
+
+	// Existing records
+	if existing, ok := existingStudents[studentID]; ok {
+		if existing.DeletedAt.Valid {
+			rowResult.Errors = append(rowResult.Errors, "student is in the recycle bin, restore it before importing")
+		} else {
+			plan.existing = &existing
+			rowResult.Action = StudentImportUpdate
+		}
+	} else {
+		rowResult.Action = StudentImportCreate
+		if hasUser {
+			rowResult.Warnings = append(rowResult.Warnings, "an existing user account will be linked to the new student")
+		}
+	}
+	if hasUser {
+		plan.user = &user
+	}
+
+	if len(rowResult.Errors) > 0 {
+		rowResult.Action = ""
+	}
+	return rowResult, plan, nil
+}
+
+// resolve sets the faculty, campus, program, curriculum and major of a student from the row's codes
+// and returns the codes that could not be resolved
+func (l *studentImportLookups) resolve(fields map[string]string, student *models.Student) []string {
+	var problems []string
+
+	facultyCode := fields["faculty_code"]
+	faculty, ok := l.faculties[strings.ToLower(facultyCode)]
+	if !ok {
+		if facultyCode == "" {
+			return append(problems, "faculty code is required")
+		}
+		return append(problems, fmt.Sprintf("unknown faculty code %q", facultyCode))
+	}
+	student.FacultyID = &faculty.ID
+	student.CampusID = faculty.CampusID
+
+	programCode, majorCode := fields["program_code"], fields["major_code"]
+	if programCode == "" {
+		if majorCode != "" {
+			problems = append(problems, "major code requires a program code")
+		}
+		return problems
+	}
+	program, ok := l.programs[studentImportKey(faculty.ID, programCode)]
+	if !ok {
+		return append(problems, fmt.Sprintf("unknown program code %q in faculty %s", programCode, faculty.Code))
+	}
+	student.ProgramID = &program.ID
+
+	if majorCode == "" {
+		return problems
+	}
+	major, ok := l.majors[studentImportKey(program.ID, majorCode)]
+	if !ok {
+		return append(problems, fmt.Sprintf("unknown major code %q in program %s", majorCode, programCode))
+	}
+	student.MajorID = &major.ID
+	student.CurriculumID = &major.CurriculumID
+	return problems
+}
+
+// applyPlan creates or updates the user account and the student of a valid row
+func (s *StudentImportService) applyPlan(tx *gorm.DB, plan *studentImportPlan) error {
+	student := plan.student
+	fullName := student.GetFullName()
+
+	if plan.user != nil {
+		if err := tx.Model(plan.user).Updates(map[string]interface{}{
+			"email":     student.Email,
+			"full_name": fullName,
+		}).Error; err != nil {
+			return err
+		}
+	} else {
+		// Imported accounts get an unusable random password; students set their own through password reset
+		password, err := s.passwordSecurity.GenerateSecurePassword(PasswordGenerationOptions{
+			Length:              24,
+			IncludeUppercase:    true,
+			IncludeLowercase:    true,
+			IncludeNumbers:      true,
+			IncludeSpecialChars: true,
+		})
+		if err != nil {
+			return err
+		}
+		user := models.User{
+			StudentID: student.StudentID,
+			Email:     student.Email,
+			FullName:  fullName,
+			Password:  password,
+			Status:    models.UserStatusActive,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+	}
+
+	if plan.existing == nil {
+		return tx.Create(&student).Error
+	}
+
+	// Optional columns left empty in the roster keep their current values
+	updates := map[string]interface{}{
+		"name":        student.Name,
+		"middle_name": student.MiddleName,
+		"surname":     student.Surname,
+		"email":       student.Email,
+		"faculty_id":  student.FacultyID,
+		"campus_id":   student.CampusID,
+	}
+	if student.PhoneNumber != "" {
+		updates["phone_number"] = student.PhoneNumber
+	}
+	if plan.hasGPAX {
+		updates["gpax"] = student.GPAX
+	}
+	if student.ProgramID != nil {
+		updates["program_id"] = student.ProgramID
+	}
+	if student.MajorID != nil {
+		updates["major_id"] = student.MajorID
+		updates["curriculum_id"] = student.CurriculumID
+	}
+	return tx.Model(plan.existing).Updates(updates).Error
+}
+
+// loadLookups loads the faculties, programs and majors that have codes
+func (s *StudentImportService) loadLookups() (*studentImportLookups, error) {
+	lookups := &studentImportLookups{
+		faculties: make(map[string]models.Faculty),
+		programs:  make(map[string]models.Program),
+		majors:    make(map[string]studentImportMajor),
+	}
+
+	var faculties []models.Faculty
+	if err := s.db.Find(&faculties).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch faculties: %w", err)
+	}
+	for _, faculty := range faculties {
+		lookups.faculties[strings.ToLower(faculty.Code)] = faculty
+	}
+
+	var programs []models.Program
+	if err := s.db.Where("code IS NOT NULL AND code <> ''").Find(&programs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch programs: %w", err)
+	}
+	for _, program := range programs {
+		lookups.programs[studentImportKey(program.FacultyID, *program.Code)] = program
+	}
+
+	var majors []studentImportMajor
+	if err := s.db.Table("majors").
+		Select("majors.id, majors.curriculum_id, curriculums.program_id, majors.code").
+		Joins("JOIN curriculums ON curriculums.id = majors.curriculum_id").
+		Where("majors.code IS NOT NULL AND majors.code <> ''").
+		Scan(&majors).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch majors: %w", err)
+	}
+	for _, major := range majors {
+		lookups.majors[studentImportKey(major.ProgramID, major.Code)] = major
+	}
+	return lookups, nil
+}
+
+// loadExisting loads the students, including deleted ones, and user accounts of the roster's student IDs
+func (s *StudentImportService) loadExisting(rows []studentImportRow) (map[string]models.Student, map[string]models.User, error) {
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if id := row.Fields["student_id"]; id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	students := make(map[string]models.Student)
+	users := make(map[string]models.User)
+	if len(ids) == 0 {
+		return students, users, nil
+	}
+
+	var existingStudents []models.Student
+	if err := s.db.Unscoped().Where("student_id IN ?", ids).Find(&existingStudents).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch students: %w", err)
+	}
+	for _, student := range existingStudents {
+		students[student.StudentID] = student
+	}
+
+	var existingUsers []models.User
+	if err := s.db.Where("student_id IN ?", ids).Find(&existingUsers).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+	for _, user := range existingUsers {
+		users[user.StudentID] = user
+	}
+	return students, users, nil
+}
+
+// parseStudentImportFile reads the rows of a CSV or XLSX roster. The first row holds the column
+// names; blank rows are skipped.
+func parseStudentImportFile(filename string, r io.Reader) ([]studentImportRow, error) {
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		var err error
+		if records, err = reader.ReadAll(); err != nil {
+			return nil, fmt.Errorf("invalid import file: %w", err)
+		}
+	case ".xlsx":
+		workbook, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid import file: %w", err)
+		}
+		defer workbook.Close()
+		if records, err = workbook.GetRows(workbook.GetSheetName(0)); err != nil {
+			return nil, fmt.Errorf("invalid import file: %w", err)
+		}
+	default:
+		return nil, errors.New("invalid import file: expected a .csv or .xlsx file")
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("invalid import file: the file is empty")
+	}
+
+	// Map the header row onto import fields
+	columns := make(map[int]string)
+	present := make(map[string]bool)
+	for i, header := range records[0] {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+		if field, ok := studentImportColumns[name]; ok {
+			columns[i] = field
+			present[field] = true
+		}
+	}
+	for _, field := range studentImportRequiredColumns {
+		if !present[field] {
+			return nil, fmt.Errorf("invalid import file: missing column %s", field)
+		}
+	}
+
+	rows := make([]studentImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := studentImportRow{Line: i + 2, Fields: make(map[string]string, len(columns))}
+		blank := true
+		for index, field := range columns {
+			if index < len(record) {
+				value := strings.TrimSpace(record[index])
+				row.Fields[field] = value
+				blank = blank && value == ""
+			}
+		}
+		if !blank {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("invalid import file: no student rows")
+	}
+	if len(rows) > studentImportMaxRows {
+		return nil, fmt.Errorf("invalid import file: more than %d student rows", studentImportMaxRows)
+	}
+	return rows, nil
+}
+
+// studentImportKey builds the lookup key of a code within a parent record
+func studentImportKey(parentID uint, code string) string {
+	return fmt.Sprintf("%d:%s", parentID, strings.ToLower(code))
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestStudentImport(t *testing.T) {
+	t.Run("csv headers are matched by alias and blank rows skipped", func(t *testing.T) {
+		roster := "\ufeffStudent ID,First Name,Last Name,Email,Phone,Faculty Code,Notes\n" +
+			"67010001234, Somchai ,Jaidee,somchai@example.ac.th,081-234-5678,01,x\n" +
+			",,,,,,\n" +
+			"67010001235,Suda,Rakdee,suda@example.ac.th,,01\n"
+
+		rows, err := parseStudentImportFile("roster.CSV", strings.NewReader(roster))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, "67010001234", rows[0].Fields["student_id"])
+		assert.Equal(t, "Somchai", rows[0].Fields["name"])
+		assert.Equal(t, "081-234-5678", rows[0].Fields["phone_number"])
+		assert.Equal(t, 4, rows[1].Line)
+		assert.Equal(t, "", rows[1].Fields["phone_number"])
+	})
+
+	t.Run("xlsx rosters are read from the first sheet", func(t *testing.T) {
+		workbook := excelize.NewFile()
+		sheet := workbook.GetSheetName(0)
+		require.NoError(t, workbook.SetSheetRow(sheet, "A1", &[]interface{}{"student_id", "name", "surname", "email", "faculty"}))
+		require.NoError(t, workbook.SetSheetRow(sheet, "A2", &[]interface{}{"67010001234", "Somchai", "Jaidee", "somchai@example.ac.th", "01"}))
+		var buffer bytes.Buffer
+		require.NoError(t, workbook.Write(&buffer))
+
+		rows, err := parseStudentImportFile("roster.xlsx", &buffer)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "01", rows[0].Fields["faculty_code"])
+	})
+
+	t.Run("unusable files are rejected", func(t *testing.T) {
+		_, err := parseStudentImportFile("roster.pdf", strings.NewReader(""))
+		assert.EqualError(t, err, "invalid import file: expected a .csv or .xlsx file")
+
+		_, err = parseStudentImportFile("roster.csv", strings.NewReader("student_id,name,surname,email\n1,a,b,c\n"))
+		assert.EqualError(t, err, "invalid import file: missing column faculty_code")
+
+		_, err = parseStudentImportFile("roster.csv", strings.NewReader("student_id,name,surname,email,faculty_code\n"))
+		assert.EqualError(t, err, "invalid import file: no student rows")
+	})
+
+	t.Run("codes resolve within their parent", func(t *testing.T) {
+		programCode, majorCode := "CPE", "SE"
+		lookups := &studentImportLookups{
+			faculties: map[string]models.Faculty{"eng": {ID: 1, Code: "ENG", CampusID: 3}},
+			programs:  map[string]models.Program{studentImportKey(1, programCode): {ID: 5, FacultyID: 1, Code: &programCode}},
+			majors:    map[string]studentImportMajor{studentImportKey(5, majorCode): {ID: 9, CurriculumID: 7, ProgramID: 5, Code: majorCode}},
+		}
+
+		var student models.Student
+		problems := lookups.resolve(map[string]string{"faculty_code": "eng", "program_code": "cpe", "major_code": "se"}, &student)
+		assert.Empty(t, problems)
+		assert.Equal(t, uint(3), student.CampusID)
+		assert.Equal(t, uint(5), *student.ProgramID)
+		assert.Equal(t, uint(9), *student.MajorID)
+		assert.Equal(t, uint(7), *student.CurriculumID)
+
+		problems = lookups.resolve(map[string]string{"faculty_code": "ENG", "major_code": "SE"}, &models.Student{})
+		assert.Equal(t, []string{"major code requires a program code"}, problems)
+
+		problems = lookups.resolve(map[string]string{"faculty_code": "SCI"}, &models.Student{})
+		assert.Equal(t, []string{`unknown faculty code "SCI"`}, problems)
+	})
+
+	t.Run("phone numbers are Thai 10 digit numbers", func(t *testing.T) {
+		assert.True(t, IsValidPhoneNumber("081-234-5678"))
+		assert.True(t, IsValidPhoneNumber("(081) 234 5678"))
+		assert.False(t, IsValidPhoneNumber("81-234-5678"))
+		assert.False(t, IsValidPhoneNumber("+66812345678"))
+		assert.Equal(t, "0812345678", NormalizePhoneNumber("081-234-5678"))
+	})
+}
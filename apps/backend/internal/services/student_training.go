@@ -1,7 +1,9 @@
 package services
 
 import (
+	"backend-go/internal/database"
 	"backend-go/internal/models"
+	"context"
 	"database/sql"
 	"errors"
 	"time"
@@ -21,6 +23,13 @@ func NewStudentTrainingService(db *gorm.DB) *StudentTrainingService {
 	}
 }
 
+// WithContext returns a copy of the service bound to the request context
+func (s *StudentTrainingService) WithContext(ctx context.Context) *StudentTrainingService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	return &bound
+}
+
 // StudentTrainingListRequest represents the request for listing student trainings
 type StudentTrainingListRequest struct {
 	Page            int    `json:"page"`
@@ -30,6 +39,9 @@ type StudentTrainingListRequest struct {
 	CompanyID       *uint  `json:"company_id"`
 	SortBy          string `json:"sort_by"`
 	SortDesc        bool   `json:"sort_desc"`
+
+	// Scope restricts results to the caller's visible students (nil means unrestricted)
+	Scope *DataScope `json:"-"`
 }
 
 // CreateStudentTrainingRequest represents the request for creating a student training
@@ -95,7 +107,8 @@ func (s *StudentTrainingService) GetStudentTrainings(req StudentTrainingListRequ
 		Preload("StudentEnroll").
 		Preload("StudentEnroll.Student").
 		Preload("StudentEnroll.CourseSection").
-		Preload("Company")
+		Preload("Company").
+		Scopes(req.Scope.StudentEnrolls("student_trainings.student_enroll_id"))
 
 	// Apply filters
 	if req.StudentEnrollID != nil {
@@ -309,7 +322,7 @@ func (s *StudentTrainingService) UpdateStudentTraining(id uint, req UpdateStuden
 	return &training, nil
 }
 
-// DeleteStudentTraining deletes a student training
+// DeleteStudentTraining moves a student training to the recycle bin
 func (s *StudentTrainingService) DeleteStudentTraining(id uint) error {
 	var training models.StudentTraining
 	if err := s.db.First(&training, id).Error; err != nil {
@@ -0,0 +1,703 @@
+package services
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TwoFactorSettings configures TOTP enrollment and verification.
+// Routes map it from config.TwoFactorConfig; zero values fall back to RFC 6238 defaults.
+type TwoFactorSettings struct {
+	Issuer           string
+	SecretLength     int // bytes of random secret
+	CodeLength       int // 6 or 8 digits
+	Period           time.Duration
+	Skew             int // accepted periods before and after the current one
+	BackupCodeCount  int
+	BackupCodeLength int
+	EncryptionKey    string // exactly 32 bytes, AES-256
+	MaxAttempts      int
+	LockoutDuration  time.Duration
+	QRCodeSize       int
+}
+
+// backupCodeAlphabet omits characters that are easily confused (0/O, 1/I)
+const backupCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorEnrollment is returned when enrollment starts. The secret is shown once
+// so it can be typed in manually; QRCode is a PNG (base64 encoded in JSON).
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     []byte `json:"qr_code"`
+}
+
+// TwoFactorStatus describes the 2FA state of an account
+type TwoFactorStatus struct {
+	Enabled              bool       `json:"enabled"`
+	EnabledAt            *time.Time `json:"enabled_at,omitempty"`
+	BackupCodesRemaining int64      `json:"backup_codes_remaining"`
+	LockedUntil          *time.Time `json:"locked_until,omitempty"`
+}
+
+// TwoFactorCodeRequest carries a TOTP or backup code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorLoginRequest completes a login that returned a 2FA challenge
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// TwoFactorLoginResponse represents the tokens issued after a successful 2FA challenge
+type TwoFactorLoginResponse struct {
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+	TokenType    string      `json:"token_type"`
+	ExpiresIn    int         `json:"expires_in"`
+	User         interface{} `json:"user"` // *models.User or *models.SuperAdmin
+}
+
+// TwoFactorService handles TOTP enrollment, verification, backup codes and lockout
+// for super admins and instructors
+type TwoFactorService struct {
+	db         *gorm.DB
+	jwtService *JWTService
+	settings   TwoFactorSettings
+}
+
+// NewTwoFactorService creates a new two-factor service instance
+func NewTwoFactorService(db *gorm.DB, jwtService *JWTService, settings TwoFactorSettings) *TwoFactorService {
+	if settings.Issuer == "" {
+		settings.Issuer = "Internship System"
+	}
+	if settings.SecretLength <= 0 {
+		settings.SecretLength = 20
+	}
+	if settings.CodeLength != 6 && settings.CodeLength != 8 {
+		settings.CodeLength = 6
+	}
+	if settings.Period <= 0 {
+		settings.Period = 30 * time.Second
+	}
+	if settings.Skew < 0 {
+		settings.Skew = 0
+	}
+	if settings.BackupCodeCount <= 0 {
+		settings.BackupCodeCount = 10
+	}
+	if settings.BackupCodeLength <= 0 {
+		settings.BackupCodeLength = 8
+	}
+	if settings.MaxAttempts <= 0 {
+		settings.MaxAttempts = 3
+	}
+	if settings.LockoutDuration <= 0 {
+		settings.LockoutDuration = 15 * time.Minute
+	}
+	if settings.QRCodeSize <= 0 {
+		settings.QRCodeSize = 256
+	}
+
+	return &TwoFactorService{
+		db:         db,
+		jwtService: jwtService,
+		settings:   settings,
+	}
+}
+
+// BeginEnrollment generates a new secret for the account. The secret is stored encrypted
+// but is not enforced until ConfirmEnrollment succeeds.
+func (s *TwoFactorService) BeginEnrollment(userType UserType, userID string) (*TwoFactorEnrollment, error) {
+	account, err := s.eligibleAccount(userType, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.findCredential(userType, userID)
+	if err != nil && err.Error() != "two-factor not enrolled" {
+		return nil, err
+	}
+	if credential != nil && credential.IsEnabled() {
+		return nil, errors.New("two-factor already enabled")
+	}
+
+	raw := make([]byte, s.settings.SecretLength)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	secret := base32NoPadding.EncodeToString(raw)
+
+	encrypted, err := s.encryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if credential == nil {
+		credential = &models.TwoFactorCredential{UserType: string(userType), UserID: userID}
+	}
+	credential.SecretEncrypted = encrypted
+	credential.FailedAttempts = 0
+	credential.LockedUntil = nil
+	credential.LastUsedStep = 0
+	if err := s.db.Save(credential).Error; err != nil {
+		return nil, fmt.Errorf("failed to save two-factor credential: %w", err)
+	}
+
+	uri := s.otpauthURI(secret, account)
+	qrCode, err := s.qrCodePNG(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     qrCode,
+	}, nil
+}
+
+// ConfirmEnrollment enables 2FA once the account proves it can generate codes
+// and returns the plain backup codes, which are only shown this once
+func (s *TwoFactorService) ConfirmEnrollment(userType UserType, userID string, code, ipAddress, userAgent string) ([]string, error) {
+	credential, err := s.findCredential(userType, userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential.IsEnabled() {
+		return nil, errors.New("two-factor already enabled")
+	}
+
+	if err := s.verifyCredential(credential, code, false, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(credential).Update("enabled_at", now).Error; err != nil {
+			return err
+		}
+		credential.EnabledAt = &now
+
+		if codes, err = s.replaceBackupCodes(tx, credential.ID); err != nil {
+			return err
+		}
+		return s.syncSuperAdmin(tx, credential, true)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Disable removes 2FA from the account after verifying a current TOTP or backup code
+func (s *TwoFactorService) Disable(userType UserType, userID string, code, ipAddress, userAgent string) error {
+	credential, err := s.findCredential(userType, userID)
+	if err != nil {
+		return err
+	}
+	if !credential.IsEnabled() {
+		return errors.New("two-factor not enabled")
+	}
+
+	if err := s.verifyCredential(credential, code, true, ipAddress, userAgent); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("credential_id = ?", credential.ID).Delete(&models.TwoFactorBackupCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(credential).Error; err != nil {
+			return err
+		}
+		return s.syncSuperAdmin(tx, credential, false)
+	})
+}
+
+// RegenerateBackupCodes invalidates all backup codes and returns a fresh set.
+// A TOTP code is required so a leaked backup code cannot be used to mint new ones.
+func (s *TwoFactorService) RegenerateBackupCodes(userType UserType, userID string, code, ipAddress, userAgent string) ([]string, error) {
+	credential, err := s.findCredential(userType, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !credential.IsEnabled() {
+		return nil, errors.New("two-factor not enabled")
+	}
+
+	if err := s.verifyCredential(credential, code, false, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		codes, err = s.replaceBackupCodes(tx, credential.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to regenerate backup codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// GetStatus returns the 2FA state of the account
+func (s *TwoFactorService) GetStatus(userType UserType, userID string) (*TwoFactorStatus, error) {
+	credential, err := s.findCredential(userType, userID)
+	if err != nil {
+		if err.Error() == "two-factor not enrolled" {
+			return &TwoFactorStatus{}, nil
+		}
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{
+		Enabled:   credential.IsEnabled(),
+		EnabledAt: credential.EnabledAt,
+	}
+	if credential.IsLocked() {
+		status.LockedUntil = credential.LockedUntil
+	}
+	if err := s.db.Model(&models.TwoFactorBackupCode{}).
+		Where("credential_id = ? AND used_at IS NULL", credential.ID).
+		Count(&status.BackupCodesRemaining).Error; err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// CompleteLogin exchanges a 2FA challenge token and a TOTP or backup code for access and refresh tokens
+func (s *TwoFactorService) CompleteLogin(req TwoFactorLoginRequest, ipAddress, userAgent string) (*TwoFactorLoginResponse, error) {
+	result, err := s.jwtService.ValidateToken(req.ChallengeToken)
+	if err != nil || !result.IsValid || result.Claims.TokenType != TokenTypeTwoFactor {
+		return nil, errors.New("invalid challenge token")
+	}
+
+	credential, err := s.findCredential(result.Claims.UserType, result.Claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid challenge token")
+	}
+	if !credential.IsEnabled() {
+		return nil, errors.New("invalid challenge token")
+	}
+
+	if err := s.verifyCredential(credential, req.Code, true, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	if err := s.jwtService.RevokeToken(req.ChallengeToken); err != nil {
+		// Log error but continue, the challenge expires shortly anyway
+		fmt.Printf("Warning: failed to revoke 2FA challenge token: %v\n", err)
+	}
+
+	var accessToken, refreshToken *models.AccessToken
+	switch user := result.User.(type) {
+	case *models.User:
+		if accessToken, err = s.jwtService.GenerateTokenForUser(user, TokenTypeAccess, []string{}, false, ""); err != nil {
+			return nil, fmt.Errorf("failed to generate access token: %w", err)
+		}
+		if refreshToken, err = s.jwtService.GenerateTokenForUser(user, TokenTypeRefresh, []string{}, false, ""); err != nil {
+			return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		}
+		if err := user.UpdateLastLogin(s.db); err != nil {
+			fmt.Printf("Failed to update last login for user %s: %v\n", user.StudentID, err)
+		}
+		user.Password = ""
+	case *models.SuperAdmin:
+		if accessToken, err = s.jwtService.GenerateTokenForSuperAdmin(user, TokenTypeAccess, []string{}, false, ""); err != nil {
+			return nil, fmt.Errorf("failed to generate access token: %w", err)
+		}
+		if refreshToken, err = s.jwtService.GenerateTokenForSuperAdmin(user, TokenTypeRefresh, []string{}, false, ""); err != nil {
+			return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		}
+		if err := user.UpdateLastLogin(s.db); err != nil {
+			fmt.Printf("Failed to update last login for admin %d: %v\n", user.ID, err)
+		}
+		user.Password = ""
+	default:
+		return nil, errors.New("invalid challenge token")
+	}
+
+	s.logSecurityEvent(credential, models.SecurityActionLogin, ipAddress, userAgent, "")
+
+	return &TwoFactorLoginResponse{
+		AccessToken:  accessToken.Token,
+		RefreshToken: refreshToken.Token,
+		TokenType:    "Bearer",
+		ExpiresIn:    24 * 60 * 60, // 24 hours in seconds
+		User:         result.User,
+	}, nil
+}
+
+// eligibleAccount checks that the account may use 2FA and returns its account label.
+// Super admins and instructors may enroll; students may not.
+func (s *TwoFactorService) eligibleAccount(userType UserType, userID string) (string, error) {
+	switch userType {
+	case UserTypeSuperAdmin:
+		var admin models.SuperAdmin
+		if err := s.db.Where("id = ?", userID).First(&admin).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", errors.New("user not found")
+			}
+			return "", err
+		}
+		return admin.Email, nil
+	case UserTypeStudent:
+		var user models.User
+		if err := s.db.Where("student_id = ?", userID).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", errors.New("user not found")
+			}
+			return "", err
+		}
+
+		// Only logins that resolve to an instructor account may enroll
+		account, ok, err := ResolveAccount(s.db, userType, userID)
+		if err != nil {
+			return "", err
+		}
+		if !ok || account.Type != AccountTypeInstructor {
+			return "", errors.New("two-factor not available for this account")
+		}
+		return user.Email, nil
+	default:
+		return "", errors.New("unsupported user type")
+	}
+}
+
+// findCredential loads the credential of a principal
+func (s *TwoFactorService) findCredential(userType UserType, userID string) (*models.TwoFactorCredential, error) {
+	var credential models.TwoFactorCredential
+	if err := s.db.Where("user_type = ? AND user_id = ?", string(userType), userID).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("two-factor not enrolled")
+		}
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// verifyCredential checks a TOTP code, and a backup code when allowed, enforcing lockout.
+// Every failure is logged; reaching MaxAttempts locks verification for LockoutDuration.
+func (s *TwoFactorService) verifyCredential(credential *models.TwoFactorCredential, code string, allowBackup bool, ipAddress, userAgent string) error {
+	if credential.IsLocked() {
+		return errors.New("two-factor verification locked")
+	}
+
+	secret, err := s.decryptSecret(credential.SecretEncrypted)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := s.validateTOTP(secret, code, time.Now()); ok {
+		// Only one request can advance last_used_step, so a code cannot be replayed concurrently
+		result := s.db.Model(&models.TwoFactorCredential{}).
+			Where("id = ? AND last_used_step < ?", credential.ID, step).
+			Updates(map[string]interface{}{
+				"last_used_step":  step,
+				"failed_attempts": 0,
+				"locked_until":    nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			credential.LastUsedStep = step
+			credential.FailedAttempts = 0
+			credential.LockedUntil = nil
+			return nil
+		}
+	}
+
+	if allowBackup {
+		used, err := s.useBackupCode(credential.ID, code)
+		if err != nil {
+			return err
+		}
+		if used {
+			credential.FailedAttempts = 0
+			return s.db.Model(credential).Updates(map[string]interface{}{
+				"failed_attempts": 0,
+				"locked_until":    nil,
+			}).Error
+		}
+	}
+
+	// Count the failure in the database so concurrent attempts cannot overwrite each other's increment
+	if err := s.db.Model(credential).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_attempts"}}}).
+		UpdateColumn("failed_attempts", gorm.Expr("failed_attempts + ?", 1)).Error; err != nil {
+		return err
+	}
+	locked := credential.FailedAttempts >= s.settings.MaxAttempts
+	if locked {
+		lockedUntil := time.Now().Add(s.settings.LockoutDuration)
+		if err := s.db.Model(credential).Updates(map[string]interface{}{
+			"locked_until":    lockedUntil,
+			"failed_attempts": 0,
+		}).Error; err != nil {
+			return err
+		}
+		credential.LockedUntil = &lockedUntil
+		credential.FailedAttempts = 0
+	}
+
+	s.logSecurityEvent(credential, models.SecurityActionFailedLogin, ipAddress, userAgent, "invalid two-factor code")
+	if locked {
+		s.logSecurityEvent(credential, models.SecurityActionAccountLocked, ipAddress, userAgent, "too many invalid two-factor codes")
+		return errors.New("two-factor verification locked")
+	}
+
+	return errors.New("invalid two-factor code")
+}
+
+// validateTOTP checks the code against the current period and Skew periods around it,
+// returning the matching time step
+func (s *TwoFactorService) validateTOTP(secret, code string, at time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != s.settings.CodeLength {
+		return 0, false
+	}
+
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := at.Unix() / int64(s.settings.Period/time.Second)
+	for offset := -s.settings.Skew; offset <= s.settings.Skew; offset++ {
+		step := current + int64(offset)
+		expected := generateTOTP(key, step, s.settings.CodeLength)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// generateTOTP computes an RFC 6238 code (HMAC-SHA1) for a time step
+func generateTOTP(key []byte, step int64, digits int) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulus := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulus)
+}
+
+// replaceBackupCodes deletes existing backup codes and stores hashes of a fresh set
+func (s *TwoFactorService) replaceBackupCodes(tx *gorm.DB, credentialID uint) ([]string, error) {
+	if err := tx.Where("credential_id = ?", credentialID).Delete(&models.TwoFactorBackupCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, s.settings.BackupCodeCount)
+	records := make([]models.TwoFactorBackupCode, s.settings.BackupCodeCount)
+	for i := range codes {
+		code, err := randomBackupCode(s.settings.BackupCodeLength)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = models.TwoFactorBackupCode{CredentialID: credentialID, CodeHash: hashBackupCode(code)}
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// useBackupCode consumes an unused backup code, reporting whether one matched
+func (s *TwoFactorService) useBackupCode(credentialID uint, code string) (bool, error) {
+	result := s.db.Model(&models.TwoFactorBackupCode{}).
+		Where("credential_id = ? AND code_hash = ? AND used_at IS NULL", credentialID, hashBackupCode(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// randomBackupCode generates a backup code from backupCodeAlphabet
+func randomBackupCode(length int) (string, error) {
+	max := big.NewInt(int64(len(backupCodeAlphabet)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate backup code: %w", err)
+		}
+		code[i] = backupCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// hashBackupCode normalizes a backup code (case, spaces, dashes) and returns its SHA-256 hex digest
+func hashBackupCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// syncSuperAdmin mirrors the 2FA state onto the super_admins columns
+func (s *TwoFactorService) syncSuperAdmin(tx *gorm.DB, credential *models.TwoFactorCredential, enabled bool) error {
+	if credential.UserType != string(UserTypeSuperAdmin) {
+		return nil
+	}
+
+	var secret interface{}
+	if enabled {
+		secret = credential.SecretEncrypted
+	}
+	return tx.Model(&models.SuperAdmin{}).
+		Where("id = ?", credential.UserID).
+		UpdateColumns(map[string]interface{}{
+			"two_factor_enabled": enabled,
+			"two_factor_secret":  secret,
+		}).Error
+}
+
+// otpauthURI builds the Key URI Format understood by authenticator apps
+func (s *TwoFactorService) otpauthURI(secret, account string) string {
+	label := url.PathEscape(s.settings.Issuer) + ":" + url.PathEscape(account)
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", s.settings.Issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", s.settings.CodeLength))
+	query.Set("period", fmt.Sprintf("%d", int(s.settings.Period/time.Second)))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// qrCodePNG renders the URI as a square PNG QR code
+func (s *TwoFactorService) qrCodePNG(uri string) ([]byte, error) {
+	code, err := qr.Encode(uri, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	code, err = barcode.Scale(code, s.settings.QRCodeSize, s.settings.QRCodeSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scale QR code: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code); err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encryptSecret encrypts the TOTP secret with AES-256-GCM, returning base64(nonce || ciphertext)
+func (s *TwoFactorService) encryptSecret(secret string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret reverses encryptSecret
+func (s *TwoFactorService) decryptSecret(encrypted string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("failed to decrypt two-factor secret")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt two-factor secret")
+	}
+	return string(plain), nil
+}
+
+// cipher builds the AES-GCM cipher from the configured encryption key
+func (s *TwoFactorService) cipher() (cipher.AEAD, error) {
+	if len(s.settings.EncryptionKey) != 32 {
+		return nil, errors.New("two-factor encryption key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher([]byte(s.settings.EncryptionKey))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// logSecurityEvent records a 2FA event in security_logs. Failures are reported but not returned.
+func (s *TwoFactorService) logSecurityEvent(credential *models.TwoFactorCredential, action models.SecurityAction, ipAddress, userAgent, reason string) {
+	metadata, _ := json.Marshal(models.SecurityLogMetadata{FailureReason: reason})
+
+	entry := models.SecurityLog{
+		UserType:  credential.UserType,
+		UserID:    credential.UserID,
+		Action:    action,
+		IPAddress: ipAddress,
+		Metadata:  string(metadata),
+	}
+	if userAgent != "" {
+		entry.UserAgent = &userAgent
+	}
+
+	if err := s.db.Create(&entry).Error; err != nil {
+		fmt.Printf("Warning: failed to write security log: %v\n", err)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorService(t *testing.T) {
+	settings := TwoFactorSettings{
+		Issuer:        "Internship System",
+		CodeLength:    8,
+		Period:        30 * time.Second,
+		Skew:          1,
+		EncryptionKey: "0123456789abcdef0123456789abcdef",
+	}
+
+	t.Run("generateTOTP matches RFC 6238 SHA1 vectors", func(t *testing.T) {
+		key := []byte("12345678901234567890")
+		vectors := map[int64]string{
+			59:         "94287082",
+			1111111109: "07081804",
+			1111111111: "14050471",
+			1234567890: "89005924",
+			2000000000: "69279037",
+		}
+		for unix, expected := range vectors {
+			assert.Equal(t, expected, generateTOTP(key, unix/30, 8), "time %d", unix)
+		}
+	})
+
+	t.Run("validateTOTP accepts codes within skew", func(t *testing.T) {
+		twoFactorService := NewTwoFactorService(nil, nil, settings)
+		secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+		at := time.Unix(1111111111, 0)
+
+		step, ok := twoFactorService.validateTOTP(secret, "14050471", at)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1111111111/30), step)
+
+		_, ok = twoFactorService.validateTOTP(secret, "14050471", at.Add(30*time.Second))
+		assert.True(t, ok, "previous period is within skew")
+
+		_, ok = twoFactorService.validateTOTP(secret, "14050471", at.Add(90*time.Second))
+		assert.False(t, ok)
+
+		_, ok = twoFactorService.validateTOTP(secret, "1405047", at)
+		assert.False(t, ok)
+	})
+
+	t.Run("secrets round-trip through AES-GCM", func(t *testing.T) {
+		twoFactorService := NewTwoFactorService(nil, nil, settings)
+
+		encrypted, err := twoFactorService.encryptSecret("JBSWY3DPEHPK3PXP")
+		require.NoError(t, err)
+		assert.NotContains(t, encrypted, "JBSWY3DPEHPK3PXP")
+
+		decrypted, err := twoFactorService.decryptSecret(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "JBSWY3DPEHPK3PXP", decrypted)
+
+		_, err = NewTwoFactorService(nil, nil, TwoFactorSettings{EncryptionKey: "short"}).encryptSecret("x")
+		assert.Error(t, err)
+	})
+
+	t.Run("backup codes are normalized before hashing", func(t *testing.T) {
+		code, err := randomBackupCode(10)
+		require.NoError(t, err)
+		assert.Len(t, code, 10)
+
+		assert.Equal(t, hashBackupCode("ABCD-EFGH"), hashBackupCode("abcd efgh"))
+		assert.NotEqual(t, hashBackupCode("ABCDEFGH"), hashBackupCode("ABCDEFGJ"))
+	})
+
+	t.Run("enrollment URI and QR code", func(t *testing.T) {
+		twoFactorService := NewTwoFactorService(nil, nil, settings)
+
+		uri := twoFactorService.otpauthURI("JBSWY3DPEHPK3PXP", "admin@example.com")
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Internship%20System:admin@example.com?"))
+		assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+		assert.Contains(t, uri, "digits=8")
+		assert.Contains(t, uri, "period=30")
+
+		png, err := twoFactorService.qrCodePNG(uri)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+	})
+}
+
+func TestTwoFactorVerification(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.TwoFactorCredential{}, &models.TwoFactorBackupCode{}))
+
+	twoFactorService := NewTwoFactorService(db, nil, TwoFactorSettings{
+		MaxAttempts:   3,
+		EncryptionKey: "0123456789abcdef0123456789abcdef",
+	})
+	secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+	encrypted, err := twoFactorService.encryptSecret(secret)
+	require.NoError(t, err)
+
+	credential := &models.TwoFactorCredential{UserType: string(UserTypeSuperAdmin), UserID: "9201", SecretEncrypted: encrypted}
+	require.NoError(t, db.Create(credential).Error)
+	t.Cleanup(func() { db.Delete(&models.TwoFactorCredential{}, credential.ID) })
+
+	// Two requests that loaded the credential before either of them wrote to it
+	stale := func() (*models.TwoFactorCredential, *models.TwoFactorCredential) {
+		var first, second models.TwoFactorCredential
+		require.NoError(t, db.First(&first, credential.ID).Error)
+		require.NoError(t, db.First(&second, credential.ID).Error)
+		return &first, &second
+	}
+
+	t.Run("a code is accepted once even by concurrent requests", func(t *testing.T) {
+		step := time.Now().Unix() / 30
+		code := generateTOTP([]byte("12345678901234567890"), step, 6)
+		first, second := stale()
+
+		assert.NoError(t, twoFactorService.verifyCredential(first, code, false, "127.0.0.1", ""))
+		assert.EqualError(t, twoFactorService.verifyCredential(second, code, false, "127.0.0.1", ""), "invalid two-factor code")
+	})
+
+	t.Run("concurrent failures all count towards the lockout", func(t *testing.T) {
+		require.NoError(t, db.Model(credential).Updates(map[string]interface{}{"failed_attempts": 0, "locked_until": nil}).Error)
+		first, second := stale()
+
+		assert.EqualError(t, twoFactorService.verifyCredential(first, "000000", false, "127.0.0.1", ""), "invalid two-factor code")
+		assert.EqualError(t, twoFactorService.verifyCredential(second, "000000", false, "127.0.0.1", ""), "invalid two-factor code")
+		assert.Equal(t, 2, second.FailedAttempts)
+
+		assert.EqualError(t, twoFactorService.verifyCredential(first, "000000", false, "127.0.0.1", ""), "two-factor verification locked")
+		var reloaded models.TwoFactorCredential
+		require.NoError(t, db.First(&reloaded, credential.ID).Error)
+		assert.True(t, reloaded.IsLocked())
+		assert.Zero(t, reloaded.FailedAttempts)
+	})
+}
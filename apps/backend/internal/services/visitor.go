@@ -1,11 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"mime/multipart"
 	"time"
 
+	"backend-go/internal/database"
 	"backend-go/internal/models"
 	"gorm.io/gorm"
 )
@@ -60,6 +62,13 @@ func NewVisitorService(db *gorm.DB) *VisitorService {
 	}
 }
 
+// WithContext returns a copy of the service bound to the request context
+func (s *VisitorService) WithContext(ctx context.Context) *VisitorService {
+	bound := *s
+	bound.db = database.WithAuditContext(s.db, ctx)
+	return &bound
+}
+
 // Request/Response types
 type VisitorTrainingListRequest struct {
 	Page                int    `json:"page"`
@@ -69,6 +78,9 @@ type VisitorTrainingListRequest struct {
 	VisitorInstructorID *uint  `json:"visitor_instructor_id"`
 	SortBy              string `json:"sort_by"`
 	SortDesc            bool   `json:"sort_desc"`
+
+	// Scope restricts results to the caller's visible students (nil means unrestricted)
+	Scope *DataScope `json:"-"`
 }
 
 type VisitorTrainingListResponse struct {
@@ -95,6 +107,9 @@ type VisitorScheduleListRequest struct {
 	VisitNo           *int   `json:"visit_no"`
 	SortBy            string `json:"sort_by"`
 	SortDesc          bool   `json:"sort_desc"`
+
+	// Scope restricts results to the caller's visible students (nil means unrestricted)
+	Scope *DataScope `json:"-"`
 }
 
 type VisitorScheduleListResponse struct {
@@ -108,41 +123,60 @@ type VisitorScheduleListResponse struct {
 type CreateVisitorScheduleRequest struct {
 	VisitorTrainingID uint       `json:"visitor_training_id" validate:"required"`
 	VisitNo           int        `json:"visit_no" validate:"required,min=1,max=4"`
-	VisitAt           *time.Time `json:"visit_at"`
-	Comment           *string    `json:"comment"`
+	VisitAt           *time.Time       `json:"visit_at"`
+	VisitMode         models.VisitMode `json:"visit_mode" validate:"omitempty,oneof=onsite online"`
+	MeetingURL        *string          `json:"meeting_url" validate:"omitempty,url"`
+	Comment           *string          `json:"comment"`
 }
 
 type UpdateVisitorScheduleRequest struct {
-	VisitAt *time.Time `json:"visit_at"`
-	Comment *string    `json:"comment"`
+	VisitAt    *time.Time        `json:"visit_at"`
+	VisitMode  *models.VisitMode `json:"visit_mode" validate:"omitempty,oneof=onsite online"`
+	MeetingURL *string           `json:"meeting_url" validate:"omitempty,url"`
+	Comment    *string           `json:"comment"`
 }
 
+// CreateVisitorEvaluateStudentRequest records a visitor's evaluation of a student. Clients either send
+// a score with a free-form questions payload, or answers to the student's visitor_student evaluation
+// form, from which the score and questions are derived.
 type CreateVisitorEvaluateStudentRequest struct {
-	VisitorTrainingID uint   `json:"visitor_training_id" validate:"required"`
-	Score             int    `json:"score" validate:"required,min=0,max=100"`
-	Questions         string `json:"questions" validate:"required"`
-	Comment           string `json:"comment" validate:"required"`
+	VisitorTrainingID uint                      `json:"visitor_training_id" validate:"required"`
+	Score             int                       `json:"score" validate:"required_without=Answers,min=0,max=100"`
+	Questions         string                    `json:"questions" validate:"required_without=Answers"`
+	Comment           string                    `json:"comment" validate:"required"`
+	Answers           []models.EvaluationAnswer `json:"answers"`
+
+	// Scope restricts the trainings the caller may evaluate (nil means unrestricted)
+	Scope *DataScope `json:"-"`
 }
 
 type UpdateVisitorEvaluateStudentRequest struct {
-	Score     *int    `json:"score" validate:"omitempty,min=0,max=100"`
-	Questions *string `json:"questions"`
-	Comment   *string `json:"comment"`
+	Score     *int                      `json:"score" validate:"omitempty,min=0,max=100"`
+	Questions *string                   `json:"questions"`
+	Comment   *string                   `json:"comment"`
+	Answers   []models.EvaluationAnswer `json:"answers"`
 }
 
+// CreateVisitorEvaluateCompanyRequest records a visitor's evaluation of a company, either as a score
+// with a free-form questions payload or as answers to the visitor_company evaluation form
 type CreateVisitorEvaluateCompanyRequest struct {
-	VisitorTrainingID uint   `json:"visitor_training_id" validate:"required"`
-	StudentTrainingID *uint  `json:"student_training_id"`
-	Score             int    `json:"score" validate:"required,min=0,max=100"`
-	Questions         string `json:"questions" validate:"required"`
-	Comment           string `json:"comment" validate:"required"`
+	VisitorTrainingID uint                      `json:"visitor_training_id" validate:"required"`
+	StudentTrainingID *uint                     `json:"student_training_id"`
+	Score             int                       `json:"score" validate:"required_without=Answers,min=0,max=100"`
+	Questions         string                    `json:"questions" validate:"required_without=Answers"`
+	Comment           string                    `json:"comment" validate:"required"`
+	Answers           []models.EvaluationAnswer `json:"answers"`
+
+	// Scope restricts the trainings the caller may evaluate (nil means unrestricted)
+	Scope *DataScope `json:"-"`
 }
 
 type UpdateVisitorEvaluateCompanyRequest struct {
-	StudentTrainingID *uint   `json:"student_training_id"`
-	Score             *int    `json:"score" validate:"omitempty,min=0,max=100"`
-	Questions         *string `json:"questions"`
-	Comment           *string `json:"comment"`
+	StudentTrainingID *uint                     `json:"student_training_id"`
+	Score             *int                      `json:"score" validate:"omitempty,min=0,max=100"`
+	Questions         *string                   `json:"questions"`
+	Comment           *string                   `json:"comment"`
+	Answers           []models.EvaluationAnswer `json:"answers"`
 }
 
 type UploadVisitPhotoRequest struct {
@@ -162,7 +196,8 @@ func (s *VisitorService) GetVisitorTrainings(req VisitorTrainingListRequest) (*V
 		Preload("Visitor.User").
 		Preload("Schedules").
 		Preload("EvaluateStudent").
-		Preload("EvaluateCompany")
+		Preload("EvaluateCompany").
+		Scopes(req.Scope.StudentEnrolls("visitor_trainings.student_enroll_id"))
 
 	if req.StudentEnrollID != nil {
 		query = query.Where("student_enroll_id = ?", *req.StudentEnrollID)
@@ -332,7 +367,8 @@ func (s *VisitorService) GetVisitorSchedules(req VisitorScheduleListRequest) (*V
 	query := s.db.Model(&models.VisitorSchedule{}).
 		Preload("Training.StudentEnroll.Student.User").
 		Preload("Training.Visitor.User").
-		Preload("Photos")
+		Preload("Photos").
+		Scopes(req.Scope.VisitorTrainings("visitor_schedules.visitor_training_id"))
 
 	if req.VisitorTrainingID != nil {
 		query = query.Where("visitor_training_id = ?", *req.VisitorTrainingID)
@@ -421,10 +457,17 @@ func (s *VisitorService) CreateVisitorSchedule(req CreateVisitorScheduleRequest)
 		return nil, errors.New("visitor schedule with this visit number already exists for this training")
 	}
 
+	visitMode := req.VisitMode
+	if visitMode == "" {
+		visitMode = models.VisitModeOnsite
+	}
+
 	schedule := models.VisitorSchedule{
 		VisitorTrainingID: req.VisitorTrainingID,
 		VisitNo:           req.VisitNo,
 		VisitAt:           req.VisitAt,
+		VisitMode:         visitMode,
+		MeetingURL:        req.MeetingURL,
 		Comment:           req.Comment,
 	}
 
@@ -447,6 +490,12 @@ func (s *VisitorService) UpdateVisitorSchedule(id uint, req UpdateVisitorSchedul
 	if req.VisitAt != nil {
 		schedule.VisitAt = req.VisitAt
 	}
+	if req.VisitMode != nil {
+		schedule.VisitMode = *req.VisitMode
+	}
+	if req.MeetingURL != nil {
+		schedule.MeetingURL = req.MeetingURL
+	}
 	if req.Comment != nil {
 		schedule.Comment = req.Comment
 	}
@@ -506,9 +555,9 @@ func (s *VisitorService) GetVisitorEvaluateStudentByID(id uint) (*models.Visitor
 }
 
 func (s *VisitorService) CreateVisitorEvaluateStudent(req CreateVisitorEvaluateStudentRequest) (*models.VisitorEvaluateStudent, error) {
-	// Verify visitor training exists
+	// Verify visitor training exists and is in the caller's scope
 	var training models.VisitorTraining
-	if err := s.db.First(&training, req.VisitorTrainingID).Error; err != nil {
+	if err := s.db.Scopes(req.Scope.VisitorTrainings("visitor_trainings.id")).First(&training, req.VisitorTrainingID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("visitor training not found")
 		}
@@ -522,8 +571,15 @@ func (s *VisitorService) CreateVisitorEvaluateStudent(req CreateVisitorEvaluateS
 		Comment:           req.Comment,
 	}
 
-	if err := s.db.Create(&evaluation).Error; err != nil {
-		return nil, fmt.Errorf("failed to create visitor evaluate student: %w", err)
+	// The evaluation is mirrored as an evaluation form submission in the same transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&evaluation).Error; err != nil {
+			return fmt.Errorf("failed to create visitor evaluate student: %w", err)
+		}
+		return syncLegacyMirror(NewLegacyEvaluationService(tx).SyncVisitorEvaluateStudent(&evaluation, req.Answers), req.Answers)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return s.GetVisitorEvaluateStudentByID(evaluation.ID)
@@ -548,8 +604,14 @@ func (s *VisitorService) UpdateVisitorEvaluateStudent(id uint, req UpdateVisitor
 		evaluation.Comment = *req.Comment
 	}
 
-	if err := s.db.Save(&evaluation).Error; err != nil {
-		return nil, fmt.Errorf("failed to update visitor evaluate student: %w", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&evaluation).Error; err != nil {
+			return fmt.Errorf("failed to update visitor evaluate student: %w", err)
+		}
+		return syncLegacyMirror(NewLegacyEvaluationService(tx).SyncVisitorEvaluateStudent(&evaluation, req.Answers), req.Answers)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return s.GetVisitorEvaluateStudentByID(evaluation.ID)
@@ -564,11 +626,13 @@ func (s *VisitorService) DeleteVisitorEvaluateStudent(id uint) error {
 		return fmt.Errorf("database error: %w", err)
 	}
 
-	if err := s.db.Delete(&evaluation).Error; err != nil {
-		return fmt.Errorf("failed to delete visitor evaluate student: %w", err)
-	}
-
-	return nil
+	// The mirrored submission goes with the record, so analytics never count a deleted evaluation
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&evaluation).Error; err != nil {
+			return fmt.Errorf("failed to delete visitor evaluate student: %w", err)
+		}
+		return NewLegacyEvaluationService(tx).RemoveMirror(models.LegacySourceVisitorEvaluateStudent, evaluation.ID)
+	})
 }
 
 // Visitor Evaluate Company methods
@@ -605,19 +669,19 @@ func (s *VisitorService) GetVisitorEvaluateCompanyByID(id uint) (*models.Visitor
 }
 
 func (s *VisitorService) CreateVisitorEvaluateCompany(req CreateVisitorEvaluateCompanyRequest) (*models.VisitorEvaluateCompany, error) {
-	// Verify visitor training exists
+	// Verify visitor training exists and is in the caller's scope
 	var training models.VisitorTraining
-	if err := s.db.First(&training, req.VisitorTrainingID).Error; err != nil {
+	if err := s.db.Scopes(req.Scope.VisitorTrainings("visitor_trainings.id")).First(&training, req.VisitorTrainingID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("visitor training not found")
 		}
 		return nil, fmt.Errorf("database error: %w", err)
 	}
 
-	// Verify student training exists if provided
+	// Verify student training exists and is in the caller's scope if provided
 	if req.StudentTrainingID != nil {
 		var studentTraining models.StudentTraining
-		if err := s.db.First(&studentTraining, *req.StudentTrainingID).Error; err != nil {
+		if err := s.db.Scopes(req.Scope.StudentTrainings("student_trainings.id")).First(&studentTraining, *req.StudentTrainingID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, errors.New("student training not found")
 			}
@@ -633,8 +697,15 @@ func (s *VisitorService) CreateVisitorEvaluateCompany(req CreateVisitorEvaluateC
 		Comment:           req.Comment,
 	}
 
-	if err := s.db.Create(&evaluation).Error; err != nil {
-		return nil, fmt.Errorf("failed to create visitor evaluate company: %w", err)
+	// The evaluation is mirrored as an evaluation form submission in the same transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&evaluation).Error; err != nil {
+			return fmt.Errorf("failed to create visitor evaluate company: %w", err)
+		}
+		return syncLegacyMirror(NewLegacyEvaluationService(tx).SyncVisitorEvaluateCompany(&evaluation, req.Answers), req.Answers)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return s.GetVisitorEvaluateCompanyByID(evaluation.ID)
@@ -670,8 +741,14 @@ func (s *VisitorService) UpdateVisitorEvaluateCompany(id uint, req UpdateVisitor
 		evaluation.Comment = *req.Comment
 	}
 
-	if err := s.db.Save(&evaluation).Error; err != nil {
-		return nil, fmt.Errorf("failed to update visitor evaluate company: %w", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&evaluation).Error; err != nil {
+			return fmt.Errorf("failed to update visitor evaluate company: %w", err)
+		}
+		return syncLegacyMirror(NewLegacyEvaluationService(tx).SyncVisitorEvaluateCompany(&evaluation, req.Answers), req.Answers)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return s.GetVisitorEvaluateCompanyByID(evaluation.ID)
@@ -686,11 +763,22 @@ func (s *VisitorService) DeleteVisitorEvaluateCompany(id uint) error {
 		return fmt.Errorf("database error: %w", err)
 	}
 
-	if err := s.db.Delete(&evaluation).Error; err != nil {
-		return fmt.Errorf("failed to delete visitor evaluate company: %w", err)
-	}
+	// The mirrored submission goes with the record, so analytics never count a deleted evaluation
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&evaluation).Error; err != nil {
+			return fmt.Errorf("failed to delete visitor evaluate company: %w", err)
+		}
+		return NewLegacyEvaluationService(tx).RemoveMirror(models.LegacySourceVisitorEvaluateCompany, evaluation.ID)
+	})
+}
 
-	return nil
+// syncLegacyMirror tolerates records that cannot be mirrored yet because the student has no training,
+// unless structured answers were sent, which need the training's evaluation form
+func syncLegacyMirror(err error, answers []models.EvaluationAnswer) error {
+	if errors.Is(err, errLegacyUnlinked) && len(answers) == 0 {
+		return nil
+	}
+	return err
 }
 
 // Visit Photo methods
@@ -131,25 +131,7 @@ func validatePassword(fl validator.FieldLevel) bool {
 
 // validatePhone validates Thai phone number format
 func validatePhone(fl validator.FieldLevel) bool {
-	phone := fl.Field().String()
-	
-	// Remove common separators
-	phone = strings.ReplaceAll(phone, "-", "")
-	phone = strings.ReplaceAll(phone, " ", "")
-	phone = strings.ReplaceAll(phone, "(", "")
-	phone = strings.ReplaceAll(phone, ")", "")
-	
-	// Check if it's a valid Thai phone number (10 digits starting with 0)
-	if len(phone) == 10 && phone[0] == '0' {
-		for _, char := range phone[1:] {
-			if char < '0' || char > '9' {
-				return false
-			}
-		}
-		return true
-	}
-	
-	return false
+	return services.IsValidPhoneNumber(fl.Field().String())
 }
 
 // validateStudentID validates student ID format using enhanced validation service